@@ -1,23 +1,80 @@
 package v1
 
 import (
+	"bufio"
 	"container/heap"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"connectrpc.com/connect"
 	service "github.com/bitbomdev/minefield/gen/api/v1"
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/bitbomdev/minefield/pkg/tools"
 	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/bitbomdev/minefield/pkg/tools/license"
 	"github.com/goccy/go-json"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// SignatureHeader carries the signature of a graph archive sent to ImportGraph or an SBOM sent
+// to IngestSBOMStream.
+const SignatureHeader = "Minefield-Signature"
+
 type Service struct {
 	storage     graph.Storage
 	concurrency int32
+	// trustedKeys is set if ingested payloads and imported archives have to be signed.
+	trustedKeys signing.Keyring
+	// mergePolicy decides how the metadata of ingested nodes that already exist is merged.
+	mergePolicy graph.MergePolicy
+	// identityQualifiers are the purl qualifiers kept in node names, nil for the default ones.
+	identityQualifiers []string
+}
+
+// Option configures optional behavior of a Service.
+type Option func(*Service)
+
+// WithRequiredSignatures makes the service refuse payloads that aren't signed by one of
+// the keys in keyring.
+func WithRequiredSignatures(keyring signing.Keyring) Option {
+	return func(s *Service) {
+		s.trustedKeys = keyring
+	}
+}
+
+// WithMergePolicy makes ingestion merge the metadata of nodes that already exist by policy.
+func WithMergePolicy(policy graph.MergePolicy) Option {
+	return func(s *Service) {
+		s.mergePolicy = policy
+	}
+}
+
+// WithIdentityQualifiers makes ingestion keep the given purl qualifiers in node names.
+func WithIdentityQualifiers(qualifiers []string) Option {
+	return func(s *Service) {
+		s.identityQualifiers = qualifiers
+	}
+}
+
+// ingestOptions returns the options ingested documents are processed with.
+func (s *Service) ingestOptions() []ingest.Option {
+	opts := []ingest.Option{ingest.WithMergePolicy(s.mergePolicy)}
+	if s.identityQualifiers != nil {
+		opts = append(opts, ingest.WithIdentityQualifiers(s.identityQualifiers))
+	}
+	return opts
 }
 
 func NodeToServiceNode(node *graph.Node) (*service.Node, error) {
@@ -36,8 +93,31 @@ func NodeToServiceNode(node *graph.Node) (*service.Node, error) {
 	}, nil
 }
 
-func NewService(storage graph.Storage, concurrency int32) *Service {
-	return &Service{storage: storage, concurrency: concurrency}
+func NewService(storage graph.Storage, concurrency int32, opts ...Option) *Service {
+	s := &Service{storage: storage, concurrency: concurrency}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// verifyPayload checks the signature of an ingested payload if signatures are required.
+func (s *Service) verifyPayload(payload, signature []byte) error {
+	if s.trustedKeys == nil {
+		return nil
+	}
+	if err := s.trustedKeys.Verify(payload, signature); err != nil {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("refusing payload: %w", err))
+	}
+	return nil
+}
+
+// storageError gives errors about missing nodes and caches the NotFound code.
+func storageError(err error) error {
+	if errors.Is(err, graph.ErrNodeNotFound) || errors.Is(err, graph.ErrCacheNotFound) {
+		return connect.NewError(connect.CodeNotFound, err)
+	}
+	return err
 }
 
 type Query struct {
@@ -46,9 +126,9 @@ type Query struct {
 }
 
 func (s *Service) GetNode(ctx context.Context, req *connect.Request[service.GetNodeRequest]) (*connect.Response[service.GetNodeResponse], error) {
-	node, err := s.storage.GetNode(req.Msg.Id)
+	node, err := s.storage.GetNode(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node by id: %w", err)
+		return nil, storageError(fmt.Errorf("failed to get node by id: %w", err))
 	}
 	serviceNode, err := NodeToServiceNode(node)
 	if err != nil {
@@ -58,28 +138,35 @@ func (s *Service) GetNode(ctx context.Context, req *connect.Request[service.GetN
 }
 
 func (s *Service) GetNodeByName(ctx context.Context, req *connect.Request[service.GetNodeByNameRequest]) (*connect.Response[service.GetNodeByNameResponse], error) {
-	id, err := s.storage.NameToID(req.Msg.Name)
+	id, err := graph.ResolveName(ctx, s.storage, req.Msg.Name)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to get node by name: %w", err))
+	}
+	node, err := s.storage.GetNode(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node by name: %w", err)
+		return nil, storageError(fmt.Errorf("failed to get node by id: %w", err))
 	}
-	node, err := s.storage.GetNode(id)
+	aliases, err := s.storage.GetAliases(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node by id: %w", err)
+		return nil, fmt.Errorf("failed to get aliases of node: %w", err)
 	}
 	serviceNode, err := NodeToServiceNode(node)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert node to service node: %w", err)
 	}
-	return connect.NewResponse(&service.GetNodeByNameResponse{Node: serviceNode}), nil
+	return connect.NewResponse(&service.GetNodeByNameResponse{Node: serviceNode, Aliases: aliases}), nil
 }
 
 func (s *Service) GetNodesByGlob(ctx context.Context, req *connect.Request[service.GetNodesByGlobRequest]) (*connect.Response[service.GetNodesByGlobResponse], error) {
-	nodes, err := s.storage.GetNodesByGlob(req.Msg.Pattern)
+	nodes, err := s.storage.GetNodesByGlob(ctx, req.Msg.Pattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nodes by glob: %w", err)
 	}
 	serviceNodes := make([]*service.Node, 0, len(nodes))
 	for _, node := range nodes {
+		if !tools.Listed(node.Type) {
+			continue
+		}
 		serviceNode, err := NodeToServiceNode(node)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert node to service node: %w", err)
@@ -90,7 +177,7 @@ func (s *Service) GetNodesByGlob(ctx context.Context, req *connect.Request[servi
 }
 
 func (s *Service) AddNode(ctx context.Context, req *connect.Request[service.AddNodeRequest]) (*connect.Response[service.AddNodeResponse], error) {
-	resultNode, err := graph.AddNode(s.storage, req.Msg.Node.Type, req.Msg.Node.Metadata, req.Msg.Node.Name)
+	resultNode, err := graph.AddNode(ctx, s.storage, req.Msg.Node.Type, req.Msg.Node.Metadata, req.Msg.Node.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add node: %w", err)
 	}
@@ -102,23 +189,50 @@ func (s *Service) AddNode(ctx context.Context, req *connect.Request[service.AddN
 }
 
 func (s *Service) SetDependency(ctx context.Context, req *connect.Request[service.SetDependencyRequest]) (*connect.Response[emptypb.Empty], error) {
-	fromNode, err := s.storage.GetNode(req.Msg.NodeId)
+	fromNode, err := s.storage.GetNode(ctx, req.Msg.NodeId)
 	if err != nil {
-		return nil, err
+		return nil, storageError(err)
 	}
-	toNode, err := s.storage.GetNode(req.Msg.DependencyID)
+	toNode, err := s.storage.GetNode(ctx, req.Msg.DependencyID)
 	if err != nil {
-		return nil, err
+		return nil, storageError(err)
 	}
-	err = fromNode.SetDependency(s.storage, toNode)
+	err = fromNode.SetDependency(ctx, s.storage, toNode)
 	if err != nil {
 		return nil, err
 	}
 	return connect.NewResponse(&emptypb.Empty{}), nil
 }
 
+func (s *Service) UpdateNodeMetadata(ctx context.Context, req *connect.Request[service.UpdateNodeMetadataRequest]) (*connect.Response[service.UpdateNodeMetadataResponse], error) {
+	strategy := graph.MergeLastWins
+	if req.Msg.Strategy != "" {
+		var err error
+		if strategy, err = graph.ParseMergeStrategy(req.Msg.Strategy); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	var metadata any
+	if err := json.Unmarshal(req.Msg.Metadata, &metadata); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("metadata is not valid JSON: %w", err))
+	}
+	node, err := s.storage.GetNode(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to get node by id: %w", err))
+	}
+	node, err = graph.MergeNodeMetadata(ctx, s.storage, node, metadata, strategy)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to update node metadata: %w", err))
+	}
+	serviceNode, err := NodeToServiceNode(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert node to service node: %w", err)
+	}
+	return connect.NewResponse(&service.UpdateNodeMetadataResponse{Node: serviceNode}), nil
+}
+
 func (s *Service) Cache(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
-	err := graph.Cache(s.storage)
+	err := graph.Cache(ctx, s.storage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to cache: %w", err)
 	}
@@ -126,7 +240,7 @@ func (s *Service) Cache(ctx context.Context, req *connect.Request[emptypb.Empty]
 }
 
 func (s *Service) Clear(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
-	err := s.storage.RemoveAllCaches()
+	err := s.storage.RemoveAllCaches(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to clear: %w", err)
 	}
@@ -134,7 +248,7 @@ func (s *Service) Clear(ctx context.Context, req *connect.Request[emptypb.Empty]
 }
 
 func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[service.CustomLeaderboardRequest]) (*connect.Response[service.CustomLeaderboardResponse], error) {
-	uncachedNodes, err := s.storage.ToBeCached()
+	uncachedNodes, err := s.storage.ToBeCached(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get uncached nodes: %w", err)
 	}
@@ -142,22 +256,27 @@ func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[se
 		return nil, fmt.Errorf("cannot use sorted leaderboards without caching")
 	}
 
-	keys, err := s.storage.GetAllKeys()
+	keys, err := s.storage.GetAllKeys(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to Query keys: %w", err)
 	}
 
-	nodes, err := s.storage.GetNodes(keys)
+	nodes, err := s.storage.GetNodes(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch Query nodes from keys: %w", err)
 	}
 
-	caches, err := s.storage.GetCaches(keys)
+	caches, err := s.storage.GetCaches(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch Query caches from keys: %w", err)
 	}
 
-	cacheStack, err := s.storage.ToBeCached()
+	cacheStack, err := s.storage.ToBeCached(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suppressions, err := s.suppressions(ctx, req.Msg.IncludeSuppressed)
 	if err != nil {
 		return nil, err
 	}
@@ -175,9 +294,13 @@ func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[se
 	var wg sync.WaitGroup
 	var atomicCounter int64
 	for _, node := range nodes {
-		if node.Name == "" {
+		if node.Name == "" || !tools.Listed(node.Type) {
 			continue
 		}
+		// Stop handing out work once the client went away or its deadline passed.
+		if ctx.Err() != nil {
+			break
+		}
 
 		wg.Add(1)
 		semaphore <- struct{}{} // Acquire a token
@@ -185,7 +308,7 @@ func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[se
 			defer wg.Done()
 			defer func() { <-semaphore }() // Release the token
 
-			execute, err := graph.ParseAndExecute(req.Msg.Script, s.storage, node.Name, nodes, caches, len(cacheStack) == 0)
+			execute, err := graph.ParseAndExecute(ctx, req.Msg.Script, s.storage, node.Name, nodes, caches, len(cacheStack) == 0, suppressions)
 			if err != nil {
 				errChan <- err
 				return
@@ -215,6 +338,9 @@ func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[se
 	for q := range queryChan {
 		heap.Push(h, q)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	queries := make([]*service.Query, h.Len())
 	for i := len(queries) - 1; i >= 0; i-- {
@@ -237,17 +363,20 @@ func (s *Service) CustomLeaderboard(ctx context.Context, req *connect.Request[se
 }
 
 func (s *Service) AllKeys(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[service.AllKeysResponse], error) {
-	keys, err := s.storage.GetAllKeys()
+	keys, err := s.storage.GetAllKeys(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all keys: %w", err)
 	}
-	nodes, err := s.storage.GetNodes(keys)
+	nodes, err := s.storage.GetNodes(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nodes by keys: %w", err)
 	}
 
 	resultNodes := make([]*service.Node, 0, len(nodes))
 	for _, node := range nodes {
+		if !tools.Listed(node.Type) {
+			continue
+		}
 		query, err := NodeToServiceNode(node)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert node to service node: %w", err)	
@@ -264,30 +393,34 @@ func (s *Service) Query(ctx context.Context, req *connect.Request[service.QueryR
 	if req == nil {
 		return nil, fmt.Errorf("request is nil")
 	}
-	keys, err := s.storage.GetAllKeys()
+	keys, err := s.storage.GetAllKeys(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all keys: %w", err)
 	}
 
-	nodes, err := s.storage.GetNodes(keys)
+	nodes, err := s.storage.GetNodes(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nodes by keys: %w", err)
 	}
 
-	caches, err := s.storage.GetCaches(keys)
+	caches, err := s.storage.GetCaches(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get caches by keys: %w", err)
 	}
-	cacheStack, err := s.storage.ToBeCached()
+	cacheStack, err := s.storage.ToBeCached(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get to be cached nodes: %w", err)
 	}
-	result, err := graph.ParseAndExecute(req.Msg.Script, s.storage, "", nodes, caches, len(cacheStack) == 0)
+	suppressions, err := s.suppressions(ctx, req.Msg.IncludeSuppressed)
+	if err != nil {
+		return nil, err
+	}
+	result, err := graph.ParseAndExecute(ctx, req.Msg.Script, s.storage, "", nodes, caches, len(cacheStack) == 0, suppressions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse and execute script: %w", err)
 	}
 
-	outputNodes, err := s.storage.GetNodes(result.ToArray())
+	outputNodes, err := s.storage.GetNodes(ctx, result.ToArray())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nodes by ids: %w", err)
 	}
@@ -312,28 +445,444 @@ func (s *Service) Check(ctx context.Context, req *connect.Request[emptypb.Empty]
 	return connect.NewResponse(&service.HealthCheckResponse{Status: "ok"}), nil
 }
 
-func (s *Service) IngestSBOM(ctx context.Context, req *connect.Request[service.IngestSBOMRequest]) (*connect.Response[emptypb.Empty], error) {
-	err := ingest.SBOM(s.storage, req.Msg.Sbom)
+func (s *Service) GetStorageCacheStats(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[service.GetStorageCacheStatsResponse], error) {
+	cached, ok := s.storage.(*storages.CachedStorage)
+	if !ok {
+		return connect.NewResponse(&service.GetStorageCacheStatsResponse{Enabled: false}), nil
+	}
+	stats := cached.Stats()
+	return connect.NewResponse(&service.GetStorageCacheStatsResponse{
+		Enabled: true,
+		Nodes:   CacheStatsToServiceStats(stats.Nodes),
+		Caches:  CacheStatsToServiceStats(stats.Caches),
+	}), nil
+}
+
+// storageNodesToServiceNodes converts nodes for a response.
+func storageNodesToServiceNodes(nodes []*graph.Node) ([]*service.Node, error) {
+	serviceNodes := make([]*service.Node, 0, len(nodes))
+	for _, node := range nodes {
+		serviceNode, err := NodeToServiceNode(node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert node to service node: %w", err)
+		}
+		serviceNodes = append(serviceNodes, serviceNode)
+	}
+	return serviceNodes, nil
+}
+
+func (s *Service) ListDocuments(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[service.ListDocumentsResponse], error) {
+	documents, err := ingest.Documents(ctx, s.storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	serviceDocuments, err := storageNodesToServiceNodes(documents)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&service.ListDocumentsResponse{Documents: serviceDocuments}), nil
+}
+
+// EvaluateLicensePolicy evaluates the license policy on the libraries of every requested
+// project, or of every root library.
+func (s *Service) EvaluateLicensePolicy(ctx context.Context, req *connect.Request[service.LicensePolicyRequest]) (*connect.Response[service.LicensePolicyResponse], error) {
+	policy := license.Policy{Allow: req.Msg.Allow, Deny: req.Msg.Deny}
+	reports, err := license.EvaluatePolicy(ctx, s.storage, policy, req.Msg.Projects)
+	if errors.Is(err, graph.ErrNodeNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate license policy: %w", err)
+	}
+	serviceReports := make([]*service.LicensePolicyReport, 0, len(reports))
+	for _, report := range reports {
+		violations := make([]*service.LicenseViolation, 0, len(report.Violations))
+		for _, violation := range report.Violations {
+			violations = append(violations, &service.LicenseViolation{
+				Library:    violation.Library,
+				Expression: violation.Expression,
+				Verdict:    violation.Verdict,
+				Licenses:   violation.Licenses,
+			})
+		}
+		serviceReports = append(serviceReports, &service.LicensePolicyReport{
+			Project:    report.Project,
+			Libraries:  int32(report.Libraries),
+			Unlicensed: int32(report.Unlicensed),
+			Violations: violations,
+		})
+	}
+	return connect.NewResponse(&service.LicensePolicyResponse{Reports: serviceReports}), nil
+}
+
+func (s *Service) GetDocumentContribution(ctx context.Context, req *connect.Request[service.GetDocumentContributionRequest]) (*connect.Response[service.GetDocumentContributionResponse], error) {
+	document, contribution, err := ingest.GetContribution(ctx, s.storage, req.Msg.Name)
+	if errors.Is(err, ingest.ErrDocumentNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to get document contribution: %w", err))
+	}
+	serviceDocument, err := NodeToServiceNode(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert node to service node: %w", err)
+	}
+	edges := []*service.Edge{}
+	for _, edge := range contribution.EdgeList() {
+		edges = append(edges, &service.Edge{From: edge.From, To: edge.To})
+	}
+	ids := contribution.Nodes.ToArray()
+	nodes, err := s.storage.GetNodes(ctx, ids)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to get contributed nodes: %w", err))
+	}
+	names := make(map[uint32]string, len(nodes))
+	for id, node := range nodes {
+		names[id] = node.Name
+	}
+	return connect.NewResponse(&service.GetDocumentContributionResponse{
+		Document: serviceDocument,
+		Nodes:    ids,
+		Edges:    edges,
+		Names:    names,
+	}), nil
+}
+
+func (s *Service) GetNodeDocuments(ctx context.Context, req *connect.Request[service.GetNodeDocumentsRequest]) (*connect.Response[service.GetNodeDocumentsResponse], error) {
+	if _, err := s.storage.GetNode(ctx, req.Msg.Id); err != nil {
+		return nil, storageError(fmt.Errorf("failed to get node by id: %w", err))
+	}
+	documents, err := ingest.DocumentsForNode(ctx, s.storage, req.Msg.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents of node: %w", err)
+	}
+	serviceDocuments, err := storageNodesToServiceNodes(documents)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&service.GetNodeDocumentsResponse{Documents: serviceDocuments}), nil
+}
+
+func CacheStatsToServiceStats(stats storages.CacheStats) *service.StorageCacheStats {
+	return &service.StorageCacheStats{
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		Entries:  uint64(stats.Entries),
+		Capacity: uint64(stats.Capacity),
+		HitRate:  stats.HitRate(),
+	}
+}
+
+func (s *Service) IngestSBOM(ctx context.Context, req *connect.Request[service.IngestSBOMRequest]) (*connect.Response[service.IngestResponse], error) {
+	if err := s.verifyPayload(req.Msg.Sbom, req.Msg.Signature); err != nil {
+		return nil, err
+	}
+	if unchanged, err := s.unchanged(ctx, ingest.Digest(req.Msg.Sbom)); err != nil || unchanged {
+		return unchangedResponse(err)
+	}
+	err := ingest.SBOM(ctx, s.storage, req.Msg.Sbom, s.ingestOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ingest sbom: %w", err)
 	}
-	return connect.NewResponse(&emptypb.Empty{}), nil
+	return connect.NewResponse(&service.IngestResponse{}), nil
+}
+
+// IngestSBOMStream ingests an SBOM uploaded in chunks. The SBOM is spooled to a temporary file,
+// so it can be verified and recognized as unchanged before anything is ingested, and its nodes
+// and edges are then decoded a batch at a time, so the document never has to fit in memory.
+// SBOMs that aren't JSON are rejected as invalid.
+func (s *Service) IngestSBOMStream(ctx context.Context, stream *connect.ClientStream[service.IngestSBOMChunk]) (*connect.Response[service.IngestResponse], error) {
+	spooled, digest, err := spool(&chunkReader[service.IngestSBOMChunk]{stream: stream}, "SBOM")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spooled.Name())
+	defer spooled.Close()
+	if s.trustedKeys != nil {
+		if err := s.verifyDigest(digest, stream.RequestHeader().Get(SignatureHeader), "SBOM"); err != nil {
+			return nil, err
+		}
+	}
+	if unchanged, err := s.unchanged(ctx, hex.EncodeToString(digest[:])); err != nil || unchanged {
+		return unchangedResponse(err)
+	}
+
+	err = ingest.SBOMStream(ctx, s.storage, spooled, s.ingestOptions()...)
+	if errors.Is(err, ingest.ErrSBOMNotJSON) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest sbom: %w", err)
+	}
+	return connect.NewResponse(&service.IngestResponse{}), nil
 }
 
-func (s *Service) IngestVulnerability(ctx context.Context, req *connect.Request[service.IngestVulnerabilityRequest]) (*connect.Response[emptypb.Empty], error) {
-	err := ingest.Vulnerabilities(s.storage, req.Msg.Vulnerability)
+func (s *Service) IngestVulnerability(ctx context.Context, req *connect.Request[service.IngestVulnerabilityRequest]) (*connect.Response[service.IngestResponse], error) {
+	if err := s.verifyPayload(req.Msg.Vulnerability, req.Msg.Signature); err != nil {
+		return nil, err
+	}
+	if unchanged, err := s.unchanged(ctx, ingest.Digest(req.Msg.Vulnerability)); err != nil || unchanged {
+		return unchangedResponse(err)
+	}
+	err := ingest.Vulnerabilities(ctx, s.storage, req.Msg.Vulnerability, s.ingestOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ingest vulnerability: %w", err)
 	}
-	return connect.NewResponse(&emptypb.Empty{}), nil
+	return connect.NewResponse(&service.IngestResponse{}), nil
+}
+
+// IngestVulnerabilityArchive ingests a ZIP archive of OSV records, like the all.zip exports of
+// osv.dev, in one pass.
+func (s *Service) IngestVulnerabilityArchive(ctx context.Context, stream *connect.ClientStream[service.IngestVulnerabilityArchiveChunk]) (*connect.Response[service.IngestVulnerabilityArchiveResponse], error) {
+	spooled, digest, err := spool(&chunkReader[service.IngestVulnerabilityArchiveChunk]{stream: stream}, "vulnerability archive")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spooled.Name())
+	defer spooled.Close()
+	if s.trustedKeys != nil {
+		if err := s.verifyDigest(digest, stream.RequestHeader().Get(SignatureHeader), "vulnerability archive"); err != nil {
+			return nil, err
+		}
+	}
+	info, err := spooled.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability archive: %w", err)
+	}
+
+	result, err := ingest.VulnerabilityArchive(ctx, s.storage, spooled, info.Size(), s.ingestOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest vulnerability archive: %w", err)
+	}
+	return connect.NewResponse(&service.IngestVulnerabilityArchiveResponse{
+		Ingested:  int32(result.Ingested),
+		Unchanged: int32(result.Unchanged),
+	}), nil
+}
+
+// Relink matches every stored advisory against the library nodes currently in the graph.
+func (s *Service) Relink(ctx context.Context, _ *connect.Request[emptypb.Empty]) (*connect.Response[service.RelinkResponse], error) {
+	result, err := ingest.Relink(ctx, s.storage, s.ingestOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to relink advisories: %w", err)
+	}
+	return connect.NewResponse(&service.RelinkResponse{
+		Advisories: int32(result.Advisories),
+		Links:      int32(result.Links),
+	}), nil
 }
 
-func (s *Service) IngestScorecard(ctx context.Context, req *connect.Request[service.IngestScorecardRequest]) (*connect.Response[emptypb.Empty], error) {
-	err := ingest.Scorecards(s.storage, req.Msg.Scorecard)
+func (s *Service) IngestScorecard(ctx context.Context, req *connect.Request[service.IngestScorecardRequest]) (*connect.Response[service.IngestResponse], error) {
+	if err := s.verifyPayload(req.Msg.Scorecard, req.Msg.Signature); err != nil {
+		return nil, err
+	}
+	// Scorecards are always ingested again, they link to the libraries in the graph when they
+	// are ingested and libraries may have arrived since.
+	err := ingest.Scorecards(ctx, s.storage, req.Msg.Scorecard, s.ingestOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ingest scorecard: %w", err)
 	}
-	return connect.NewResponse(&emptypb.Empty{}), nil
+	return connect.NewResponse(&service.IngestResponse{}), nil
+}
+
+func (s *Service) IngestVEX(ctx context.Context, req *connect.Request[service.IngestVEXRequest]) (*connect.Response[service.IngestResponse], error) {
+	if err := s.verifyPayload(req.Msg.Vex, req.Msg.Signature); err != nil {
+		return nil, err
+	}
+	if unchanged, err := s.unchanged(ctx, ingest.Digest(req.Msg.Vex)); err != nil || unchanged {
+		return unchangedResponse(err)
+	}
+	err := ingest.VEX(ctx, s.storage, req.Msg.Vex, s.ingestOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest VEX: %w", err)
+	}
+	return connect.NewResponse(&service.IngestResponse{}), nil
+}
+
+func (s *Service) IngestLockfile(ctx context.Context, req *connect.Request[service.IngestLockfileRequest]) (*connect.Response[service.IngestResponse], error) {
+	if err := s.verifyPayload(req.Msg.Lockfile, req.Msg.Signature); err != nil {
+		return nil, err
+	}
+	format := req.Msg.Format
+	if format == "" {
+		detected, err := ingest.DetectLockfile(req.Msg.Name, req.Msg.Lockfile)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		format = detected
+	} else if !slices.Contains(ingest.LockfileFormats, format) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unsupported lockfile format %q, expected one of %s", format, strings.Join(ingest.LockfileFormats, ", ")))
+	}
+	// The same lockfile may be ingested for another project.
+	if req.Msg.Project == "" {
+		if unchanged, err := s.unchanged(ctx, ingest.Digest(req.Msg.Lockfile)); err != nil || unchanged {
+			return unchangedResponse(err)
+		}
+	}
+	skipped, err := ingest.Lockfile(ctx, s.storage, format, req.Msg.Lockfile, req.Msg.Project, s.ingestOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest lockfile: %w", err)
+	}
+	return connect.NewResponse(&service.IngestResponse{Skipped: skipped}), nil
+}
+
+// suppressions returns the vulnerabilities queries leave out because VEX statements say they
+// don't affect a product, or none if the request includes them.
+func (s *Service) suppressions(ctx context.Context, include bool) (graph.Suppressions, error) {
+	if include {
+		return nil, nil
+	}
+	suppressions, err := ingest.Suppressions(ctx, s.storage, s.ingestOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load VEX suppressions: %w", err)
+	}
+	return suppressions, nil
+}
+
+// unchanged reports whether the payload with the given digest was already ingested, so
+// ingesting it again would only re-parse it and push its nodes onto the cache stack again.
+func (s *Service) unchanged(ctx context.Context, sha256 string) (bool, error) {
+	unchanged, err := ingest.Unchanged(ctx, s.storage, sha256)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up payload: %w", err)
+	}
+	return unchanged, nil
+}
+
+func unchangedResponse(err error) (*connect.Response[service.IngestResponse], error) {
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&service.IngestResponse{Unchanged: true}), nil
+}
+
+// archiveChunkSize is the size of the archive chunks sent by ExportGraph.
+const archiveChunkSize = 64 * 1024
+
+func (s *Service) ExportGraph(ctx context.Context, req *connect.Request[emptypb.Empty], stream *connect.ServerStream[service.ArchiveChunk]) error {
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, archiveChunkSize)
+	if _, err := storages.ExportGraph(ctx, s.storage, w, storages.DefaultCopyBatchSize); err != nil {
+		return fmt.Errorf("failed to export graph: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to send archive: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) ImportGraph(ctx context.Context, stream *connect.ClientStream[service.ArchiveChunk]) (*connect.Response[service.ImportGraphResponse], error) {
+	var archive io.Reader = &chunkReader[service.ArchiveChunk]{stream: stream}
+	if s.trustedKeys != nil {
+		// The archive has to be verified before anything is written to the storage, so it
+		// is spooled to a temporary file while its digest is computed.
+		spooled, digest, err := spool(archive, "archive")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(spooled.Name())
+		defer spooled.Close()
+		if err := s.verifyDigest(digest, stream.RequestHeader().Get(SignatureHeader), "archive"); err != nil {
+			return nil, err
+		}
+		archive = spooled
+	}
+
+	summary, err := storages.ImportGraph(ctx, s.storage, archive, storages.DefaultCopyBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import graph: %w", err)
+	}
+	return connect.NewResponse(&service.ImportGraphResponse{Summary: SummaryToServiceSummary(summary)}), nil
+}
+
+// spool copies the upload, an archive or SBOM as named by kind, into a temporary file while
+// computing its SHA-256 digest. It returns the file positioned at its start.
+func spool(upload io.Reader, kind string) (*os.File, [sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	file, err := os.CreateTemp("", "minefield-upload-*")
+	if err != nil {
+		return nil, digest, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), upload); err != nil {
+		cleanup()
+		return nil, digest, fmt.Errorf("failed to receive %s: %w", kind, err)
+	}
+	copy(digest[:], hash.Sum(nil))
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, digest, fmt.Errorf("failed to read %s: %w", kind, err)
+	}
+	return file, digest, nil
+}
+
+// verifyDigest verifies the digest of an upload against the base64 encoded signature.
+func (s *Service) verifyDigest(digest [sha256.Size]byte, encodedSignature, kind string) error {
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid %s header: %w", SignatureHeader, err))
+	}
+	if err := s.trustedKeys.VerifyDigest(digest, signature); err != nil {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("refusing %s: %w", kind, err))
+	}
+	return nil
+}
+
+func SummaryToServiceSummary(summary *storages.GraphSummary) *service.GraphSummary {
+	return &service.GraphSummary{
+		Nodes:              uint64(summary.Nodes),
+		Caches:             uint64(summary.Caches),
+		CacheStack:         uint64(summary.CacheStack),
+		CustomData:         uint64(summary.CustomData),
+		IdCounter:          summary.IDCounter,
+		NodesChecksum:      summary.NodesChecksum,
+		CachesChecksum:     summary.CachesChecksum,
+		CacheStackChecksum: summary.CacheStackChecksum,
+		CustomDataChecksum: summary.CustomDataChecksum,
+	}
+}
+
+// chunkWriter sends everything written to it as archive chunks.
+type chunkWriter struct {
+	stream *connect.ServerStream[service.ArchiveChunk]
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	// The stream may hold on to the message, so it gets its own copy of the buffer.
+	if err := w.stream.Send(&service.ArchiveChunk{Data: append([]byte(nil), p...)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// dataChunk is a message carrying a part of an upload.
+type dataChunk interface {
+	GetData() []byte
+}
+
+// chunkReader reads the data of the chunks received on a stream.
+type chunkReader[T any] struct {
+	stream *connect.ClientStream[T]
+	buf    []byte
+}
+
+func (r *chunkReader[T]) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if !r.stream.Receive() {
+			if err := r.stream.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		r.buf = any(r.stream.Msg()).(dataChunk).GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
 }
 
 type queryHeap []*Query
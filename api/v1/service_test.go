@@ -1,13 +1,23 @@
 package v1
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	service "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/bitbomdev/minefield/pkg/tools"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -20,7 +30,7 @@ func setupService() *Service {
 
 func TestGetNode(t *testing.T) {
 	s := setupService()
-	node, err := graph.AddNode(s.storage, "type1", "metadata1", "name1")
+	node, err := graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "name1")
 	require.NoError(t, err)
 	req := connect.NewRequest(&service.GetNodeRequest{Id: node.ID})
 	resp, err := s.GetNode(context.Background(), req)
@@ -31,23 +41,34 @@ func TestGetNode(t *testing.T) {
 
 func TestGetNodeByName(t *testing.T) {
 	s := setupService()
-	node, err := graph.AddNode(s.storage, "type1", "metadata1", "name1")
+	node, err := graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "name1")
 	require.NoError(t, err)
 	req := connect.NewRequest(&service.GetNodeByNameRequest{Name: node.Name})
 	resp, err := s.GetNodeByName(context.Background(), req)
 	require.NoError(t, err)
 	assert.NotNil(t, resp.Msg.Node)
 	assert.Equal(t, node.Name, resp.Msg.Node.Name)
+	assert.Empty(t, resp.Msg.Aliases)
+
+	require.NoError(t, s.storage.AddAliases(context.Background(), node.ID, []string{"sha256:aa", "cpe:2.3:a:name:name1:1.0:*:*:*:*:*:*:*"}))
+	req = connect.NewRequest(&service.GetNodeByNameRequest{Name: "sha256:aa"})
+	resp, err = s.GetNodeByName(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, node.ID, resp.Msg.Node.Id)
+	assert.Equal(t, []string{"cpe:2.3:a:name:name1:1.0:*:*:*:*:*:*:*", "sha256:aa"}, resp.Msg.Aliases)
+
+	_, err = s.GetNodeByName(context.Background(), connect.NewRequest(&service.GetNodeByNameRequest{Name: "sha256:bb"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
 }
 
 func TestGetNodesByGlob(t *testing.T) {
 	s := setupService()
 	// Add test nodes
-	_, err := graph.AddNode(s.storage, "type1", "metadata1", "test_node1")
+	_, err := graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "test_node1")
 	require.NoError(t, err)
-	_, err = graph.AddNode(s.storage, "type1", "metadata1", "test_node2")
+	_, err = graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "test_node2")
 	require.NoError(t, err)
-	_, err = graph.AddNode(s.storage, "type1", "metadata1", "other_node")
+	_, err = graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "other_node")
 	require.NoError(t, err)
 
 	// Test GetNodesByGlob with pattern "test_*"
@@ -113,6 +134,16 @@ func TestQueriesIngestAndCache(t *testing.T) {
 		t.Fatalf("No queries found")
 	}
 
+	// A client that went away or ran out of time doesn't get a leaderboard.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.CustomLeaderboard(cancelled, customLeaderboardReq)
+	assert.ErrorIs(t, err, context.Canceled)
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = s.CustomLeaderboard(expired, customLeaderboardReq)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
 	queryReq := connect.NewRequest(&service.QueryRequest{Script: "dependencies vuln pkg:github.com/google/agi@"})
 	queryResp, err := s.Query(context.Background(), queryReq)
 	require.NoError(t, err)
@@ -122,7 +153,24 @@ func TestQueriesIngestAndCache(t *testing.T) {
 	allKeysReq := connect.NewRequest(&emptypb.Empty{})
 	allKeysResp, err := s.AllKeys(context.Background(), allKeysReq)
 	require.NoError(t, err)
+	// The libraries and the vulnerability, without the documents of the SBOM and the OSV record.
 	assert.Len(t, allKeysResp.Msg.Nodes, 24)
+	for _, node := range allKeysResp.Msg.Nodes {
+		assert.True(t, tools.Listed(node.Type), "AllKeys listed the %s node %s", node.Type, node.Name)
+	}
+	keys, err := s.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, keys, 26)
+
+	globResp, err := s.GetNodesByGlob(context.Background(), connect.NewRequest(&service.GetNodesByGlobRequest{Pattern: "*"}))
+	require.NoError(t, err)
+	assert.Len(t, globResp.Msg.Nodes, 24)
+
+	libraryLeaderboardResp, err := s.CustomLeaderboard(context.Background(), connect.NewRequest(&service.CustomLeaderboardRequest{Script: "dependents library"}))
+	require.NoError(t, err)
+	for _, query := range libraryLeaderboardResp.Msg.Queries {
+		assert.True(t, tools.Listed(query.Node.Type), "the leaderboard ranked the %s node %s", query.Node.Type, query.Node.Name)
+	}
 
 	clearReq := connect.NewRequest(&emptypb.Empty{})
 	_, err = s.Clear(context.Background(), clearReq)
@@ -133,9 +181,9 @@ func TestQuery(t *testing.T) {
 	s := setupService()
 
 	// Add test nodes
-	_, err := graph.AddNode(s.storage, "type1", "metadata1", "node1")
+	_, err := graph.AddNode(context.Background(), s.storage, "type1", "metadata1", "node1")
 	require.NoError(t, err)
-	_, err = graph.AddNode(s.storage, "type2", "metadata2", "node2")
+	_, err = graph.AddNode(context.Background(), s.storage, "type2", "metadata2", "node2")
 	require.NoError(t, err)
 
 	// Test query with no results
@@ -160,8 +208,72 @@ func TestIngestSBOM(t *testing.T) {
 	req := connect.NewRequest(&service.IngestSBOMRequest{
 		Sbom: content,
 	})
-	_, err = s.IngestSBOM(context.Background(), req)
+	res, err := s.IngestSBOM(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+
+	// The same SBOM again is recognized without being ingested.
+	s.storage.(*graph.MockStorage).SaveNodeErr = assert.AnError
+	res, err = s.IngestSBOM(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, res.Msg.Unchanged)
+}
+
+func TestIngestSBOMStream(t *testing.T) {
+	s := setupService()
+	server := httptest.NewServer(ingestHandler(s))
+	defer server.Close()
+	content, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+
+	unchanged, err := ingestSBOMStream(apiv1connect.NewIngestServiceClient(server.Client(), server.URL), content, nil)
+	require.NoError(t, err)
+	assert.False(t, unchanged)
+	expected := setupService()
+	_, err = expected.IngestSBOM(context.Background(), connect.NewRequest(&service.IngestSBOMRequest{Sbom: content}))
+	require.NoError(t, err)
+	keys, err := s.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	expectedKeys, err := expected.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, keys, len(expectedKeys))
+
+	unchanged, err = ingestSBOMStream(apiv1connect.NewIngestServiceClient(server.Client(), server.URL), content, nil)
 	require.NoError(t, err)
+	assert.True(t, unchanged)
+
+	_, err = ingestSBOMStream(apiv1connect.NewIngestServiceClient(server.Client(), server.URL), []byte(`{"bomFormat": "CycloneDX", "components": [`), nil)
+	assert.Error(t, err)
+
+	// Only JSON SBOMs can be streamed.
+	_, err = ingestSBOMStream(apiv1connect.NewIngestServiceClient(server.Client(), server.URL), []byte("SPDXVersion: SPDX-2.3\nSPDXID: SPDXRef-DOCUMENT\nDocumentName: app\n"), nil)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+// ingestSBOMStream uploads the SBOM to IngestSBOMStream in small chunks and reports whether
+// it was unchanged.
+func ingestSBOMStream(client apiv1connect.IngestServiceClient, sbom, signature []byte) (bool, error) {
+	stream := client.IngestSBOMStream(context.Background())
+	if signature != nil {
+		stream.RequestHeader().Set(SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	}
+	for start := 0; start < len(sbom); start += 1024 {
+		end := min(start+1024, len(sbom))
+		if err := stream.Send(&service.IngestSBOMChunk{Data: sbom[start:end]}); err != nil {
+			break
+		}
+	}
+	res, err := stream.CloseAndReceive()
+	if err != nil {
+		return false, err
+	}
+	return res.Msg.Unchanged, nil
+}
+
+func ingestHandler(s *Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(s))
+	return mux
 }
 
 func TestIngestVulnerability(t *testing.T) {
@@ -171,8 +283,66 @@ func TestIngestVulnerability(t *testing.T) {
 	req := connect.NewRequest(&service.IngestVulnerabilityRequest{
 		Vulnerability: content,
 	})
-	_, err = s.IngestVulnerability(context.Background(), req)
+	res, err := s.IngestVulnerability(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+	res, err = s.IngestVulnerability(context.Background(), req)
 	require.NoError(t, err)
+	assert.True(t, res.Msg.Unchanged)
+}
+
+func TestIngestVulnerabilityArchive(t *testing.T) {
+	s := setupService()
+	server := httptest.NewServer(ingestHandler(s))
+	defer server.Close()
+	client := apiv1connect.NewIngestServiceClient(server.Client(), server.URL)
+	content, err := os.ReadFile("../../testdata/osv-vulns/GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	f, err := w.Create("GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	_, err = f.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	upload := func(archive []byte) (*service.IngestVulnerabilityArchiveResponse, error) {
+		stream := client.IngestVulnerabilityArchive(context.Background())
+		for start := 0; start < len(archive); start += 1024 {
+			if err := stream.Send(&service.IngestVulnerabilityArchiveChunk{Data: archive[start:min(start+1024, len(archive))]}); err != nil {
+				break
+			}
+		}
+		res, err := stream.CloseAndReceive()
+		if err != nil {
+			return nil, err
+		}
+		return res.Msg, nil
+	}
+	res, err := upload(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.Ingested)
+	res, err = upload(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.Unchanged)
+
+	_, err = upload([]byte("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestRelink(t *testing.T) {
+	s := setupService()
+	res, err := s.Relink(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	assert.Zero(t, res.Msg.Advisories)
+
+	content, err := os.ReadFile("../../testdata/osv-vulns/GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	_, err = s.IngestVulnerability(context.Background(), connect.NewRequest(&service.IngestVulnerabilityRequest{Vulnerability: content}))
+	require.NoError(t, err)
+	res, err = s.Relink(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.Msg.Advisories)
 }
 
 func TestIngestScorecard(t *testing.T) {
@@ -182,8 +352,264 @@ func TestIngestScorecard(t *testing.T) {
 	req := connect.NewRequest(&service.IngestScorecardRequest{
 		Scorecard: content,
 	})
-	_, err = s.IngestScorecard(context.Background(), req)
+	res, err := s.IngestScorecard(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+
+	// The library arrives after the scorecard, sending the scorecard again links it.
+	library, err := graph.AddNode(context.Background(), s.storage, tools.LibraryType, nil, "pkg:pypi/setuptools@65.5.1")
+	require.NoError(t, err)
+	res, err = s.IngestScorecard(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+	library, err = s.storage.GetNode(context.Background(), library.ID)
+	require.NoError(t, err)
+	children, err := s.storage.GetNodes(context.Background(), library.Children.ToArray())
+	require.NoError(t, err)
+	var scorecards int
+	for _, child := range children {
+		if child.Type == tools.ScorecardType {
+			scorecards++
+		}
+	}
+	assert.Equal(t, 1, scorecards)
+}
+
+func TestIngestVEX(t *testing.T) {
+	s := setupService()
+	sbomData, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	_, err = s.IngestSBOM(context.Background(), connect.NewRequest(&service.IngestSBOMRequest{Sbom: sbomData}))
+	require.NoError(t, err)
+	vulnData, err := os.ReadFile("../../testdata/osv-vulns/GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	_, err = s.IngestVulnerability(context.Background(), connect.NewRequest(&service.IngestVulnerabilityRequest{Vulnerability: vulnData}))
+	require.NoError(t, err)
+
+	vex := []byte(`{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/agi",
+  "author": "Example Security",
+  "timestamp": "2024-05-01T00:00:00Z",
+  "version": 1,
+  "statements": [{
+    "vulnerability": {"name": "GHSA-cx63-2mw6-8hw5"},
+    "products": [{"@id": "pkg:github.com/google/agi@"}],
+    "status": "not_affected",
+    "justification": "vulnerable_code_not_in_execute_path"
+  }]
+}`)
+	req := connect.NewRequest(&service.IngestVEXRequest{Vex: vex})
+	res, err := s.IngestVEX(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+	res, err = s.IngestVEX(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, res.Msg.Unchanged)
+	_, err = s.IngestVEX(context.Background(), connect.NewRequest(&service.IngestVEXRequest{Vex: []byte("{}")}))
+	assert.Error(t, err)
+
+	_, err = s.Cache(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+
+	// The suppressed vulnerability is left out unless the request includes it.
+	queryResp, err := s.Query(context.Background(), connect.NewRequest(&service.QueryRequest{Script: "dependencies vuln pkg:github.com/google/agi@"}))
+	require.NoError(t, err)
+	assert.Empty(t, queryResp.Msg.Nodes)
+	queryResp, err = s.Query(context.Background(), connect.NewRequest(&service.QueryRequest{Script: "dependencies vuln pkg:github.com/google/agi@", IncludeSuppressed: true}))
+	require.NoError(t, err)
+	require.Len(t, queryResp.Msg.Nodes, 1)
+	assert.Equal(t, "GHSA-cx63-2mw6-8hw5", queryResp.Msg.Nodes[0].Name)
+
+	outputs := func(includeSuppressed bool) map[string]int {
+		resp, err := s.CustomLeaderboard(context.Background(), connect.NewRequest(&service.CustomLeaderboardRequest{Script: "dependencies vuln", IncludeSuppressed: includeSuppressed}))
+		require.NoError(t, err)
+		result := map[string]int{}
+		for _, query := range resp.Msg.Queries {
+			result[query.Node.Name] = len(query.Output)
+		}
+		return result
+	}
+	assert.Equal(t, 1, outputs(true)["pkg:github.com/google/agi@"])
+	assert.Zero(t, outputs(false)["pkg:github.com/google/agi@"])
+}
+
+func TestIngestLockfile(t *testing.T) {
+	s := setupService()
+	cargoLock := []byte(`version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = ["serde"]
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`)
+	req := connect.NewRequest(&service.IngestLockfileRequest{Lockfile: cargoLock, Name: "app/Cargo.lock"})
+	res, err := s.IngestLockfile(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+	res, err = s.IngestLockfile(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, res.Msg.Unchanged)
+
+	nodeResp, err := s.GetNodeByName(context.Background(), connect.NewRequest(&service.GetNodeByNameRequest{Name: "pkg:cargo/app@0.1.0"}))
+	require.NoError(t, err)
+	serde, err := s.GetNodeByName(context.Background(), connect.NewRequest(&service.GetNodeByNameRequest{Name: "pkg:cargo/serde@1.0.200"}))
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{serde.Msg.Node.Id}, nodeResp.Msg.Node.Dependencies)
+
+	// The same lockfile is ingested again for another project.
+	req = connect.NewRequest(&service.IngestLockfileRequest{Lockfile: cargoLock, Format: "Cargo.lock", Project: "pkg:github/example/app"})
+	res, err = s.IngestLockfile(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.Msg.Unchanged)
+	_, err = s.GetNodeByName(context.Background(), connect.NewRequest(&service.GetNodeByNameRequest{Name: "pkg:github/example/app"}))
 	require.NoError(t, err)
+	assert.Empty(t, res.Msg.Skipped)
+
+	// The requirements that aren't pinned are reported.
+	res, err = s.IngestLockfile(context.Background(), connect.NewRequest(&service.IngestLockfileRequest{Lockfile: []byte("requests==2.31.0\nflask>=2.0\n"), Name: "requirements.txt"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flask>=2.0: not pinned to a single version with == or ==="}, res.Msg.Skipped)
+
+	_, err = s.IngestLockfile(context.Background(), connect.NewRequest(&service.IngestLockfileRequest{Lockfile: cargoLock, Format: "yarn.lock"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	_, err = s.IngestLockfile(context.Background(), connect.NewRequest(&service.IngestLockfileRequest{Lockfile: []byte("# app"), Name: "README.md"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestEvaluateLicensePolicy(t *testing.T) {
+	s := setupService()
+	sbom := []byte(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app", "version": "1.0.0", "purl": "pkg:npm/app@1.0.0"}},
+  "components": [{"bom-ref": "b", "type": "library", "name": "b", "version": "1.0.0", "purl": "pkg:npm/b@1.0.0",
+    "licenses": [{"expression": "GPL-3.0-only AND MIT"}]}],
+  "dependencies": [{"ref": "app", "dependsOn": ["b"]}]
+}`)
+	_, err := s.IngestSBOM(context.Background(), connect.NewRequest(&service.IngestSBOMRequest{Sbom: sbom}))
+	require.NoError(t, err)
+
+	res, err := s.EvaluateLicensePolicy(context.Background(), connect.NewRequest(&service.LicensePolicyRequest{Deny: []string{"GPL-3.0-only"}}))
+	require.NoError(t, err)
+	require.Len(t, res.Msg.Reports, 1)
+	report := res.Msg.Reports[0]
+	assert.Equal(t, "pkg:npm/app@1.0.0", report.Project)
+	assert.Equal(t, int32(2), report.Libraries)
+	assert.Equal(t, int32(1), report.Unlicensed)
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, "pkg:npm/b@1.0.0", report.Violations[0].Library)
+	assert.Equal(t, "denied", report.Violations[0].Verdict)
+	assert.Equal(t, []string{"GPL-3.0-only"}, report.Violations[0].Licenses)
+
+	_, err = s.EvaluateLicensePolicy(context.Background(), connect.NewRequest(&service.LicensePolicyRequest{Projects: []string{"pkg:npm/unknown@1.0.0"}}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestRequireSignedIngest(t *testing.T) {
+	public, private, err := signing.GenerateKey()
+	require.NoError(t, err)
+	_, untrusted, err := signing.GenerateKey()
+	require.NoError(t, err)
+
+	sbom, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	vuln, err := os.ReadFile("../../testdata/osv-vulns/GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	scorecard, err := os.ReadFile("../../testdata/scorecards/scorecards.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		ingest  func(s *Service, payload, signature []byte) error
+	}{
+		{
+			name:    "sbom",
+			payload: sbom,
+			ingest: func(s *Service, payload, signature []byte) error {
+				_, err := s.IngestSBOM(context.Background(), connect.NewRequest(&service.IngestSBOMRequest{Sbom: payload, Signature: signature}))
+				return err
+			},
+		},
+		{
+			name:    "vulnerability",
+			payload: vuln,
+			ingest: func(s *Service, payload, signature []byte) error {
+				_, err := s.IngestVulnerability(context.Background(), connect.NewRequest(&service.IngestVulnerabilityRequest{Vulnerability: payload, Signature: signature}))
+				return err
+			},
+		},
+		{
+			name:    "scorecard",
+			payload: scorecard,
+			ingest: func(s *Service, payload, signature []byte) error {
+				_, err := s.IngestScorecard(context.Background(), connect.NewRequest(&service.IngestScorecardRequest{Scorecard: payload, Signature: signature}))
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(graph.NewMockStorage(), 1, WithRequiredSignatures(signing.NewKeyring(public)))
+
+			err := tt.ingest(s, tt.payload, nil)
+			assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			assert.ErrorIs(t, err, signing.ErrUnsigned)
+
+			untrustedSignature, err := signing.Sign(untrusted, tt.payload)
+			require.NoError(t, err)
+			err = tt.ingest(s, tt.payload, untrustedSignature)
+			assert.ErrorIs(t, err, signing.ErrUntrustedKey)
+
+			signature, err := signing.Sign(private, tt.payload)
+			require.NoError(t, err)
+			err = tt.ingest(s, append([]byte(" "), tt.payload...), signature)
+			assert.ErrorIs(t, err, signing.ErrInvalidSignature)
+
+			keys, err := s.storage.GetAllKeys(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, keys, "refused payloads must not be ingested")
+
+			require.NoError(t, tt.ingest(s, tt.payload, signature))
+		})
+	}
+}
+
+func TestRequireSignedIngestSBOMStream(t *testing.T) {
+	public, private, err := signing.GenerateKey()
+	require.NoError(t, err)
+	sbom, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	signature, err := signing.Sign(private, sbom)
+	require.NoError(t, err)
+
+	s := NewService(graph.NewMockStorage(), 1, WithRequiredSignatures(signing.NewKeyring(public)))
+	server := httptest.NewServer(ingestHandler(s))
+	defer server.Close()
+	client := apiv1connect.NewIngestServiceClient(server.Client(), server.URL)
+
+	_, err = ingestSBOMStream(client, sbom, nil)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	_, err = ingestSBOMStream(client, append([]byte(" "), sbom...), signature)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	keys, err := s.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, keys, "refused SBOMs must not be ingested")
+
+	_, err = ingestSBOMStream(client, sbom, signature)
+	require.NoError(t, err)
+	keys, err = s.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, keys)
 }
 
 func TestAddNode(t *testing.T) {
@@ -203,6 +629,10 @@ func TestAddNode(t *testing.T) {
 	resp, err = s.GetNodeByName(context.Background(), getNodeReq)
 	require.Error(t, err)
 	assert.Nil(t, resp)
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = s.GetNode(context.Background(), connect.NewRequest(&service.GetNodeRequest{Id: 1000}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
 }
 
 func TestSetDependency(t *testing.T) {
@@ -239,6 +669,39 @@ func TestSetDependency(t *testing.T) {
 	})
 }
 
+func TestUpdateNodeMetadata(t *testing.T) {
+	s := setupService()
+	node, err := graph.AddNode(context.Background(), s.storage, "library", map[string]any{"licenses": []any{"MIT"}, "name": "a"}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+
+	update := func(metadata, strategy string) (*connect.Response[service.UpdateNodeMetadataResponse], error) {
+		return s.UpdateNodeMetadata(context.Background(), connect.NewRequest(&service.UpdateNodeMetadataRequest{
+			Id:       node.ID,
+			Metadata: []byte(metadata),
+			Strategy: strategy,
+		}))
+	}
+
+	resp, err := update(`{"licenses":["Apache-2.0"],"version":"1.0.0"}`, "deep-merge")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"licenses":["MIT","Apache-2.0"],"name":"a","version":"1.0.0"}`, string(resp.Msg.Node.Metadata))
+
+	resp, err = update(`{"name":"b"}`, "first-wins")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"licenses":["MIT","Apache-2.0"],"name":"a","version":"1.0.0"}`, string(resp.Msg.Node.Metadata))
+
+	resp, err = update(`{"name":"b"}`, "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"b"}`, string(resp.Msg.Node.Metadata))
+
+	_, err = update(`{"name":"b"}`, "newest")
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	_, err = update(`{`, "")
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	_, err = s.UpdateNodeMetadata(context.Background(), connect.NewRequest(&service.UpdateNodeMetadataRequest{Id: 1000, Metadata: []byte(`{}`)}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
 func TestHealthCheck(t *testing.T) {
 	s := setupService()
 	req := connect.NewRequest(&emptypb.Empty{})
@@ -246,3 +709,156 @@ func TestHealthCheck(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "ok", resp.Msg.Status)
 }
+
+func TestGetStorageCacheStats(t *testing.T) {
+	s := setupService()
+	resp, err := s.GetStorageCacheStats(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	assert.False(t, resp.Msg.Enabled)
+
+	cached, err := storages.NewCachedStorage(graph.NewMockStorage(), 10)
+	require.NoError(t, err)
+	s = NewService(cached, 1)
+	node, err := graph.AddNode(context.Background(), s.storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		_, err := s.GetNode(context.Background(), connect.NewRequest(&service.GetNodeRequest{Id: node.ID}))
+		require.NoError(t, err)
+	}
+
+	resp, err = s.GetStorageCacheStats(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	assert.True(t, resp.Msg.Enabled)
+	assert.Equal(t, uint64(3), resp.Msg.Nodes.Hits)
+	assert.Equal(t, uint64(1), resp.Msg.Nodes.Misses)
+	assert.Equal(t, uint64(10), resp.Msg.Nodes.Capacity)
+	assert.InDelta(t, 0.75, resp.Msg.Nodes.HitRate, 0.001)
+	assert.Zero(t, resp.Msg.Caches.Hits)
+}
+
+func TestExportAndImportGraph(t *testing.T) {
+	src := setupService()
+	a, err := graph.AddNode(context.Background(), src.storage, "library", map[string]any{"version": "1.0.0"}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), src.storage, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), src.storage, b))
+
+	srcServer := httptest.NewServer(archiveHandler(src))
+	defer srcServer.Close()
+	dst := setupService()
+	dstServer := httptest.NewServer(archiveHandler(dst))
+	defer dstServer.Close()
+
+	ctx := context.Background()
+	exportStream, err := apiv1connect.NewArchiveServiceClient(srcServer.Client(), srcServer.URL).ExportGraph(ctx, connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	importStream := apiv1connect.NewArchiveServiceClient(dstServer.Client(), dstServer.URL).ImportGraph(ctx)
+	for exportStream.Receive() {
+		require.NoError(t, importStream.Send(exportStream.Msg()))
+	}
+	require.NoError(t, exportStream.Err())
+	require.NoError(t, exportStream.Close())
+	res, err := importStream.CloseAndReceive()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), res.Msg.Summary.Nodes)
+	assert.Equal(t, uint32(2), res.Msg.Summary.IdCounter)
+
+	node, err := dst.storage.GetNode(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, a.Name, node.Name)
+	assert.True(t, node.Children.Contains(b.ID))
+
+	// Data that is not an archive is rejected.
+	importStream = apiv1connect.NewArchiveServiceClient(dstServer.Client(), dstServer.URL).ImportGraph(ctx)
+	require.NoError(t, importStream.Send(&service.ArchiveChunk{Data: []byte("not an archive")}))
+	_, err = importStream.CloseAndReceive()
+	assert.Error(t, err)
+}
+
+func TestRequireSignedImportGraph(t *testing.T) {
+	public, private, err := signing.GenerateKey()
+	require.NoError(t, err)
+
+	src := graph.NewMockStorage()
+	_, err = graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	archive := &bytes.Buffer{}
+	_, err = storages.ExportGraph(context.Background(), src, archive, storages.DefaultCopyBatchSize)
+	require.NoError(t, err)
+	signature, err := signing.Sign(private, archive.Bytes())
+	require.NoError(t, err)
+
+	dst := NewService(graph.NewMockStorage(), 1, WithRequiredSignatures(signing.NewKeyring(public)))
+	server := httptest.NewServer(archiveHandler(dst))
+	defer server.Close()
+	client := apiv1connect.NewArchiveServiceClient(server.Client(), server.URL)
+
+	importArchive := func(data, signature []byte) error {
+		stream := client.ImportGraph(context.Background())
+		if signature != nil {
+			stream.RequestHeader().Set(SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+		}
+		require.NoError(t, stream.Send(&service.ArchiveChunk{Data: data}))
+		_, err := stream.CloseAndReceive()
+		return err
+	}
+
+	err = importArchive(archive.Bytes(), nil)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	tampered := append([]byte(nil), archive.Bytes()...)
+	tampered[len(tampered)-1] ^= 0xff
+	err = importArchive(tampered, signature)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	keys, err := dst.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, keys, "refused archives must not be imported")
+
+	require.NoError(t, importArchive(archive.Bytes(), signature))
+	keys, err = dst.storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func archiveHandler(s *Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewArchiveServiceHandler(s))
+	return mux
+}
+
+func TestProvenance(t *testing.T) {
+	s := setupService()
+
+	sbomData, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	_, err = s.IngestSBOM(context.Background(), connect.NewRequest(&service.IngestSBOMRequest{Sbom: sbomData}))
+	require.NoError(t, err)
+
+	listResp, err := s.ListDocuments(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	require.NoError(t, err)
+	require.Len(t, listResp.Msg.Documents, 1)
+	document := listResp.Msg.Documents[0]
+	assert.Equal(t, "sbom:urn:uuid:0b60d901-9176-4fd8-b013-2966a2d422b5", document.Name)
+	assert.Equal(t, "sbom", document.Type)
+	assert.Contains(t, string(document.Metadata), `"tools":["anchore syft 1.11.0"]`)
+	assert.Contains(t, string(document.Metadata), `"timestamp":"2024-08-24T11:29:57Z"`)
+
+	contributionResp, err := s.GetDocumentContribution(context.Background(), connect.NewRequest(&service.GetDocumentContributionRequest{Name: document.Name}))
+	require.NoError(t, err)
+	assert.Equal(t, document.Id, contributionResp.Msg.Document.Id)
+	assert.NotEmpty(t, contributionResp.Msg.Edges)
+	require.NotEmpty(t, contributionResp.Msg.Nodes)
+	assert.Len(t, contributionResp.Msg.Names, len(contributionResp.Msg.Nodes))
+
+	nodeResp, err := s.GetNodeDocuments(context.Background(), connect.NewRequest(&service.GetNodeDocumentsRequest{Id: contributionResp.Msg.Nodes[0]}))
+	require.NoError(t, err)
+	require.Len(t, nodeResp.Msg.Documents, 1)
+	assert.Equal(t, document.Name, nodeResp.Msg.Documents[0].Name)
+
+	_, err = s.GetDocumentContribution(context.Background(), connect.NewRequest(&service.GetDocumentContributionRequest{Name: "sbom:missing"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	_, err = s.GetNodeDocuments(context.Background(), connect.NewRequest(&service.GetNodeDocumentsRequest{Id: 1 << 30}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, storage graph.Storage) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewArchiveServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func run(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestExportAndImport(t *testing.T) {
+	src := graph.NewMockStorage()
+	a, err := graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), src, b))
+	require.NoError(t, src.AddOrUpdateCustomData(context.Background(), "tag", "key", "field", []byte("value")))
+
+	srcServer := newTestServer(t, src)
+	dst := graph.NewMockStorage()
+	dstServer := newTestServer(t, dst)
+
+	path := filepath.Join(t.TempDir(), "graph.archive.gz")
+	out, err := run(t, NewExport(), "--addr", srcServer.URL, path)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Exported graph to "+path)
+
+	out, err = run(t, NewImport(), "--addr", dstServer.URL, path)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Imported 2 nodes")
+	assert.Contains(t, out, "1 custom data entries")
+
+	node, err := dst.GetNode(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.True(t, node.Children.Contains(b.ID))
+	data, err := dst.GetCustomData(context.Background(), "tag", "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), data["field"])
+
+	// The destination already holds the graph, so a second import is refused.
+	_, err = run(t, NewImport(), "--addr", dstServer.URL, path)
+	assert.Error(t, err)
+}
+
+func TestExportAndImportErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := run(t, NewImport(), "--addr", "http://localhost:0", filepath.Join(dir, "missing.gz"))
+	assert.Error(t, err)
+
+	_, err = run(t, NewExport(), "--addr", "http://localhost:0", filepath.Join(dir, "graph.gz"))
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "graph.gz"))
+
+	_, err = run(t, NewExport())
+	assert.Error(t, err)
+}
+
+func TestSignedExportAndImport(t *testing.T) {
+	dir := t.TempDir()
+	public, private, err := signing.GenerateKey()
+	require.NoError(t, err)
+	encoded, err := signing.EncodePrivateKey(private)
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, "minefield.key")
+	require.NoError(t, os.WriteFile(keyPath, encoded, 0o600))
+
+	src := graph.NewMockStorage()
+	_, err = graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	srcServer := newTestServer(t, src)
+
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewArchiveServiceHandler(service.NewService(graph.NewMockStorage(), 1, service.WithRequiredSignatures(signing.NewKeyring(public)))))
+	dstServer := httptest.NewServer(mux)
+	t.Cleanup(dstServer.Close)
+
+	_, err = run(t, NewExport(), "--addr", srcServer.URL, "--sign-key", keyPath, "-")
+	assert.ErrorContains(t, err, "sign-key can't be used when exporting to stdout")
+
+	unsigned := filepath.Join(dir, "unsigned.archive.gz")
+	_, err = run(t, NewExport(), "--addr", srcServer.URL, unsigned)
+	require.NoError(t, err)
+	_, err = run(t, NewImport(), "--addr", dstServer.URL, unsigned)
+	assert.ErrorContains(t, err, "payload is not signed")
+
+	signed := filepath.Join(dir, "signed.archive.gz")
+	_, err = run(t, NewExport(), "--addr", srcServer.URL, "--sign-key", keyPath, signed)
+	require.NoError(t, err)
+	assert.FileExists(t, signed+signing.SignatureExt)
+	out, err := run(t, NewImport(), "--addr", dstServer.URL, signed)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Imported 1 nodes")
+}
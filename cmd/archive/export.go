@@ -0,0 +1,133 @@
+package archive
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"connectrpc.com/connect"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+// exportOptions for the export command
+type exportOptions struct {
+	addr    string // Address of the minefield server
+	signKey string // Path of the private key the archive is signed with
+
+	archiveServiceClient apiv1connect.ArchiveServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *exportOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().StringVar(&o.signKey, "sign-key", "", "Path to a PEM encoded ed25519 private key to sign the archive with, the signature is written next to it")
+}
+
+// Run streams the graph archive from the server into the file given as the only argument,
+// or to stdout if it is "-".
+func (o *exportOptions) Run(cmd *cobra.Command, args []string) error {
+	if o.archiveServiceClient == nil {
+		o.archiveServiceClient = apiv1connect.NewArchiveServiceClient(http.DefaultClient, o.addr)
+	}
+
+	var key ed25519.PrivateKey
+	if o.signKey != "" {
+		var err error
+		if key, err = signing.LoadPrivateKey(o.signKey); err != nil {
+			return err
+		}
+	}
+
+	path := args[0]
+	if path == "-" {
+		if key != nil {
+			return fmt.Errorf("sign-key can't be used when exporting to stdout")
+		}
+		_, err := o.export(cmd, cmd.OutOrStdout())
+		return err
+	}
+
+	// The archive is written next to its destination and only moved there once it is
+	// complete, so a failed export never leaves a truncated archive behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := o.export(cmd, io.MultiWriter(tmp, hash))
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		var digest [sha256.Size]byte
+		copy(digest[:], hash.Sum(nil))
+		signature, err := signing.SignDigest(key, digest)
+		if err != nil {
+			return fmt.Errorf("failed to sign archive: %w", err)
+		}
+		if err := os.WriteFile(path+signing.SignatureExt, signature, 0o644); err != nil {
+			return fmt.Errorf("failed to write signature: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported graph to %s (%d bytes)\n", path, size)
+	return nil
+}
+
+func (o *exportOptions) export(cmd *cobra.Command, w io.Writer) (int64, error) {
+	stream, err := o.archiveServiceClient.ExportGraph(cmd.Context(), connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to export graph: %w", err)
+	}
+	defer stream.Close()
+
+	var size int64
+	for stream.Receive() {
+		n, err := w.Write(stream.Msg().Data)
+		if err != nil {
+			return 0, fmt.Errorf("failed to write archive: %w", err)
+		}
+		size += int64(n)
+	}
+	if err := stream.Err(); err != nil {
+		return 0, fmt.Errorf("failed to export graph: %w", err)
+	}
+	return size, nil
+}
+
+// NewExport returns a new cobra command for the export command.
+func NewExport() *cobra.Command {
+	o := &exportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the whole graph into a portable archive",
+		Long: `Export the nodes, metadata, edges, caches, custom data and ID counter of the graph into a
+single self-describing archive, which can be imported into a server with any storage backend.`,
+		Example: `minefield export graph.archive.gz
+minefield export --sign-key minefield.key graph.archive.gz`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
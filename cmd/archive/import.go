@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+// importChunkSize is the size of the archive chunks sent to the server.
+const importChunkSize = 64 * 1024
+
+// importOptions for the import command
+type importOptions struct {
+	addr      string // Address of the minefield server
+	signature string // Path of the archive's signature file
+
+	archiveServiceClient apiv1connect.ArchiveServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *importOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().StringVar(&o.signature, "signature", "", "Path to the archive's signature file (defaults to the archive path with a "+signing.SignatureExt+" suffix)")
+}
+
+// Run streams the archive given as the only argument, or stdin if it is "-", to the server.
+func (o *importOptions) Run(cmd *cobra.Command, args []string) error {
+	if o.archiveServiceClient == nil {
+		o.archiveServiceClient = apiv1connect.NewArchiveServiceClient(http.DefaultClient, o.addr)
+	}
+
+	var r io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	signature, err := o.readSignature(args[0])
+	if err != nil {
+		return err
+	}
+
+	stream := o.archiveServiceClient.ImportGraph(cmd.Context())
+	if signature != nil {
+		stream.RequestHeader().Set(service.SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	}
+	buf := make([]byte, importChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&apiv1.ArchiveChunk{Data: append([]byte(nil), buf[:n]...)}); sendErr != nil {
+				// The server's error is only returned by CloseAndReceive.
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_, _ = stream.CloseAndReceive()
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+	}
+	res, err := stream.CloseAndReceive()
+	if err != nil {
+		return fmt.Errorf("failed to import graph: %w", err)
+	}
+
+	summary := res.Msg.Summary
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d nodes, %d caches, %d cache stack entries and %d custom data entries, ID counter %d\n",
+		summary.GetNodes(), summary.GetCaches(), summary.GetCacheStack(), summary.GetCustomData(), summary.GetIdCounter())
+	return nil
+}
+
+// readSignature returns the signature to send along with the archive at path, or nil if it
+// isn't signed.
+func (o *importOptions) readSignature(path string) ([]byte, error) {
+	if o.signature != "" {
+		signature, err := os.ReadFile(o.signature)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature: %w", err)
+		}
+		return signature, nil
+	}
+	if path == "-" {
+		return nil, nil
+	}
+	return signing.ReadSignature(path)
+}
+
+// NewImport returns a new cobra command for the import command.
+func NewImport() *cobra.Command {
+	o := &importOptions{}
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import a graph archive created by export",
+		Long: `Import a graph archive created by export into the server. The server's storage has to be
+empty, since node IDs are kept. The imported graph is verified against the archive's checksums.
+A signature file next to the archive is sent along, which servers started with --require-signed
+need to accept it.`,
+		Example:           "minefield import graph.archive.gz",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,26 @@
+package documents
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+// New returns a new cobra command for the documents command and its subcommands.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "documents",
+		Short: "Inspect the ingested SBOMs, OSV records and scorecard results",
+		Long: `Inspect the ingested SBOMs, OSV records and scorecard results. Every ingested document is
+recorded with the nodes and edges it asserted, and ingesting a new version of a document replaces
+what the previous version asserted.`,
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(NewList())
+	cmd.AddCommand(NewShow())
+	return cmd
+}
@@ -0,0 +1,68 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, storage graph.Storage) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewProvenanceServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func run(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestListAndShow(t *testing.T) {
+	storage := graph.NewMockStorage()
+	data, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	require.NoError(t, ingest.SBOM(context.Background(), storage, data))
+	server := newTestServer(t, storage)
+	const name = "sbom:urn:uuid:0b60d901-9176-4fd8-b013-2966a2d422b5"
+
+	out, err := run(t, New(), "list", "--addr", server.URL)
+	require.NoError(t, err)
+	assert.Contains(t, out, name)
+	assert.Contains(t, out, "anchore syft 1.11.0")
+
+	out, err = run(t, New(), "list", "--addr", server.URL, "--output", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"name": "`+name+`"`)
+
+	_, err = run(t, New(), "list", "--addr", server.URL, "--output", "xml")
+	assert.Error(t, err)
+
+	out, err = run(t, New(), "show", "--addr", server.URL, name)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Document "+name+" (sbom)")
+	assert.Contains(t, out, "  Timestamp: 2024-08-24T11:29:57Z")
+	assert.Contains(t, out, "Nodes (")
+	assert.Contains(t, out, "pkg:github.com/google/agi@ -> pkg:pypi/astroid@2.11.7")
+
+	_, err = run(t, New(), "show", "--addr", server.URL, "sbom:missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/bitbomdev/minefield/cmd/helpers"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// listOptions for the list command
+type listOptions struct {
+	addr   string // Address of the minefield server
+	output string // Output format
+
+	provenanceServiceClient apiv1connect.ProvenanceServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *listOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().StringVar(&o.output, "output", "table", "output format (table or json)")
+}
+
+// Run lists the ingested documents.
+func (o *listOptions) Run(cmd *cobra.Command, args []string) error {
+	if o.provenanceServiceClient == nil {
+		o.provenanceServiceClient = apiv1connect.NewProvenanceServiceClient(http.DefaultClient, o.addr)
+	}
+
+	res, err := o.provenanceServiceClient.ListDocuments(cmd.Context(), connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	switch o.output {
+	case "json":
+		jsonOutput, err := helpers.FormatNodeJSON(res.Msg.Documents)
+		if err != nil {
+			return fmt.Errorf("failed to format documents as JSON: %w", err)
+		}
+		cmd.Println(string(jsonOutput))
+		return nil
+	case "table":
+		return formatDocuments(cmd.OutOrStdout(), res.Msg.Documents)
+	default:
+		return fmt.Errorf("unknown output format: %s", o.output)
+	}
+}
+
+// formatDocuments formats the documents into a table and writes it to the provided writer.
+func formatDocuments(w io.Writer, documents []*apiv1.Node) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Name", "Type", "ID", "Timestamp", "Tools"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+
+	for _, document := range documents {
+		metadata, err := documentMetadata(document)
+		if err != nil {
+			return err
+		}
+		table.Append([]string{
+			document.Name,
+			document.Type,
+			strconv.FormatUint(uint64(document.Id), 10),
+			metadata.Timestamp,
+			strings.Join(metadata.Tools, ", "),
+		})
+	}
+
+	table.Render()
+	return nil
+}
+
+// documentMetadata decodes the metadata of a document node.
+func documentMetadata(document *apiv1.Node) (ingest.DocumentMetadata, error) {
+	var metadata ingest.DocumentMetadata
+	if err := json.Unmarshal(document.Metadata, &metadata); err != nil {
+		return metadata, fmt.Errorf("failed to decode metadata of document %s: %w", document.Name, err)
+	}
+	return metadata, nil
+}
+
+// NewList returns a new cobra command for the list command.
+func NewList() *cobra.Command {
+	o := &listOptions{}
+	cmd := &cobra.Command{
+		Use:               "list",
+		Short:             "List the ingested documents",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,82 @@
+package documents
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/spf13/cobra"
+)
+
+// showOptions for the show command
+type showOptions struct {
+	addr string // Address of the minefield server
+
+	provenanceServiceClient apiv1connect.ProvenanceServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *showOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+}
+
+// Run prints the metadata of the document given as the only argument and the nodes and edges it
+// contributed to the graph.
+func (o *showOptions) Run(cmd *cobra.Command, args []string) error {
+	if o.provenanceServiceClient == nil {
+		o.provenanceServiceClient = apiv1connect.NewProvenanceServiceClient(http.DefaultClient, o.addr)
+	}
+
+	res, err := o.provenanceServiceClient.GetDocumentContribution(cmd.Context(), connect.NewRequest(&apiv1.GetDocumentContributionRequest{Name: args[0]}))
+	if err != nil {
+		return fmt.Errorf("failed to get document contribution: %w", err)
+	}
+	metadata, err := documentMetadata(res.Msg.Document)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Document %s (%s)\n", res.Msg.Document.Name, res.Msg.Document.Type)
+	for _, field := range []struct{ name, value string }{
+		{"Name", metadata.Name},
+		{"Serial number", metadata.SerialNumber},
+		{"Version", metadata.Version},
+		{"Tools", strings.Join(metadata.Tools, ", ")},
+		{"Timestamp", metadata.Timestamp},
+		{"SHA256", metadata.SHA256},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(w, "  %s: %s\n", field.name, field.value)
+		}
+	}
+
+	names := res.Msg.Names
+	fmt.Fprintf(w, "Nodes (%d):\n", len(res.Msg.Nodes))
+	for _, id := range res.Msg.Nodes {
+		fmt.Fprintf(w, "  %s\n", names[id])
+	}
+	fmt.Fprintf(w, "Edges (%d):\n", len(res.Msg.Edges))
+	for _, edge := range res.Msg.Edges {
+		fmt.Fprintf(w, "  %s -> %s\n", names[edge.From], names[edge.To])
+	}
+	return nil
+}
+
+// NewShow returns a new cobra command for the show command.
+func NewShow() *cobra.Command {
+	o := &showOptions{}
+	cmd := &cobra.Command{
+		Use:               "show [name]",
+		Short:             "Show a document and the nodes and edges it contributed",
+		Example:           `minefield documents show sbom:urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
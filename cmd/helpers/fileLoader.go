@@ -1,147 +1,557 @@
 package helpers
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
 )
 
-// LoadDataFromPath takes in a directory or file path and processes the data into the storage.
-// The data can either be JSON files or ZIP files containing JSON files.
+// StdinPath is the path that makes ListFiles read from standard input.
+const StdinPath = "-"
+
+// sniffSize is the number of bytes read to tell what a file contains, enough to find the
+// magic of a tar header.
+const sniffSize = 512
 
-type Data struct {
+// File is a document found by ListFiles. Its content is only read once it is opened, so
+// files of any size can be sent in chunks.
+type File struct {
+	// Path is the path of the file, continuing into the archives it is in.
 	Path string
-	Data []byte
+	// Signature is the content of the signature file next to Path, if there is one.
+	Signature []byte
+
+	open func() (io.ReadCloser, error)
 }
 
-func LoadDataFromPath(path string) ([]Data, error) {
-	info, err := os.Stat(path)
+// Open opens the content of the file.
+func (f File) Open() (io.ReadCloser, error) {
+	return f.open()
+}
+
+// ReadAll reads the content of the file into memory.
+func (f File) ReadAll() ([]byte, error) {
+	rc, err := f.open()
 	if err != nil {
-		return nil, fmt.Errorf("error accessing path %s: %w", path, err)
+		return nil, err
 	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", f.Path, err)
+	}
+	return data, nil
+}
 
-	result := []Data{}
+// SkippedFile is a file ListFiles didn't return, and why.
+type SkippedFile struct {
+	Path   string
+	Reason string
+}
 
-	var errors []error
+// LoadOptions select the files ListFiles returns.
+type LoadOptions struct {
+	// Include and Exclude are globs matched against the path of a document relative to the
+	// listed path, see MatchGlob. A document is returned if it matches an Include glob, or
+	// there are none, and no Exclude glob.
+	Include []string
+	Exclude []string
+	// Document reports whether a file that isn't an archive is a document to return, by its
+	// path and the start of its content. JSON, XML and SPDX tag-value documents are if nil.
+	Document func(path string, header []byte) bool
+	// Stdin is read for StdinPath.
+	Stdin io.Reader
+}
 
-	if info.IsDir() {
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read directory %s: %w", path, err)
-		}
-		for _, entry := range entries {
-			entryPath := filepath.Join(path, entry.Name())
-			subResult, err := LoadDataFromPath(entryPath)
-			if err != nil {
-				errors = append(errors, fmt.Errorf("failed to load data from path %s: %w", entryPath, err))
-			} else {
-				result = append(result, subResult...)
-			}
-			if err != nil {
-				errors = append(errors, fmt.Errorf("failed to ingest data from path %s: %w", entryPath, err))
-			}
+// Listing holds the files found by ListFiles. Files within archives may be copied to
+// temporary files, which Close removes, so it must be closed once the files aren't needed.
+type Listing struct {
+	Files   []File
+	Skipped []SkippedFile
+
+	temporary []string
+}
+
+// Close removes the temporary files of the listing.
+func (l *Listing) Close() {
+	for _, name := range l.temporary {
+		os.Remove(name)
+	}
+	l.temporary = nil
+}
+
+// ListFiles returns the documents in the directory or file at path, or in standard input for
+// StdinPath, without reading them into memory. What a file holds is told by its content, not
+// its name: JSON, XML and SPDX tag-value documents, or those LoadOptions.Document accepts, are
+// returned, and zip, tar and gzip files,
+// which may be nested, are looked into. ZIP files on disk are read in place, the content of
+// other archives is copied to temporary files. Other files, and documents that the filters
+// exclude, are reported as skipped.
+func ListFiles(path string, opts LoadOptions) (*Listing, error) {
+	for _, pattern := range append(append([]string(nil), opts.Include...), opts.Exclude...) {
+		if err := ValidateGlob(pattern); err != nil {
+			return nil, err
 		}
+	}
+	l := &lister{opts: opts, root: path, listing: &Listing{}}
+
+	var err error
+	if path == StdinPath {
+		err = l.addStdin()
 	} else {
-		switch filepath.Ext(path) {
-		case ".zip":
-			subResult, err := processZipFile(path)
-			if err != nil {
-				errors = append(errors, fmt.Errorf("failed to process zip file %s: %w", path, err))
-			} else {
-				result = subResult
-			}
-		case ".json":
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read JSON file %s: %w", path, err)
-			}
-			result = append(result, Data{Path: path, Data: data})
-		}
+		err = l.addPath(path)
+	}
+	if err != nil {
+		l.listing.Close()
+		return nil, err
 	}
+	return l.listing, nil
+}
 
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("errors occurred during data ingestion: %v", errors)
+// PrintSkipped writes the files that were skipped, if any, to w.
+func PrintSkipped(w io.Writer, skipped []SkippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Skipped %d files:\n", len(skipped))
+	for _, file := range skipped {
+		fmt.Fprintf(w, "  %s: %s\n", file.Path, file.Reason)
 	}
+}
+
+// LoadFlags are the flags of the commands that ingest the files found by ListFiles.
+type LoadFlags struct {
+	Include []string
+	Exclude []string
+}
+
+func (f *LoadFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.Include, "include", nil, "Only ingest files whose path relative to the given path matches the glob, ** matches any number of directories (repeatable)")
+	cmd.Flags().StringArrayVar(&f.Exclude, "exclude", nil, "Don't ingest files whose path relative to the given path matches the glob (repeatable)")
+}
 
-	return result, nil
+// Options returns the load options for the flags, reading standard input from cmd.
+func (f *LoadFlags) Options(cmd *cobra.Command) LoadOptions {
+	return LoadOptions{Include: f.Include, Exclude: f.Exclude, Stdin: cmd.InOrStdin()}
 }
 
-// processZipFile safely extracts files from a ZIP archive, preventing Zip Slip.
-func processZipFile(filePath string) ([]Data, error) {
-	r, err := zip.OpenReader(filePath)
+type lister struct {
+	opts    LoadOptions
+	root    string
+	listing *Listing
+}
+
+func (l *lister) addStdin() error {
+	if l.opts.Stdin == nil {
+		return fmt.Errorf("no standard input to read")
+	}
+	spooled, err := l.spool(l.opts.Stdin)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open zip file %s: %w", filePath, err)
+		return fmt.Errorf("failed to read standard input: %w", err)
 	}
-	defer r.Close()
+	return l.add(StdinPath, spooled, nil, openFile(spooled))
+}
 
-	tempDir, err := os.MkdirTemp("", "unzipped")
+func (l *lister) addPath(path string) error {
+	info, err := os.Stat(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		return fmt.Errorf("error accessing path %s: %w", path, err)
 	}
-	// Ensure the temp directory is removed in case of an error.
-	defer func() {
+
+	if !info.IsDir() {
+		signature, err := signing.ReadSignature(path)
 		if err != nil {
-			os.RemoveAll(tempDir)
+			return fmt.Errorf("failed to read signature of %s: %w", path, err)
 		}
-	}()
+		return l.add(path, path, signature, openFile(path))
+	}
 
-	for _, f := range r.File {
-		// Clean the file name to remove any path traversal.
-		cleanName := filepath.Clean(f.Name)
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	var errors []error
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+		if strings.HasSuffix(entry.Name(), signing.SignatureExt) {
+			// Signatures are read along with the file they sign.
+			continue
+		}
+		if err := l.addPath(entryPath); err != nil {
+			errors = append(errors, fmt.Errorf("failed to load data from path %s: %w", entryPath, err))
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred during data ingestion: %v", errors)
+	}
+	return nil
+}
 
-		// Prevent absolute paths.
-		if filepath.IsAbs(cleanName) {
-			return nil, fmt.Errorf("invalid file path %s: absolute paths are not allowed", f.Name)
+// add adds the file shown as display, looking into it if it is an archive. diskPath is the
+// path of the file on disk, if it is one.
+func (l *lister) add(display, diskPath string, signature []byte, open func() (io.ReadCloser, error)) error {
+	header, err := readHeader(open)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", display, err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")), bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		if diskPath == "" {
+			if diskPath, err = l.spoolFrom(open); err != nil {
+				return fmt.Errorf("failed to read %s: %w", display, err)
+			}
+		}
+		if err := l.addZip(display, diskPath); err != nil {
+			return fmt.Errorf("failed to process zip file %s: %w", display, err)
 		}
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		spooled, err := l.spoolFrom(func() (io.ReadCloser, error) { return openGzip(open) })
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", display, err)
+		}
+		return l.add(display, spooled, signature, openFile(spooled))
+	case len(header) > 262 && string(header[257:262]) == "ustar":
+		if err := l.addTar(display, open); err != nil {
+			return fmt.Errorf("failed to process tar file %s: %w", display, err)
+		}
+	case l.isDocument(display, header):
+		if reason := l.filter(display); reason != "" {
+			l.listing.Skipped = append(l.listing.Skipped, SkippedFile{Path: display, Reason: reason})
+			return nil
+		}
+		l.listing.Files = append(l.listing.Files, File{Path: display, Signature: signature, open: open})
+	case l.opts.Document != nil:
+		l.listing.Skipped = append(l.listing.Skipped, SkippedFile{Path: display, Reason: "not a supported document or archive"})
+	default:
+		l.listing.Skipped = append(l.listing.Skipped, SkippedFile{Path: display, Reason: "not a JSON, XML or SPDX document or a supported archive"})
+	}
+	return nil
+}
 
-		// Resolve the absolute path.
-		extractedFilePath := filepath.Join(tempDir, cleanName)
-		absExtractedPath, err := filepath.Abs(extractedFilePath)
+// addZip adds the entries of the ZIP file at zipPath, shown as being at display.
+func (l *lister) addZip(display, zipPath string) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip file %s: %w", zipPath, err)
+	}
+	defer r.Close()
+
+	entries := map[string]*zip.File{}
+	names := []string{}
+	for _, f := range r.File {
+		name, err := archiveEntryName(f.Name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get absolute path for %s: %w", extractedFilePath, err)
+			return err
 		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		entries[name] = f
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		absTempDir, err := filepath.Abs(tempDir)
+	for _, name := range names {
+		if strings.HasSuffix(name, signing.SignatureExt) {
+			continue
+		}
+		entryPath := filepath.Join(display, name)
+		var signature []byte
+		if signatureFile, ok := entries[name+signing.SignatureExt]; ok {
+			if signature, err = readZipEntry(signatureFile); err != nil {
+				return fmt.Errorf("failed to read signature of %s: %w", entryPath, err)
+			}
+		}
+		name := name
+		if err := l.add(entryPath, "", signature, func() (io.ReadCloser, error) {
+			return openZipEntry(zipPath, name)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTar adds the regular files of a tar stream, shown as being at display. Tar files can
+// only be read in order, so every entry is copied to a temporary file.
+func (l *lister) addTar(display string, open func() (io.ReadCloser, error)) error {
+	rc, err := open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	spooled := map[string]string{}
+	names := []string{}
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to get absolute path for temp directory: %w", err)
+			return err
 		}
+		name, err := archiveEntryName(header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeDir:
+			continue
+		default:
+			l.listing.Skipped = append(l.listing.Skipped, SkippedFile{Path: filepath.Join(display, name), Reason: "not a regular file"})
+			continue
+		}
+		if spooled[name], err = l.spool(tr); err != nil {
+			return fmt.Errorf("failed to copy file %s: %w", header.Name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.HasSuffix(name, signing.SignatureExt) {
+			continue
+		}
+		entryPath := filepath.Join(display, name)
+		var signature []byte
+		if signatureFile, ok := spooled[name+signing.SignatureExt]; ok {
+			if signature, err = os.ReadFile(signatureFile); err != nil {
+				return fmt.Errorf("failed to read signature of %s: %w", entryPath, err)
+			}
+		}
+		if err := l.add(entryPath, spooled[name], signature, openFile(spooled[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// Ensure that the extracted path is within the temp directory.
-		if !strings.HasPrefix(absExtractedPath, absTempDir+string(os.PathSeparator)) {
-			return nil, fmt.Errorf("invalid file path %s: outside of the extraction directory", f.Name)
+// filter returns why the document shown as display is excluded by the filters, or nothing if
+// it isn't.
+func (l *lister) filter(display string) string {
+	relative := display
+	if display != l.root {
+		if rel, err := filepath.Rel(l.root, display); err == nil && !strings.HasPrefix(rel, "..") {
+			relative = rel
 		}
+	} else if display != StdinPath {
+		relative = filepath.Base(display)
+	}
+	relative = filepath.ToSlash(relative)
 
-		// Create necessary directories.
-		if f.FileInfo().IsDir() {
-			if err := os.MkdirAll(absExtractedPath, os.ModePerm); err != nil {
-				return nil, fmt.Errorf("failed to create directory %s: %w", absExtractedPath, err)
+	if len(l.opts.Include) > 0 {
+		included := false
+		for _, pattern := range l.opts.Include {
+			if MatchGlob(pattern, relative) {
+				included = true
+				break
 			}
-			continue
-		} else {
-			if err := os.MkdirAll(filepath.Dir(absExtractedPath), os.ModePerm); err != nil {
-				return nil, fmt.Errorf("failed to create directory for file %s: %w", absExtractedPath, err)
+		}
+		if !included {
+			return "not matched by --include"
+		}
+	}
+	for _, pattern := range l.opts.Exclude {
+		if MatchGlob(pattern, relative) {
+			return "matched by --exclude " + pattern
+		}
+	}
+	return ""
+}
+
+// spool copies r to a temporary file that is removed when the listing is closed.
+func (l *lister) spool(r io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "minefield-load-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+	l.listing.temporary = append(l.listing.temporary, file.Name())
+	if _, err := io.Copy(file, r); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func (l *lister) spoolFrom(open func() (io.ReadCloser, error)) (string, error) {
+	rc, err := open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return l.spool(rc)
+}
+
+// MatchGlob reports whether the slash separated name matches the glob. Path segments are
+// matched with path.Match, and a ** segment matches any number of segments. A glob without a
+// slash is matched against the last segment of the name, so *.json matches JSON files in
+// every directory.
+func MatchGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		return matchSegments([]string{pattern}, []string{path.Base(name)})
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// ValidateGlob returns an error if the glob is malformed.
+func ValidateGlob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
 			}
 		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	matched, err := path.Match(pattern[0], name[0])
+	return err == nil && matched && matchSegments(pattern[1:], name[1:])
+}
 
-		rc, err := f.Open()
+func (l *lister) isDocument(display string, header []byte) bool {
+	if l.opts.Document != nil {
+		return l.opts.Document(display, header)
+	}
+	return isDocument(header)
+}
+
+// isDocument reports whether the content starts like a JSON, XML or SPDX tag-value document.
+func isDocument(header []byte) bool {
+	header = bytes.TrimPrefix(header, []byte("\xef\xbb\xbf"))
+	header = bytes.TrimLeft(header, " \t\r\n")
+	if len(header) == 0 {
+		return false
+	}
+	switch header[0] {
+	case '{', '[', '<':
+		return true
+	}
+	return bytes.HasPrefix(header, []byte("SPDXVersion:")) || bytes.HasPrefix(header, []byte("##"))
+}
+
+// archiveEntryName cleans the name of an archive entry and rejects names that would escape
+// the archive, as extracting them would be unsafe.
+func archiveEntryName(name string) (string, error) {
+	// Clean the file name to remove any path traversal.
+	cleanName := filepath.Clean(name)
+	if filepath.IsAbs(cleanName) {
+		return "", fmt.Errorf("invalid file path %s: absolute paths are not allowed", name)
+	}
+	if cleanName == ".." || strings.HasPrefix(cleanName, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid file path %s: outside of the extraction directory", name)
+	}
+	return cleanName, nil
+}
+
+func readHeader(open func() (io.ReadCloser, error)) ([]byte, error) {
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	header := make([]byte, sniffSize)
+	n, err := io.ReadFull(rc, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return header[:n], nil
+}
+
+func openFile(path string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		file, err := os.Open(path)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open file %s in zip: %w", f.Name, err)
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
 		}
-		defer rc.Close()
+		return file, nil
+	}
+}
 
-		outFile, err := os.Create(absExtractedPath)
+func openGzip(open func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return &multiCloser{Reader: gz, closers: []io.Closer{gz, rc}}, nil
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// openZipEntry opens the entry with the given cleaned name in the ZIP file at zipPath. Closing
+// the entry closes the ZIP file too.
+func openZipEntry(zipPath, name string) (io.ReadCloser, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file %s: %w", zipPath, err)
+	}
+	for _, f := range r.File {
+		if filepath.Clean(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
 		if err != nil {
-			return nil, fmt.Errorf("failed to create file %s: %w", absExtractedPath, err)
+			r.Close()
+			return nil, fmt.Errorf("failed to open file %s in zip: %w", f.Name, err)
 		}
-		defer outFile.Close()
+		return &multiCloser{Reader: rc, closers: []io.Closer{rc, r}}, nil
+	}
+	r.Close()
+	return nil, fmt.Errorf("file %s not found in zip file %s", name, zipPath)
+}
 
-		if _, err := io.Copy(outFile, rc); err != nil {
-			return nil, fmt.Errorf("failed to copy file %s: %w", absExtractedPath, err)
+// multiCloser reads from a reader and closes all closers in order when closed.
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	var err error
+	for _, closer := range m.closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
 		}
 	}
-
-	return LoadDataFromPath(tempDir)
+	return err
 }
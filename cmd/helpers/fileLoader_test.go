@@ -0,0 +1,237 @@
+package helpers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipOf returns a ZIP file with the given entries, in the given order.
+func zipOf(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for i := 0; i < len(entries); i += 2 {
+		f, err := w.Create(entries[i])
+		require.NoError(t, err)
+		_, err = f.Write([]byte(entries[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// tarGzOf returns a gzip compressed tar file with the given regular files, in the given order.
+func tarGzOf(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	w := tar.NewWriter(gz)
+	for i := 0; i < len(entries); i += 2 {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: entries[i], Mode: 0o644, Size: int64(len(entries[i+1])), Typeflag: tar.TypeReg}))
+		_, err := w.Write([]byte(entries[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func gzipOf(t *testing.T, data string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	_, err := gz.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// readListing returns the paths of the files of the listing, in order, and their contents and
+// signatures by path.
+func readListing(t *testing.T, listing *Listing) ([]string, map[string]string, map[string]string) {
+	t.Helper()
+	var paths []string
+	contents := map[string]string{}
+	signatures := map[string]string{}
+	for _, file := range listing.Files {
+		paths = append(paths, file.Path)
+		data, err := file.ReadAll()
+		require.NoError(t, err)
+		contents[file.Path] = string(data)
+		signatures[file.Path] = string(file.Signature)
+	}
+	return paths, contents, signatures
+}
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json.sig"), []byte("sig-a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+	nested := zipOf(t, "d.json", `{"d":4}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.zip"), zipOf(t,
+		"sub/c.json", `{"c":3}`,
+		"b.json", `{"b":2}`,
+		"b.json.sig", "sig-b",
+		"inner.zip", string(nested),
+	), 0o644))
+
+	listing, err := ListFiles(dir, LoadOptions{})
+	require.NoError(t, err)
+
+	paths, contents, signatures := readListing(t, listing)
+	zipPath := filepath.Join(dir, "b.zip")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.json"),
+		filepath.Join(zipPath, "b.json"),
+		filepath.Join(zipPath, "inner.zip", "d.json"),
+		filepath.Join(zipPath, "sub", "c.json"),
+	}, paths)
+	assert.Equal(t, `{"d":4}`, contents[filepath.Join(zipPath, "inner.zip", "d.json")])
+	assert.Equal(t, `{"c":3}`, contents[filepath.Join(zipPath, "sub", "c.json")])
+	assert.Equal(t, "sig-a", signatures[filepath.Join(dir, "a.json")])
+	assert.Equal(t, "sig-b", signatures[filepath.Join(zipPath, "b.json")])
+	assert.Empty(t, signatures[filepath.Join(zipPath, "sub", "c.json")])
+	assert.Equal(t, []SkippedFile{{Path: filepath.Join(dir, "notes.txt"), Reason: "not a JSON, XML or SPDX document or a supported archive"}}, listing.Skipped)
+
+	// The nested ZIP file was copied to a temporary file, which Close removes.
+	temporary := listing.temporary
+	require.Len(t, temporary, 1)
+	listing.Close()
+	for _, name := range temporary {
+		_, err := os.Stat(name)
+		assert.True(t, os.IsNotExist(err), "%s should have been removed", name)
+	}
+}
+
+func TestListFilesSniffsContent(t *testing.T) {
+	dir := t.TempDir()
+	// Names don't matter, only content does.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.cdx"), []byte("\xef\xbb\xbf\n  {\"bomFormat\":\"CycloneDX\"}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.spdx"), []byte("SPDXVersion: SPDX-2.3\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.xml"), []byte(`<?xml version="1.0"?><bom/>`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "export.tgz"), tarGzOf(t,
+		"sboms/b.json", `{"b":2}`,
+		"sboms/b.json.sig", "sig-b",
+		"sboms/readme.md", "# readme",
+		"sboms/nested.json.gz", string(gzipOf(t, `{"n":1}`)),
+	), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "single.gz"), gzipOf(t, `{"g":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.json"), nil, 0o644))
+
+	listing, err := ListFiles(dir, LoadOptions{})
+	require.NoError(t, err)
+	defer listing.Close()
+
+	paths, contents, signatures := readListing(t, listing)
+	tarPath := filepath.Join(dir, "export.tgz")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "app.cdx"),
+		filepath.Join(dir, "app.spdx"),
+		filepath.Join(dir, "app.xml"),
+		filepath.Join(tarPath, "sboms", "b.json"),
+		filepath.Join(tarPath, "sboms", "nested.json.gz"),
+		filepath.Join(dir, "single.gz"),
+	}, paths)
+	assert.Equal(t, `{"b":2}`, contents[filepath.Join(tarPath, "sboms", "b.json")])
+	assert.Equal(t, "sig-b", signatures[filepath.Join(tarPath, "sboms", "b.json")])
+	assert.Equal(t, `{"n":1}`, contents[filepath.Join(tarPath, "sboms", "nested.json.gz")])
+	assert.Equal(t, `{"g":1}`, contents[filepath.Join(dir, "single.gz")])
+
+	var skipped []string
+	for _, file := range listing.Skipped {
+		skipped = append(skipped, file.Path)
+	}
+	assert.Equal(t, []string{filepath.Join(dir, "empty.json"), filepath.Join(tarPath, "sboms", "readme.md")}, skipped)
+}
+
+func TestListFilesDocument(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sbom.json"), []byte(`{"bomFormat":"CycloneDX"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor.tgz"), tarGzOf(t, "lib/go.mod", "module example.com/lib\n"), 0o644))
+
+	listing, err := ListFiles(dir, LoadOptions{Document: func(path string, header []byte) bool {
+		return filepath.Base(path) == "go.mod" && bytes.HasPrefix(header, []byte("module "))
+	}})
+	require.NoError(t, err)
+	defer listing.Close()
+
+	paths, _, _ := readListing(t, listing)
+	assert.Equal(t, []string{filepath.Join(dir, "go.mod"), filepath.Join(dir, "vendor.tgz", "lib", "go.mod")}, paths)
+	require.Len(t, listing.Skipped, 1)
+	assert.Equal(t, SkippedFile{Path: filepath.Join(dir, "sbom.json"), Reason: "not a supported document or archive"}, listing.Skipped[0])
+}
+
+func TestListFilesStdin(t *testing.T) {
+	listing, err := ListFiles(StdinPath, LoadOptions{Stdin: bytes.NewReader(tarGzOf(t, "a.json", `{"a":1}`, "b.json", `{"b":2}`))})
+	require.NoError(t, err)
+	defer listing.Close()
+	paths, contents, _ := readListing(t, listing)
+	assert.Equal(t, []string{filepath.Join(StdinPath, "a.json"), filepath.Join(StdinPath, "b.json")}, paths)
+	assert.Equal(t, `{"b":2}`, contents[filepath.Join(StdinPath, "b.json")])
+
+	listing, err = ListFiles(StdinPath, LoadOptions{Stdin: strings.NewReader(`{"a":1}`)})
+	require.NoError(t, err)
+	defer listing.Close()
+	paths, _, _ = readListing(t, listing)
+	assert.Equal(t, []string{StdinPath}, paths)
+
+	_, err = ListFiles(StdinPath, LoadOptions{})
+	assert.Error(t, err)
+}
+
+func TestListFilesFilters(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sboms", "test"), 0o755))
+	for _, name := range []string{"a.json", "sboms/b.json", "sboms/test/c.json", "sboms/d.xml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(`{}`), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "e.zip"), zipOf(t, "sboms/e.json", `{}`), 0o644))
+
+	tests := []struct {
+		name     string
+		opts     LoadOptions
+		expected []string
+	}{
+		{"no filters", LoadOptions{}, []string{"a.json", "e.zip/sboms/e.json", "sboms/b.json", "sboms/d.xml", "sboms/test/c.json"}},
+		{"base name", LoadOptions{Include: []string{"*.json"}}, []string{"a.json", "e.zip/sboms/e.json", "sboms/b.json", "sboms/test/c.json"}},
+		{"recursive", LoadOptions{Include: []string{"sboms/**/*.json"}}, []string{"sboms/b.json", "sboms/test/c.json"}},
+		{"into archives", LoadOptions{Include: []string{"**/sboms/*"}}, []string{"e.zip/sboms/e.json", "sboms/b.json", "sboms/d.xml"}},
+		{"exclude", LoadOptions{Exclude: []string{"**/test/**", "*.xml"}}, []string{"a.json", "e.zip/sboms/e.json", "sboms/b.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing, err := ListFiles(dir, tt.opts)
+			require.NoError(t, err)
+			defer listing.Close()
+			var paths []string
+			for _, file := range listing.Files {
+				rel, err := filepath.Rel(dir, file.Path)
+				require.NoError(t, err)
+				paths = append(paths, filepath.ToSlash(rel))
+			}
+			assert.Equal(t, tt.expected, paths)
+			assert.Len(t, listing.Skipped, 5-len(tt.expected))
+		})
+	}
+
+	_, err := ListFiles(dir, LoadOptions{Include: []string{"["}})
+	assert.ErrorContains(t, err, "invalid glob")
+}
+
+func TestListFilesRejectsTraversal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evil.zip")
+	require.NoError(t, os.WriteFile(path, zipOf(t, "../evil.json", `{}`), 0o644))
+	_, err := ListFiles(path, LoadOptions{})
+	assert.ErrorContains(t, err, "outside of the extraction directory")
+}
@@ -37,10 +37,13 @@ func ComputeAdditionalInfo(node *apiv1.Node) string {
 		}
 	case tools.VulnerabilityType:
 		// Unmarshal metadata into Vulnerability
-		var vulnerability ingest.Vulnerability
-		if node.Metadata != nil {
-			metadataBytes := node.Metadata
-			if err := json.Unmarshal(metadataBytes, &vulnerability); err == nil {
+		var metadata any
+		if node.Metadata != nil && json.Unmarshal(node.Metadata, &metadata) == nil {
+			if vulnerability, err := ingest.NodeVulnerability(metadata); err == nil {
+				var details []string
+				if severity := severityInfo(vulnerability); severity != "" {
+					details = append(details, severity)
+				}
 				var fixedInfo []string
 				for _, affected := range vulnerability.Affected {
 					for _, r := range affected.Ranges {
@@ -52,11 +55,24 @@ func ComputeAdditionalInfo(node *apiv1.Node) string {
 					}
 				}
 				if len(fixedInfo) > 0 {
-					additionalInfo = "Affected Package PURL (Package URL) : Fixed Version\n\n" + strings.Join(fixedInfo, "\n")
+					details = append(details, "Affected Package PURL (Package URL) : Fixed Version\n\n"+strings.Join(fixedInfo, "\n"))
 				}
+				additionalInfo = strings.Join(details, "\n\n")
 			}
 		}
 	}
 
 	return additionalInfo
 }
+
+// severityInfo describes the normalized severity of a vulnerability, like
+// "Severity: CRITICAL 9.8 (CVSS_V3)", or returns "" if it has none.
+func severityInfo(vulnerability ingest.Vulnerability) string {
+	if vulnerability.SeverityRating == "" {
+		return ""
+	}
+	if vulnerability.SeverityScore == 0 {
+		return fmt.Sprintf("Severity: %s (%s)", vulnerability.SeverityRating, vulnerability.SeverityType)
+	}
+	return fmt.Sprintf("Severity: %s %.1f (%s)", vulnerability.SeverityRating, vulnerability.SeverityScore, vulnerability.SeverityType)
+}
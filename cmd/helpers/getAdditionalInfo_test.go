@@ -67,6 +67,29 @@ func TestComputeAdditionalInfo(t *testing.T) {
 			},
 			expected: "Affected Package PURL (Package URL) : Fixed Version\n\npkg:npm/example@1.0.0 : 1.0.1",
 		},
+		{
+			name: "Vulnerability with severity",
+			input: &apiv1.Node{
+				Type: tools.VulnerabilityType,
+				Metadata: mustMarshal(ingest.Vulnerability{
+					SeverityType:   ingest.SeverityCVSSV3,
+					SeverityScore:  9.8,
+					SeverityRating: ingest.RatingCritical,
+				}),
+			},
+			expected: "Severity: CRITICAL 9.8 (CVSS_V3)",
+		},
+		{
+			name: "Vulnerability stored as encoded JSON, rated by its database",
+			input: &apiv1.Node{
+				Type: tools.VulnerabilityType,
+				Metadata: mustMarshal(mustMarshal(ingest.Vulnerability{
+					SeverityType:   ingest.SeverityDatabaseSpecific,
+					SeverityRating: ingest.RatingMedium,
+				})),
+			},
+			expected: "Severity: MEDIUM (DATABASE_SPECIFIC)",
+		},
 		{
 			name: "Node with nil metadata",
 			input: &apiv1.Node{
@@ -1,9 +1,11 @@
 package ingest
 
 import (
+	"github.com/bitbomdev/minefield/cmd/ingest/lockfile"
 	"github.com/bitbomdev/minefield/cmd/ingest/osv"
 	"github.com/bitbomdev/minefield/cmd/ingest/sbom"
 	"github.com/bitbomdev/minefield/cmd/ingest/scorecard"
+	"github.com/bitbomdev/minefield/cmd/ingest/vex"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +22,10 @@ func New() *cobra.Command {
 		DisableAutoGenTag: true,
 	}
 
+	cmd.AddCommand(lockfile.New())
 	cmd.AddCommand(osv.New())
 	cmd.AddCommand(sbom.New())
 	cmd.AddCommand(scorecard.New())
+	cmd.AddCommand(vex.New())
 	return cmd
 }
@@ -20,9 +20,11 @@ func TestNewCommand(t *testing.T) {
 	}
 
 	expectedSubcommands := []string{
+		"lockfile [path to lockfile file/dir]",
 		"osv [path to vulnerability file/dir]",
 		"sbom [path to sbom file/dir]",
 		"scorecard [path to scorecard file/dir]",
+		"vex [path to OpenVEX or CSAF VEX file/dir]",
 	}
 	assert.ElementsMatch(t, expectedSubcommands, subcommandUses, "Subcommands should match expected list")
 }
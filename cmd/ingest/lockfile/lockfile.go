@@ -0,0 +1,135 @@
+package lockfile
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/bitbomdev/minefield/cmd/helpers"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	addr                string // Address of the minefield server
+	format              string
+	project             string
+	load                helpers.LoadFlags
+	ingestServiceClient apiv1connect.IngestServiceClient
+}
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().StringVar(&o.format, "format", "", fmt.Sprintf("Format of the lockfiles, one of %s, detected from their name or content if empty. Every file is ingested in that format if set", strings.Join(ingest.LockfileFormats, ", ")))
+	cmd.Flags().StringVar(&o.project, "project", "", "Purl of the project the lockfiles belong to, by default the project the lockfile names, if any")
+	o.load.AddFlags(cmd)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.format != "" && !slices.Contains(ingest.LockfileFormats, o.format) {
+		return fmt.Errorf("unsupported lockfile format %q, expected one of %s", o.format, strings.Join(ingest.LockfileFormats, ", "))
+	}
+	if o.ingestServiceClient == nil {
+		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(
+			http.DefaultClient,
+			o.addr,
+		)
+	}
+	lockfilePath := args[0]
+
+	loadOptions := o.load.Options(cmd)
+	// Lockfiles are told apart by their whole content, which is only read below. Binary files
+	// can't be lockfiles.
+	loadOptions.Document = func(path string, header []byte) bool {
+		return o.format != "" || path == lockfilePath || !bytes.Contains(header, []byte{0})
+	}
+	listing, err := helpers.ListFiles(lockfilePath, loadOptions)
+	if err != nil {
+		return fmt.Errorf("failed to ingest lockfiles: %w", err)
+	}
+	defer listing.Close()
+
+	unchanged := 0
+	var skippedEntries []string
+	for index, file := range listing.Files {
+		data, err := file.ReadAll()
+		if err != nil {
+			return err
+		}
+		format := o.format
+		if format == "" {
+			if format, err = ingest.DetectLockfile(file.Path, data); err != nil {
+				// The file the user named is never skipped.
+				if file.Path == lockfilePath {
+					return err
+				}
+				listing.Skipped = append(listing.Skipped, helpers.SkippedFile{Path: file.Path, Reason: "not a supported document or archive"})
+				continue
+			}
+		}
+		req := connect.NewRequest(&apiv1.IngestLockfileRequest{
+			Lockfile:  data,
+			Signature: file.Signature,
+			Name:      filepath.Base(file.Path),
+			Format:    format,
+			Project:   o.project,
+		})
+		res, err := o.ingestServiceClient.IngestLockfile(cmd.Context(), req)
+		if err != nil {
+			return fmt.Errorf("failed to ingest lockfile %s: %w", file.Path, err)
+		}
+		if res.Msg.GetUnchanged() {
+			unchanged++
+		}
+		for _, entry := range res.Msg.GetSkipped() {
+			skippedEntries = append(skippedEntries, fmt.Sprintf("%s: %s", file.Path, entry))
+		}
+		// Clear the line by overwriting with spaces
+		fmt.Printf("\r\033[1;36m%-80s\033[0m", " ")
+		fmt.Printf("\r\033[1;36mIngested %d/%d lockfiles\033[0m | \033[1;34m%s\033[0m", index+1, len(listing.Files), helpers.TruncateString(file.Path, 50))
+	}
+
+	fmt.Println("\nLockfiles ingested successfully")
+	helpers.PrintSkipped(cmd.OutOrStdout(), listing.Skipped)
+	if len(skippedEntries) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Skipped %d lockfile entries:\n", len(skippedEntries))
+		for _, entry := range skippedEntries {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", entry)
+		}
+	}
+	if unchanged > 0 {
+		fmt.Printf("%d lockfiles were unchanged since they were last ingested\n", unchanged)
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "lockfile [path to lockfile file/dir]",
+		Short: "Ingest the packages and dependencies pinned by lockfiles, for projects without SBOMs",
+		Long: `Ingest go.mod, go.sum, package-lock.json, npm-shrinkwrap.json, pnpm-lock.yaml, Cargo.lock,
+poetry.lock and requirements.txt files, and the output of go mod graph.
+
+Every pinned package becomes a library node named by its purl, depending on the packages the
+lockfile says it depends on. The project the lockfile belongs to, named by the lockfile or by
+--project, depends on its direct dependencies and on the packages nothing else depends on.
+Files of a directory that aren't lockfiles are skipped.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
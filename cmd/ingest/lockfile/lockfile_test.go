@@ -0,0 +1,116 @@
+package lockfile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	cmd := New()
+
+	assert.Equal(t, "lockfile [path to lockfile file/dir]", cmd.Use)
+	assert.NotNil(t, cmd.Flags().Lookup("addr"))
+	assert.NotNil(t, cmd.Flags().Lookup("format"))
+	assert.NotNil(t, cmd.Flags().Lookup("project"))
+	assert.True(t, cmd.DisableAutoGenTag)
+	assert.NoError(t, cmd.Args(nil, []string{"arg1"}))
+	assert.Error(t, cmd.Args(nil, nil))
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\nrequire github.com/google/uuid v1.6.0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("requests==2.31.0\nflask>=2.0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# app\n"), 0o600))
+
+	out := &bytes.Buffer{}
+	cmd := New()
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--addr", server.URL, dir})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "README.md: not a supported document or archive")
+	assert.Contains(t, out.String(), "Skipped 1 lockfile entries:\n  "+filepath.Join(dir, "requirements.txt")+": flask>=2.0: not pinned to a single version with == or ===\n")
+
+	app, err := graph.ResolveName(ctx, storage, "pkg:golang/example.com/app")
+	require.NoError(t, err)
+	uuid, err := graph.ResolveName(ctx, storage, "pkg:golang/github.com/google/uuid@v1.6.0")
+	require.NoError(t, err)
+	node, err := storage.GetNode(ctx, app)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{uuid}, node.Children.ToArray())
+	_, err = graph.ResolveName(ctx, storage, "pkg:pypi/requests@2.31.0")
+	require.NoError(t, err)
+
+	// The output of go mod graph has no usual name, and is recognized by its content.
+	graphPath := filepath.Join(dir, "graph.txt")
+	require.NoError(t, os.WriteFile(graphPath, []byte("example.com/app github.com/google/uuid@v1.6.0\ngithub.com/google/uuid@v1.6.0 golang.org/x/text@v0.3.0\n"), 0o600))
+	cmd = New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", server.URL, "--project", "pkg:github/example/app", graphPath})
+	require.NoError(t, cmd.Execute())
+	project, err := graph.ResolveName(ctx, storage, "pkg:github/example/app")
+	require.NoError(t, err)
+	node, err = storage.GetNode(ctx, project)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{uuid}, node.Children.ToArray())
+
+	cmd = New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", server.URL, "--format", "yarn.lock", graphPath})
+	assert.Error(t, cmd.Execute())
+
+	// A file the user names is never skipped.
+	cmd = New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", server.URL, filepath.Join(dir, "README.md")})
+	assert.ErrorContains(t, cmd.Execute(), "isn't a lockfile")
+}
+
+func TestRunStdin(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// The output of go mod graph is longer than what is read to tell what a file is, and its
+	// format is detected on the whole of it.
+	var graphOutput strings.Builder
+	for i := 0; graphOutput.Len() <= 1024; i++ {
+		fmt.Fprintf(&graphOutput, "example.com/app example.com/dependency-with-a-rather-long-module-path-%03d@v1.0.%d\n", i, i)
+	}
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader(graphOutput.String()))
+	cmd.SetArgs([]string{"--addr", server.URL, "-"})
+	require.NoError(t, cmd.Execute())
+
+	app, err := graph.ResolveName(ctx, storage, "pkg:golang/example.com/app")
+	require.NoError(t, err)
+	node, err := storage.GetNode(ctx, app)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(strings.Count(graphOutput.String(), "\n")), node.Children.GetCardinality())
+}
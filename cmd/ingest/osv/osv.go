@@ -1,30 +1,39 @@
 package osv
 
 import (
-	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 
 	"connectrpc.com/connect"
+	service "github.com/bitbomdev/minefield/api/v1"
 	"github.com/bitbomdev/minefield/cmd/helpers"
 	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
 	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/signing"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
 	addr                string // Address of the minefield server
+	archive             bool   // Whether the path is a ZIP archive of OSV records to send as a whole
+	load                helpers.LoadFlags
 	ingestServiceClient apiv1connect.IngestServiceClient
 }
 
 const (
 	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+	chunkSize   = 64 * 1024               // Size of the archive chunks sent to the server
 )
 
 func (o *options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().BoolVar(&o.archive, "archive", false, "Send the path, a ZIP archive of OSV records like osv.dev's all.zip, to be ingested in one pass")
+	o.load.AddFlags(cmd)
 }
-func (o *options) Run(_ *cobra.Command, args []string) error {
+func (o *options) Run(cmd *cobra.Command, args []string) error {
 	if o.ingestServiceClient == nil {
 		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(
 			http.DefaultClient,
@@ -32,23 +41,88 @@ func (o *options) Run(_ *cobra.Command, args []string) error {
 		)
 	}
 	vulnsPath := args[0]
+	if o.archive {
+		return o.ingestArchive(cmd, vulnsPath)
+	}
 	// Ingest vulnerabilities
-	result, err := helpers.LoadDataFromPath(vulnsPath)
+	listing, err := helpers.ListFiles(vulnsPath, o.load.Options(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to load vulnerabilities: %w", err)
 	}
-	for index, data := range result {
+	defer listing.Close()
+
+	unchanged := 0
+	for index, file := range listing.Files {
+		data, err := file.ReadAll()
+		if err != nil {
+			return err
+		}
 		req := connect.NewRequest(&apiv1.IngestVulnerabilityRequest{
-			Vulnerability: data.Data,
+			Vulnerability: data,
+			Signature:     file.Signature,
 		})
-		if _, err := o.ingestServiceClient.IngestVulnerability(context.Background(), req); err != nil {
+		res, err := o.ingestServiceClient.IngestVulnerability(cmd.Context(), req)
+		if err != nil {
 			return fmt.Errorf("failed to ingest vulnerabilities: %w", err)
 		}
+		if res.Msg.GetUnchanged() {
+			unchanged++
+		}
 		// Clear the line by overwriting with spaces
 		fmt.Printf("\r\033[1;36m%-80s\033[0m", " ")
-		fmt.Printf("\r\033[K\033[1;36mIngested %d/%d vulnerabilities\033[0m | \033[1;34mCurrent: %s\033[0m", index+1, len(result), helpers.TruncateString(data.Path, 50))
+		fmt.Printf("\r\033[K\033[1;36mIngested %d/%d vulnerabilities\033[0m | \033[1;34mCurrent: %s\033[0m", index+1, len(listing.Files), helpers.TruncateString(file.Path, 50))
 	}
 	fmt.Println("\nVulnerabilities ingested successfully")
+	helpers.PrintSkipped(cmd.OutOrStdout(), listing.Skipped)
+	if unchanged > 0 {
+		fmt.Printf("%d vulnerabilities were unchanged since they were last ingested\n", unchanged)
+	}
+	return nil
+}
+
+// ingestArchive uploads the ZIP archive at path to be ingested in one pass.
+func (o *options) ingestArchive(cmd *cobra.Command, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open vulnerability archive: %w", err)
+	}
+	defer file.Close()
+	signature, err := signing.ReadSignature(path)
+	if err != nil {
+		return err
+	}
+
+	stream := o.ingestServiceClient.IngestVulnerabilityArchive(cmd.Context())
+	if signature != nil {
+		stream.RequestHeader().Set(service.SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	}
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&apiv1.IngestVulnerabilityArchiveChunk{Data: append([]byte(nil), buf[:n]...)}); sendErr != nil {
+				// The server's error is only returned by CloseAndReceive.
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_, _ = stream.CloseAndReceive()
+			return fmt.Errorf("failed to read vulnerability archive: %w", err)
+		}
+	}
+	res, err := stream.CloseAndReceive()
+	if err != nil {
+		return fmt.Errorf("failed to ingest vulnerability archive: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Ingested %d vulnerabilities\n", res.Msg.GetIngested())
+	if res.Msg.GetUnchanged() > 0 {
+		fmt.Fprintf(out, "%d vulnerabilities were unchanged since they were last ingested\n", res.Msg.GetUnchanged())
+	}
 	return nil
 }
 
@@ -18,7 +18,7 @@ func TestNew(t *testing.T) {
 			name:          "creates command with correct configuration",
 			wantUse:       "osv [path to vulnerability file/dir]",
 			wantShort:     "Graph vulnerability data into the graph, and connect it to existing library nodes",
-			wantFlagCount: 1, // Should have the "addr" flag
+			wantFlagCount: 4, // Should have the "addr", "archive", "include" and "exclude" flags
 		},
 	}
 
@@ -0,0 +1,101 @@
+package sbom
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/bitbomdev/minefield/cmd/helpers"
+)
+
+// checkpointEntry is a line of a checkpoint file, recording an ingested SBOM.
+type checkpointEntry struct {
+	SHA256 string `json:"sha256"`
+	Path   string `json:"path"`
+}
+
+// checkpoint records the SBOMs that were ingested by their content hash, so a rerun can skip
+// them even if they were moved. It is a file of JSON lines that is only ever appended to, so
+// an interrupted run leaves at most a partial last line, which is cut off when it is opened.
+type checkpoint struct {
+	mu       sync.Mutex
+	file     *os.File
+	ingested map[string]bool
+}
+
+// openCheckpoint opens the checkpoint file at path, creating it if needed.
+func openCheckpoint(path string) (*checkpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint %s: %w", path, err)
+	}
+	c := &checkpoint{file: file, ingested: map[string]bool{}}
+	reader := bufio.NewReader(file)
+	var complete int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+		}
+		complete += int64(len(line))
+		var entry checkpointEntry
+		if err := json.Unmarshal(line, &entry); err == nil && entry.SHA256 != "" {
+			c.ingested[entry.SHA256] = true
+		}
+	}
+	// The next entry would otherwise be appended to the partial line and be lost with it.
+	if err := file.Truncate(complete); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate checkpoint %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Ingested reports whether an SBOM with the given hash was ingested.
+func (c *checkpoint) Ingested(hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ingested[hash]
+}
+
+// Add records that the SBOM at path with the given hash was ingested.
+func (c *checkpoint) Add(hash, path string) error {
+	line, err := json.Marshal(checkpointEntry{SHA256: hash, Path: path})
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	c.ingested[hash] = true
+	return nil
+}
+
+func (c *checkpoint) Close() error {
+	return c.file.Close()
+}
+
+// hashFile returns the hex encoded SHA-256 digest of the content of the file.
+func hashFile(file helpers.File) (string, error) {
+	r, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -2,10 +2,19 @@ package sbom
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"connectrpc.com/connect"
+	service "github.com/bitbomdev/minefield/api/v1"
 	"github.com/bitbomdev/minefield/cmd/helpers"
 	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
 	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
@@ -13,20 +22,59 @@ import (
 )
 
 type options struct {
-	addr string // Address of the minefield server
+	addr            string        // Address of the minefield server
+	workers         int           // Number of SBOMs ingested at once
+	retries         int           // Number of times a failed SBOM is retried
+	retryBackoff    time.Duration // Wait before the first retry, doubled for every further one
+	continueOnError bool          // Whether to ingest the other SBOMs when one fails
+	failuresReport  string        // Path the failures are written to with continueOnError
+	checkpoint      string        // Path of the checkpoint file, if any
+	load            helpers.LoadFlags
 
 	ingestServiceClient apiv1connect.IngestServiceClient
 }
 
 const (
 	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+	chunkSize   = 64 * 1024               // Size of the SBOM chunks sent to the server
+	// maxRetryBackoff caps the wait between retries.
+	maxRetryBackoff = 30 * time.Second
 )
 
 func (o *options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().IntVar(&o.workers, "workers", 1, "Number of SBOMs to ingest in parallel")
+	cmd.Flags().IntVar(&o.retries, "retries", 3, "Number of times to retry an SBOM that failed to ingest")
+	cmd.Flags().DurationVar(&o.retryBackoff, "retry-backoff", time.Second, "Wait before the first retry, doubled for every further retry")
+	cmd.Flags().BoolVar(&o.continueOnError, "continue-on-error", false, "Keep ingesting the other SBOMs when one fails, and write the failures to --failures-report")
+	cmd.Flags().StringVar(&o.failuresReport, "failures-report", "sbom-ingest-failures.json", "Path of the report of SBOMs that failed to ingest, with --continue-on-error")
+	cmd.Flags().StringVar(&o.checkpoint, "checkpoint", "", "Path of a checkpoint file recording ingested SBOMs by content hash, so a rerun skips them")
+	o.load.AddFlags(cmd)
 }
 
-func (o *options) Run(_ *cobra.Command, args []string) error {
+// failure is an entry of the failures report.
+type failure struct {
+	Path     string `json:"path"`
+	SHA256   string `json:"sha256,omitempty"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error"`
+}
+
+// result is the outcome of ingesting a file.
+type result struct {
+	file    helpers.File
+	sha256  string
+	skipped bool
+	// unchanged is set if the server had ingested the same SBOM before.
+	unchanged bool
+	attempts  int
+	err       error
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.workers < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
 	if o.ingestServiceClient == nil {
 		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(
 			http.DefaultClient,
@@ -35,27 +83,206 @@ func (o *options) Run(_ *cobra.Command, args []string) error {
 	}
 	sbomPath := args[0]
 	// Ingest SBOM
-	result, err := helpers.LoadDataFromPath(sbomPath)
+	listing, err := helpers.ListFiles(sbomPath, o.load.Options(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to ingest SBOM: %w", err)
 	}
+	defer listing.Close()
+	files := listing.Files
 
-	for index, data := range result {
-		req := connect.NewRequest(&apiv1.IngestSBOMRequest{
-			Sbom: data.Data,
-		})
-		if _, err := o.ingestServiceClient.IngestSBOM(context.Background(), req); err != nil {
-			return fmt.Errorf("failed to ingest SBOM: %w", err)
+	var cp *checkpoint
+	if o.checkpoint != "" {
+		if cp, err = openCheckpoint(o.checkpoint); err != nil {
+			return err
 		}
+		defer cp.Close()
+	}
+
+	// Without continueOnError, the first failure cancels the SBOMs being ingested.
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	jobs := make(chan helpers.File)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < o.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				results <- o.process(ctx, cp, file)
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, file := range files {
+			select {
+			case jobs <- file:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	out := cmd.OutOrStdout()
+	done, skipped, unchanged := 0, 0, 0
+	var failures []failure
+	var firstErr error
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to ingest SBOM %s: %w", res.file.Path, res.err)
+			}
+			if !o.continueOnError {
+				cancel()
+				continue
+			}
+			failures = append(failures, failure{Path: res.file.Path, SHA256: res.sha256, Attempts: res.attempts, Error: res.err.Error()})
+		} else if res.skipped {
+			skipped++
+		} else if res.unchanged {
+			unchanged++
+		}
+		done++
 		// Clear the line by overwriting with spaces
-		fmt.Printf("\r\033[1;36m%-80s\033[0m", " ")
-		fmt.Printf("\r\033[1;36mIngested %d/%d SBOMs\033[0m | \033[1;34m%s\033[0m", index+1, len(result), helpers.TruncateString(data.Path, 50))
+		fmt.Fprintf(out, "\r\033[1;36m%-80s\033[0m", " ")
+		fmt.Fprintf(out, "\r\033[1;36mIngested %d/%d SBOMs\033[0m | \033[1;34m%s\033[0m", done, len(files), helpers.TruncateString(res.file.Path, 50))
+	}
+	fmt.Fprintln(out)
+	helpers.PrintSkipped(out, listing.Skipped)
+	if skipped > 0 {
+		fmt.Fprintf(out, "Skipped %d SBOMs that were already ingested\n", skipped)
+	}
+	if unchanged > 0 {
+		fmt.Fprintf(out, "%d SBOMs were unchanged since they were last ingested\n", unchanged)
+	}
+
+	if firstErr != nil && !o.continueOnError {
+		return firstErr
+	}
+	if len(failures) > 0 {
+		if err := writeFailuresReport(o.failuresReport, failures); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to ingest %d of %d SBOMs, see %s", len(failures), len(files), o.failuresReport)
+	}
+
+	fmt.Fprintln(out, "SBOMs ingested successfully")
+	return nil
+}
+
+// process ingests the file unless the checkpoint shows it was ingested, retrying failures
+// that may be temporary.
+func (o *options) process(ctx context.Context, cp *checkpoint, file helpers.File) result {
+	res := result{file: file}
+	if cp != nil {
+		if res.sha256, res.err = hashFile(file); res.err != nil {
+			return res
+		}
+		if cp.Ingested(res.sha256) {
+			res.skipped = true
+			return res
+		}
+	}
+
+	backoff := o.retryBackoff
+	for {
+		res.attempts++
+		res.unchanged, res.err = o.ingest(ctx, file)
+		if res.err == nil || res.attempts > o.retries || !retryable(res.err) {
+			break
+		}
+		if err := sleep(ctx, backoff); err != nil {
+			break
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
 	}
+	if res.err == nil && cp != nil {
+		res.err = cp.Add(res.sha256, file.Path)
+	}
+	return res
+}
 
-	fmt.Println("\nSBOMs ingested successfully")
+// retryable reports whether the server may accept the SBOM when it is sent again. Errors
+// reading the file and errors telling that the SBOM will never be accepted are final.
+func retryable(err error) bool {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return false
+	}
+	switch connectErr.Code() {
+	case connect.CodeCanceled, connect.CodeInvalidArgument, connect.CodeNotFound, connect.CodeAlreadyExists,
+		connect.CodePermissionDenied, connect.CodeFailedPrecondition, connect.CodeUnimplemented, connect.CodeUnauthenticated:
+		return false
+	}
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writeFailuresReport writes the failures, sorted by path, to a JSON file.
+func writeFailuresReport(path string, failures []failure) error {
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Path < failures[j].Path })
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode failures report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write failures report: %w", err)
+	}
 	return nil
 }
 
+// ingest streams the SBOM to the server in chunks, so it is never held in memory whole. It
+// reports whether the server had ingested the same SBOM before.
+func (o *options) ingest(ctx context.Context, file helpers.File) (bool, error) {
+	r, err := file.Open()
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	stream := o.ingestServiceClient.IngestSBOMStream(ctx)
+	if file.Signature != nil {
+		stream.RequestHeader().Set(service.SignatureHeader, base64.StdEncoding.EncodeToString(file.Signature))
+	}
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&apiv1.IngestSBOMChunk{Data: append([]byte(nil), buf[:n]...)}); sendErr != nil {
+				// The server's error is only returned by CloseAndReceive.
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_, _ = stream.CloseAndReceive()
+			return false, fmt.Errorf("failed to read SBOM: %w", err)
+		}
+	}
+	res, err := stream.CloseAndReceive()
+	if err != nil {
+		return false, err
+	}
+	return res.Msg.GetUnchanged(), nil
+}
+
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
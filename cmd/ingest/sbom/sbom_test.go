@@ -1,7 +1,24 @@
 package sbom
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+
+	"connectrpc.com/connect"
+	service "github.com/bitbomdev/minefield/api/v1"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -27,3 +44,167 @@ func TestNew(t *testing.T) {
 		t.Errorf("expected RunE to be set")
 	}
 }
+
+func TestIngest(t *testing.T) {
+	storage := graph.NewMockStorage()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ingest := func(args ...string) string {
+		out := &bytes.Buffer{}
+		cmd := New()
+		cmd.SetOut(out)
+		cmd.SetArgs(append([]string{"--addr", server.URL}, args...))
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+	out := ingest("../../../testdata/small")
+	assert.NotContains(t, out, "unchanged")
+
+	for _, name := range []string{"pkg:dep1@1.0.0", "pkg:lib-A@1.0.0", "pkg:lib-B@1.0.0"} {
+		_, err := storage.NameToID(context.Background(), name)
+		assert.NoError(t, err, name)
+	}
+
+	// The server recognizes SBOMs it ingested before.
+	out = ingest("../../../testdata/small")
+	assert.Contains(t, out, "3 SBOMs were unchanged")
+
+	// Files excluded by a filter are reported.
+	out = ingest("--exclude", "*.json", "../../../testdata/small")
+	assert.Contains(t, out, "Skipped 3 files:")
+
+	// SBOMs can be read from standard input.
+	sbom, err := os.ReadFile(filepath.Join("../../../testdata/small", "dep1.json"))
+	require.NoError(t, err)
+	stdout := &bytes.Buffer{}
+	cmd := New()
+	cmd.SetOut(stdout)
+	cmd.SetIn(bytes.NewReader(sbom))
+	cmd.SetArgs([]string{"--addr", server.URL, "-"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "1 SBOMs were unchanged")
+
+	cmd = New()
+	cmd.SetArgs([]string{"--addr", server.URL, "../../../testdata/does-not-exist"})
+	assert.Error(t, cmd.Execute())
+}
+
+// flakyIngestHandler fails SBOMs containing "flaky" the first time they are sent and SBOMs
+// containing "bad" every time, and counts the attempts per SBOM.
+type flakyIngestHandler struct {
+	apiv1connect.UnimplementedIngestServiceHandler
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (h *flakyIngestHandler) IngestSBOMStream(ctx context.Context, stream *connect.ClientStream[apiv1.IngestSBOMChunk]) (*connect.Response[apiv1.IngestResponse], error) {
+	var data []byte
+	for stream.Receive() {
+		data = append(data, stream.Msg().Data...)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	h.attempts[string(data)]++
+	attempts := h.attempts[string(data)]
+	h.mu.Unlock()
+
+	switch {
+	case bytes.Contains(data, []byte("bad")):
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad SBOM"))
+	case bytes.Contains(data, []byte("flaky")) && attempts == 1:
+		return nil, connect.NewError(connect.CodeUnavailable, errors.New("try again"))
+	}
+	return connect.NewResponse(&apiv1.IngestResponse{}), nil
+}
+
+func TestIngestRetriesAndCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	sbomDir := filepath.Join(dir, "sboms")
+	require.NoError(t, os.Mkdir(sbomDir, 0o755))
+	sboms := map[string]string{"a.json": `{"a":1}`, "b.json": `{"b":2}`, "flaky.json": `{"flaky":3}`, "bad.json": `{"bad":4}`}
+	for name, content := range sboms {
+		require.NoError(t, os.WriteFile(filepath.Join(sbomDir, name), []byte(content), 0o644))
+	}
+
+	report := filepath.Join(dir, "failures.json")
+	checkpointPath := filepath.Join(dir, "checkpoint")
+	// run ingests the SBOMs with a new server, so requests canceled by earlier runs aren't
+	// counted.
+	run := func(extraArgs ...string) (*flakyIngestHandler, error) {
+		handler := &flakyIngestHandler{attempts: map[string]int{}}
+		mux := http.NewServeMux()
+		mux.Handle(apiv1connect.NewIngestServiceHandler(handler))
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		cmd := New()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"--addr", server.URL, "--workers", "3", "--retry-backoff", "1ms",
+			"--checkpoint", checkpointPath, "--failures-report", report}, append(extraArgs, sbomDir)...))
+		return handler, cmd.Execute()
+	}
+
+	// Without --continue-on-error the bad SBOM stops the ingestion.
+	_, err := run()
+	assert.ErrorContains(t, err, "bad SBOM")
+	assert.NoFileExists(t, report)
+
+	handler, err := run("--continue-on-error")
+	assert.ErrorContains(t, err, "failed to ingest 1 of 4 SBOMs")
+	data, err := os.ReadFile(report)
+	require.NoError(t, err)
+	var failures []failure
+	require.NoError(t, json.Unmarshal(data, &failures))
+	require.Len(t, failures, 1)
+	assert.Equal(t, filepath.Join(sbomDir, "bad.json"), failures[0].Path)
+	// Invalid SBOMs aren't retried.
+	assert.Equal(t, 1, failures[0].Attempts)
+	assert.Contains(t, failures[0].Error, "bad SBOM")
+	assert.NotEmpty(t, failures[0].SHA256)
+	assert.Equal(t, 1, handler.attempts[sboms["bad.json"]])
+
+	// A rerun only sends the SBOM that failed.
+	handler, err = run("--continue-on-error")
+	assert.Error(t, err)
+	assert.Equal(t, map[string]int{sboms["bad.json"]: 1}, handler.attempts)
+
+	// Without a checkpoint everything is sent again, and the flaky SBOM is retried.
+	require.NoError(t, os.Remove(filepath.Join(sbomDir, "bad.json")))
+	require.NoError(t, os.Remove(checkpointPath))
+	handler, err = run()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{sboms["a.json"]: 1, sboms["b.json"]: 1, sboms["flaky.json"]: 2}, handler.attempts)
+}
+
+func TestIngestInvalidWorkers(t *testing.T) {
+	cmd := New()
+	cmd.SetArgs([]string{"--workers", "0", "../../../testdata/small"})
+	assert.ErrorContains(t, cmd.Execute(), "--workers must be at least 1")
+}
+
+func TestCheckpointPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	// An interrupted run left the second entry half written.
+	require.NoError(t, os.WriteFile(path, []byte(`{"sha256":"aaa","path":"a.json"}`+"\n"+`{"sha256":"bb`), 0o600))
+
+	c, err := openCheckpoint(path)
+	require.NoError(t, err)
+	assert.True(t, c.Ingested("aaa"))
+	assert.False(t, c.Ingested("bb"))
+	require.NoError(t, c.Add("ccc", "c.json"))
+	require.NoError(t, c.Close())
+
+	c, err = openCheckpoint(path)
+	require.NoError(t, err)
+	defer c.Close()
+	assert.True(t, c.Ingested("aaa"))
+	assert.True(t, c.Ingested("ccc"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"sha256":"aaa","path":"a.json"}`+"\n"+`{"sha256":"ccc","path":"c.json"}`+"\n", string(data))
+}
@@ -1,7 +1,6 @@
 package scorecard
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
@@ -14,6 +13,7 @@ import (
 
 type options struct {
 	addr                string // Address of the minefield server
+	load                helpers.LoadFlags
 	ingestServiceClient apiv1connect.IngestServiceClient
 }
 
@@ -23,9 +23,10 @@ const (
 
 func (o *options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	o.load.AddFlags(cmd)
 }
 
-func (o *options) Run(_ *cobra.Command, args []string) error {
+func (o *options) Run(cmd *cobra.Command, args []string) error {
 	if o.ingestServiceClient == nil {
 		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(
 			http.DefaultClient,
@@ -34,24 +35,31 @@ func (o *options) Run(_ *cobra.Command, args []string) error {
 	}
 	scorecardPath := args[0]
 
-	result, err := helpers.LoadDataFromPath(scorecardPath)
+	listing, err := helpers.ListFiles(scorecardPath, o.load.Options(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to ingest SBOM: %w", err)
 	}
+	defer listing.Close()
 
-	for index, data := range result {
+	for index, file := range listing.Files {
+		data, err := file.ReadAll()
+		if err != nil {
+			return err
+		}
 		req := connect.NewRequest(&apiv1.IngestScorecardRequest{
-			Scorecard: data.Data,
+			Scorecard: data,
+			Signature: file.Signature,
 		})
-		if _, err := o.ingestServiceClient.IngestScorecard(context.Background(), req); err != nil {
+		if _, err := o.ingestServiceClient.IngestScorecard(cmd.Context(), req); err != nil {
 			return fmt.Errorf("failed to ingest Scorecard: %w", err)
 		}
 		// Clear the line by overwriting with spaces
 		fmt.Printf("\r\033[1;36m%-80s\033[0m", " ")
-		fmt.Printf("\r\033[1;36mIngested %d/%d Scorecards\033[0m | \033[1;34m%s\033[0m", index+1, len(result), helpers.TruncateString(data.Path, 50))
+		fmt.Printf("\r\033[1;36mIngested %d/%d Scorecards\033[0m | \033[1;34m%s\033[0m", index+1, len(listing.Files), helpers.TruncateString(file.Path, 50))
 	}
 
 	fmt.Println("\nScorecards ingested successfully")
+	helpers.PrintSkipped(cmd.OutOrStdout(), listing.Skipped)
 	return nil
 }
 
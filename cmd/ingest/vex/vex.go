@@ -0,0 +1,91 @@
+package vex
+
+import (
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/bitbomdev/minefield/cmd/helpers"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	addr                string // Address of the minefield server
+	load                helpers.LoadFlags
+	ingestServiceClient apiv1connect.IngestServiceClient
+}
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	o.load.AddFlags(cmd)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.ingestServiceClient == nil {
+		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(
+			http.DefaultClient,
+			o.addr,
+		)
+	}
+	vexPath := args[0]
+
+	listing, err := helpers.ListFiles(vexPath, o.load.Options(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to ingest VEX: %w", err)
+	}
+	defer listing.Close()
+
+	unchanged := 0
+	for index, file := range listing.Files {
+		data, err := file.ReadAll()
+		if err != nil {
+			return err
+		}
+		req := connect.NewRequest(&apiv1.IngestVEXRequest{
+			Vex:       data,
+			Signature: file.Signature,
+		})
+		res, err := o.ingestServiceClient.IngestVEX(cmd.Context(), req)
+		if err != nil {
+			return fmt.Errorf("failed to ingest VEX document %s: %w", file.Path, err)
+		}
+		if res.Msg.GetUnchanged() {
+			unchanged++
+		}
+		// Clear the line by overwriting with spaces
+		fmt.Printf("\r\033[1;36m%-80s\033[0m", " ")
+		fmt.Printf("\r\033[1;36mIngested %d/%d VEX documents\033[0m | \033[1;34m%s\033[0m", index+1, len(listing.Files), helpers.TruncateString(file.Path, 50))
+	}
+
+	fmt.Println("\nVEX documents ingested successfully")
+	helpers.PrintSkipped(cmd.OutOrStdout(), listing.Skipped)
+	if unchanged > 0 {
+		fmt.Printf("%d VEX documents were unchanged since they were last ingested\n", unchanged)
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "vex [path to OpenVEX or CSAF VEX file/dir]",
+		Short: "Ingest VEX statements, so queries leave out the vulnerabilities they say don't affect a product",
+		Long: `Ingest OpenVEX and CSAF VEX documents.
+
+Queries and leaderboards leave out the vulnerabilities the latest statement about a product
+says it is not_affected by or fixed in, unless --include-suppressed is set. A statement about
+a dependency applies to the products that only reach the vulnerability through it.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
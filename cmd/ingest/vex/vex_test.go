@@ -0,0 +1,62 @@
+package vex
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	cmd := New()
+
+	assert.Equal(t, "vex [path to OpenVEX or CSAF VEX file/dir]", cmd.Use)
+	assert.NotNil(t, cmd.Flags().Lookup("addr"))
+	assert.True(t, cmd.DisableAutoGenTag)
+	assert.NoError(t, cmd.Args(nil, []string{"arg1"}))
+	assert.Error(t, cmd.Args(nil, nil))
+}
+
+func TestRun(t *testing.T) {
+	storage := graph.NewMockStorage()
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.openvex.json"), []byte(`{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/app",
+  "timestamp": "2024-05-01T00:00:00Z",
+  "statements": [{"vulnerability": {"name": "CVE-2024-0001"}, "products": [{"@id": "pkg:npm/app@1.0.0"}], "status": "fixed"}]
+}`), 0o600))
+
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", server.URL, dir})
+	require.NoError(t, cmd.Execute())
+
+	statements, err := ingest.VEXStatements(context.Background(), storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]ingest.VEXStatement{
+		"vex:https://example.com/vex/app": {{Vulnerability: "CVE-2024-0001", Product: "pkg:npm/app@1.0.0", Status: ingest.VEXFixed, Timestamp: "2024-05-01T00:00:00Z"}},
+	}, statements)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{}`), 0o600))
+	cmd = New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", server.URL, dir})
+	assert.Error(t, cmd.Execute())
+}
@@ -17,13 +17,14 @@ import (
 
 // options defines the command-line options for the custom command.
 type options struct {
-	all       bool
-	maxOutput int
-	showInfo  bool
-	saveQuery string
-	addr      string
-	output    string
-	client    apiv1connect.LeaderboardServiceClient
+	all               bool
+	maxOutput         int
+	showInfo          bool
+	saveQuery         string
+	addr              string
+	output            string
+	includeSuppressed bool
+	client            apiv1connect.LeaderboardServiceClient
 }
 
 // AddFlags adds command-line flags to the provided cobra command.
@@ -33,6 +34,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.showInfo, "show-info", true, "display the info column")
 	cmd.Flags().StringVarP(&o.addr, "addr", "a", "http://localhost:8089", "Address of the Minefield server")
 	cmd.Flags().StringVarP(&o.output, "output", "o", "table", "Output format (table or json)")
+	cmd.Flags().BoolVar(&o.includeSuppressed, "include-suppressed", false, "Include vulnerabilities VEX statements say don't affect a product")
 }
 
 // Run executes the custom command.
@@ -52,7 +54,8 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 
 	// Create and send the request
 	req := connect.NewRequest(&apiv1.CustomLeaderboardRequest{
-		Script: script,
+		Script:            script,
+		IncludeSuppressed: o.includeSuppressed,
 	})
 	res, err := o.client.CustomLeaderboard(ctx, req)
 	if err != nil {
@@ -279,6 +279,12 @@ func TestOptions_AddFlags(t *testing.T) {
 			defaultValue: "table",
 			usage:        "Output format (table or json)",
 		},
+		{
+			name:         "include-suppressed",
+			shorthand:    "",
+			defaultValue: false,
+			usage:        "Include vulnerabilities VEX statements say don't affect a product",
+		},
 	}
 
 	for _, tt := range tests {
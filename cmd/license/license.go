@@ -0,0 +1,25 @@
+package license
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+// New returns a new cobra command for the license command and its subcommands.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "license",
+		Short: "Check the licenses of the ingested libraries",
+		Long: `Check the licenses of the ingested libraries. Ingesting an SBOM links every library to a license
+node per license of its concluded license expression, or else of its declared ones, so
+"dependencies license <purl>" lists the licenses of a project and its dependencies.`,
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(NewPolicy())
+	return cmd
+}
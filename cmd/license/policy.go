@@ -0,0 +1,129 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/bitbomdev/minefield/gen/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// policyOptions for the policy command
+type policyOptions struct {
+	addr   string   // Address of the minefield server
+	allow  []string // Acceptable licenses
+	deny   []string // Licenses that are never acceptable
+	output string   // Output format
+	strict bool     // Fail if a project violates the policy
+
+	licenseServiceClient apiv1connect.LicenseServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *policyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+	cmd.Flags().StringSliceVar(&o.allow, "allow", nil, "SPDX IDs of the acceptable licenses, every license that isn't denied if empty")
+	cmd.Flags().StringSliceVar(&o.deny, "deny", nil, "SPDX IDs of the licenses that are never acceptable")
+	cmd.Flags().StringVar(&o.output, "output", "table", "output format (table or json)")
+	cmd.Flags().BoolVar(&o.strict, "strict", false, "exit with an error if a project violates the policy")
+}
+
+// Run evaluates the license policy and reports it per project.
+func (o *policyOptions) Run(cmd *cobra.Command, args []string) error {
+	if len(o.allow) == 0 && len(o.deny) == 0 {
+		return fmt.Errorf("a policy needs --allow or --deny licenses")
+	}
+	if o.licenseServiceClient == nil {
+		o.licenseServiceClient = apiv1connect.NewLicenseServiceClient(http.DefaultClient, o.addr)
+	}
+
+	res, err := o.licenseServiceClient.EvaluateLicensePolicy(cmd.Context(), connect.NewRequest(&apiv1.LicensePolicyRequest{
+		Allow:    o.allow,
+		Deny:     o.deny,
+		Projects: args,
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to evaluate license policy: %w", err)
+	}
+
+	switch o.output {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(res.Msg.Reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format reports as JSON: %w", err)
+		}
+		cmd.Println(string(jsonOutput))
+	case "table":
+		formatReports(cmd.OutOrStdout(), res.Msg.Reports)
+	default:
+		return fmt.Errorf("unknown output format: %s", o.output)
+	}
+
+	if o.strict {
+		for _, report := range res.Msg.Reports {
+			if len(report.Violations) > 0 {
+				return fmt.Errorf("%s violates the license policy", report.Project)
+			}
+		}
+	}
+	return nil
+}
+
+// formatReports formats the violations of every project into a table, and lists the projects
+// without any.
+func formatReports(w io.Writer, reports []*apiv1.LicensePolicyReport) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Project", "Library", "Expression", "Verdict", "Licenses"})
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+
+	var compliant []string
+	for _, report := range reports {
+		if len(report.Violations) == 0 {
+			compliant = append(compliant, report.Project)
+			continue
+		}
+		for _, violation := range report.Violations {
+			table.Append([]string{
+				report.Project,
+				violation.Library,
+				violation.Expression,
+				violation.Verdict,
+				strings.Join(violation.Licenses, ", "),
+			})
+		}
+	}
+	if table.NumLines() > 0 {
+		table.Render()
+	}
+
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s: %d libraries, %d unlicensed, %d violations\n", report.Project, report.Libraries, report.Unlicensed, len(report.Violations))
+	}
+	if len(compliant) > 0 {
+		fmt.Fprintf(w, "%d of %d projects comply with the license policy\n", len(compliant), len(reports))
+	}
+}
+
+// NewPolicy returns a new cobra command for the policy command.
+func NewPolicy() *cobra.Command {
+	o := &policyOptions{}
+	cmd := &cobra.Command{
+		Use:   "policy [project purls...]",
+		Short: "Evaluate a license policy on every root project, or the given ones",
+		Long: `Evaluate a license policy on every root project, a library no other library depends on, or the
+given ones. A library violates the policy if its license expression names a denied license,
+or, if licenses are allowed, can't be satisfied with allowed licenses only. A disjunction is
+satisfied by any of its choices.`,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,78 @@
+package license
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sbom = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {
+    "component": {"bom-ref": "app", "type": "application", "name": "app", "version": "1.0.0", "purl": "pkg:npm/app@1.0.0",
+      "licenses": [{"license": {"id": "MIT"}}]}
+  },
+  "components": [
+    {"bom-ref": "b", "type": "library", "name": "b", "version": "1.0.0", "purl": "pkg:npm/b@1.0.0",
+      "licenses": [{"expression": "MIT OR GPL-3.0-only"}]},
+    {"bom-ref": "c", "type": "library", "name": "c", "version": "1.0.0", "purl": "pkg:npm/c@1.0.0",
+      "licenses": [{"license": {"id": "AGPL-3.0-only"}}]}
+  ],
+  "dependencies": [{"ref": "app", "dependsOn": ["b", "c"]}]
+}`
+
+func run(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestPolicy(t *testing.T) {
+	storage := graph.NewMockStorage()
+	require.NoError(t, ingest.SBOM(context.Background(), storage, []byte(sbom)))
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewLicenseServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	out, err := run(t, New(), "policy", "--addr", server.URL, "--deny", "AGPL-3.0-only,GPL-3.0-only")
+	require.NoError(t, err)
+	assert.Contains(t, out, "pkg:npm/c@1.0.0")
+	assert.Contains(t, out, "denied")
+	assert.NotContains(t, out, "pkg:npm/b@1.0.0", "b may be used under MIT")
+	assert.Contains(t, out, "pkg:npm/app@1.0.0: 3 libraries, 0 unlicensed, 1 violations")
+
+	out, err = run(t, New(), "policy", "--addr", server.URL, "--allow", "MIT", "--output", "json", "pkg:npm/app@1.0.0")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"verdict": "not_allowed"`)
+	assert.Contains(t, out, `"AGPL-3.0-only"`)
+
+	_, err = run(t, New(), "policy", "--addr", server.URL, "--deny", "AGPL-3.0-only", "--strict")
+	assert.Error(t, err)
+	out, err = run(t, New(), "policy", "--addr", server.URL, "--deny", "BSD-4-Clause", "--strict")
+	require.NoError(t, err)
+	assert.Contains(t, out, "1 of 1 projects comply with the license policy")
+
+	_, err = run(t, New(), "policy", "--addr", server.URL)
+	assert.Error(t, err, "a policy needs licenses")
+	_, err = run(t, New(), "policy", "--addr", server.URL, "--deny", "MIT", "pkg:npm/unknown@1.0.0")
+	assert.Error(t, err)
+	_, err = run(t, New(), "policy", "--addr", server.URL, "--deny", "MIT", "--output", "xml")
+	assert.Error(t, err)
+}
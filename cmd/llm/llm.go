@@ -22,7 +22,7 @@ import (
 
 const PROMPT_TEMPLATE = `You are an AI assistant that helps users understand and work with a DSL (Domain Specific Language) for querying a graph database of supply chain security artifacts. You have access to documentation and examples about this DSL through the provided context.
 
-If the user asks for a DSL query, convert their natural language into the appropriate DSL script. The DSL uses keywords like: dependencies, dependents, library, vuln, xor, or, and.
+If the user asks for a DSL query, convert their natural language into the appropriate DSL script. The DSL uses keywords like: dependencies, dependents, library, vuln, where, xor, or, and.
 
 If the user asks general questions about the DSL or how it works, provide helpful explanations based on the context.
 
@@ -0,0 +1,101 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/migrations"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/spf13/cobra"
+)
+
+// options for the migrate command
+type options struct {
+	from      string // Storage to copy the graph from
+	to        string // Storage to copy the graph to
+	batchSize int    // Number of nodes copied at once
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *options) AddFlags(cmd *cobra.Command) {
+	backends := strings.Join(storages.Backends(), ", ")
+	cmd.Flags().StringVar(&o.from, "from", "", fmt.Sprintf("Storage to migrate from as <backend>:<location>, backends: %s (e.g. sqlite:minefield.db)", backends))
+	cmd.Flags().StringVar(&o.to, "to", "", fmt.Sprintf("Storage to migrate to as <backend>:<location>, backends: %s (e.g. redis:localhost:6379)", backends))
+	cmd.Flags().IntVar(&o.batchSize, "batch-size", storages.DefaultCopyBatchSize, "Number of nodes read and written at once")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+}
+
+// Run copies the graph from one storage to the other and verifies the copy.
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if o.batchSize <= 0 {
+		return fmt.Errorf("batch-size must be greater than zero")
+	}
+	if o.from == o.to {
+		return fmt.Errorf("--from and --to must be different storages")
+	}
+
+	src, err := storages.Open(o.from)
+	if err != nil {
+		return fmt.Errorf("failed to open source storage: %w", err)
+	}
+	dst, err := storages.Open(o.to)
+	if err != nil {
+		return fmt.Errorf("failed to open destination storage: %w", err)
+	}
+
+	// The graph is decoded and written again, which only works in the current format.
+	migrations.Register()
+	if err := storages.CheckSchema(ctx, src); err != nil {
+		return fmt.Errorf("source storage: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Migrating %s to %s\n", o.from, o.to)
+	if err := storages.CopyGraph(ctx, src, dst, o.batchSize); err != nil {
+		return fmt.Errorf("failed to migrate graph: %w", err)
+	}
+
+	srcSummary, err := storages.Summarize(ctx, src, o.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to summarize source storage: %w", err)
+	}
+	dstSummary, err := storages.Summarize(ctx, dst, o.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to summarize destination storage: %w", err)
+	}
+	printSummary(out, srcSummary)
+
+	if diffs := srcSummary.Diff(dstSummary); len(diffs) > 0 {
+		return fmt.Errorf("verification failed, source and destination differ:\n  %s", strings.Join(diffs, "\n  "))
+	}
+	fmt.Fprintln(out, "Migration verified, counts and checksums match")
+	return nil
+}
+
+func printSummary(out io.Writer, summary *storages.GraphSummary) {
+	fmt.Fprintf(out, "  nodes:       %d (sha256 %s)\n", summary.Nodes, summary.NodesChecksum)
+	fmt.Fprintf(out, "  caches:      %d (sha256 %s)\n", summary.Caches, summary.CachesChecksum)
+	fmt.Fprintf(out, "  cache stack: %d (sha256 %s)\n", summary.CacheStack, summary.CacheStackChecksum)
+	fmt.Fprintf(out, "  custom data: %d (sha256 %s)\n", summary.CustomData, summary.CustomDataChecksum)
+	fmt.Fprintf(out, "  ID counter:  %d\n", summary.IDCounter)
+}
+
+// New returns a new cobra command for the migrate command.
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy the graph from one storage backend to another",
+		Long: `Copy all nodes, caches, the cache stack, custom data and the ID counter from one
+storage backend to another, keeping node IDs, and verify the copy with counts and checksums.`,
+		Example:           "minefield migrate --from sqlite:minefield.db --to redis:localhost:6379",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/migrations"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	from := "sqlite:" + filepath.Join(dir, "from.db")
+	to := "sqlite:" + filepath.Join(dir, "to.db")
+
+	// The command registers the migrations before checking the schema, so must the setup.
+	migrations.Register()
+	src, err := storages.Open(from)
+	require.NoError(t, err)
+	require.NoError(t, storages.CheckSchema(context.Background(), src))
+	a, err := graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), src, b))
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "missing flags", args: []string{}, wantErr: true},
+		{name: "same storage", args: []string{"--from", from, "--to", from}, wantErr: true},
+		{name: "unknown backend", args: []string{"--from", from, "--to", "mysql:localhost"}, wantErr: true},
+		{name: "invalid batch size", args: []string{"--from", from, "--to", to, "--batch-size", "0"}, wantErr: true},
+		{name: "migrate", args: []string{"--from", from, "--to", to}},
+		{name: "destination not empty", args: []string{"--from", from, "--to", to}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := New()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "nodes:       2")
+			assert.Contains(t, out.String(), "Migration verified")
+		})
+	}
+
+	dst, err := storages.Open(to)
+	require.NoError(t, err)
+	node, err := dst.GetNode(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:npm/a@1.0.0", node.Name)
+	assert.True(t, node.Children.Contains(b.ID))
+}
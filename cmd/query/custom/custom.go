@@ -22,6 +22,7 @@ type options struct {
 	saveQuery          string
 	addr               string
 	output             string
+	includeSuppressed  bool
 	queryServiceClient apiv1connect.QueryServiceClient
 }
 
@@ -31,6 +32,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.showInfo, "show-info", true, "display the info column")
 	cmd.Flags().StringVar(&o.addr, "addr", "http://localhost:8089", "address of the minefield server")
 	cmd.Flags().StringVar(&o.output, "output", "table", "output format (table or json)")
+	cmd.Flags().BoolVar(&o.includeSuppressed, "include-suppressed", false, "include vulnerabilities VEX statements say don't affect a product")
 }
 
 // Run executes the custom command with the provided arguments.
@@ -52,7 +54,8 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 
 	ctx := cmd.Context()
 	req := connect.NewRequest(&apiv1.QueryRequest{
-		Script: script,
+		Script:            script,
+		IncludeSuppressed: o.includeSuppressed,
 	})
 
 	res, err := o.queryServiceClient.Query(ctx, req)
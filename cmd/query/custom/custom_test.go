@@ -100,6 +100,7 @@ func TestNewCommand(t *testing.T) {
 		{name: "show-info", defValue: "true"},
 		{name: "addr", defValue: "http://localhost:8089"},
 		{name: "output", defValue: "table"},
+		{name: "include-suppressed", defValue: "false"},
 	}
 
 	for _, flag := range flags {
@@ -46,11 +46,12 @@ func TestFormatTable(t *testing.T) {
 }
 
 type mockGraphServiceClient struct {
-	GetNodesByGlobFunc func(ctx context.Context, req *connect.Request[apiv1.GetNodesByGlobRequest]) (*connect.Response[apiv1.GetNodesByGlobResponse], error)
-	GetNodeFunc        func(ctx context.Context, req *connect.Request[apiv1.GetNodeRequest]) (*connect.Response[apiv1.GetNodeResponse], error)
-	GetNodeByNameFunc  func(ctx context.Context, req *connect.Request[apiv1.GetNodeByNameRequest]) (*connect.Response[apiv1.GetNodeByNameResponse], error)
-	AddNodeFunc        func(ctx context.Context, req *connect.Request[apiv1.AddNodeRequest]) (*connect.Response[apiv1.AddNodeResponse], error)
-	SetDependencyFunc  func(ctx context.Context, req *connect.Request[apiv1.SetDependencyRequest]) (*connect.Response[emptypb.Empty], error)
+	GetNodesByGlobFunc     func(ctx context.Context, req *connect.Request[apiv1.GetNodesByGlobRequest]) (*connect.Response[apiv1.GetNodesByGlobResponse], error)
+	GetNodeFunc            func(ctx context.Context, req *connect.Request[apiv1.GetNodeRequest]) (*connect.Response[apiv1.GetNodeResponse], error)
+	GetNodeByNameFunc      func(ctx context.Context, req *connect.Request[apiv1.GetNodeByNameRequest]) (*connect.Response[apiv1.GetNodeByNameResponse], error)
+	AddNodeFunc            func(ctx context.Context, req *connect.Request[apiv1.AddNodeRequest]) (*connect.Response[apiv1.AddNodeResponse], error)
+	SetDependencyFunc      func(ctx context.Context, req *connect.Request[apiv1.SetDependencyRequest]) (*connect.Response[emptypb.Empty], error)
+	UpdateNodeMetadataFunc func(ctx context.Context, req *connect.Request[apiv1.UpdateNodeMetadataRequest]) (*connect.Response[apiv1.UpdateNodeMetadataResponse], error)
 }
 
 func (m *mockGraphServiceClient) GetNodesByGlob(ctx context.Context, req *connect.Request[apiv1.GetNodesByGlobRequest]) (*connect.Response[apiv1.GetNodesByGlobResponse], error) {
@@ -73,6 +74,10 @@ func (m *mockGraphServiceClient) SetDependency(ctx context.Context, req *connect
 	return m.SetDependencyFunc(ctx, req)
 }
 
+func (m *mockGraphServiceClient) UpdateNodeMetadata(ctx context.Context, req *connect.Request[apiv1.UpdateNodeMetadataRequest]) (*connect.Response[apiv1.UpdateNodeMetadataResponse], error) {
+	return m.UpdateNodeMetadataFunc(ctx, req)
+}
+
 func TestRun(t *testing.T) {
 	tests := []struct {
 		name                string
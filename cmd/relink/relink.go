@@ -0,0 +1,58 @@
+package relink
+
+import (
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultAddr = "http://localhost:8089" // Default address of the minefield server
+)
+
+// options for the relink command
+type options struct {
+	addr string // Address of the minefield server
+
+	ingestServiceClient apiv1connect.IngestServiceClient
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.addr, "addr", DefaultAddr, "Address of the minefield server")
+}
+
+// Run executes the relink command with the provided arguments.
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.ingestServiceClient == nil {
+		o.ingestServiceClient = apiv1connect.NewIngestServiceClient(http.DefaultClient, o.addr)
+	}
+
+	res, err := o.ingestServiceClient.Relink(cmd.Context(), connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		return fmt.Errorf("failed to relink advisories: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Relinked %d advisories with %d links to library nodes\n", res.Msg.Advisories, res.Msg.Links)
+	return nil
+}
+
+// New returns a new cobra command for the relink command.
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "relink",
+		Short: "Re-match every stored advisory against the current graph",
+		Long: `Match every ingested OSV advisory against the library nodes currently in the graph,
+linking the affected versions to the vulnerability and removing links the advisories no longer
+assert, e.g. after version matching changed.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
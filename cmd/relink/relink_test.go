@@ -0,0 +1,51 @@
+package relink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	service "github.com/bitbomdev/minefield/api/v1"
+	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelink(t *testing.T) {
+	storage := graph.NewMockStorage()
+	vuln, err := os.ReadFile("../../testdata/osv-vulns/GHSA-cx63-2mw6-8hw5.json")
+	require.NoError(t, err)
+	require.NoError(t, ingest.Vulnerabilities(context.Background(), storage, vuln))
+	sbom, err := os.ReadFile("../../testdata/osv-sboms/google_agi.sbom.json")
+	require.NoError(t, err)
+	require.NoError(t, ingest.SBOM(context.Background(), storage, sbom))
+	_, contribution, err := ingest.GetContribution(context.Background(), storage, tools.OSVDocumentType+":GHSA-cx63-2mw6-8hw5")
+	require.NoError(t, err)
+	links := len(contribution.EdgeList())
+	require.NotZero(t, links, "the SBOM ingested after the advisory is linked")
+
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewIngestServiceHandler(service.NewService(storage, 1)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cmd := New()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--addr", server.URL})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, fmt.Sprintf("Relinked 1 advisories with %d links to library nodes\n", links), out.String())
+
+	cmd = New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", "http://127.0.0.1:0"})
+	assert.Error(t, cmd.Execute())
+}
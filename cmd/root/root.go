@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/bitbomdev/minefield/cmd/archive"
 	"github.com/bitbomdev/minefield/cmd/cache"
+	"github.com/bitbomdev/minefield/cmd/documents"
 	"github.com/bitbomdev/minefield/cmd/ingest"
 	"github.com/bitbomdev/minefield/cmd/leaderboard"
+	"github.com/bitbomdev/minefield/cmd/license"
+	"github.com/bitbomdev/minefield/cmd/migrate"
 	"github.com/bitbomdev/minefield/cmd/query"
+	"github.com/bitbomdev/minefield/cmd/relink"
 	"github.com/bitbomdev/minefield/cmd/server"
+	"github.com/bitbomdev/minefield/cmd/signing"
+	"github.com/bitbomdev/minefield/cmd/storage"
 	llm "github.com/bitbomdev/minefield/cmd/llm"
 	"github.com/spf13/cobra"
 )
@@ -56,5 +63,13 @@ func New() *cobra.Command {
 	rootCmd.AddCommand(leaderboard.New())
 	rootCmd.AddCommand(server.New())
 	rootCmd.AddCommand(llm.New())
+	rootCmd.AddCommand(migrate.New())
+	rootCmd.AddCommand(archive.NewExport())
+	rootCmd.AddCommand(archive.NewImport())
+	rootCmd.AddCommand(signing.New())
+	rootCmd.AddCommand(storage.New())
+	rootCmd.AddCommand(documents.New())
+	rootCmd.AddCommand(relink.New())
+	rootCmd.AddCommand(license.New())
 	return rootCmd
 }
@@ -16,6 +16,9 @@ import (
 	service "github.com/bitbomdev/minefield/api/v1"
 	"github.com/bitbomdev/minefield/gen/api/v1/apiv1connect"
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/bitbomdev/minefield/pkg/migrations"
 	"github.com/bitbomdev/minefield/pkg/storages"
 	chromadb "github.com/philippgille/chromem-go"
 	"github.com/rs/cors"
@@ -35,6 +38,18 @@ type options struct {
 	CORS         []string
 	UseOpenAILLM bool
 	VectorDBPath string
+	// RequireSigned makes the server refuse ingested payloads and imported archives that
+	// aren't signed by one of the TrustedKeys.
+	RequireSigned bool
+	TrustedKeys   []string
+	// StorageCacheSize is the number of decoded nodes and node caches kept in memory, 0
+	// disables the cache.
+	StorageCacheSize int
+	// MergeStrategies are type=strategy pairs deciding how the metadata of ingested nodes that
+	// already exist is merged.
+	MergeStrategies []string
+	// IdentityQualifiers are the purl qualifiers kept in node names.
+	IdentityQualifiers []string
 }
 
 const (
@@ -59,22 +74,40 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	)
 	cmd.Flags().BoolVar(&o.UseOpenAILLM, "use-openai-llm", false, "Use OpenAI LLM for graph analysis")
 	cmd.Flags().StringVar(&o.VectorDBPath, "vector-db-path", "./db", "Path to the vector database")
+	cmd.Flags().IntVar(&o.StorageCacheSize, "storage-cache-size", 0, "Number of decoded nodes and node caches to keep in an in-memory LRU in front of the storage (0 disables it)")
+	cmd.Flags().BoolVar(&o.RequireSigned, "require-signed", false, "Refuse unsigned or untrusted SBOM, vulnerability, scorecard and graph archive uploads")
+	cmd.Flags().StringSliceVar(&o.TrustedKeys, "trusted-key", nil, "Path to a PEM encoded ed25519 public key trusted to sign uploads (repeatable)")
+	cmd.Flags().StringSliceVar(&o.IdentityQualifiers, "purl-identity-qualifiers", purl.DefaultIdentityQualifiers, "Purl qualifiers that tell packages apart and are kept in node names, the others are dropped")
+	cmd.Flags().StringSliceVar(&o.MergeStrategies, "merge-strategy", nil, "Metadata merge strategy for a node type as type=strategy, the strategy being first-wins (default), last-wins or deep-merge (repeatable)")
 }
 
-func (o *options) ProvideStorage() (graph.Storage, error) {
+func (o *options) ProvideStorage(ctx context.Context) (graph.Storage, error) {
+	var storage graph.Storage
+	var err error
 	switch o.StorageType {
 	case redisStorageType:
-		return storages.NewRedisStorage(o.StorageAddr)
+		storage, err = storages.NewRedisStorage(o.StorageAddr)
 	case sqliteStorageType:
-		return storages.NewSQLStorage(o.StoragePath, o.UseInMemory)
+		storage, err = storages.NewSQLStorage(o.StoragePath, o.UseInMemory)
 	default:
 		return nil, fmt.Errorf("unknown storage type: %s", o.StorageType)
 	}
+	if err != nil {
+		return nil, err
+	}
+	migrations.Register()
+	if err := storages.CheckSchema(ctx, storage); err != nil {
+		return nil, err
+	}
+	if o.StorageCacheSize <= 0 {
+		return storage, nil
+	}
+	return storages.NewCachedStorage(storage, o.StorageCacheSize)
 }
 
-func (o *options) Run(_ *cobra.Command, _ []string) error {
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	var err error
-	o.storage, err = o.ProvideStorage()
+	o.storage, err = o.ProvideStorage(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("failed to initialize storage: %w", err)
 	}
@@ -101,6 +134,14 @@ func (o *options) PersistentPreRunE(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("storage-addr is required when using Redis (format: host:port)")
 	}
 
+	if o.RequireSigned && len(o.TrustedKeys) == 0 {
+		return fmt.Errorf("at least one trusted-key is required when using require-signed")
+	}
+
+	if o.StorageCacheSize < 0 {
+		return fmt.Errorf("storage-cache-size must not be negative")
+	}
+
 	return nil
 }
 
@@ -114,7 +155,24 @@ func (o *options) setupServer() (*http.Server, error) {
 		serviceAddr = defaultAddr
 	}
 
-	newService := service.NewService(o.storage, o.concurrency)
+	var serviceOpts []service.Option
+	if o.RequireSigned {
+		keyring, err := signing.LoadKeyring(o.TrustedKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trusted keys: %w", err)
+		}
+		serviceOpts = append(serviceOpts, service.WithRequiredSignatures(keyring))
+	}
+	policy, err := graph.ParseMergePolicy(o.MergeStrategies)
+	if err != nil {
+		return nil, err
+	}
+	serviceOpts = append(serviceOpts, service.WithMergePolicy(policy))
+	if o.IdentityQualifiers != nil {
+		serviceOpts = append(serviceOpts, service.WithIdentityQualifiers(o.IdentityQualifiers))
+	}
+
+	newService := service.NewService(o.storage, o.concurrency, serviceOpts...)
 	mux := http.NewServeMux()
 	path, handler := apiv1connect.NewQueryServiceHandler(newService)
 	mux.Handle(path, handler)
@@ -128,6 +186,14 @@ func (o *options) setupServer() (*http.Server, error) {
 	mux.Handle(path, handler)
 	path, handler = apiv1connect.NewIngestServiceHandler(newService)
 	mux.Handle(path, handler)
+	path, handler = apiv1connect.NewArchiveServiceHandler(newService)
+	mux.Handle(path, handler)
+	path, handler = apiv1connect.NewStatsServiceHandler(newService)
+	mux.Handle(path, handler)
+	path, handler = apiv1connect.NewProvenanceServiceHandler(newService)
+	mux.Handle(path, handler)
+	path, handler = apiv1connect.NewLicenseServiceHandler(newService)
+	mux.Handle(path, handler)
 
 	server := &http.Server{
 		Addr:    serviceAddr,
@@ -193,7 +259,7 @@ func (o *options) startServer(server *http.Server) error {
 			},
 			{
 				ID:      "10",
-				Content: "Ensure that all keywords are used correctly. The keywords are: dependencies, dependents, library, vuln, xor, or, and.",
+				Content: "Ensure that all keywords are used correctly. The keywords are: dependencies, dependents, library, vuln, where, xor, or, and.",
 			},
 			{
 				ID:      "11",
@@ -227,6 +293,10 @@ func (o *options) startServer(server *http.Server) error {
 				ID:      "18",
 				Content: "When glob seaching never assume the position of anything, so wrap everything can in ** on both sides.",
 			},
+			{
+				ID:      "19",
+				Content: "A query can end with a filter on the metadata of the nodes it returns: where, a field, one of = != > >= < <= and a value. Vulnerabilities have a severity_score from 0 to 10 and a severity_rating of none, low, medium, high or critical. For example, and only output the query: dependencies vuln pkg:A where severity_rating >= high.",
+			},
 		}, runtime.NumCPU())
 		if err != nil {
 			return fmt.Errorf("failed to add documents to ChromaDB: %w", err)
@@ -250,6 +320,10 @@ func (o *options) startServer(server *http.Server) error {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
+	if cached, ok := o.storage.(*storages.CachedStorage); ok {
+		stats := cached.Stats()
+		log.Printf("Storage cache hit rate: nodes %.2f, caches %.2f\n", stats.Nodes.HitRate(), stats.Caches.HitRate())
+	}
 	log.Println("Server gracefully stopped")
 	return nil
 }
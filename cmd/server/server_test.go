@@ -1,13 +1,19 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/bitbomdev/minefield/pkg/storages"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOptions_AddFlags(t *testing.T) {
@@ -132,6 +138,82 @@ func TestSetupServer(t *testing.T) {
 	}
 }
 
+func TestSetupServerRequireSigned(t *testing.T) {
+	public, _, err := signing.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	encoded, err := signing.EncodePublicKey(public)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "trusted.pub")
+	if err := os.WriteFile(keyPath, encoded, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	o := &options{
+		storage:       &mockStorage{},
+		concurrency:   10,
+		RequireSigned: true,
+		TrustedKeys:   []string{keyPath},
+	}
+	_, err = o.setupServer()
+	assert.NoError(t, err)
+
+	o.TrustedKeys = []string{filepath.Join(t.TempDir(), "missing.pub")}
+	_, err = o.setupServer()
+	assert.ErrorContains(t, err, "failed to load trusted keys")
+}
+
+func TestSetupServerMergeStrategy(t *testing.T) {
+	o := &options{
+		storage:         &mockStorage{},
+		concurrency:     10,
+		MergeStrategies: []string{"library=deep-merge", "scorecard=last-wins"},
+	}
+	_, err := o.setupServer()
+	assert.NoError(t, err)
+
+	o.MergeStrategies = []string{"library=newest"}
+	_, err = o.setupServer()
+	assert.ErrorContains(t, err, "unknown merge strategy")
+}
+
+func TestProvideStorageCache(t *testing.T) {
+	o := &options{StorageType: sqliteStorageType, UseInMemory: true}
+	storage, err := o.ProvideStorage(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, &storages.SQLStorage{}, storage)
+
+	o.StorageCacheSize = 100
+	storage, err = o.ProvideStorage(context.Background())
+	require.NoError(t, err)
+	cached, ok := storage.(*storages.CachedStorage)
+	require.True(t, ok, "expected a cached storage, got %T", storage)
+	assert.Equal(t, 100, cached.Stats().Nodes.Capacity)
+}
+
+func TestProvideStorageSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "minefield.db")
+	o := &options{StorageType: sqliteStorageType, StoragePath: path}
+	storage, err := o.ProvideStorage(context.Background())
+	require.NoError(t, err, "an empty storage is stamped with the current version")
+	version, err := storage.GetSchemaVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, storages.CurrentSchemaVersion(), version)
+
+	require.NoError(t, storage.SetSchemaVersion(context.Background(), storages.CurrentSchemaVersion()+1))
+	_, err = o.ProvideStorage(context.Background())
+	assert.ErrorIs(t, err, storages.ErrSchemaNewer)
+
+	require.NoError(t, storage.SetSchemaVersion(context.Background(), 0))
+	_, err = graph.AddNode(context.Background(), storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	_, err = o.ProvideStorage(context.Background())
+	assert.ErrorIs(t, err, storages.ErrSchemaOutdated)
+}
+
 func TestOptions_PersistentPreRunE(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -182,6 +264,36 @@ func TestOptions_PersistentPreRunE(t *testing.T) {
 			wantErr:      true,
 			errorMessage: `invalid storage-type "unsupported": must be one of [redis, sqlite]`,
 		},
+		{
+			name: "RequireSigned without TrustedKeys",
+			options: &options{
+				StorageType:   sqliteStorageType,
+				UseInMemory:   true,
+				RequireSigned: true,
+			},
+			wantErr:      true,
+			errorMessage: "at least one trusted-key is required when using require-signed",
+		},
+		{
+			name: "Negative StorageCacheSize",
+			options: &options{
+				StorageType:      sqliteStorageType,
+				UseInMemory:      true,
+				StorageCacheSize: -1,
+			},
+			wantErr:      true,
+			errorMessage: "storage-cache-size must not be negative",
+		},
+		{
+			name: "RequireSigned with TrustedKeys",
+			options: &options{
+				StorageType:   sqliteStorageType,
+				UseInMemory:   true,
+				RequireSigned: true,
+				TrustedKeys:   []string{"/path/to/key.pub"},
+			},
+			wantErr: false,
+		},
 	}
 
 	cmd := &cobra.Command{}
@@ -4,6 +4,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/bitbomdev/minefield/pkg/graph"
 	"github.com/google/wire"
 	"github.com/spf13/cobra"
@@ -18,5 +20,5 @@ func InitializeServerCommand(o *options) (*cobra.Command, error) {
 }
 
 func ProvideStorage(o *options) (graph.Storage, error) {
-	return o.ProvideStorage()
+	return o.ProvideStorage(context.Background())
 }
@@ -7,6 +7,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/bitbomdev/minefield/pkg/graph"
 	"github.com/spf13/cobra"
 )
@@ -28,5 +30,5 @@ func InitializeServerCommand(o *options) (*cobra.Command, error) {
 // wire.go:
 
 func ProvideStorage(o *options) (graph.Storage, error) {
-	return o.ProvideStorage()
+	return o.ProvideStorage(context.Background())
 }
@@ -0,0 +1,77 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	sig "github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	privateKeyExt = ".key"
+	publicKeyExt  = ".pub"
+)
+
+// keygenOptions for the keygen command
+type keygenOptions struct {
+	force bool // Overwrite existing key files
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *keygenOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.force, "force", false, "Overwrite existing key files")
+}
+
+// Run writes a new key pair to <prefix>.key and <prefix>.pub, where prefix is the only argument.
+func (o *keygenOptions) Run(cmd *cobra.Command, args []string) error {
+	privatePath, publicPath := args[0]+privateKeyExt, args[0]+publicKeyExt
+	if !o.force {
+		for _, path := range []string{privatePath, publicPath} {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to check %s: %w", path, err)
+			}
+		}
+	}
+
+	public, private, err := sig.GenerateKey()
+	if err != nil {
+		return err
+	}
+	encodedPrivate, err := sig.EncodePrivateKey(private)
+	if err != nil {
+		return err
+	}
+	encodedPublic, err := sig.EncodePublicKey(public)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(privatePath, encodedPrivate, 0o600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(publicPath, encodedPublic, 0o644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote private key to %s and public key to %s (key ID %s)\n", privatePath, publicPath, sig.KeyID(public))
+	return nil
+}
+
+// NewKeygen returns a new cobra command for the keygen command.
+func NewKeygen() *cobra.Command {
+	o := &keygenOptions{}
+	cmd := &cobra.Command{
+		Use:               "keygen [prefix]",
+		Short:             "Generate an ed25519 key pair for signing files",
+		Long:              "Generate an ed25519 key pair, written to <prefix>.key and <prefix>.pub. Keep the private key offline, and pass the public key to the server with --trusted-key.",
+		Example:           "minefield signing keygen minefield",
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
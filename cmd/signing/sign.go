@@ -0,0 +1,86 @@
+package signing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sig "github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+// signOptions for the sign command
+type signOptions struct {
+	key string // Path of the private key to sign with
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *signOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.key, "key", "", "Path to the PEM encoded ed25519 private key to sign with")
+	_ = cmd.MarkFlagRequired("key")
+}
+
+// Run signs every file given as an argument, and every file in the given directories, and
+// writes the signatures next to them.
+func (o *signOptions) Run(cmd *cobra.Command, args []string) error {
+	key, err := sig.LoadPrivateKey(o.key)
+	if err != nil {
+		return err
+	}
+	files, err := collectFiles(args)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		digest, err := sig.DigestFile(path)
+		if err != nil {
+			return err
+		}
+		signature, err := sig.SignDigest(key, digest)
+		if err != nil {
+			return fmt.Errorf("failed to sign %s: %w", path, err)
+		}
+		if err := os.WriteFile(path+sig.SignatureExt, signature, 0o644); err != nil {
+			return fmt.Errorf("failed to write signature of %s: %w", path, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Signed %s\n", path)
+	}
+	return nil
+}
+
+// collectFiles returns the files in paths, walking directories recursively. Signature files
+// are skipped.
+func collectFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.Type().IsRegular() && !strings.HasSuffix(path, sig.SignatureExt) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", root, err)
+		}
+	}
+	return files, nil
+}
+
+// NewSign returns a new cobra command for the sign command.
+func NewSign() *cobra.Command {
+	o := &signOptions{}
+	cmd := &cobra.Command{
+		Use:               "sign [path to file/dir]...",
+		Short:             "Sign files and write the signatures next to them",
+		Example:           "minefield signing sign --key minefield.key sboms/",
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
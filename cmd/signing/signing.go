@@ -0,0 +1,24 @@
+package signing
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command for the signing command and its subcommands.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signing",
+		Short: "Create keys and sign or verify files before moving them to a minefield server",
+		Long: `Create ed25519 keys and sign or verify SBOM, vulnerability and scorecard files or graph
+archives. Signatures are stored next to the signed file with a .sig suffix, and are sent along
+by the ingest and import commands. Servers started with --require-signed refuse files that
+aren't signed by one of their trusted keys.`,
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(NewKeygen())
+	cmd.AddCommand(NewSign())
+	cmd.AddCommand(NewVerify())
+	return cmd
+}
@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func run(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestKeygenSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "minefield")
+	out, err := run(t, New(), "keygen", prefix)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Wrote private key to "+prefix+".key")
+
+	info, err := os.Stat(prefix + ".key")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Existing keys are only replaced with --force.
+	_, err = run(t, New(), "keygen", prefix)
+	assert.ErrorContains(t, err, "already exists")
+
+	data := filepath.Join(dir, "data")
+	require.NoError(t, os.MkdirAll(filepath.Join(data, "nested"), 0o755))
+	sbom := filepath.Join(data, "sbom.json")
+	osv := filepath.Join(data, "nested", "osv.json")
+	require.NoError(t, os.WriteFile(sbom, []byte(`{"sbom":true}`), 0o644))
+	require.NoError(t, os.WriteFile(osv, []byte(`{"osv":true}`), 0o644))
+
+	out, err = run(t, New(), "sign", "--key", prefix+".key", data)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Signed "+sbom)
+	assert.Contains(t, out, "Signed "+osv)
+	assert.FileExists(t, sbom+".sig")
+	assert.FileExists(t, osv+".sig")
+
+	out, err = run(t, New(), "verify", "--trusted-key", prefix+".pub", data)
+	require.NoError(t, err)
+	assert.Contains(t, out, "OK     "+sbom)
+
+	// Any change to a signed file is detected.
+	require.NoError(t, os.WriteFile(osv, []byte(`{"osv":false}`), 0o644))
+	out, err = run(t, New(), "verify", "--trusted-key", prefix+".pub", data)
+	assert.ErrorContains(t, err, "1 of 2 files failed verification")
+	assert.Contains(t, out, "FAILED "+osv)
+
+	// Files signed by another key aren't trusted.
+	_, err = run(t, New(), "keygen", "--force", prefix)
+	require.NoError(t, err)
+	_, err = run(t, New(), "verify", "--trusted-key", prefix+".pub", sbom)
+	assert.ErrorContains(t, err, "1 of 1 files failed verification")
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "minefield")
+	_, err := run(t, New(), "keygen", prefix)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "sbom.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o644))
+	out, err := run(t, New(), "verify", "--trusted-key", prefix+".pub", path)
+	assert.Error(t, err)
+	assert.Contains(t, out, "payload is not signed")
+
+	_, err = run(t, New(), "sign", path)
+	assert.Error(t, err, "the key flag is required")
+}
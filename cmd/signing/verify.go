@@ -0,0 +1,73 @@
+package signing
+
+import (
+	"fmt"
+
+	sig "github.com/bitbomdev/minefield/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+// verifyOptions for the verify command
+type verifyOptions struct {
+	trustedKeys []string // Paths of the public keys trusted to sign the files
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *verifyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.trustedKeys, "trusted-key", nil, "Path to a PEM encoded ed25519 public key trusted to sign the files (repeatable)")
+	_ = cmd.MarkFlagRequired("trusted-key")
+}
+
+// Run verifies the signature next to every file given as an argument, and every file in the
+// given directories.
+func (o *verifyOptions) Run(cmd *cobra.Command, args []string) error {
+	keyring, err := sig.LoadKeyring(o.trustedKeys...)
+	if err != nil {
+		return err
+	}
+	files, err := collectFiles(args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, path := range files {
+		if err := verifyFile(keyring, path); err != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "FAILED %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "OK     %s\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, len(files))
+	}
+	return nil
+}
+
+func verifyFile(keyring sig.Keyring, path string) error {
+	signature, err := sig.ReadSignature(path)
+	if err != nil {
+		return err
+	}
+	digest, err := sig.DigestFile(path)
+	if err != nil {
+		return err
+	}
+	return keyring.VerifyDigest(digest, signature)
+}
+
+// NewVerify returns a new cobra command for the verify command.
+func NewVerify() *cobra.Command {
+	o := &verifyOptions{}
+	cmd := &cobra.Command{
+		Use:               "verify [path to file/dir]...",
+		Short:             "Verify the signatures next to files against trusted keys",
+		Example:           "minefield signing verify --trusted-key minefield.pub sboms/",
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
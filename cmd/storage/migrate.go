@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/migrations"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/spf13/cobra"
+)
+
+// migrateOptions for the storage migrate command
+type migrateOptions struct {
+	storage string // Storage to migrate
+	dryRun  bool   // Only list the pending migrations
+}
+
+// AddFlags adds command-line flags to the provided cobra command.
+func (o *migrateOptions) AddFlags(cmd *cobra.Command) {
+	backends := strings.Join(storages.Backends(), ", ")
+	cmd.Flags().StringVar(&o.storage, "storage", "", fmt.Sprintf("Storage to migrate as <backend>:<location>, backends: %s (e.g. sqlite:minefield.db)", backends))
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only list the pending migrations")
+	_ = cmd.MarkFlagRequired("storage")
+}
+
+// Run applies the pending schema migrations to the storage.
+func (o *migrateOptions) Run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	migrations.Register()
+	storage, err := storages.Open(o.storage)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	status, err := storages.GetSchemaStatus(ctx, storage)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Schema version %d, current version %d\n", status.Version, status.Current)
+	if len(status.Pending) == 0 {
+		fmt.Fprintln(out, "Storage is up to date")
+		return nil
+	}
+	if o.dryRun {
+		for _, migration := range status.Pending {
+			fmt.Fprintf(out, "  pending %d: %s\n", migration.Version, migration.Description)
+		}
+		return nil
+	}
+
+	applied, err := storages.MigrateSchema(ctx, storage)
+	for _, migration := range applied {
+		fmt.Fprintf(out, "  applied %d: %s\n", migration.Version, migration.Description)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate storage: %w", err)
+	}
+	fmt.Fprintf(out, "Storage migrated to schema version %d\n", status.Current)
+	return nil
+}
+
+// NewMigrate returns a new cobra command for the storage migrate command.
+func NewMigrate() *cobra.Command {
+	o := &migrateOptions{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the data in a storage to the current schema version",
+		Long: `Apply the pending schema migrations to the data in a storage, in order. The schema version
+is recorded after every migration, so an interrupted migration continues where it stopped.
+Stop the server using the storage first.`,
+		Example:           "minefield storage migrate --storage redis:localhost:6379",
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
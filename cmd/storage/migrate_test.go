@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	uri := "sqlite:" + filepath.Join(t.TempDir(), "minefield.db")
+	storage, err := storages.Open(uri)
+	require.NoError(t, err)
+	_, err = graph.AddNode(context.Background(), storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	require.ErrorIs(t, storages.CheckSchema(context.Background(), storage), storages.ErrSchemaOutdated)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "missing storage", args: []string{}, wantErr: true},
+		{name: "unknown backend", args: []string{"--storage", "mysql:localhost"}, wantErr: true},
+		{name: "dry run", args: []string{"--storage", uri, "--dry-run"}, want: []string{"Schema version 0", "pending 1:"}},
+		{name: "migrate", args: []string{"--storage", uri}, want: []string{"applied 1:", "migrated to schema version"}},
+		{name: "up to date", args: []string{"--storage", uri}, want: []string{"Storage is up to date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := New()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+			cmd.SetArgs(append([]string{"migrate"}, tt.args...))
+			err := cmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+
+	assert.NoError(t, storages.CheckSchema(context.Background(), storage))
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	uri := "sqlite:" + filepath.Join(t.TempDir(), "minefield.db")
+	storage, err := storages.Open(uri)
+	require.NoError(t, err)
+	require.NoError(t, storage.SetSchemaVersion(context.Background(), storages.CurrentSchemaVersion()+1))
+
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"migrate", "--storage", uri})
+	assert.ErrorIs(t, cmd.Execute(), storages.ErrSchemaNewer)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command for the storage command and its subcommands.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Maintain the data in a storage backend",
+		Long: `Maintain the data in a storage backend. Every backend records the schema version of the
+data it holds, and the server refuses to start until outdated data has been migrated.`,
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(NewMigrate())
+	return cmd
+}
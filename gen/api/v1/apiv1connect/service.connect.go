@@ -32,6 +32,14 @@ const (
 	GraphServiceName = "api.v1.GraphService"
 	// IngestServiceName is the fully-qualified name of the IngestService service.
 	IngestServiceName = "api.v1.IngestService"
+	// ArchiveServiceName is the fully-qualified name of the ArchiveService service.
+	ArchiveServiceName = "api.v1.ArchiveService"
+	// StatsServiceName is the fully-qualified name of the StatsService service.
+	StatsServiceName = "api.v1.StatsService"
+	// ProvenanceServiceName is the fully-qualified name of the ProvenanceService service.
+	ProvenanceServiceName = "api.v1.ProvenanceService"
+	// LicenseServiceName is the fully-qualified name of the LicenseService service.
+	LicenseServiceName = "api.v1.LicenseService"
 	// HealthServiceName is the fully-qualified name of the HealthService service.
 	HealthServiceName = "api.v1.HealthService"
 )
@@ -69,41 +77,95 @@ const (
 	// GraphServiceSetDependencyProcedure is the fully-qualified name of the GraphService's
 	// SetDependency RPC.
 	GraphServiceSetDependencyProcedure = "/api.v1.GraphService/SetDependency"
+	// GraphServiceUpdateNodeMetadataProcedure is the fully-qualified name of the GraphService's
+	// UpdateNodeMetadata RPC.
+	GraphServiceUpdateNodeMetadataProcedure = "/api.v1.GraphService/UpdateNodeMetadata"
 	// IngestServiceIngestSBOMProcedure is the fully-qualified name of the IngestService's IngestSBOM
 	// RPC.
 	IngestServiceIngestSBOMProcedure = "/api.v1.IngestService/IngestSBOM"
+	// IngestServiceIngestSBOMStreamProcedure is the fully-qualified name of the IngestService's
+	// IngestSBOMStream RPC.
+	IngestServiceIngestSBOMStreamProcedure = "/api.v1.IngestService/IngestSBOMStream"
 	// IngestServiceIngestVulnerabilityProcedure is the fully-qualified name of the IngestService's
 	// IngestVulnerability RPC.
 	IngestServiceIngestVulnerabilityProcedure = "/api.v1.IngestService/IngestVulnerability"
+	// IngestServiceIngestVulnerabilityArchiveProcedure is the fully-qualified name of the
+	// IngestService's IngestVulnerabilityArchive RPC.
+	IngestServiceIngestVulnerabilityArchiveProcedure = "/api.v1.IngestService/IngestVulnerabilityArchive"
+	// IngestServiceRelinkProcedure is the fully-qualified name of the IngestService's Relink RPC.
+	IngestServiceRelinkProcedure = "/api.v1.IngestService/Relink"
 	// IngestServiceIngestScorecardProcedure is the fully-qualified name of the IngestService's
 	// IngestScorecard RPC.
 	IngestServiceIngestScorecardProcedure = "/api.v1.IngestService/IngestScorecard"
+	// IngestServiceIngestVEXProcedure is the fully-qualified name of the IngestService's IngestVEX RPC.
+	IngestServiceIngestVEXProcedure = "/api.v1.IngestService/IngestVEX"
+	// IngestServiceIngestLockfileProcedure is the fully-qualified name of the IngestService's
+	// IngestLockfile RPC.
+	IngestServiceIngestLockfileProcedure = "/api.v1.IngestService/IngestLockfile"
+	// ArchiveServiceExportGraphProcedure is the fully-qualified name of the ArchiveService's
+	// ExportGraph RPC.
+	ArchiveServiceExportGraphProcedure = "/api.v1.ArchiveService/ExportGraph"
+	// ArchiveServiceImportGraphProcedure is the fully-qualified name of the ArchiveService's
+	// ImportGraph RPC.
+	ArchiveServiceImportGraphProcedure = "/api.v1.ArchiveService/ImportGraph"
+	// StatsServiceGetStorageCacheStatsProcedure is the fully-qualified name of the StatsService's
+	// GetStorageCacheStats RPC.
+	StatsServiceGetStorageCacheStatsProcedure = "/api.v1.StatsService/GetStorageCacheStats"
+	// ProvenanceServiceListDocumentsProcedure is the fully-qualified name of the ProvenanceService's
+	// ListDocuments RPC.
+	ProvenanceServiceListDocumentsProcedure = "/api.v1.ProvenanceService/ListDocuments"
+	// ProvenanceServiceGetDocumentContributionProcedure is the fully-qualified name of the
+	// ProvenanceService's GetDocumentContribution RPC.
+	ProvenanceServiceGetDocumentContributionProcedure = "/api.v1.ProvenanceService/GetDocumentContribution"
+	// ProvenanceServiceGetNodeDocumentsProcedure is the fully-qualified name of the ProvenanceService's
+	// GetNodeDocuments RPC.
+	ProvenanceServiceGetNodeDocumentsProcedure = "/api.v1.ProvenanceService/GetNodeDocuments"
+	// LicenseServiceEvaluateLicensePolicyProcedure is the fully-qualified name of the LicenseService's
+	// EvaluateLicensePolicy RPC.
+	LicenseServiceEvaluateLicensePolicyProcedure = "/api.v1.LicenseService/EvaluateLicensePolicy"
 	// HealthServiceCheckProcedure is the fully-qualified name of the HealthService's Check RPC.
 	HealthServiceCheckProcedure = "/api.v1.HealthService/Check"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	queryServiceServiceDescriptor                       = v1.File_api_v1_service_proto.Services().ByName("QueryService")
-	queryServiceQueryMethodDescriptor                   = queryServiceServiceDescriptor.Methods().ByName("Query")
-	cacheServiceServiceDescriptor                       = v1.File_api_v1_service_proto.Services().ByName("CacheService")
-	cacheServiceCacheMethodDescriptor                   = cacheServiceServiceDescriptor.Methods().ByName("Cache")
-	cacheServiceClearMethodDescriptor                   = cacheServiceServiceDescriptor.Methods().ByName("Clear")
-	leaderboardServiceServiceDescriptor                 = v1.File_api_v1_service_proto.Services().ByName("LeaderboardService")
-	leaderboardServiceCustomLeaderboardMethodDescriptor = leaderboardServiceServiceDescriptor.Methods().ByName("CustomLeaderboard")
-	leaderboardServiceAllKeysMethodDescriptor           = leaderboardServiceServiceDescriptor.Methods().ByName("AllKeys")
-	graphServiceServiceDescriptor                       = v1.File_api_v1_service_proto.Services().ByName("GraphService")
-	graphServiceGetNodeMethodDescriptor                 = graphServiceServiceDescriptor.Methods().ByName("GetNode")
-	graphServiceGetNodesByGlobMethodDescriptor          = graphServiceServiceDescriptor.Methods().ByName("GetNodesByGlob")
-	graphServiceGetNodeByNameMethodDescriptor           = graphServiceServiceDescriptor.Methods().ByName("GetNodeByName")
-	graphServiceAddNodeMethodDescriptor                 = graphServiceServiceDescriptor.Methods().ByName("AddNode")
-	graphServiceSetDependencyMethodDescriptor           = graphServiceServiceDescriptor.Methods().ByName("SetDependency")
-	ingestServiceServiceDescriptor                      = v1.File_api_v1_service_proto.Services().ByName("IngestService")
-	ingestServiceIngestSBOMMethodDescriptor             = ingestServiceServiceDescriptor.Methods().ByName("IngestSBOM")
-	ingestServiceIngestVulnerabilityMethodDescriptor    = ingestServiceServiceDescriptor.Methods().ByName("IngestVulnerability")
-	ingestServiceIngestScorecardMethodDescriptor        = ingestServiceServiceDescriptor.Methods().ByName("IngestScorecard")
-	healthServiceServiceDescriptor                      = v1.File_api_v1_service_proto.Services().ByName("HealthService")
-	healthServiceCheckMethodDescriptor                  = healthServiceServiceDescriptor.Methods().ByName("Check")
+	queryServiceServiceDescriptor                            = v1.File_api_v1_service_proto.Services().ByName("QueryService")
+	queryServiceQueryMethodDescriptor                        = queryServiceServiceDescriptor.Methods().ByName("Query")
+	cacheServiceServiceDescriptor                            = v1.File_api_v1_service_proto.Services().ByName("CacheService")
+	cacheServiceCacheMethodDescriptor                        = cacheServiceServiceDescriptor.Methods().ByName("Cache")
+	cacheServiceClearMethodDescriptor                        = cacheServiceServiceDescriptor.Methods().ByName("Clear")
+	leaderboardServiceServiceDescriptor                      = v1.File_api_v1_service_proto.Services().ByName("LeaderboardService")
+	leaderboardServiceCustomLeaderboardMethodDescriptor      = leaderboardServiceServiceDescriptor.Methods().ByName("CustomLeaderboard")
+	leaderboardServiceAllKeysMethodDescriptor                = leaderboardServiceServiceDescriptor.Methods().ByName("AllKeys")
+	graphServiceServiceDescriptor                            = v1.File_api_v1_service_proto.Services().ByName("GraphService")
+	graphServiceGetNodeMethodDescriptor                      = graphServiceServiceDescriptor.Methods().ByName("GetNode")
+	graphServiceGetNodesByGlobMethodDescriptor               = graphServiceServiceDescriptor.Methods().ByName("GetNodesByGlob")
+	graphServiceGetNodeByNameMethodDescriptor                = graphServiceServiceDescriptor.Methods().ByName("GetNodeByName")
+	graphServiceAddNodeMethodDescriptor                      = graphServiceServiceDescriptor.Methods().ByName("AddNode")
+	graphServiceSetDependencyMethodDescriptor                = graphServiceServiceDescriptor.Methods().ByName("SetDependency")
+	graphServiceUpdateNodeMetadataMethodDescriptor           = graphServiceServiceDescriptor.Methods().ByName("UpdateNodeMetadata")
+	ingestServiceServiceDescriptor                           = v1.File_api_v1_service_proto.Services().ByName("IngestService")
+	ingestServiceIngestSBOMMethodDescriptor                  = ingestServiceServiceDescriptor.Methods().ByName("IngestSBOM")
+	ingestServiceIngestSBOMStreamMethodDescriptor            = ingestServiceServiceDescriptor.Methods().ByName("IngestSBOMStream")
+	ingestServiceIngestVulnerabilityMethodDescriptor         = ingestServiceServiceDescriptor.Methods().ByName("IngestVulnerability")
+	ingestServiceIngestVulnerabilityArchiveMethodDescriptor  = ingestServiceServiceDescriptor.Methods().ByName("IngestVulnerabilityArchive")
+	ingestServiceRelinkMethodDescriptor                      = ingestServiceServiceDescriptor.Methods().ByName("Relink")
+	ingestServiceIngestScorecardMethodDescriptor             = ingestServiceServiceDescriptor.Methods().ByName("IngestScorecard")
+	ingestServiceIngestVEXMethodDescriptor                   = ingestServiceServiceDescriptor.Methods().ByName("IngestVEX")
+	ingestServiceIngestLockfileMethodDescriptor              = ingestServiceServiceDescriptor.Methods().ByName("IngestLockfile")
+	archiveServiceServiceDescriptor                          = v1.File_api_v1_service_proto.Services().ByName("ArchiveService")
+	archiveServiceExportGraphMethodDescriptor                = archiveServiceServiceDescriptor.Methods().ByName("ExportGraph")
+	archiveServiceImportGraphMethodDescriptor                = archiveServiceServiceDescriptor.Methods().ByName("ImportGraph")
+	statsServiceServiceDescriptor                            = v1.File_api_v1_service_proto.Services().ByName("StatsService")
+	statsServiceGetStorageCacheStatsMethodDescriptor         = statsServiceServiceDescriptor.Methods().ByName("GetStorageCacheStats")
+	provenanceServiceServiceDescriptor                       = v1.File_api_v1_service_proto.Services().ByName("ProvenanceService")
+	provenanceServiceListDocumentsMethodDescriptor           = provenanceServiceServiceDescriptor.Methods().ByName("ListDocuments")
+	provenanceServiceGetDocumentContributionMethodDescriptor = provenanceServiceServiceDescriptor.Methods().ByName("GetDocumentContribution")
+	provenanceServiceGetNodeDocumentsMethodDescriptor        = provenanceServiceServiceDescriptor.Methods().ByName("GetNodeDocuments")
+	licenseServiceServiceDescriptor                          = v1.File_api_v1_service_proto.Services().ByName("LicenseService")
+	licenseServiceEvaluateLicensePolicyMethodDescriptor      = licenseServiceServiceDescriptor.Methods().ByName("EvaluateLicensePolicy")
+	healthServiceServiceDescriptor                           = v1.File_api_v1_service_proto.Services().ByName("HealthService")
+	healthServiceCheckMethodDescriptor                       = healthServiceServiceDescriptor.Methods().ByName("Check")
 )
 
 // QueryServiceClient is a client for the api.v1.QueryService service.
@@ -369,6 +431,7 @@ type GraphServiceClient interface {
 	GetNodeByName(context.Context, *connect.Request[v1.GetNodeByNameRequest]) (*connect.Response[v1.GetNodeByNameResponse], error)
 	AddNode(context.Context, *connect.Request[v1.AddNodeRequest]) (*connect.Response[v1.AddNodeResponse], error)
 	SetDependency(context.Context, *connect.Request[v1.SetDependencyRequest]) (*connect.Response[emptypb.Empty], error)
+	UpdateNodeMetadata(context.Context, *connect.Request[v1.UpdateNodeMetadataRequest]) (*connect.Response[v1.UpdateNodeMetadataResponse], error)
 }
 
 // NewGraphServiceClient constructs a client for the api.v1.GraphService service. By default, it
@@ -411,16 +474,23 @@ func NewGraphServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			connect.WithSchema(graphServiceSetDependencyMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		updateNodeMetadata: connect.NewClient[v1.UpdateNodeMetadataRequest, v1.UpdateNodeMetadataResponse](
+			httpClient,
+			baseURL+GraphServiceUpdateNodeMetadataProcedure,
+			connect.WithSchema(graphServiceUpdateNodeMetadataMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // graphServiceClient implements GraphServiceClient.
 type graphServiceClient struct {
-	getNode        *connect.Client[v1.GetNodeRequest, v1.GetNodeResponse]
-	getNodesByGlob *connect.Client[v1.GetNodesByGlobRequest, v1.GetNodesByGlobResponse]
-	getNodeByName  *connect.Client[v1.GetNodeByNameRequest, v1.GetNodeByNameResponse]
-	addNode        *connect.Client[v1.AddNodeRequest, v1.AddNodeResponse]
-	setDependency  *connect.Client[v1.SetDependencyRequest, emptypb.Empty]
+	getNode            *connect.Client[v1.GetNodeRequest, v1.GetNodeResponse]
+	getNodesByGlob     *connect.Client[v1.GetNodesByGlobRequest, v1.GetNodesByGlobResponse]
+	getNodeByName      *connect.Client[v1.GetNodeByNameRequest, v1.GetNodeByNameResponse]
+	addNode            *connect.Client[v1.AddNodeRequest, v1.AddNodeResponse]
+	setDependency      *connect.Client[v1.SetDependencyRequest, emptypb.Empty]
+	updateNodeMetadata *connect.Client[v1.UpdateNodeMetadataRequest, v1.UpdateNodeMetadataResponse]
 }
 
 // GetNode calls api.v1.GraphService.GetNode.
@@ -448,6 +518,11 @@ func (c *graphServiceClient) SetDependency(ctx context.Context, req *connect.Req
 	return c.setDependency.CallUnary(ctx, req)
 }
 
+// UpdateNodeMetadata calls api.v1.GraphService.UpdateNodeMetadata.
+func (c *graphServiceClient) UpdateNodeMetadata(ctx context.Context, req *connect.Request[v1.UpdateNodeMetadataRequest]) (*connect.Response[v1.UpdateNodeMetadataResponse], error) {
+	return c.updateNodeMetadata.CallUnary(ctx, req)
+}
+
 // GraphServiceHandler is an implementation of the api.v1.GraphService service.
 type GraphServiceHandler interface {
 	GetNode(context.Context, *connect.Request[v1.GetNodeRequest]) (*connect.Response[v1.GetNodeResponse], error)
@@ -455,6 +530,7 @@ type GraphServiceHandler interface {
 	GetNodeByName(context.Context, *connect.Request[v1.GetNodeByNameRequest]) (*connect.Response[v1.GetNodeByNameResponse], error)
 	AddNode(context.Context, *connect.Request[v1.AddNodeRequest]) (*connect.Response[v1.AddNodeResponse], error)
 	SetDependency(context.Context, *connect.Request[v1.SetDependencyRequest]) (*connect.Response[emptypb.Empty], error)
+	UpdateNodeMetadata(context.Context, *connect.Request[v1.UpdateNodeMetadataRequest]) (*connect.Response[v1.UpdateNodeMetadataResponse], error)
 }
 
 // NewGraphServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -493,6 +569,12 @@ func NewGraphServiceHandler(svc GraphServiceHandler, opts ...connect.HandlerOpti
 		connect.WithSchema(graphServiceSetDependencyMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	graphServiceUpdateNodeMetadataHandler := connect.NewUnaryHandler(
+		GraphServiceUpdateNodeMetadataProcedure,
+		svc.UpdateNodeMetadata,
+		connect.WithSchema(graphServiceUpdateNodeMetadataMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.GraphService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case GraphServiceGetNodeProcedure:
@@ -505,6 +587,8 @@ func NewGraphServiceHandler(svc GraphServiceHandler, opts ...connect.HandlerOpti
 			graphServiceAddNodeHandler.ServeHTTP(w, r)
 		case GraphServiceSetDependencyProcedure:
 			graphServiceSetDependencyHandler.ServeHTTP(w, r)
+		case GraphServiceUpdateNodeMetadataProcedure:
+			graphServiceUpdateNodeMetadataHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -534,11 +618,20 @@ func (UnimplementedGraphServiceHandler) SetDependency(context.Context, *connect.
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.GraphService.SetDependency is not implemented"))
 }
 
+func (UnimplementedGraphServiceHandler) UpdateNodeMetadata(context.Context, *connect.Request[v1.UpdateNodeMetadataRequest]) (*connect.Response[v1.UpdateNodeMetadataResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.GraphService.UpdateNodeMetadata is not implemented"))
+}
+
 // IngestServiceClient is a client for the api.v1.IngestService service.
 type IngestServiceClient interface {
-	IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[emptypb.Empty], error)
-	IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[emptypb.Empty], error)
-	IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[emptypb.Empty], error)
+	IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestSBOMStream(context.Context) *connect.ClientStreamForClient[v1.IngestSBOMChunk, v1.IngestResponse]
+	IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestVulnerabilityArchive(context.Context) *connect.ClientStreamForClient[v1.IngestVulnerabilityArchiveChunk, v1.IngestVulnerabilityArchiveResponse]
+	Relink(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.RelinkResponse], error)
+	IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestVEX(context.Context, *connect.Request[v1.IngestVEXRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestLockfile(context.Context, *connect.Request[v1.IngestLockfileRequest]) (*connect.Response[v1.IngestResponse], error)
 }
 
 // NewIngestServiceClient constructs a client for the api.v1.IngestService service. By default, it
@@ -551,54 +644,119 @@ type IngestServiceClient interface {
 func NewIngestServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) IngestServiceClient {
 	baseURL = strings.TrimRight(baseURL, "/")
 	return &ingestServiceClient{
-		ingestSBOM: connect.NewClient[v1.IngestSBOMRequest, emptypb.Empty](
+		ingestSBOM: connect.NewClient[v1.IngestSBOMRequest, v1.IngestResponse](
 			httpClient,
 			baseURL+IngestServiceIngestSBOMProcedure,
 			connect.WithSchema(ingestServiceIngestSBOMMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
-		ingestVulnerability: connect.NewClient[v1.IngestVulnerabilityRequest, emptypb.Empty](
+		ingestSBOMStream: connect.NewClient[v1.IngestSBOMChunk, v1.IngestResponse](
+			httpClient,
+			baseURL+IngestServiceIngestSBOMStreamProcedure,
+			connect.WithSchema(ingestServiceIngestSBOMStreamMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		ingestVulnerability: connect.NewClient[v1.IngestVulnerabilityRequest, v1.IngestResponse](
 			httpClient,
 			baseURL+IngestServiceIngestVulnerabilityProcedure,
 			connect.WithSchema(ingestServiceIngestVulnerabilityMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
-		ingestScorecard: connect.NewClient[v1.IngestScorecardRequest, emptypb.Empty](
+		ingestVulnerabilityArchive: connect.NewClient[v1.IngestVulnerabilityArchiveChunk, v1.IngestVulnerabilityArchiveResponse](
+			httpClient,
+			baseURL+IngestServiceIngestVulnerabilityArchiveProcedure,
+			connect.WithSchema(ingestServiceIngestVulnerabilityArchiveMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		relink: connect.NewClient[emptypb.Empty, v1.RelinkResponse](
+			httpClient,
+			baseURL+IngestServiceRelinkProcedure,
+			connect.WithSchema(ingestServiceRelinkMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		ingestScorecard: connect.NewClient[v1.IngestScorecardRequest, v1.IngestResponse](
 			httpClient,
 			baseURL+IngestServiceIngestScorecardProcedure,
 			connect.WithSchema(ingestServiceIngestScorecardMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		ingestVEX: connect.NewClient[v1.IngestVEXRequest, v1.IngestResponse](
+			httpClient,
+			baseURL+IngestServiceIngestVEXProcedure,
+			connect.WithSchema(ingestServiceIngestVEXMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		ingestLockfile: connect.NewClient[v1.IngestLockfileRequest, v1.IngestResponse](
+			httpClient,
+			baseURL+IngestServiceIngestLockfileProcedure,
+			connect.WithSchema(ingestServiceIngestLockfileMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // ingestServiceClient implements IngestServiceClient.
 type ingestServiceClient struct {
-	ingestSBOM          *connect.Client[v1.IngestSBOMRequest, emptypb.Empty]
-	ingestVulnerability *connect.Client[v1.IngestVulnerabilityRequest, emptypb.Empty]
-	ingestScorecard     *connect.Client[v1.IngestScorecardRequest, emptypb.Empty]
+	ingestSBOM                 *connect.Client[v1.IngestSBOMRequest, v1.IngestResponse]
+	ingestSBOMStream           *connect.Client[v1.IngestSBOMChunk, v1.IngestResponse]
+	ingestVulnerability        *connect.Client[v1.IngestVulnerabilityRequest, v1.IngestResponse]
+	ingestVulnerabilityArchive *connect.Client[v1.IngestVulnerabilityArchiveChunk, v1.IngestVulnerabilityArchiveResponse]
+	relink                     *connect.Client[emptypb.Empty, v1.RelinkResponse]
+	ingestScorecard            *connect.Client[v1.IngestScorecardRequest, v1.IngestResponse]
+	ingestVEX                  *connect.Client[v1.IngestVEXRequest, v1.IngestResponse]
+	ingestLockfile             *connect.Client[v1.IngestLockfileRequest, v1.IngestResponse]
 }
 
 // IngestSBOM calls api.v1.IngestService.IngestSBOM.
-func (c *ingestServiceClient) IngestSBOM(ctx context.Context, req *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[emptypb.Empty], error) {
+func (c *ingestServiceClient) IngestSBOM(ctx context.Context, req *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return c.ingestSBOM.CallUnary(ctx, req)
 }
 
+// IngestSBOMStream calls api.v1.IngestService.IngestSBOMStream.
+func (c *ingestServiceClient) IngestSBOMStream(ctx context.Context) *connect.ClientStreamForClient[v1.IngestSBOMChunk, v1.IngestResponse] {
+	return c.ingestSBOMStream.CallClientStream(ctx)
+}
+
 // IngestVulnerability calls api.v1.IngestService.IngestVulnerability.
-func (c *ingestServiceClient) IngestVulnerability(ctx context.Context, req *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[emptypb.Empty], error) {
+func (c *ingestServiceClient) IngestVulnerability(ctx context.Context, req *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return c.ingestVulnerability.CallUnary(ctx, req)
 }
 
+// IngestVulnerabilityArchive calls api.v1.IngestService.IngestVulnerabilityArchive.
+func (c *ingestServiceClient) IngestVulnerabilityArchive(ctx context.Context) *connect.ClientStreamForClient[v1.IngestVulnerabilityArchiveChunk, v1.IngestVulnerabilityArchiveResponse] {
+	return c.ingestVulnerabilityArchive.CallClientStream(ctx)
+}
+
+// Relink calls api.v1.IngestService.Relink.
+func (c *ingestServiceClient) Relink(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.RelinkResponse], error) {
+	return c.relink.CallUnary(ctx, req)
+}
+
 // IngestScorecard calls api.v1.IngestService.IngestScorecard.
-func (c *ingestServiceClient) IngestScorecard(ctx context.Context, req *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[emptypb.Empty], error) {
+func (c *ingestServiceClient) IngestScorecard(ctx context.Context, req *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return c.ingestScorecard.CallUnary(ctx, req)
 }
 
+// IngestVEX calls api.v1.IngestService.IngestVEX.
+func (c *ingestServiceClient) IngestVEX(ctx context.Context, req *connect.Request[v1.IngestVEXRequest]) (*connect.Response[v1.IngestResponse], error) {
+	return c.ingestVEX.CallUnary(ctx, req)
+}
+
+// IngestLockfile calls api.v1.IngestService.IngestLockfile.
+func (c *ingestServiceClient) IngestLockfile(ctx context.Context, req *connect.Request[v1.IngestLockfileRequest]) (*connect.Response[v1.IngestResponse], error) {
+	return c.ingestLockfile.CallUnary(ctx, req)
+}
+
 // IngestServiceHandler is an implementation of the api.v1.IngestService service.
 type IngestServiceHandler interface {
-	IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[emptypb.Empty], error)
-	IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[emptypb.Empty], error)
-	IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[emptypb.Empty], error)
+	IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestSBOMStream(context.Context, *connect.ClientStream[v1.IngestSBOMChunk]) (*connect.Response[v1.IngestResponse], error)
+	IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestVulnerabilityArchive(context.Context, *connect.ClientStream[v1.IngestVulnerabilityArchiveChunk]) (*connect.Response[v1.IngestVulnerabilityArchiveResponse], error)
+	Relink(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.RelinkResponse], error)
+	IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestVEX(context.Context, *connect.Request[v1.IngestVEXRequest]) (*connect.Response[v1.IngestResponse], error)
+	IngestLockfile(context.Context, *connect.Request[v1.IngestLockfileRequest]) (*connect.Response[v1.IngestResponse], error)
 }
 
 // NewIngestServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -613,26 +771,66 @@ func NewIngestServiceHandler(svc IngestServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(ingestServiceIngestSBOMMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	ingestServiceIngestSBOMStreamHandler := connect.NewClientStreamHandler(
+		IngestServiceIngestSBOMStreamProcedure,
+		svc.IngestSBOMStream,
+		connect.WithSchema(ingestServiceIngestSBOMStreamMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	ingestServiceIngestVulnerabilityHandler := connect.NewUnaryHandler(
 		IngestServiceIngestVulnerabilityProcedure,
 		svc.IngestVulnerability,
 		connect.WithSchema(ingestServiceIngestVulnerabilityMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	ingestServiceIngestVulnerabilityArchiveHandler := connect.NewClientStreamHandler(
+		IngestServiceIngestVulnerabilityArchiveProcedure,
+		svc.IngestVulnerabilityArchive,
+		connect.WithSchema(ingestServiceIngestVulnerabilityArchiveMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ingestServiceRelinkHandler := connect.NewUnaryHandler(
+		IngestServiceRelinkProcedure,
+		svc.Relink,
+		connect.WithSchema(ingestServiceRelinkMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	ingestServiceIngestScorecardHandler := connect.NewUnaryHandler(
 		IngestServiceIngestScorecardProcedure,
 		svc.IngestScorecard,
 		connect.WithSchema(ingestServiceIngestScorecardMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	ingestServiceIngestVEXHandler := connect.NewUnaryHandler(
+		IngestServiceIngestVEXProcedure,
+		svc.IngestVEX,
+		connect.WithSchema(ingestServiceIngestVEXMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ingestServiceIngestLockfileHandler := connect.NewUnaryHandler(
+		IngestServiceIngestLockfileProcedure,
+		svc.IngestLockfile,
+		connect.WithSchema(ingestServiceIngestLockfileMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.IngestService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case IngestServiceIngestSBOMProcedure:
 			ingestServiceIngestSBOMHandler.ServeHTTP(w, r)
+		case IngestServiceIngestSBOMStreamProcedure:
+			ingestServiceIngestSBOMStreamHandler.ServeHTTP(w, r)
 		case IngestServiceIngestVulnerabilityProcedure:
 			ingestServiceIngestVulnerabilityHandler.ServeHTTP(w, r)
+		case IngestServiceIngestVulnerabilityArchiveProcedure:
+			ingestServiceIngestVulnerabilityArchiveHandler.ServeHTTP(w, r)
+		case IngestServiceRelinkProcedure:
+			ingestServiceRelinkHandler.ServeHTTP(w, r)
 		case IngestServiceIngestScorecardProcedure:
 			ingestServiceIngestScorecardHandler.ServeHTTP(w, r)
+		case IngestServiceIngestVEXProcedure:
+			ingestServiceIngestVEXHandler.ServeHTTP(w, r)
+		case IngestServiceIngestLockfileProcedure:
+			ingestServiceIngestLockfileHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -642,18 +840,388 @@ func NewIngestServiceHandler(svc IngestServiceHandler, opts ...connect.HandlerOp
 // UnimplementedIngestServiceHandler returns CodeUnimplemented from all methods.
 type UnimplementedIngestServiceHandler struct{}
 
-func (UnimplementedIngestServiceHandler) IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[emptypb.Empty], error) {
+func (UnimplementedIngestServiceHandler) IngestSBOM(context.Context, *connect.Request[v1.IngestSBOMRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestSBOM is not implemented"))
 }
 
-func (UnimplementedIngestServiceHandler) IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[emptypb.Empty], error) {
+func (UnimplementedIngestServiceHandler) IngestSBOMStream(context.Context, *connect.ClientStream[v1.IngestSBOMChunk]) (*connect.Response[v1.IngestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestSBOMStream is not implemented"))
+}
+
+func (UnimplementedIngestServiceHandler) IngestVulnerability(context.Context, *connect.Request[v1.IngestVulnerabilityRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestVulnerability is not implemented"))
 }
 
-func (UnimplementedIngestServiceHandler) IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[emptypb.Empty], error) {
+func (UnimplementedIngestServiceHandler) IngestVulnerabilityArchive(context.Context, *connect.ClientStream[v1.IngestVulnerabilityArchiveChunk]) (*connect.Response[v1.IngestVulnerabilityArchiveResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestVulnerabilityArchive is not implemented"))
+}
+
+func (UnimplementedIngestServiceHandler) Relink(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.RelinkResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.Relink is not implemented"))
+}
+
+func (UnimplementedIngestServiceHandler) IngestScorecard(context.Context, *connect.Request[v1.IngestScorecardRequest]) (*connect.Response[v1.IngestResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestScorecard is not implemented"))
 }
 
+func (UnimplementedIngestServiceHandler) IngestVEX(context.Context, *connect.Request[v1.IngestVEXRequest]) (*connect.Response[v1.IngestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestVEX is not implemented"))
+}
+
+func (UnimplementedIngestServiceHandler) IngestLockfile(context.Context, *connect.Request[v1.IngestLockfileRequest]) (*connect.Response[v1.IngestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.IngestService.IngestLockfile is not implemented"))
+}
+
+// ArchiveServiceClient is a client for the api.v1.ArchiveService service.
+type ArchiveServiceClient interface {
+	ExportGraph(context.Context, *connect.Request[emptypb.Empty]) (*connect.ServerStreamForClient[v1.ArchiveChunk], error)
+	ImportGraph(context.Context) *connect.ClientStreamForClient[v1.ArchiveChunk, v1.ImportGraphResponse]
+}
+
+// NewArchiveServiceClient constructs a client for the api.v1.ArchiveService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewArchiveServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ArchiveServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &archiveServiceClient{
+		exportGraph: connect.NewClient[emptypb.Empty, v1.ArchiveChunk](
+			httpClient,
+			baseURL+ArchiveServiceExportGraphProcedure,
+			connect.WithSchema(archiveServiceExportGraphMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		importGraph: connect.NewClient[v1.ArchiveChunk, v1.ImportGraphResponse](
+			httpClient,
+			baseURL+ArchiveServiceImportGraphProcedure,
+			connect.WithSchema(archiveServiceImportGraphMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// archiveServiceClient implements ArchiveServiceClient.
+type archiveServiceClient struct {
+	exportGraph *connect.Client[emptypb.Empty, v1.ArchiveChunk]
+	importGraph *connect.Client[v1.ArchiveChunk, v1.ImportGraphResponse]
+}
+
+// ExportGraph calls api.v1.ArchiveService.ExportGraph.
+func (c *archiveServiceClient) ExportGraph(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.ServerStreamForClient[v1.ArchiveChunk], error) {
+	return c.exportGraph.CallServerStream(ctx, req)
+}
+
+// ImportGraph calls api.v1.ArchiveService.ImportGraph.
+func (c *archiveServiceClient) ImportGraph(ctx context.Context) *connect.ClientStreamForClient[v1.ArchiveChunk, v1.ImportGraphResponse] {
+	return c.importGraph.CallClientStream(ctx)
+}
+
+// ArchiveServiceHandler is an implementation of the api.v1.ArchiveService service.
+type ArchiveServiceHandler interface {
+	ExportGraph(context.Context, *connect.Request[emptypb.Empty], *connect.ServerStream[v1.ArchiveChunk]) error
+	ImportGraph(context.Context, *connect.ClientStream[v1.ArchiveChunk]) (*connect.Response[v1.ImportGraphResponse], error)
+}
+
+// NewArchiveServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewArchiveServiceHandler(svc ArchiveServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	archiveServiceExportGraphHandler := connect.NewServerStreamHandler(
+		ArchiveServiceExportGraphProcedure,
+		svc.ExportGraph,
+		connect.WithSchema(archiveServiceExportGraphMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	archiveServiceImportGraphHandler := connect.NewClientStreamHandler(
+		ArchiveServiceImportGraphProcedure,
+		svc.ImportGraph,
+		connect.WithSchema(archiveServiceImportGraphMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.ArchiveService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ArchiveServiceExportGraphProcedure:
+			archiveServiceExportGraphHandler.ServeHTTP(w, r)
+		case ArchiveServiceImportGraphProcedure:
+			archiveServiceImportGraphHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedArchiveServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedArchiveServiceHandler struct{}
+
+func (UnimplementedArchiveServiceHandler) ExportGraph(context.Context, *connect.Request[emptypb.Empty], *connect.ServerStream[v1.ArchiveChunk]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ArchiveService.ExportGraph is not implemented"))
+}
+
+func (UnimplementedArchiveServiceHandler) ImportGraph(context.Context, *connect.ClientStream[v1.ArchiveChunk]) (*connect.Response[v1.ImportGraphResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ArchiveService.ImportGraph is not implemented"))
+}
+
+// StatsServiceClient is a client for the api.v1.StatsService service.
+type StatsServiceClient interface {
+	GetStorageCacheStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStorageCacheStatsResponse], error)
+}
+
+// NewStatsServiceClient constructs a client for the api.v1.StatsService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewStatsServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) StatsServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &statsServiceClient{
+		getStorageCacheStats: connect.NewClient[emptypb.Empty, v1.GetStorageCacheStatsResponse](
+			httpClient,
+			baseURL+StatsServiceGetStorageCacheStatsProcedure,
+			connect.WithSchema(statsServiceGetStorageCacheStatsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// statsServiceClient implements StatsServiceClient.
+type statsServiceClient struct {
+	getStorageCacheStats *connect.Client[emptypb.Empty, v1.GetStorageCacheStatsResponse]
+}
+
+// GetStorageCacheStats calls api.v1.StatsService.GetStorageCacheStats.
+func (c *statsServiceClient) GetStorageCacheStats(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStorageCacheStatsResponse], error) {
+	return c.getStorageCacheStats.CallUnary(ctx, req)
+}
+
+// StatsServiceHandler is an implementation of the api.v1.StatsService service.
+type StatsServiceHandler interface {
+	GetStorageCacheStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStorageCacheStatsResponse], error)
+}
+
+// NewStatsServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewStatsServiceHandler(svc StatsServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	statsServiceGetStorageCacheStatsHandler := connect.NewUnaryHandler(
+		StatsServiceGetStorageCacheStatsProcedure,
+		svc.GetStorageCacheStats,
+		connect.WithSchema(statsServiceGetStorageCacheStatsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.StatsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case StatsServiceGetStorageCacheStatsProcedure:
+			statsServiceGetStorageCacheStatsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedStatsServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedStatsServiceHandler struct{}
+
+func (UnimplementedStatsServiceHandler) GetStorageCacheStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStorageCacheStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.StatsService.GetStorageCacheStats is not implemented"))
+}
+
+// ProvenanceServiceClient is a client for the api.v1.ProvenanceService service.
+type ProvenanceServiceClient interface {
+	ListDocuments(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.ListDocumentsResponse], error)
+	GetDocumentContribution(context.Context, *connect.Request[v1.GetDocumentContributionRequest]) (*connect.Response[v1.GetDocumentContributionResponse], error)
+	GetNodeDocuments(context.Context, *connect.Request[v1.GetNodeDocumentsRequest]) (*connect.Response[v1.GetNodeDocumentsResponse], error)
+}
+
+// NewProvenanceServiceClient constructs a client for the api.v1.ProvenanceService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewProvenanceServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ProvenanceServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &provenanceServiceClient{
+		listDocuments: connect.NewClient[emptypb.Empty, v1.ListDocumentsResponse](
+			httpClient,
+			baseURL+ProvenanceServiceListDocumentsProcedure,
+			connect.WithSchema(provenanceServiceListDocumentsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getDocumentContribution: connect.NewClient[v1.GetDocumentContributionRequest, v1.GetDocumentContributionResponse](
+			httpClient,
+			baseURL+ProvenanceServiceGetDocumentContributionProcedure,
+			connect.WithSchema(provenanceServiceGetDocumentContributionMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getNodeDocuments: connect.NewClient[v1.GetNodeDocumentsRequest, v1.GetNodeDocumentsResponse](
+			httpClient,
+			baseURL+ProvenanceServiceGetNodeDocumentsProcedure,
+			connect.WithSchema(provenanceServiceGetNodeDocumentsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// provenanceServiceClient implements ProvenanceServiceClient.
+type provenanceServiceClient struct {
+	listDocuments           *connect.Client[emptypb.Empty, v1.ListDocumentsResponse]
+	getDocumentContribution *connect.Client[v1.GetDocumentContributionRequest, v1.GetDocumentContributionResponse]
+	getNodeDocuments        *connect.Client[v1.GetNodeDocumentsRequest, v1.GetNodeDocumentsResponse]
+}
+
+// ListDocuments calls api.v1.ProvenanceService.ListDocuments.
+func (c *provenanceServiceClient) ListDocuments(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.ListDocumentsResponse], error) {
+	return c.listDocuments.CallUnary(ctx, req)
+}
+
+// GetDocumentContribution calls api.v1.ProvenanceService.GetDocumentContribution.
+func (c *provenanceServiceClient) GetDocumentContribution(ctx context.Context, req *connect.Request[v1.GetDocumentContributionRequest]) (*connect.Response[v1.GetDocumentContributionResponse], error) {
+	return c.getDocumentContribution.CallUnary(ctx, req)
+}
+
+// GetNodeDocuments calls api.v1.ProvenanceService.GetNodeDocuments.
+func (c *provenanceServiceClient) GetNodeDocuments(ctx context.Context, req *connect.Request[v1.GetNodeDocumentsRequest]) (*connect.Response[v1.GetNodeDocumentsResponse], error) {
+	return c.getNodeDocuments.CallUnary(ctx, req)
+}
+
+// ProvenanceServiceHandler is an implementation of the api.v1.ProvenanceService service.
+type ProvenanceServiceHandler interface {
+	ListDocuments(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.ListDocumentsResponse], error)
+	GetDocumentContribution(context.Context, *connect.Request[v1.GetDocumentContributionRequest]) (*connect.Response[v1.GetDocumentContributionResponse], error)
+	GetNodeDocuments(context.Context, *connect.Request[v1.GetNodeDocumentsRequest]) (*connect.Response[v1.GetNodeDocumentsResponse], error)
+}
+
+// NewProvenanceServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewProvenanceServiceHandler(svc ProvenanceServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	provenanceServiceListDocumentsHandler := connect.NewUnaryHandler(
+		ProvenanceServiceListDocumentsProcedure,
+		svc.ListDocuments,
+		connect.WithSchema(provenanceServiceListDocumentsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	provenanceServiceGetDocumentContributionHandler := connect.NewUnaryHandler(
+		ProvenanceServiceGetDocumentContributionProcedure,
+		svc.GetDocumentContribution,
+		connect.WithSchema(provenanceServiceGetDocumentContributionMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	provenanceServiceGetNodeDocumentsHandler := connect.NewUnaryHandler(
+		ProvenanceServiceGetNodeDocumentsProcedure,
+		svc.GetNodeDocuments,
+		connect.WithSchema(provenanceServiceGetNodeDocumentsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.ProvenanceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ProvenanceServiceListDocumentsProcedure:
+			provenanceServiceListDocumentsHandler.ServeHTTP(w, r)
+		case ProvenanceServiceGetDocumentContributionProcedure:
+			provenanceServiceGetDocumentContributionHandler.ServeHTTP(w, r)
+		case ProvenanceServiceGetNodeDocumentsProcedure:
+			provenanceServiceGetNodeDocumentsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedProvenanceServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedProvenanceServiceHandler struct{}
+
+func (UnimplementedProvenanceServiceHandler) ListDocuments(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.ListDocumentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ProvenanceService.ListDocuments is not implemented"))
+}
+
+func (UnimplementedProvenanceServiceHandler) GetDocumentContribution(context.Context, *connect.Request[v1.GetDocumentContributionRequest]) (*connect.Response[v1.GetDocumentContributionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ProvenanceService.GetDocumentContribution is not implemented"))
+}
+
+func (UnimplementedProvenanceServiceHandler) GetNodeDocuments(context.Context, *connect.Request[v1.GetNodeDocumentsRequest]) (*connect.Response[v1.GetNodeDocumentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ProvenanceService.GetNodeDocuments is not implemented"))
+}
+
+// LicenseServiceClient is a client for the api.v1.LicenseService service.
+type LicenseServiceClient interface {
+	EvaluateLicensePolicy(context.Context, *connect.Request[v1.LicensePolicyRequest]) (*connect.Response[v1.LicensePolicyResponse], error)
+}
+
+// NewLicenseServiceClient constructs a client for the api.v1.LicenseService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewLicenseServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) LicenseServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &licenseServiceClient{
+		evaluateLicensePolicy: connect.NewClient[v1.LicensePolicyRequest, v1.LicensePolicyResponse](
+			httpClient,
+			baseURL+LicenseServiceEvaluateLicensePolicyProcedure,
+			connect.WithSchema(licenseServiceEvaluateLicensePolicyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// licenseServiceClient implements LicenseServiceClient.
+type licenseServiceClient struct {
+	evaluateLicensePolicy *connect.Client[v1.LicensePolicyRequest, v1.LicensePolicyResponse]
+}
+
+// EvaluateLicensePolicy calls api.v1.LicenseService.EvaluateLicensePolicy.
+func (c *licenseServiceClient) EvaluateLicensePolicy(ctx context.Context, req *connect.Request[v1.LicensePolicyRequest]) (*connect.Response[v1.LicensePolicyResponse], error) {
+	return c.evaluateLicensePolicy.CallUnary(ctx, req)
+}
+
+// LicenseServiceHandler is an implementation of the api.v1.LicenseService service.
+type LicenseServiceHandler interface {
+	EvaluateLicensePolicy(context.Context, *connect.Request[v1.LicensePolicyRequest]) (*connect.Response[v1.LicensePolicyResponse], error)
+}
+
+// NewLicenseServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewLicenseServiceHandler(svc LicenseServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	licenseServiceEvaluateLicensePolicyHandler := connect.NewUnaryHandler(
+		LicenseServiceEvaluateLicensePolicyProcedure,
+		svc.EvaluateLicensePolicy,
+		connect.WithSchema(licenseServiceEvaluateLicensePolicyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.LicenseService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LicenseServiceEvaluateLicensePolicyProcedure:
+			licenseServiceEvaluateLicensePolicyHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedLicenseServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedLicenseServiceHandler struct{}
+
+func (UnimplementedLicenseServiceHandler) EvaluateLicensePolicy(context.Context, *connect.Request[v1.LicensePolicyRequest]) (*connect.Response[v1.LicensePolicyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.LicenseService.EvaluateLicensePolicy is not implemented"))
+}
+
 // HealthServiceClient is a client for the api.v1.HealthService service.
 type HealthServiceClient interface {
 	Check(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.HealthCheckResponse], error)
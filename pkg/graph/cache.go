@@ -1,27 +1,30 @@
 package graph
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/RoaringBitmap/roaring"
 	"github.com/bitbomdev/minefield/pkg/utils"
 )
 
-func Cache(storage Storage) error {
-	uncachedNodes, err := storage.ToBeCached()
+// Cache computes the transitive parents and children of every node on the cache stack and
+// saves them, so queries don't have to walk the graph.
+func Cache(ctx context.Context, storage Storage) error {
+	uncachedNodes, err := storage.ToBeCached(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting uncached nodes: %w", err)
 	}
 	if len(uncachedNodes) == 0 {
 		return nil
 	}
-	keys, err := storage.GetAllKeys()
+	keys, err := storage.GetAllKeys(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting keys: %w", err)
 	}
 
 	// Retrieve all nodes at once
-	allNodes, err := storage.GetNodes(keys)
+	allNodes, err := storage.GetNodes(ctx, keys)
 	if err != nil {
 		return fmt.Errorf("error getting all nodes: %w", err)
 	}
@@ -38,6 +41,11 @@ func Cache(storage Storage) error {
 		return fmt.Errorf("error building cached parents: %w", err)
 	}
 
+	// Building the caches can take a while, don't save them for a caller that gave up.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	cachedChildKeys, cachedChildValues, err := cachedChildren.GetAllKeysAndValues()
 	if err != nil {
 		return fmt.Errorf("error getting cached child keys and values: %w", err)
@@ -64,10 +72,10 @@ func Cache(storage Storage) error {
 		caches = append(caches, NewNodeCache(childIntId, parentBindValue, childBindValue))
 	}
 
-	if err := storage.SaveCaches(caches); err != nil {
+	if err := storage.SaveCaches(ctx, caches); err != nil {
 		return fmt.Errorf("error saving caches: %w", err)
 	}
-	return storage.ClearCacheStack()
+	return storage.ClearCacheStack(ctx)
 }
 
 func findCycles(numOfNodes int, allNodes map[uint32]*Node) map[uint32]uint32 {
@@ -1,24 +1,26 @@
 package graph
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_findCycles(t *testing.T) {
 	storage := NewMockStorage()
-	node1, err := AddNode(storage, "type1", "metadata1", "1")
+	node1, err := AddNode(context.Background(), storage, "type1", "metadata1", "1")
 	assert.NoError(t, err)
-	node2, err := AddNode(storage, "type2", "metadata2", "2")
+	node2, err := AddNode(context.Background(), storage, "type2", "metadata2", "2")
 	assert.NoError(t, err)
-	err = node1.SetDependency(storage, node2)
+	err = node1.SetDependency(context.Background(), storage, node2)
 	assert.NoError(t, err)
 
-	allNodes, err := storage.GetNodes([]uint32{node1.ID, node2.ID})
+	allNodes, err := storage.GetNodes(context.Background(), []uint32{node1.ID, node2.ID})
 	assert.NoError(t, err)
 
 	got := findCycles(2, allNodes)
@@ -27,21 +29,21 @@ func Test_findCycles(t *testing.T) {
 
 func Test_findCycles_With_Cycles(t *testing.T) {
 	storage := NewMockStorage()
-	node1, err := AddNode(storage, "type1", "metadata1", "1")
+	node1, err := AddNode(context.Background(), storage, "type1", "metadata1", "1")
 	assert.NoError(t, err)
-	node2, err := AddNode(storage, "type2", "metadata2", "2")
+	node2, err := AddNode(context.Background(), storage, "type2", "metadata2", "2")
 	assert.NoError(t, err)
-	node3, err := AddNode(storage, "type3", "metadata3", "3")
+	node3, err := AddNode(context.Background(), storage, "type3", "metadata3", "3")
 	assert.NoError(t, err)
 
-	err = node1.SetDependency(storage, node2)
+	err = node1.SetDependency(context.Background(), storage, node2)
 	assert.NoError(t, err)
-	err = node2.SetDependency(storage, node3)
+	err = node2.SetDependency(context.Background(), storage, node3)
 	assert.NoError(t, err)
-	err = node3.SetDependency(storage, node1)
+	err = node3.SetDependency(context.Background(), storage, node1)
 	assert.NoError(t, err)
 
-	allNodes, err := storage.GetNodes([]uint32{node1.ID, node2.ID, node3.ID})
+	allNodes, err := storage.GetNodes(context.Background(), []uint32{node1.ID, node2.ID, node3.ID})
 	assert.NoError(t, err)
 
 	got := findCycles(3, allNodes)
@@ -60,7 +62,7 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 		// Create nodes and set dependencies
 
 		for i := 0; i < n; i++ {
-			node, err := AddNode(storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+			node, err := AddNode(context.Background(), storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 			assert.NoError(t, err)
 			nodes[i] = node
 		}
@@ -75,10 +77,10 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 				if targetIndex != i { // Avoid self-dependency and control cycle creation
 					v := max(targetIndex-rand.Intn(100), 0)
 					if shouldCycle && v != i {
-						err := nodes[i].SetDependency(storage, nodes[v])
+						err := nodes[i].SetDependency(context.Background(), storage, nodes[v])
 						assert.NoError(t, err)
 					} else {
-						err := nodes[i].SetDependency(storage, nodes[targetIndex])
+						err := nodes[i].SetDependency(context.Background(), storage, nodes[targetIndex])
 						assert.NoError(t, err)
 					}
 
@@ -88,11 +90,11 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 
 		// Precompute expected results for QueryDependentsNoCache and QueryDependenciesNoCache
 		for _, node := range nodes {
-			dependents, err := node.QueryDependentsNoCache(storage)
+			dependents, err := node.QueryDependentsNoCache(context.Background(), storage)
 			assert.NoError(t, err)
 			expectedDependents[node.ID] = dependents.ToArray()
 
-			dependencies, err := node.QueryDependenciesNoCache(storage)
+			dependencies, err := node.QueryDependenciesNoCache(context.Background(), storage)
 			assert.NoError(t, err)
 			expectedDependencies[node.ID] = dependencies.ToArray()
 		}
@@ -100,7 +102,7 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 		start := time.Now()
 
 		// Cache the current state
-		err := Cache(storage)
+		err := Cache(context.Background(), storage)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -111,7 +113,7 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 
 		// Benchmark QueryDependents, QueryDependencies and Cache
 		for _, node := range nodes {
-			dependents, err := node.QueryDependents(storage)
+			dependents, err := node.QueryDependents(context.Background(), storage)
 			assert.NoError(t, err)
 			depArr := []uint32{}
 			if dependents != nil {
@@ -119,7 +121,7 @@ func TestRandomGraphDependenciesWithControlledCircles(t *testing.T) {
 			}
 			assert.Equal(t, expectedDependents[node.ID], depArr, fmt.Sprintf("Dependents of node %v", node.ID))
 
-			dependencies, err := node.QueryDependencies(storage)
+			dependencies, err := node.QueryDependencies(context.Background(), storage)
 			assert.NoError(t, err)
 			depArr = []uint32{}
 			if dependencies != nil {
@@ -141,7 +143,7 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 		// Create nodes and set dependencies
 
 		for i := 0; i < n; i++ {
-			node, err := AddNode(storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+			node, err := AddNode(context.Background(), storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 			assert.NoError(t, err)
 			nodes[i] = node
 		}
@@ -153,7 +155,7 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 			for j := 0; j < 15 && j < len(possibleDeps); j++ { // Each node has up to 10 random dependencies
 				targetIndex := possibleDeps[j] + i + 1
 				if targetIndex < n {
-					err := nodes[i].SetDependency(storage, nodes[targetIndex])
+					err := nodes[i].SetDependency(context.Background(), storage, nodes[targetIndex])
 					assert.NoError(t, err)
 					m[int(nodes[i].ID)] = append(m[int(nodes[i].ID)], int(nodes[targetIndex].ID))
 				}
@@ -162,11 +164,11 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 
 		// Precompute expected results for QueryDependentsNoCache and QueryDependenciesNoCache
 		for _, node := range nodes {
-			dependents, err := node.QueryDependentsNoCache(storage)
+			dependents, err := node.QueryDependentsNoCache(context.Background(), storage)
 			assert.NoError(t, err)
 			expectedDependents[node.ID] = dependents.ToArray()
 
-			dependencies, err := node.QueryDependenciesNoCache(storage)
+			dependencies, err := node.QueryDependenciesNoCache(context.Background(), storage)
 			assert.NoError(t, err)
 			expectedDependencies[node.ID] = dependencies.ToArray()
 		}
@@ -174,7 +176,7 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 		start := time.Now()
 
 		// Cache the current state
-		err := Cache(storage)
+		err := Cache(context.Background(), storage)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -185,7 +187,7 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 
 		// Benchmark QueryDependents, QueryDependencies and Cache
 		for _, node := range nodes {
-			dependents, err := node.QueryDependents(storage)
+			dependents, err := node.QueryDependents(context.Background(), storage)
 			assert.NoError(t, err)
 			depArr := []uint32{}
 			if dependents != nil {
@@ -193,7 +195,7 @@ func TestRandomGraphDependenciesNoCircles(t *testing.T) {
 			}
 			assert.Equal(t, expectedDependents[node.ID], depArr, fmt.Sprintf("Dependents of node %v", node.ID))
 
-			dependencies, err := node.QueryDependencies(storage)
+			dependencies, err := node.QueryDependencies(context.Background(), storage)
 			assert.NoError(t, err)
 			depArr = []uint32{}
 			if dependencies != nil {
@@ -211,68 +213,68 @@ func TestComplexCircularDependency(t *testing.T) {
 
 	// Create nodes
 	for i := 0; i < 13; i++ {
-		nodes[i], err = AddNode(storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+		nodes[i], err = AddNode(context.Background(), storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 		assert.NoError(t, err, "Expected no error")
 	}
 
 	// Create circular dependencies like figure 8s
 	// Circle 1: node0 -> node1 -> node2 -> node0
-	err = nodes[0].SetDependency(storage, nodes[1])
+	err = nodes[0].SetDependency(context.Background(), storage, nodes[1])
 	assert.NoError(t, err)
-	err = nodes[1].SetDependency(storage, nodes[2])
+	err = nodes[1].SetDependency(context.Background(), storage, nodes[2])
 	assert.NoError(t, err)
-	err = nodes[2].SetDependency(storage, nodes[0])
+	err = nodes[2].SetDependency(context.Background(), storage, nodes[0])
 	assert.NoError(t, err)
 
 	// Circle 2: node3 -> node4 -> node5 -> node3
-	err = nodes[3].SetDependency(storage, nodes[4])
+	err = nodes[3].SetDependency(context.Background(), storage, nodes[4])
 	assert.NoError(t, err)
-	err = nodes[4].SetDependency(storage, nodes[5])
+	err = nodes[4].SetDependency(context.Background(), storage, nodes[5])
 	assert.NoError(t, err)
-	err = nodes[5].SetDependency(storage, nodes[3])
+	err = nodes[5].SetDependency(context.Background(), storage, nodes[3])
 	assert.NoError(t, err)
 
 	// Figure 8 linking Circle 1 and Circle 2: node2 -> node3
-	err = nodes[2].SetDependency(storage, nodes[3])
+	err = nodes[2].SetDependency(context.Background(), storage, nodes[3])
 	assert.NoError(t, err)
 
 	// Additional circle: node6 -> node7 -> node8 -> node9 -> node6
-	err = nodes[6].SetDependency(storage, nodes[7])
+	err = nodes[6].SetDependency(context.Background(), storage, nodes[7])
 	assert.NoError(t, err)
-	err = nodes[7].SetDependency(storage, nodes[8])
+	err = nodes[7].SetDependency(context.Background(), storage, nodes[8])
 	assert.NoError(t, err)
-	err = nodes[8].SetDependency(storage, nodes[9])
+	err = nodes[8].SetDependency(context.Background(), storage, nodes[9])
 	assert.NoError(t, err)
-	err = nodes[9].SetDependency(storage, nodes[6])
+	err = nodes[9].SetDependency(context.Background(), storage, nodes[6])
 	assert.NoError(t, err)
 
 	// Linking node9 to node1 to form another figure 8 between Circle 1 and the additional circle
-	err = nodes[9].SetDependency(storage, nodes[1])
+	err = nodes[9].SetDependency(context.Background(), storage, nodes[1])
 	assert.NoError(t, err)
 
 	// Additional independent circle: node10 -> node11 -> node12 -> node10
-	err = nodes[10].SetDependency(storage, nodes[11])
+	err = nodes[10].SetDependency(context.Background(), storage, nodes[11])
 	assert.NoError(t, err)
-	err = nodes[11].SetDependency(storage, nodes[12])
+	err = nodes[11].SetDependency(context.Background(), storage, nodes[12])
 	assert.NoError(t, err)
-	err = nodes[12].SetDependency(storage, nodes[10])
+	err = nodes[12].SetDependency(context.Background(), storage, nodes[10])
 	assert.NoError(t, err)
 
-	if err := Cache(storage); err != nil {
+	if err := Cache(context.Background(), storage); err != nil {
 		t.Fatal(err)
 	}
 
 	// Test QueryDependents and QueryDependencies for complex circular dependencies
 	for _, node := range nodes {
-		dependents, err := node.QueryDependents(storage)
+		dependents, err := node.QueryDependents(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependents")
-		dependentsNoCache, err := node.QueryDependentsNoCache(storage)
+		dependentsNoCache, err := node.QueryDependentsNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependents")
 		assert.Equal(t, dependentsNoCache.ToArray(), dependents.ToArray(), "Cached and non-cached dependents should match")
 
-		dependencies, err := node.QueryDependencies(storage)
+		dependencies, err := node.QueryDependencies(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependencies")
-		dependenciesNoCache, err := node.QueryDependenciesNoCache(storage)
+		dependenciesNoCache, err := node.QueryDependenciesNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependencies")
 		assert.Equal(t, dependenciesNoCache.ToArray(), dependencies.ToArray(), "Cached and non-cached dependencies should match")
 	}
@@ -286,33 +288,33 @@ func TestIntermediateSimpleCircles(t *testing.T) {
 
 	// Create nodes
 	for i := 0; i < 6; i++ {
-		nodes[i], err = AddNode(storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+		nodes[i], err = AddNode(context.Background(), storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 		assert.NoError(t, err, "Expected no error")
 	}
 
 	// Circle 1: node0 -> node1 -> node2 -> node0
-	err = nodes[0].SetDependency(storage, nodes[1])
+	err = nodes[0].SetDependency(context.Background(), storage, nodes[1])
 	assert.NoError(t, err)
-	err = nodes[1].SetDependency(storage, nodes[2])
+	err = nodes[1].SetDependency(context.Background(), storage, nodes[2])
 	assert.NoError(t, err)
-	err = nodes[2].SetDependency(storage, nodes[0])
+	err = nodes[2].SetDependency(context.Background(), storage, nodes[0])
 	assert.NoError(t, err)
 
 	// Circle 2: node3 -> node4 -> node5 -> node3
-	err = nodes[3].SetDependency(storage, nodes[4])
+	err = nodes[3].SetDependency(context.Background(), storage, nodes[4])
 	assert.NoError(t, err)
-	err = nodes[4].SetDependency(storage, nodes[5])
+	err = nodes[4].SetDependency(context.Background(), storage, nodes[5])
 	assert.NoError(t, err)
-	err = nodes[5].SetDependency(storage, nodes[3])
+	err = nodes[5].SetDependency(context.Background(), storage, nodes[3])
 	assert.NoError(t, err)
 
 	// Linking Circle 1 and Circle 2
-	err = nodes[2].SetDependency(storage, nodes[3])
+	err = nodes[2].SetDependency(context.Background(), storage, nodes[3])
 	assert.NoError(t, err)
 	// err = nodes[5].SetDependency(storages, nodes[0])
 	// assert.NoError(t, err)
 
-	if err := Cache(storage); err != nil {
+	if err := Cache(context.Background(), storage); err != nil {
 		t.Fatal(err)
 	}
 
@@ -320,15 +322,15 @@ func TestIntermediateSimpleCircles(t *testing.T) {
 
 	// Test QueryDependents and QueryDependencies for intermediate simple circles
 	for _, node := range nodes {
-		dependents, err := node.QueryDependents(storage)
+		dependents, err := node.QueryDependents(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependents")
-		dependentsNoCache, err := node.QueryDependentsNoCache(storage)
+		dependentsNoCache, err := node.QueryDependentsNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependents")
 		assert.Equal(t, dependentsNoCache.ToArray(), dependents.ToArray(), "Cached and non-cached dependents should match")
 
-		dependencies, err := node.QueryDependencies(storage)
+		dependencies, err := node.QueryDependencies(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependencies")
-		dependenciesNoCache, err := node.QueryDependenciesNoCache(storage)
+		dependenciesNoCache, err := node.QueryDependenciesNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependencies")
 		assert.Equal(t, dependenciesNoCache.ToArray(), dependencies.ToArray(), "Cached and non-cached dependencies should match")
 	}
@@ -341,19 +343,19 @@ func TestSimpleCircle(t *testing.T) {
 
 	// Create nodes
 	for i := 0; i < 3; i++ {
-		nodes[i], err = AddNode(storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+		nodes[i], err = AddNode(context.Background(), storage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 		assert.NoError(t, err, "Expected no error")
 	}
 
 	// Simple Circle: node0 -> node1 -> node2 -> node0
-	err = nodes[0].SetDependency(storage, nodes[1])
+	err = nodes[0].SetDependency(context.Background(), storage, nodes[1])
 	assert.NoError(t, err)
-	err = nodes[1].SetDependency(storage, nodes[2])
+	err = nodes[1].SetDependency(context.Background(), storage, nodes[2])
 	assert.NoError(t, err)
-	err = nodes[2].SetDependency(storage, nodes[0])
+	err = nodes[2].SetDependency(context.Background(), storage, nodes[0])
 	assert.NoError(t, err)
 
-	if err := Cache(storage); err != nil {
+	if err := Cache(context.Background(), storage); err != nil {
 		t.Fatal(err)
 	}
 
@@ -361,15 +363,15 @@ func TestSimpleCircle(t *testing.T) {
 
 	// Test QueryDependents and QueryDependencies for simple circle
 	for _, node := range nodes {
-		dependents, err := node.QueryDependents(storage)
+		dependents, err := node.QueryDependents(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependents")
-		dependentsNoCache, err := node.QueryDependentsNoCache(storage)
+		dependentsNoCache, err := node.QueryDependentsNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependents")
 		assert.Equal(t, dependentsNoCache.ToArray(), dependents.ToArray(), "Cached and non-cached dependents should match")
 
-		dependencies, err := node.QueryDependencies(storage)
+		dependencies, err := node.QueryDependencies(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying cached dependencies")
-		dependenciesNoCache, err := node.QueryDependenciesNoCache(storage)
+		dependenciesNoCache, err := node.QueryDependenciesNoCache(context.Background(), storage)
 		assert.NoError(t, err, "Expected no error when querying non-cached dependencies")
 		assert.Equal(t, dependenciesNoCache.ToArray(), dependencies.ToArray(), "Cached and non-cached dependencies should match")
 	}
@@ -417,21 +419,37 @@ func TestCacheErrors(t *testing.T) {
 			// Create nodes
 			for i := 0; i < 3; i++ {
 				var err error
-				nodes[i], err = AddNode(mockStorage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
+				nodes[i], err = AddNode(context.Background(), mockStorage, fmt.Sprintf("type %d", i+1), fmt.Sprintf("metadata %d", i), fmt.Sprintf("name %d", i+1))
 				assert.NoError(t, err)
 			}
 
 			// Create circle: node0 -> node1 -> node2 -> node0
 			for i := 0; i < 3; i++ {
-				err := nodes[i].SetDependency(mockStorage, nodes[(i+1)%3])
+				err := nodes[i].SetDependency(context.Background(), mockStorage, nodes[(i+1)%3])
 				assert.NoError(t, err)
 			}
 
 			tt.setupMock(mockStorage)
-			err := Cache(mockStorage)
+			err := Cache(context.Background(), mockStorage)
 			if err == nil || err.Error() != tt.wantErr {
 				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
 			}
 		})
 	}
 }
+
+func TestCacheCancelled(t *testing.T) {
+	mockStorage := NewMockStorage()
+	a, err := AddNode(context.Background(), mockStorage, "library", nil, "a")
+	require.NoError(t, err)
+	b, err := AddNode(context.Background(), mockStorage, "library", nil, "b")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), mockStorage, b))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Cache(ctx, mockStorage), context.Canceled)
+	uncached, err := mockStorage.ToBeCached(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, uncached, "the cache stack is kept for a cancelled context")
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// ratings are the qualitative severity ratings in ascending order, so filters compare them by
+// severity rather than alphabetically.
+var ratings = map[string]int{
+	"none":     0,
+	"low":      1,
+	"medium":   2,
+	"moderate": 2,
+	"high":     3,
+	"critical": 4,
+}
+
+// Matches reports whether the metadata of the node has the field and it compares to the value
+// of the filter. Numbers are compared numerically, severity ratings by severity, and other
+// values as case-insensitive strings. A nil filter matches every node.
+func (f *Filter) Matches(node *Node) bool {
+	if f == nil {
+		return true
+	}
+	value, err := metadataValue(node.Metadata)
+	if err != nil {
+		return false
+	}
+	for _, key := range strings.Split(f.Field, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	var order int
+	switch value := value.(type) {
+	case float64:
+		want, err := strconv.ParseFloat(f.Value, 64)
+		if err != nil {
+			return false
+		}
+		order = cmp.Compare(value, want)
+	case string:
+		got, want := strings.ToLower(value), strings.ToLower(f.Value)
+		gotRating, gotOK := ratings[got]
+		wantRating, wantOK := ratings[want]
+		if gotOK && wantOK {
+			order = cmp.Compare(gotRating, wantRating)
+		} else {
+			order = strings.Compare(got, want)
+		}
+	case bool:
+		want, err := strconv.ParseBool(f.Value)
+		if err != nil || (f.Op != "=" && f.Op != "==" && f.Op != "!=") {
+			return false
+		}
+		if value != want {
+			order = 1
+		}
+	default:
+		return false
+	}
+
+	switch f.Op {
+	case "=", "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	default:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -11,6 +12,12 @@ import (
 var (
 	ErrNodeAlreadyExists = errors.New("node with name already exists")
 	ErrSelfDependency    = errors.New("cannot add self as dependency")
+	// ErrNodeNotFound is returned by storages for IDs and names without a node.
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrCacheNotFound is returned by storages for nodes without a cache.
+	ErrCacheNotFound = errors.New("cache not found")
+	// ErrNodeHasEdges is returned by storages when deleting a node that still has edges.
+	ErrNodeHasEdges = errors.New("node still has edges")
 )
 
 type Direction string
@@ -151,15 +158,15 @@ func (n *Node) UnmarshalJSON(data []byte) error {
 }
 
 // AddNode becomes generic in terms of metadata
-func AddNode(storage Storage, _type string, metadata any, name string) (*Node, error) {
-	var ID uint32
-	if id, err := storage.NameToID(name); err == nil {
-		return storage.GetNode(id)
-	} else {
-		ID, err = storage.GenerateID()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate ID: %w", err)
-		}
+func AddNode(ctx context.Context, storage Storage, _type string, metadata any, name string) (*Node, error) {
+	if id, err := storage.NameToID(ctx, name); err == nil {
+		return storage.GetNode(ctx, id)
+	} else if !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("failed to look up node %s: %w", name, err)
+	}
+	ID, err := storage.GenerateID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
 	}
 
 	n := &Node{
@@ -175,17 +182,35 @@ func AddNode(storage Storage, _type string, metadata any, name string) (*Node, e
 		AllParents:  roaring.New(),
 		AllChildren: roaring.New(),
 	}
-	if err := storage.SaveNode(n); err != nil {
+	id, err := storage.CreateNode(ctx, n)
+	if err != nil {
 		return nil, fmt.Errorf("failed to save node: %w", err)
 	}
-	if err := storage.SaveCache(nCache); err != nil {
+	if id != n.ID {
+		// Another writer added a node of the same name since the lookup.
+		return storage.GetNode(ctx, id)
+	}
+	if err := storage.SaveCache(ctx, nCache); err != nil {
 		return nil, err
 	}
 	return n, nil
 }
 
+// ResolveName returns the ID of the node with the given name, or of the node the name is an
+// alias of.
+func ResolveName(ctx context.Context, storage Storage, name string) (uint32, error) {
+	id, err := storage.NameToID(ctx, name)
+	if !errors.Is(err, ErrNodeNotFound) {
+		return id, err
+	}
+	if id, err := storage.AliasToID(ctx, name); !errors.Is(err, ErrNodeNotFound) {
+		return id, err
+	}
+	return 0, fmt.Errorf("node %s: %w", name, ErrNodeNotFound)
+}
+
 // SetDependency now uses generic types for metadata
-func (n *Node) SetDependency(storage Storage, neighbor *Node) error {
+func (n *Node) SetDependency(ctx context.Context, storage Storage, neighbor *Node) error {
 	if n == nil {
 		return fmt.Errorf("cannot add dependency to nil node")
 	}
@@ -199,19 +224,58 @@ func (n *Node) SetDependency(storage Storage, neighbor *Node) error {
 		return fmt.Errorf("storages cannot be nil")
 	}
 
+	// The edge is written by the storage backend as a single atomic update, the in-memory
+	// bitmaps are only updated afterwards so that callers see the same state as the storage.
+	if err := storage.AddDependency(ctx, n.ID, neighbor.ID); err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
 	n.Children.Add(neighbor.ID)
 	neighbor.Parents.Add(n.ID)
+	return nil
+}
+
+// DeleteNode removes all edges of the node with the given ID and then the node itself.
+func DeleteNode(ctx context.Context, storage Storage, id uint32) error {
+	node, err := storage.GetNode(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, child := range node.Children.ToArray() {
+		if err := storage.RemoveDependency(ctx, id, child); err != nil {
+			return fmt.Errorf("failed to remove dependency %d -> %d: %w", id, child, err)
+		}
+	}
+	for _, parent := range node.Parents.ToArray() {
+		if err := storage.RemoveDependency(ctx, parent, id); err != nil {
+			return fmt.Errorf("failed to remove dependency %d -> %d: %w", parent, id, err)
+		}
+	}
+	if err := storage.DeleteNode(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete node %d: %w", id, err)
+	}
+	return nil
+}
 
-	if err := storage.SaveNode(n); err != nil {
-		return fmt.Errorf("failed to save node: %w", err)
+// RemoveDependency removes the edge between the node and its neighbor.
+func (n *Node) RemoveDependency(ctx context.Context, storage Storage, neighbor *Node) error {
+	if n == nil || neighbor == nil {
+		return fmt.Errorf("cannot remove dependency from nil node")
 	}
-	if err := storage.SaveNode(neighbor); err != nil {
-		return fmt.Errorf("failed to save neighbor node: %w", err)
+	if storage == nil {
+		return fmt.Errorf("storages cannot be nil")
 	}
+
+	if err := storage.RemoveDependency(ctx, n.ID, neighbor.ID); err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	n.Children.Remove(neighbor.ID)
+	neighbor.Parents.Remove(n.ID)
 	return nil
 }
 
-func (n *Node) queryBitmap(storage Storage, direction Direction) (*roaring.Bitmap, error) {
+func (n *Node) queryBitmap(ctx context.Context, storage Storage, direction Direction) (*roaring.Bitmap, error) {
 	if n == nil {
 		return nil, fmt.Errorf("cannot query bitmap of nil node")
 	}
@@ -244,7 +308,7 @@ func (n *Node) queryBitmap(storage Storage, direction Direction) (*roaring.Bitma
 
 		result.Or(bitmap)
 		for _, nID := range bitmap.Clone().ToArray() {
-			node, err := storage.GetNode(nID)
+			node, err := storage.GetNode(ctx, nID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get node: %w", err)
 			}
@@ -257,20 +321,20 @@ func (n *Node) queryBitmap(storage Storage, direction Direction) (*roaring.Bitma
 	return result, nil
 }
 
-func (n *Node) QueryDependentsNoCache(storage Storage) (*roaring.Bitmap, error) {
-	return n.queryBitmap(storage, ParentsDirection)
+func (n *Node) QueryDependentsNoCache(ctx context.Context, storage Storage) (*roaring.Bitmap, error) {
+	return n.queryBitmap(ctx, storage, ParentsDirection)
 }
 
-func (n *Node) QueryDependenciesNoCache(storage Storage) (*roaring.Bitmap, error) {
-	return n.queryBitmap(storage, ChildrenDirection)
+func (n *Node) QueryDependenciesNoCache(ctx context.Context, storage Storage) (*roaring.Bitmap, error) {
+	return n.queryBitmap(ctx, storage, ChildrenDirection)
 }
 
-func BatchQueryDependents(storage Storage, nodes []*Node, caches map[uint32]*NodeCache, isCached bool) (map[uint32]*roaring.Bitmap, error) {
+func BatchQueryDependents(ctx context.Context, storage Storage, nodes []*Node, caches map[uint32]*NodeCache, isCached bool) (map[uint32]*roaring.Bitmap, error) {
 	result := map[uint32]*roaring.Bitmap{}
 
 	for _, node := range nodes {
 		if !isCached {
-			ans, err := node.QueryDependentsNoCache(storage)
+			ans, err := node.QueryDependentsNoCache(ctx, storage)
 			if err != nil {
 				return nil, err
 			}
@@ -292,16 +356,16 @@ func BatchQueryDependents(storage Storage, nodes []*Node, caches map[uint32]*Nod
 }
 
 // QueryDependents checks if all nodes are cached, if so find the dependents in the cache, if not find the dependents without searching the cache
-func (n *Node) QueryDependents(storage Storage) (*roaring.Bitmap, error) {
-	uncachedNodes, err := storage.ToBeCached()
+func (n *Node) QueryDependents(ctx context.Context, storage Storage) (*roaring.Bitmap, error) {
+	uncachedNodes, err := storage.ToBeCached(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if len(uncachedNodes) > 0 {
-		return n.QueryDependentsNoCache(storage)
+		return n.QueryDependentsNoCache(ctx, storage)
 	}
 
-	nCache, err := storage.GetCache(n.ID)
+	nCache, err := storage.GetCache(ctx, n.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -309,7 +373,7 @@ func (n *Node) QueryDependents(storage Storage) (*roaring.Bitmap, error) {
 	return nCache.AllParents, nil
 }
 
-func BatchQueryDependencies(storage Storage, nodes []*Node, caches map[uint32]*NodeCache, isCached bool) (map[uint32]*roaring.Bitmap, error) {
+func BatchQueryDependencies(ctx context.Context, storage Storage, nodes []*Node, caches map[uint32]*NodeCache, isCached bool) (map[uint32]*roaring.Bitmap, error) {
 	result := map[uint32]*roaring.Bitmap{}
 
 	for _, node := range nodes {
@@ -317,7 +381,7 @@ func BatchQueryDependencies(storage Storage, nodes []*Node, caches map[uint32]*N
 			return nil, fmt.Errorf("node is nil is because the node was not found in the cache. Please check the cache for the node before querying dependencies")
 		}
 		if !isCached {
-			ans, err := node.QueryDependenciesNoCache(storage)
+			ans, err := node.QueryDependenciesNoCache(ctx, storage)
 			if err != nil {
 				return nil, err
 			}
@@ -331,16 +395,16 @@ func BatchQueryDependencies(storage Storage, nodes []*Node, caches map[uint32]*N
 	return result, nil
 }
 
-func (n *Node) QueryDependencies(storage Storage) (*roaring.Bitmap, error) {
-	uncachedNodes, err := storage.ToBeCached()
+func (n *Node) QueryDependencies(ctx context.Context, storage Storage) (*roaring.Bitmap, error) {
+	uncachedNodes, err := storage.ToBeCached(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if len(uncachedNodes) > 0 {
-		return n.QueryDependenciesNoCache(storage)
+		return n.QueryDependenciesNoCache(ctx, storage)
 	}
 
-	nCache, err := storage.GetCache(n.ID)
+	nCache, err := storage.GetCache(ctx, n.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -12,22 +14,34 @@ import (
 
 func TestAddNode(t *testing.T) {
 	storage := NewMockStorage()
-	node, err := AddNode(storage, "type1", "metadata1", "name1")
+	node, err := AddNode(context.Background(), storage, "type1", "metadata1", "name1")
 
 	assert.NoError(t, err)
-	pulledNode, err := storage.GetNode(node.ID)
+	pulledNode, err := storage.GetNode(context.Background(), node.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, node, pulledNode, "Expected 1 node")
 }
 
+func TestAddNodeLookupError(t *testing.T) {
+	storage := NewMockStorage()
+	storage.NameToIDErr = errors.New("connection refused")
+	_, err := AddNode(context.Background(), storage, "type1", "metadata1", "name1")
+	assert.ErrorIs(t, err, storage.NameToIDErr)
+
+	// A failed lookup must not be mistaken for a missing node.
+	keys, err := storage.GetAllKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
 func TestSetDependency(t *testing.T) {
 	storage := NewMockStorage()
-	node1, err := AddNode(storage, "type1", "metadata1", "name1")
+	node1, err := AddNode(context.Background(), storage, "type1", "metadata1", "name1")
 	assert.NoError(t, err, "Expected no error")
-	node2, err := AddNode(storage, "type2", "metadata2", "name2")
+	node2, err := AddNode(context.Background(), storage, "type2", "metadata2", "name2")
 	assert.NoError(t, err, "Expected no error")
 
-	err = node1.SetDependency(storage, node2)
+	err = node1.SetDependency(context.Background(), storage, node2)
 
 	assert.NoError(t, err)
 	assert.Contains(t, node1.Children.ToArray(), node2.ID, "Expected node1 to have node2 as child dependency")
@@ -36,12 +50,12 @@ func TestSetDependency(t *testing.T) {
 
 func TestSetDependent(t *testing.T) {
 	storage := NewMockStorage()
-	node1, err := AddNode(storage, "type1", "metadata1", "name1")
+	node1, err := AddNode(context.Background(), storage, "type1", "metadata1", "name1")
 	assert.NoError(t, err, "Expected no error")
-	node2, err := AddNode(storage, "type2", "metadata2", "name2")
+	node2, err := AddNode(context.Background(), storage, "type2", "metadata2", "name2")
 	assert.NoError(t, err, "Expected no error")
 
-	err = node1.SetDependency(storage, node2)
+	err = node1.SetDependency(context.Background(), storage, node2)
 
 	assert.NoError(t, err)
 	assert.Contains(t, node2.Parents.ToArray(), node1.ID, "Expected node2 to have node1 as parent dependency")
@@ -58,7 +72,7 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "empty graph",
 			setup: func(storage Storage) (*Node, error) {
-				return AddNode(storage, "type", "metadata", "empty")
+				return AddNode(context.Background(), storage, "type", "metadata", "empty")
 			},
 			wantDependents:   []uint32{1},
 			wantDependencies: []uint32{1},
@@ -66,9 +80,9 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "single parent-child relationship",
 			setup: func(storage Storage) (*Node, error) {
-				child, _ := AddNode(storage, "type", "metadata", "child")
-				parent, _ := AddNode(storage, "type", "metadata", "parent")
-				parent.SetDependency(storage, child)
+				child, _ := AddNode(context.Background(), storage, "type", "metadata", "child")
+				parent, _ := AddNode(context.Background(), storage, "type", "metadata", "parent")
+				parent.SetDependency(context.Background(), storage, child)
 				return parent, nil
 			},
 			wantDependents:   []uint32{2},
@@ -77,14 +91,14 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "complex graph",
 			setup: func(storage Storage) (*Node, error) {
-				n1, _ := AddNode(storage, "type", "metadata", "n1")
-				n2, _ := AddNode(storage, "type", "metadata", "n2")
-				n3, _ := AddNode(storage, "type", "metadata", "n3")
-				n4, _ := AddNode(storage, "type", "metadata", "n4")
-				n1.SetDependency(storage, n2)
-				n2.SetDependency(storage, n3)
-				n2.SetDependency(storage, n4)
-				n3.SetDependency(storage, n4)
+				n1, _ := AddNode(context.Background(), storage, "type", "metadata", "n1")
+				n2, _ := AddNode(context.Background(), storage, "type", "metadata", "n2")
+				n3, _ := AddNode(context.Background(), storage, "type", "metadata", "n3")
+				n4, _ := AddNode(context.Background(), storage, "type", "metadata", "n4")
+				n1.SetDependency(context.Background(), storage, n2)
+				n2.SetDependency(context.Background(), storage, n3)
+				n2.SetDependency(context.Background(), storage, n4)
+				n3.SetDependency(context.Background(), storage, n4)
 				return n2, nil
 			},
 			wantDependents:   []uint32{1, 2},
@@ -93,12 +107,12 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "cyclic graph",
 			setup: func(storage Storage) (*Node, error) {
-				n1, _ := AddNode(storage, "type", "metadata", "n1")
-				n2, _ := AddNode(storage, "type", "metadata", "n2")
-				n3, _ := AddNode(storage, "type", "metadata", "n3")
-				n1.SetDependency(storage, n2)
-				n2.SetDependency(storage, n3)
-				n3.SetDependency(storage, n1)
+				n1, _ := AddNode(context.Background(), storage, "type", "metadata", "n1")
+				n2, _ := AddNode(context.Background(), storage, "type", "metadata", "n2")
+				n3, _ := AddNode(context.Background(), storage, "type", "metadata", "n3")
+				n1.SetDependency(context.Background(), storage, n2)
+				n2.SetDependency(context.Background(), storage, n3)
+				n3.SetDependency(context.Background(), storage, n1)
 				return n1, nil
 			},
 			wantDependents:   []uint32{1, 2, 3},
@@ -107,15 +121,15 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "deep dependency chain",
 			setup: func(storage Storage) (*Node, error) {
-				n1, _ := AddNode(storage, "type", "metadata", "n1")
-				n2, _ := AddNode(storage, "type", "metadata", "n2")
-				n3, _ := AddNode(storage, "type", "metadata", "n3")
-				n4, _ := AddNode(storage, "type", "metadata", "n4")
-				n5, _ := AddNode(storage, "type", "metadata", "n5")
-				n1.SetDependency(storage, n2)
-				n2.SetDependency(storage, n3)
-				n3.SetDependency(storage, n4)
-				n4.SetDependency(storage, n5)
+				n1, _ := AddNode(context.Background(), storage, "type", "metadata", "n1")
+				n2, _ := AddNode(context.Background(), storage, "type", "metadata", "n2")
+				n3, _ := AddNode(context.Background(), storage, "type", "metadata", "n3")
+				n4, _ := AddNode(context.Background(), storage, "type", "metadata", "n4")
+				n5, _ := AddNode(context.Background(), storage, "type", "metadata", "n5")
+				n1.SetDependency(context.Background(), storage, n2)
+				n2.SetDependency(context.Background(), storage, n3)
+				n3.SetDependency(context.Background(), storage, n4)
+				n4.SetDependency(context.Background(), storage, n5)
 				return n3, nil
 			},
 			wantDependents:   []uint32{1, 2, 3},
@@ -124,14 +138,14 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 		{
 			name: "diamond dependency",
 			setup: func(storage Storage) (*Node, error) {
-				n1, _ := AddNode(storage, "type", "metadata", "n1")
-				n2, _ := AddNode(storage, "type", "metadata", "n2")
-				n3, _ := AddNode(storage, "type", "metadata", "n3")
-				n4, _ := AddNode(storage, "type", "metadata", "n4")
-				n1.SetDependency(storage, n2)
-				n1.SetDependency(storage, n3)
-				n2.SetDependency(storage, n4)
-				n3.SetDependency(storage, n4)
+				n1, _ := AddNode(context.Background(), storage, "type", "metadata", "n1")
+				n2, _ := AddNode(context.Background(), storage, "type", "metadata", "n2")
+				n3, _ := AddNode(context.Background(), storage, "type", "metadata", "n3")
+				n4, _ := AddNode(context.Background(), storage, "type", "metadata", "n4")
+				n1.SetDependency(context.Background(), storage, n2)
+				n1.SetDependency(context.Background(), storage, n3)
+				n2.SetDependency(context.Background(), storage, n4)
+				n3.SetDependency(context.Background(), storage, n4)
 				return n1, nil
 			},
 			wantDependents:   []uint32{1},
@@ -147,22 +161,22 @@ func TestQueryDependentsAndDependenciesNoCache(t *testing.T) {
 				t.Fatalf("Setup failed: %v", err)
 			}
 
-			gotDependents, err := node.QueryDependentsNoCache(storage)
+			gotDependents, err := node.QueryDependentsNoCache(context.Background(), storage)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("QueryDependentsNoCache() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("QueryDependentsNoCache(context.Background()) error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if err == nil && !reflect.DeepEqual(gotDependents.ToArray(), tt.wantDependents) {
-				t.Errorf("QueryDependentsNoCache() = %v, want %v", gotDependents.ToArray(), tt.wantDependents)
+				t.Errorf("QueryDependentsNoCache(context.Background()) = %v, want %v", gotDependents.ToArray(), tt.wantDependents)
 			}
 
-			gotDependencies, err := node.QueryDependenciesNoCache(storage)
+			gotDependencies, err := node.QueryDependenciesNoCache(context.Background(), storage)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("QueryDependenciesNoCache() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("QueryDependenciesNoCache(context.Background()) error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if err == nil && !reflect.DeepEqual(gotDependencies.ToArray(), tt.wantDependencies) {
-				t.Errorf("QueryDependenciesNoCache() = %v, want %v", gotDependencies.ToArray(), tt.wantDependencies)
+				t.Errorf("QueryDependenciesNoCache(context.Background()) = %v, want %v", gotDependencies.ToArray(), tt.wantDependencies)
 			}
 		})
 	}
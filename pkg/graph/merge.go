@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// MergeStrategy decides what happens to the metadata of a node that is added again.
+type MergeStrategy string
+
+const (
+	// MergeFirstWins keeps the metadata the node was first added with.
+	MergeFirstWins MergeStrategy = "first-wins"
+	// MergeLastWins replaces the metadata with the one the node was last added with.
+	MergeLastWins MergeStrategy = "last-wins"
+	// MergeDeep merges the metadata field by field. Objects are merged recursively, lists
+	// get the elements they don't have yet, and empty fields are filled in. Fields that are
+	// set on both sides keep their first value.
+	MergeDeep MergeStrategy = "deep-merge"
+)
+
+// MergeStrategies are all supported merge strategies.
+var MergeStrategies = []MergeStrategy{MergeFirstWins, MergeLastWins, MergeDeep}
+
+// ParseMergeStrategy returns the merge strategy with the given name.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	for _, strategy := range MergeStrategies {
+		if string(strategy) == name {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown merge strategy %q, expected one of %v", name, MergeStrategies)
+}
+
+// MergePolicy maps node types to the strategy their metadata is merged with. Types without an
+// entry use MergeFirstWins.
+type MergePolicy map[string]MergeStrategy
+
+// ParseMergePolicy parses a policy from type=strategy pairs.
+func ParseMergePolicy(pairs []string) (MergePolicy, error) {
+	policy := MergePolicy{}
+	for _, pair := range pairs {
+		nodeType, name, ok := strings.Cut(pair, "=")
+		if !ok || nodeType == "" {
+			return nil, fmt.Errorf("invalid merge policy %q, expected type=strategy", pair)
+		}
+		strategy, err := ParseMergeStrategy(name)
+		if err != nil {
+			return nil, err
+		}
+		policy[nodeType] = strategy
+	}
+	return policy, nil
+}
+
+// Strategy returns the merge strategy for nodes of the given type.
+func (p MergePolicy) Strategy(nodeType string) MergeStrategy {
+	if strategy, ok := p[nodeType]; ok {
+		return strategy
+	}
+	return MergeFirstWins
+}
+
+// String returns the policy as sorted type=strategy pairs.
+func (p MergePolicy) String() string {
+	pairs := make([]string, 0, len(p))
+	for nodeType, strategy := range p {
+		pairs = append(pairs, nodeType+"="+string(strategy))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// AddOrMergeNode is AddNode for nodes that may already exist. The metadata of an existing node
+// is merged with the given metadata by the strategy the policy has for the node's type.
+func AddOrMergeNode(ctx context.Context, storage Storage, policy MergePolicy, _type string, metadata any, name string) (*Node, error) {
+	strategy := policy.Strategy(_type)
+	if strategy == MergeFirstWins {
+		return AddNode(ctx, storage, _type, metadata, name)
+	}
+	id, err := storage.NameToID(ctx, name)
+	if errors.Is(err, ErrNodeNotFound) {
+		return AddNode(ctx, storage, _type, metadata, name)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to look up node %s: %w", name, err)
+	}
+	node, err := storage.GetNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return MergeNodeMetadata(ctx, storage, node, metadata, strategy)
+}
+
+// MergeNodeMetadata merges the metadata into the node by the given strategy and returns the
+// updated node. The storage isn't written to if the merge doesn't change the metadata.
+func MergeNodeMetadata(ctx context.Context, storage Storage, node *Node, metadata any, strategy MergeStrategy) (*Node, error) {
+	merged, changed, err := MergeMetadata(strategy, node.Metadata, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return node, nil
+	}
+	updated, err := storage.UpdateNodeMetadata(ctx, node.ID, func(current any) (any, error) {
+		// The metadata may have changed since the node was read.
+		if reflect.DeepEqual(current, node.Metadata) {
+			return merged, nil
+		}
+		remerged, _, err := MergeMetadata(strategy, current, metadata)
+		return remerged, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update metadata of node %d: %w", node.ID, err)
+	}
+	return updated, nil
+}
+
+// MergeMetadata merges incoming into existing metadata by the given strategy. It reports
+// whether the result differs from the existing metadata.
+func MergeMetadata(strategy MergeStrategy, existing, incoming any) (any, bool, error) {
+	switch strategy {
+	case MergeFirstWins:
+		return existing, false, nil
+	case MergeLastWins:
+		changed, err := metadataDiffers(existing, incoming)
+		if err != nil {
+			return nil, false, err
+		}
+		return incoming, changed, nil
+	case MergeDeep:
+		existingValue, err := metadataValue(existing)
+		if err != nil {
+			return nil, false, err
+		}
+		incomingValue, err := metadataValue(incoming)
+		if err != nil {
+			return nil, false, err
+		}
+		merged := deepMerge(existingValue, incomingValue)
+		return merged, !reflect.DeepEqual(existingValue, merged), nil
+	default:
+		return nil, false, fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+}
+
+// metadataValue converts metadata into the plain maps, lists and scalars it is stored as, so
+// metadata of a freshly parsed document compares equal to the same metadata read back from a
+// storage.
+func metadataValue(metadata any) (any, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return value, nil
+}
+
+func metadataDiffers(a, b any) (bool, error) {
+	aValue, err := metadataValue(a)
+	if err != nil {
+		return false, err
+	}
+	bValue, err := metadataValue(b)
+	if err != nil {
+		return false, err
+	}
+	return !reflect.DeepEqual(aValue, bValue), nil
+}
+
+func deepMerge(existing, incoming any) any {
+	if isEmptyValue(existing) {
+		return incoming
+	}
+	switch existing := existing.(type) {
+	case map[string]any:
+		incoming, ok := incoming.(map[string]any)
+		if !ok {
+			return existing
+		}
+		merged := make(map[string]any, len(existing))
+		for key, value := range existing {
+			merged[key] = value
+		}
+		for key, value := range incoming {
+			merged[key] = deepMerge(merged[key], value)
+		}
+		return merged
+	case []any:
+		incoming, ok := incoming.([]any)
+		if !ok {
+			return existing
+		}
+		merged := append([]any(nil), existing...)
+		for _, value := range incoming {
+			if !containsValue(merged, value) {
+				merged = append(merged, value)
+			}
+		}
+		return merged
+	default:
+		return existing
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case float64:
+		return value == 0
+	case bool:
+		return !value
+	case map[string]any:
+		return len(value) == 0
+	case []any:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergePolicy(t *testing.T) {
+	policy, err := ParseMergePolicy([]string{"library=deep-merge", "scorecard=last-wins"})
+	require.NoError(t, err)
+	assert.Equal(t, MergeDeep, policy.Strategy("library"))
+	assert.Equal(t, MergeLastWins, policy.Strategy("scorecard"))
+	assert.Equal(t, MergeFirstWins, policy.Strategy("vuln"))
+	assert.Equal(t, "library=deep-merge,scorecard=last-wins", policy.String())
+
+	var empty MergePolicy
+	assert.Equal(t, MergeFirstWins, empty.Strategy("library"))
+
+	for _, pairs := range [][]string{{"library"}, {"=deep-merge"}, {"library=newest"}} {
+		_, err := ParseMergePolicy(pairs)
+		assert.Error(t, err, pairs)
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	existing := map[string]any{
+		"name":     "a",
+		"version":  "",
+		"licenses": []any{"MIT"},
+		"hashes":   map[string]any{"1": "abc"},
+	}
+	incoming := map[string]any{
+		"name":      "b",
+		"version":   "1.0.0",
+		"licenses":  []any{"MIT", "Apache-2.0"},
+		"hashes":    map[string]any{"1": "def", "2": "123"},
+		"suppliers": []any{map[string]any{"name": "acme"}},
+	}
+
+	tests := []struct {
+		strategy MergeStrategy
+		want     string
+		changed  bool
+	}{
+		{MergeFirstWins, `{"name":"a","version":"","licenses":["MIT"],"hashes":{"1":"abc"}}`, false},
+		{MergeLastWins, `{"name":"b","version":"1.0.0","licenses":["MIT","Apache-2.0"],"hashes":{"1":"def","2":"123"},"suppliers":[{"name":"acme"}]}`, true},
+		{MergeDeep, `{"name":"a","version":"1.0.0","licenses":["MIT","Apache-2.0"],"hashes":{"1":"abc","2":"123"},"suppliers":[{"name":"acme"}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			merged, changed, err := MergeMetadata(tt.strategy, existing, incoming)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+			data, err := json.Marshal(merged)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+
+	// Merging the same metadata again changes nothing.
+	merged, _, err := MergeMetadata(MergeDeep, existing, incoming)
+	require.NoError(t, err)
+	_, changed, err := MergeMetadata(MergeDeep, merged, incoming)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	_, changed, err = MergeMetadata(MergeLastWins, incoming, incoming)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	_, _, err = MergeMetadata("newest", existing, incoming)
+	assert.Error(t, err)
+}
+
+func TestAddOrMergeNode(t *testing.T) {
+	storage := NewMockStorage()
+	policy := MergePolicy{"library": MergeDeep}
+
+	node, err := AddOrMergeNode(context.Background(), storage, policy, "library", map[string]any{"licenses": []any{"MIT"}}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	other, err := AddNode(context.Background(), storage, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, node.SetDependency(context.Background(), storage, other))
+
+	merged, err := AddOrMergeNode(context.Background(), storage, policy, "library", map[string]any{"licenses": []any{"Apache-2.0"}}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, node.ID, merged.ID)
+	assert.Equal(t, []uint32{other.ID}, merged.Children.ToArray())
+	got, err := storage.GetNode(context.Background(), node.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"licenses": []any{"MIT", "Apache-2.0"}}, got.Metadata)
+
+	// Types without a strategy keep their first metadata.
+	_, err = AddOrMergeNode(context.Background(), storage, policy, "vuln", "first", "GHSA-1")
+	require.NoError(t, err)
+	vuln, err := AddOrMergeNode(context.Background(), storage, policy, "vuln", "second", "GHSA-1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", vuln.Metadata)
+
+	storage.UpdateNodeMetadataErr = assert.AnError
+	_, err = AddOrMergeNode(context.Background(), storage, policy, "library", map[string]any{"licenses": []any{"BSD-3-Clause"}}, "pkg:npm/a@1.0.0")
+	assert.ErrorIs(t, err, assert.AnError)
+}
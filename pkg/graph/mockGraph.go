@@ -1,25 +1,33 @@
 package graph
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/utils"
 )
 
+// customDataKey identifies the custom data stored for a tag and key.
+type customDataKey struct {
+	tag, key string
+}
+
 type MockStorage struct {
 	nodes        map[uint32]*Node
 	dependencies map[uint32]*roaring.Bitmap
 	dependents   map[uint32]*roaring.Bitmap
 	nameToID     map[string]uint32
+	aliasToID    map[string]uint32
 	cache        map[uint32]*NodeCache
 	toBeCached   []uint32
 	mu           sync.Mutex
 	idCounter    uint32
+	version      int
 	fullyCached  bool
-	db           map[string]map[string][]byte
+	db           map[customDataKey]map[string][]byte
 
 	// Error injection fields
 	SaveNodeErr              error
@@ -39,6 +47,18 @@ type MockStorage struct {
 	RemoveAllCachesErr       error
 	AddOrUpdateCustomDataErr error
 	GetCustomDataErr         error
+	GetCustomDataKeysErr     error
+	GetIDCounterErr          error
+	SetIDCounterErr          error
+	AddDependencyErr         error
+	RemoveDependencyErr      error
+	AddAliasesErr            error
+	AliasToIDErr             error
+	GetAliasesErr            error
+	DeleteNodeErr            error
+	UpdateNodeMetadataErr    error
+	GetSchemaVersionErr      error
+	SetSchemaVersionErr      error
 }
 
 func NewMockStorage() *MockStorage {
@@ -47,12 +67,13 @@ func NewMockStorage() *MockStorage {
 		dependencies: make(map[uint32]*roaring.Bitmap),
 		dependents:   make(map[uint32]*roaring.Bitmap),
 		nameToID:     make(map[string]uint32),
+		aliasToID:    make(map[string]uint32),
 		idCounter:    0,
-		db:           make(map[string]map[string][]byte),
+		db:           make(map[customDataKey]map[string][]byte),
 	}
 }
 
-func (m *MockStorage) SaveNode(node *Node) error {
+func (m *MockStorage) SaveNode(ctx context.Context, node *Node) error {
 	if m.SaveNodeErr != nil {
 		return m.SaveNodeErr
 	}
@@ -64,7 +85,22 @@ func (m *MockStorage) SaveNode(node *Node) error {
 	return nil
 }
 
-func (m *MockStorage) GetNode(id uint32) (*Node, error) {
+func (m *MockStorage) CreateNode(ctx context.Context, node *Node) (uint32, error) {
+	if m.SaveNodeErr != nil {
+		return 0, m.SaveNodeErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, exists := m.nameToID[node.Name]; exists {
+		return id, nil
+	}
+	m.nameToID[node.Name] = node.ID
+	m.nodes[node.ID] = node
+	m.toBeCached = append(m.toBeCached, node.ID)
+	return node.ID, nil
+}
+
+func (m *MockStorage) GetNode(ctx context.Context, id uint32) (*Node, error) {
 	if m.GetNodeErr != nil {
 		return nil, m.GetNodeErr
 	}
@@ -72,12 +108,12 @@ func (m *MockStorage) GetNode(id uint32) (*Node, error) {
 	defer m.mu.Unlock()
 	node, exists := m.nodes[id]
 	if !exists {
-		return nil, fmt.Errorf("node %v not found", id)
+		return nil, fmt.Errorf("node %v: %w", id, ErrNodeNotFound)
 	}
 	return node, nil
 }
 
-func (m *MockStorage) GetNodesByGlob(pattern string) ([]*Node, error) {
+func (m *MockStorage) GetNodesByGlob(ctx context.Context, pattern string) ([]*Node, error) {
 	if m.GetNodesByGlobErr != nil {
 		return nil, m.GetNodesByGlobErr
 	}
@@ -86,18 +122,14 @@ func (m *MockStorage) GetNodesByGlob(pattern string) ([]*Node, error) {
 
 	nodes := make([]*Node, 0)
 	for _, node := range m.nodes {
-		matched, err := filepath.Match(pattern, node.Name)
-		if err != nil {
-			return nil, fmt.Errorf("invalid glob pattern: %v", err)
-		}
-		if matched {
+		if utils.MatchGlob(pattern, node.Name) {
 			nodes = append(nodes, node)
 		}
 	}
 	return nodes, nil
 }
 
-func (m *MockStorage) GetAllKeys() ([]uint32, error) {
+func (m *MockStorage) GetAllKeys(ctx context.Context) ([]uint32, error) {
 	if m.GetAllKeysErr != nil {
 		return nil, m.GetAllKeysErr
 	}
@@ -112,7 +144,7 @@ func (m *MockStorage) GetAllKeys() ([]uint32, error) {
 	return keys, nil
 }
 
-func (m *MockStorage) SaveCache(cache *NodeCache) error {
+func (m *MockStorage) SaveCache(ctx context.Context, cache *NodeCache) error {
 	if m.SaveCacheErr != nil {
 		return m.SaveCacheErr
 	}
@@ -125,7 +157,7 @@ func (m *MockStorage) SaveCache(cache *NodeCache) error {
 	return nil
 }
 
-func (m *MockStorage) ToBeCached() ([]uint32, error) {
+func (m *MockStorage) ToBeCached(ctx context.Context) ([]uint32, error) {
 	if m.ToBeCachedErr != nil {
 		return nil, m.ToBeCachedErr
 	}
@@ -134,7 +166,7 @@ func (m *MockStorage) ToBeCached() ([]uint32, error) {
 	return m.toBeCached, nil
 }
 
-func (m *MockStorage) AddNodeToCachedStack(id uint32) error {
+func (m *MockStorage) AddNodeToCachedStack(ctx context.Context, id uint32) error {
 	if m.AddNodeToCachedStackErr != nil {
 		return m.AddNodeToCachedStackErr
 	}
@@ -142,7 +174,7 @@ func (m *MockStorage) AddNodeToCachedStack(id uint32) error {
 	return nil
 }
 
-func (m *MockStorage) ClearCacheStack() error {
+func (m *MockStorage) ClearCacheStack(ctx context.Context) error {
 	if m.ClearCacheStackErr != nil {
 		return m.ClearCacheStackErr
 	}
@@ -150,19 +182,19 @@ func (m *MockStorage) ClearCacheStack() error {
 	return nil
 }
 
-func (m *MockStorage) GetCache(id uint32) (*NodeCache, error) {
+func (m *MockStorage) GetCache(ctx context.Context, id uint32) (*NodeCache, error) {
 	if m.GetCacheErr != nil {
 		return nil, m.GetCacheErr
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if _, ok := m.cache[id]; !ok {
-		return nil, errors.New("cache not found")
+		return nil, fmt.Errorf("cache %v: %w", id, ErrCacheNotFound)
 	}
 	return m.cache[id], nil
 }
 
-func (m *MockStorage) GenerateID() (uint32, error) {
+func (m *MockStorage) GenerateID(ctx context.Context) (uint32, error) {
 	if m.GenerateIDErr != nil {
 		return 0, m.GenerateIDErr
 	}
@@ -172,7 +204,7 @@ func (m *MockStorage) GenerateID() (uint32, error) {
 	return m.idCounter, nil
 }
 
-func (m *MockStorage) NameToID(name string) (uint32, error) {
+func (m *MockStorage) NameToID(ctx context.Context, name string) (uint32, error) {
 	if m.NameToIDErr != nil {
 		return 0, m.NameToIDErr
 	}
@@ -181,10 +213,10 @@ func (m *MockStorage) NameToID(name string) (uint32, error) {
 	if id, exists := m.nameToID[name]; exists {
 		return id, nil
 	}
-	return 0, errors.New("node with name not found")
+	return 0, fmt.Errorf("node %s: %w", name, ErrNodeNotFound)
 }
 
-func (m *MockStorage) GetNodes(ids []uint32) (map[uint32]*Node, error) {
+func (m *MockStorage) GetNodes(ctx context.Context, ids []uint32) (map[uint32]*Node, error) {
 	if m.GetNodesErr != nil {
 		return nil, m.GetNodesErr
 	}
@@ -203,7 +235,7 @@ func (m *MockStorage) GetNodes(ids []uint32) (map[uint32]*Node, error) {
 	return nodes, nil
 }
 
-func (m *MockStorage) SaveCaches(caches []*NodeCache) error {
+func (m *MockStorage) SaveCaches(ctx context.Context, caches []*NodeCache) error {
 	if m.SaveCachesErr != nil {
 		return m.SaveCachesErr
 	}
@@ -218,7 +250,7 @@ func (m *MockStorage) SaveCaches(caches []*NodeCache) error {
 	return nil
 }
 
-func (m *MockStorage) GetCaches(ids []uint32) (map[uint32]*NodeCache, error) {
+func (m *MockStorage) GetCaches(ctx context.Context, ids []uint32) (map[uint32]*NodeCache, error) {
 	if m.GetCachesErr != nil {
 		return nil, m.GetCachesErr
 	}
@@ -237,7 +269,7 @@ func (m *MockStorage) GetCaches(ids []uint32) (map[uint32]*NodeCache, error) {
 	return caches, nil
 }
 
-func (m *MockStorage) RemoveAllCaches() error {
+func (m *MockStorage) RemoveAllCaches(ctx context.Context) error {
 	if m.RemoveAllCachesErr != nil {
 		return m.RemoveAllCachesErr
 	}
@@ -255,13 +287,13 @@ func (m *MockStorage) RemoveAllCaches() error {
 	return nil
 }
 
-func (m *MockStorage) AddOrUpdateCustomData(tag, key, dataKey string, data []byte) error {
+func (m *MockStorage) AddOrUpdateCustomData(ctx context.Context, tag, key, dataKey string, data []byte) error {
 	if m.AddOrUpdateCustomDataErr != nil {
 		return m.AddOrUpdateCustomDataErr
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	fullKey := fmt.Sprintf("%s:%s", tag, key)
+	fullKey := customDataKey{tag: tag, key: key}
 	if m.db[fullKey] == nil {
 		m.db[fullKey] = make(map[string][]byte)
 	}
@@ -269,16 +301,202 @@ func (m *MockStorage) AddOrUpdateCustomData(tag, key, dataKey string, data []byt
 	return nil
 }
 
-func (m *MockStorage) GetCustomData(tag, key string) (map[string][]byte, error) {
+func (m *MockStorage) GetCustomData(ctx context.Context, tag, key string) (map[string][]byte, error) {
 	if m.GetCustomDataErr != nil {
 		return nil, m.GetCustomDataErr
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	fullKey := fmt.Sprintf("%s:%s", tag, key)
-	data, exists := m.db[fullKey]
+	result := make(map[string][]byte)
+	for dataKey, value := range m.db[customDataKey{tag: tag, key: key}] {
+		result[dataKey] = value
+	}
+	return result, nil
+}
+
+func (m *MockStorage) GetCustomDataKeys(ctx context.Context) (map[string][]string, error) {
+	if m.GetCustomDataKeysErr != nil {
+		return nil, m.GetCustomDataKeysErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make(map[string][]string)
+	for k := range m.db {
+		keys[k.tag] = append(keys[k.tag], k.key)
+	}
+	return keys, nil
+}
+
+func (m *MockStorage) GetIDCounter(ctx context.Context) (uint32, error) {
+	if m.GetIDCounterErr != nil {
+		return 0, m.GetIDCounterErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.idCounter, nil
+}
+
+func (m *MockStorage) SetIDCounter(ctx context.Context, id uint32) error {
+	if m.SetIDCounterErr != nil {
+		return m.SetIDCounterErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id > m.idCounter {
+		m.idCounter = id
+	}
+	return nil
+}
+
+func (m *MockStorage) AddDependency(ctx context.Context, from, to uint32) error {
+	if m.AddDependencyErr != nil {
+		return m.AddDependencyErr
+	}
+	return m.updateEdge(ctx, from, to, func(fromNode, toNode *Node) {
+		fromNode.Children.Add(to)
+		toNode.Parents.Add(from)
+	})
+}
+
+func (m *MockStorage) RemoveDependency(ctx context.Context, from, to uint32) error {
+	if m.RemoveDependencyErr != nil {
+		return m.RemoveDependencyErr
+	}
+	return m.updateEdge(ctx, from, to, func(fromNode, toNode *Node) {
+		fromNode.Children.Remove(to)
+		toNode.Parents.Remove(from)
+	})
+}
+
+func (m *MockStorage) updateEdge(ctx context.Context, from, to uint32, update func(fromNode, toNode *Node)) error {
+	if from == to {
+		return ErrSelfDependency
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fromNode, exists := m.nodes[from]
+	if !exists {
+		return fmt.Errorf("node %v: %w", from, ErrNodeNotFound)
+	}
+	toNode, exists := m.nodes[to]
 	if !exists {
-		return nil, fmt.Errorf("no data found for tag: %s, key: %s", tag, key)
+		return fmt.Errorf("node %v: %w", to, ErrNodeNotFound)
 	}
-	return data, nil
+	update(fromNode, toNode)
+	m.toBeCached = append(m.toBeCached, from, to)
+	return nil
+}
+
+func (m *MockStorage) AddAliases(ctx context.Context, id uint32, aliases []string) error {
+	if m.AddAliasesErr != nil {
+		return m.AddAliasesErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.nodes[id]; !exists {
+		return fmt.Errorf("node %v: %w", id, ErrNodeNotFound)
+	}
+	for _, alias := range aliases {
+		if _, exists := m.aliasToID[alias]; !exists {
+			m.aliasToID[alias] = id
+		}
+	}
+	return nil
+}
+
+func (m *MockStorage) AliasToID(ctx context.Context, alias string) (uint32, error) {
+	if m.AliasToIDErr != nil {
+		return 0, m.AliasToIDErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, exists := m.aliasToID[alias]
+	if !exists {
+		return 0, fmt.Errorf("alias %s: %w", alias, ErrNodeNotFound)
+	}
+	return id, nil
+}
+
+func (m *MockStorage) GetAliases(ctx context.Context, id uint32) ([]string, error) {
+	if m.GetAliasesErr != nil {
+		return nil, m.GetAliasesErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	aliases := []string{}
+	for alias, aliasID := range m.aliasToID {
+		if aliasID == id {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+func (m *MockStorage) DeleteNode(ctx context.Context, id uint32) error {
+	if m.DeleteNodeErr != nil {
+		return m.DeleteNodeErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, exists := m.nodes[id]
+	if !exists {
+		return fmt.Errorf("node %v: %w", id, ErrNodeNotFound)
+	}
+	if !node.Children.IsEmpty() || !node.Parents.IsEmpty() {
+		return fmt.Errorf("node %v: %w", id, ErrNodeHasEdges)
+	}
+	delete(m.nodes, id)
+	delete(m.nameToID, node.Name)
+	for alias, aliasID := range m.aliasToID {
+		if aliasID == id {
+			delete(m.aliasToID, alias)
+		}
+	}
+	delete(m.cache, id)
+	toBeCached := m.toBeCached[:0]
+	for _, cached := range m.toBeCached {
+		if cached != id {
+			toBeCached = append(toBeCached, cached)
+		}
+	}
+	m.toBeCached = toBeCached
+	return nil
+}
+
+func (m *MockStorage) UpdateNodeMetadata(ctx context.Context, id uint32, update func(metadata any) (any, error)) (*Node, error) {
+	if m.UpdateNodeMetadataErr != nil {
+		return nil, m.UpdateNodeMetadataErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, exists := m.nodes[id]
+	if !exists {
+		return nil, fmt.Errorf("node %v: %w", id, ErrNodeNotFound)
+	}
+	metadata, err := update(node.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	node.Metadata = metadata
+	return node, nil
+}
+
+func (m *MockStorage) GetSchemaVersion(ctx context.Context) (int, error) {
+	if m.GetSchemaVersionErr != nil {
+		return 0, m.GetSchemaVersionErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.version, nil
+}
+
+func (m *MockStorage) SetSchemaVersion(ctx context.Context, version int) error {
+	if m.SetSchemaVersionErr != nil {
+		return m.SetSchemaVersionErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.version = version
+	return nil
 }
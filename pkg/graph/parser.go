@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/RoaringBitmap/roaring"
@@ -29,14 +31,26 @@ type Term struct {
 }
 
 type Query struct {
-	QueryType string  `@Ident`  // For example "dependencies" or "dependents"
-	NodeType  string  `@Ident`  // For example "library" or "vulns"
-	NodeName  *string `@Ident?` // NodeName is now optional // The purl being inputted
+	QueryType string  `@Ident`                                             // For example "dependencies" or "dependents"
+	NodeType  string  `@Ident`                                             // For example "library" or "vulns"
+	NodeName  *string `@((?! "where" Ident Comparator) (Ident | String))?` // The optional name or alias of the node, quoted if it has other characters, like CPEs
+	Filter    *Filter `@@?`                                                // The optional filter of the resulting nodes, like "where severity_score >= 7"
+}
+
+// Filter keeps the nodes whose metadata field compares to the value. Fields are the keys of
+// the metadata, nested ones joined by dots. where isn't reserved: it only starts a filter when
+// a field and a comparator follow it, otherwise it is a node name.
+type Filter struct {
+	Field string `"where" @Ident`
+	Op    string `@Comparator`
+	Value string `@(Ident | String | Number)`
 }
 
 var (
 	simpleLexer = lexer.MustSimple([]lexer.SimpleRule{
-		{"Operator", `\b(?:and|or|xor)\b`},      // Prioritize operators
+		{"Operator", `\b(?:and|or|xor)\b`}, // Prioritize operators
+		{"Comparator", `>=|<=|!=|==|=|>|<`},
+		{"Number", `-?[0-9]+(?:\.[0-9]+)?`},
 		{"Ident", `[a-zA-Z][a-zA-Z0-9:/._@-]*`}, // Updated to handle colons, slashes, dots, underscores, hyphens, and @
 		{"String", `"(?:\\.|[^"])*"`},
 		{"Whitespace", `[ \t\n\r]+`},
@@ -48,11 +62,13 @@ var (
 	parser = participle.MustBuild[Expression](
 		participle.Lexer(simpleLexer),
 		participle.Elide("Whitespace"),
+		participle.Unquote("String"),
 	)
 )
 
-// ParseAndExecute parses and executes a script using the given storage backend.
-func ParseAndExecute(script string, storage Storage, defaultNodeName string, nodes map[uint32]*Node, caches map[uint32]*NodeCache, isCached bool) (*roaring.Bitmap, error) {
+// ParseAndExecute parses and executes a script using the given storage backend. Dependencies
+// the suppressions say a vulnerability doesn't affect don't have it; suppressions may be nil.
+func ParseAndExecute(ctx context.Context, script string, storage Storage, defaultNodeName string, nodes map[uint32]*Node, caches map[uint32]*NodeCache, isCached bool, suppressions Suppressions) (*roaring.Bitmap, error) {
 	nameToIDs := make(map[string]uint32, len(nodes))
 	for _, node := range nodes {
 		if node == nil {
@@ -68,6 +84,9 @@ func ParseAndExecute(script string, storage Storage, defaultNodeName string, nod
 
 	// Collect all packages for batch querying
 	dependenciesToQuery, dependentsToQuery := collectPackages(expression, defaultNodeName)
+	if err := resolveAliases(ctx, storage, append(dependenciesToQuery, dependentsToQuery...), nameToIDs, nodes); err != nil {
+		return nil, err
+	}
 
 	var nodeDependencies, nodeDependents []*Node
 
@@ -95,17 +114,32 @@ func ParseAndExecute(script string, storage Storage, defaultNodeName string, nod
 		caches = make(map[uint32]*NodeCache)
 	}
 
-	dependenciesForID, err := BatchQueryDependencies(storage, nodeDependencies, caches, isCached)
+	dependenciesForID, err := BatchQueryDependencies(ctx, storage, nodeDependencies, caches, isCached)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get dependencies from batch query: %v", err)
+		return nil, fmt.Errorf("failed to get dependencies from batch query: %w", err)
 	}
-	dependentsForID, err := BatchQueryDependents(storage, nodeDependents, caches, isCached)
+	dependentsForID, err := BatchQueryDependents(ctx, storage, nodeDependents, caches, isCached)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get dependents from batch query: %v", err)
+		return nil, fmt.Errorf("failed to get dependents from batch query: %w", err)
+	}
+	// Whether a vulnerability is suppressed for its dependents is resolved once per
+	// vulnerability, rather than for each dependent against its dependencies.
+	suppressedDependents := map[uint32]*roaring.Bitmap{}
+	if len(suppressions) > 0 {
+		dependentsOf := func(parents []*Node) (map[uint32]*roaring.Bitmap, error) {
+			return BatchQueryDependents(ctx, storage, parents, caches, isCached)
+		}
+		for id, dependents := range dependentsForID {
+			suppressed, err := suppressions.SuppressedDependents(id, dependents, nodes, dependentsOf)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dependents of suppressed vulnerabilities: %w", err)
+			}
+			suppressedDependents[id] = suppressed
+		}
 	}
 
 	// Iterate through the parsed structure
-	bm, err := iterateExpression(expression, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName)
+	bm, err := iterateExpression(expression, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName, suppressions, suppressedDependents)
 	if err != nil {
 		return nil, fmt.Errorf("failed to iterate expression: %v", err)
 	}
@@ -117,6 +151,26 @@ func ParseAndExecute(script string, storage Storage, defaultNodeName string, nod
 	return bm, nil
 }
 
+// resolveAliases adds the packages that aren't named by a node but are an alias of one to
+// nameToIDs.
+func resolveAliases(ctx context.Context, storage Storage, packages []purlData, nameToIDs map[string]uint32, nodes map[uint32]*Node) error {
+	for _, pkg := range packages {
+		if _, exists := nameToIDs[pkg.purl]; exists {
+			continue
+		}
+		id, err := storage.AliasToID(ctx, pkg.purl)
+		if errors.Is(err, ErrNodeNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to resolve alias %s: %w", pkg.purl, err)
+		}
+		if _, exists := nodes[id]; exists {
+			nameToIDs[pkg.purl] = id
+		}
+	}
+	return nil
+}
+
 type purlData struct {
 	purl  string
 	_type string
@@ -168,18 +222,18 @@ func collectPackagesFromTerm(term *Term, dependenciesToQuery, dependentsToQuery
 }
 
 // iterateExpression iterates through the expression and returns the result
-func iterateExpression(expr *Expression, dependenciesForID, dependentsForID map[uint32]*roaring.Bitmap, nameToIDs map[string]uint32, nodes map[uint32]*Node, defaultNodeName string) (*roaring.Bitmap, error) {
+func iterateExpression(expr *Expression, dependenciesForID, dependentsForID map[uint32]*roaring.Bitmap, nameToIDs map[string]uint32, nodes map[uint32]*Node, defaultNodeName string, suppressions Suppressions, suppressedDependents map[uint32]*roaring.Bitmap) (*roaring.Bitmap, error) {
 	if expr == nil {
 		return nil, nil
 	}
 
-	bm, err := iterateTerm(expr.Left, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName)
+	bm, err := iterateTerm(expr.Left, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName, suppressions, suppressedDependents)
 	if err != nil {
 		return nil, err
 	}
 
 	if expr.Op != nil {
-		bm2, err := iterateExpression(expr.Right, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName)
+		bm2, err := iterateExpression(expr.Right, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName, suppressions, suppressedDependents)
 
 		if err != nil {
 			return nil, err
@@ -200,7 +254,7 @@ func iterateExpression(expr *Expression, dependenciesForID, dependentsForID map[
 	return bm, nil
 }
 
-func iterateTerm(term *Term, dependenciesForID, dependentsForID map[uint32]*roaring.Bitmap, nameToIDs map[string]uint32, nodes map[uint32]*Node, defaultNodeName string) (*roaring.Bitmap, error) {
+func iterateTerm(term *Term, dependenciesForID, dependentsForID map[uint32]*roaring.Bitmap, nameToIDs map[string]uint32, nodes map[uint32]*Node, defaultNodeName string, suppressions Suppressions, suppressedDependents map[uint32]*roaring.Bitmap) (*roaring.Bitmap, error) {
 	if term == nil {
 		return nil, nil
 	}
@@ -218,13 +272,15 @@ func iterateTerm(term *Term, dependenciesForID, dependentsForID map[uint32]*roar
 		switch term.Query.QueryType {
 		case dependencies:
 			for _, depId := range dependenciesForID[id].ToArray() {
-				if nodes[depId] != nil && nodes[depId].Type == term.Query.NodeType {
+				if nodes[depId] != nil && nodes[depId].Type == term.Query.NodeType && term.Query.Filter.Matches(nodes[depId]) &&
+					!suppressions.Suppressed(id, depId, dependenciesForID[id], nodes) {
 					bm.Add(depId)
 				}
 			}
 		case dependents:
 			for _, depId := range dependentsForID[id].ToArray() {
-				if nodes[depId] != nil && nodes[depId].Type == term.Query.NodeType {
+				if nodes[depId] != nil && nodes[depId].Type == term.Query.NodeType && term.Query.Filter.Matches(nodes[depId]) &&
+					(suppressedDependents[id] == nil || !suppressedDependents[id].Contains(depId)) {
 					bm.Add(depId)
 				}
 			}
@@ -234,7 +290,7 @@ func iterateTerm(term *Term, dependenciesForID, dependentsForID map[uint32]*roar
 	}
 
 	if term.Expression != nil {
-		_, err := iterateExpression(term.Expression, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName)
+		_, err := iterateExpression(term.Expression, dependenciesForID, dependentsForID, nameToIDs, nodes, defaultNodeName, suppressions, suppressedDependents)
 		if err != nil {
 			return nil, err
 		}
@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/RoaringBitmap/roaring"
@@ -9,37 +11,41 @@ import (
 func TestParseAndExecute(t *testing.T) {
 	storage := NewMockStorage()
 
-	node1, err := AddNode(storage, "PACKAGE", nil, "pkg:generic/lib-A@1.0.0")
+	node1, err := AddNode(context.Background(), storage, "PACKAGE", nil, "pkg:generic/lib-A@1.0.0")
 	if err != nil {
 		t.Fatal(err)
 	}
-	node2, err := AddNode(storage, "PACKAGE", nil, "pkg:generic/lib-B@1.0.0")
+	node2, err := AddNode(context.Background(), storage, "PACKAGE", nil, "pkg:generic/lib-B@1.0.0")
 	if err != nil {
 		t.Fatal(err)
 	}
-	node3, err := AddNode(storage, "PACKAGE", nil, "pkg:generic/dep1@1.0.0")
+	node3, err := AddNode(context.Background(), storage, "PACKAGE", nil, "pkg:generic/dep1@1.0.0")
 	if err != nil {
 		t.Fatal(err)
 	}
-	node4, err := AddNode(storage, "PACKAGE", nil, "pkg:generic/dep2@1.0.0")
+	node4, err := AddNode(context.Background(), storage, "PACKAGE", nil, "pkg:generic/dep2@1.0.0")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = node1.SetDependency(storage, node3)
+	err = node1.SetDependency(context.Background(), storage, node3)
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = node2.SetDependency(storage, node3)
+	err = node2.SetDependency(context.Background(), storage, node3)
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = node3.SetDependency(storage, node4)
+	err = node3.SetDependency(context.Background(), storage, node4)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := Cache(storage); err != nil {
+	if err := storage.AddAliases(context.Background(), node3.ID, []string{"cpe:2.3:a:generic:dep1:1.0.0:*:*:*:*:*:*:*", "sha256:d1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Cache(context.Background(), storage); err != nil {
 		t.Fatal(err)
 	}
 
@@ -80,6 +86,24 @@ func TestParseAndExecute(t *testing.T) {
 			wantErr:         true,
 			defaultNodeName: "",
 		},
+		{
+			name:            "Alias",
+			script:          "dependents PACKAGE sha256:d1",
+			want:            roaring.BitmapOf(1, 2, 3),
+			defaultNodeName: "",
+		},
+		{
+			name:            "Quoted alias",
+			script:          `dependencies PACKAGE "cpe:2.3:a:generic:dep1:1.0.0:*:*:*:*:*:*:*" or dependencies PACKAGE "pkg:generic/lib-B@1.0.0"`,
+			want:            roaring.BitmapOf(2, 3, 4),
+			defaultNodeName: "",
+		},
+		{
+			name:            "Unknown name",
+			script:          "dependents PACKAGE sha256:ffff",
+			wantErr:         true,
+			defaultNodeName: "",
+		},
 		{
 			name:            "Empty node name",
 			script:          "dependents PACKAGE or dependencies PACKAGE",
@@ -90,24 +114,232 @@ func TestParseAndExecute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			keys, err := storage.GetAllKeys()
+			keys, err := storage.GetAllKeys(context.Background())
 			if err != nil {
 				t.Fatal(err)
 			}
-			nodes, err := storage.GetNodes(keys)
+			nodes, err := storage.GetNodes(context.Background(), keys)
 			if err != nil {
 				t.Fatal(err)
 			}
-			caches, err := storage.GetCaches(keys)
+			caches, err := storage.GetCaches(context.Background(), keys)
 			if err != nil {
 				t.Fatal(err)
 			}
-			result, err := ParseAndExecute(tt.script, storage, tt.defaultNodeName, nodes, caches, true)
+			result, err := ParseAndExecute(context.Background(), tt.script, storage, tt.defaultNodeName, nodes, caches, true, nil)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ParseAndExecute() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ParseAndExecute(context.Background()) error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && !result.Equals(tt.want) {
+				t.Errorf("ParseAndExecute(context.Background()) got = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAndExecuteFilter(t *testing.T) {
+	storage := NewMockStorage()
+	lib, err := AddNode(context.Background(), storage, "library", nil, "pkg:generic/lib@1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, metadata := range []map[string]any{
+		{"severity_score": 9.8, "severity_rating": "CRITICAL"},
+		{"severity_score": 5.0, "severity_rating": "MEDIUM", "database_specific": map[string]any{"cwe": "CWE-79"}},
+		{"severity_rating": "LOW"},
+	} {
+		vuln, err := AddNode(context.Background(), storage, "vuln", metadata, fmt.Sprintf("TEST-%v", metadata["severity_rating"]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := lib.SetDependency(context.Background(), storage, vuln); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Cache(context.Background(), storage); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := storage.GetAllKeys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := storage.GetNodes(context.Background(), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caches, err := storage.GetCaches(context.Background(), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		script  string
+		want    *roaring.Bitmap
+		wantErr bool
+	}{
+		{script: "dependencies vuln pkg:generic/lib@1.0.0", want: roaring.BitmapOf(2, 3, 4)},
+		{script: "dependencies vuln pkg:generic/lib@1.0.0 where severity_score >= 7", want: roaring.BitmapOf(2)},
+		{script: "dependencies vuln pkg:generic/lib@1.0.0 where severity_score < 9.8", want: roaring.BitmapOf(3)},
+		{script: "dependencies vuln pkg:generic/lib@1.0.0 where severity_rating >= medium", want: roaring.BitmapOf(2, 3)},
+		{script: "dependencies vuln pkg:generic/lib@1.0.0 where severity_rating = low", want: roaring.BitmapOf(4)},
+		{script: `dependencies vuln pkg:generic/lib@1.0.0 where database_specific.cwe = "cwe-79"`, want: roaring.BitmapOf(3)},
+		{script: "dependencies vuln where severity_score > 1 or dependencies vuln where severity_rating = low", want: roaring.BitmapOf(2, 3, 4)},
+		{script: "dependencies vuln pkg:generic/lib@1.0.0 where severity_score high", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			result, err := ParseAndExecute(context.Background(), tt.script, storage, "pkg:generic/lib@1.0.0", nodes, caches, true, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAndExecute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !result.Equals(tt.want) {
+				t.Errorf("ParseAndExecute() got = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWhereName(t *testing.T) {
+	// where only starts a filter when a field and a comparator follow it.
+	tests := []struct {
+		script   string
+		nodeName string
+		field    string
+	}{
+		{script: "dependencies library where", nodeName: "where"},
+		{script: `dependencies library "where"`, nodeName: "where"},
+		{script: "dependencies library where where severity_score >= 7", nodeName: "where", field: "severity_score"},
+		{script: "dependencies library where severity_score >= 7", field: "severity_score"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			expression, err := parser.ParseString("", tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query := expression.Left.Query
+			nodeName := ""
+			if query.NodeName != nil {
+				nodeName = *query.NodeName
+			}
+			if nodeName != tt.nodeName {
+				t.Errorf("node name = %q, want %q", nodeName, tt.nodeName)
+			}
+			field := ""
+			if query.Filter != nil {
+				field = query.Filter.Field
+			}
+			if field != tt.field {
+				t.Errorf("filter field = %q, want %q", field, tt.field)
+			}
+		})
+	}
+}
+
+func TestParseAndExecuteSuppressions(t *testing.T) {
+	storage := NewMockStorage()
+	ctx := context.Background()
+	app, err := AddNode(ctx, storage, "library", nil, "pkg:generic/app@1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := AddNode(ctx, storage, "library", nil, "pkg:generic/b@1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := AddNode(ctx, storage, "library", nil, "pkg:generic/c@1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := AddNode(ctx, storage, "vuln", nil, "TEST-SHARED")
+	if err != nil {
+		t.Fatal(err)
+	}
+	only, err := AddNode(ctx, storage, "vuln", nil, "TEST-B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range [][2]*Node{{app, b}, {app, c}, {b, shared}, {c, shared}, {b, only}} {
+		if err := edge[0].SetDependency(ctx, storage, edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Cache(ctx, storage); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caches, err := storage.GetCaches(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const affected = "dependents library TEST-SHARED"
+	tests := []struct {
+		name         string
+		query        string
+		suppressions Suppressions
+		want         *roaring.Bitmap
+	}{
+		{name: "none", want: roaring.BitmapOf(shared.ID, only.ID)},
+		{
+			name:         "dependency only reaching",
+			suppressions: Suppressions{b.ID: roaring.BitmapOf(only.ID)},
+			want:         roaring.BitmapOf(shared.ID),
+		},
+		{
+			name:         "one of the dependencies reaching",
+			suppressions: Suppressions{b.ID: roaring.BitmapOf(shared.ID)},
+			want:         roaring.BitmapOf(shared.ID, only.ID),
+		},
+		{
+			name:         "all dependencies reaching",
+			suppressions: Suppressions{b.ID: roaring.BitmapOf(shared.ID), c.ID: roaring.BitmapOf(shared.ID)},
+			want:         roaring.BitmapOf(only.ID),
+		},
+		{
+			name:         "product",
+			suppressions: Suppressions{app.ID: roaring.BitmapOf(shared.ID, only.ID)},
+			want:         roaring.New(),
+		},
+		{name: "dependents none", query: affected, want: roaring.BitmapOf(app.ID, b.ID, c.ID)},
+		{
+			name:         "dependents one of the dependencies reaching",
+			query:        affected,
+			suppressions: Suppressions{b.ID: roaring.BitmapOf(shared.ID)},
+			want:         roaring.BitmapOf(app.ID, c.ID),
+		},
+		{
+			name:         "dependents all dependencies reaching",
+			query:        affected,
+			suppressions: Suppressions{b.ID: roaring.BitmapOf(shared.ID), c.ID: roaring.BitmapOf(shared.ID)},
+			want:         roaring.New(),
+		},
+		{
+			name:         "dependents product",
+			query:        affected,
+			suppressions: Suppressions{app.ID: roaring.BitmapOf(shared.ID)},
+			want:         roaring.BitmapOf(b.ID, c.ID),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			if query == "" {
+				query = "dependencies vuln pkg:generic/app@1.0.0"
+			}
+			result, err := ParseAndExecute(ctx, query, storage, "", nodes, caches, true, tt.suppressions)
+			if err != nil {
+				t.Fatalf("ParseAndExecute() error = %v", err)
+			}
+			if !result.Equals(tt.want) {
 				t.Errorf("ParseAndExecute() got = %v, want %v", result, tt.want)
 			}
 		})
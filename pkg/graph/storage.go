@@ -1,22 +1,66 @@
 package graph
 
+import "context"
+
 // Storage is the interface that wraps the methods for a storage backend.
+//
+// Lookups of a single missing node, by ID or name, return an error wrapping ErrNodeNotFound,
+// and of a missing cache one wrapping ErrCacheNotFound. Batch lookups skip missing entries.
+// Glob patterns follow the rules of utils.MatchGlob. Implementations stop waiting on the
+// backend and return the context's error once ctx is done. The storagetest package checks
+// that an implementation behaves like the others.
 type Storage interface {
-	NameToID(name string) (uint32, error)
-	SaveNode(node *Node) error
-	GetNode(id uint32) (*Node, error)
-	GetNodes(ids []uint32) (map[uint32]*Node, error)
-	GetNodesByGlob(pattern string) ([]*Node, error)
-	GetAllKeys() ([]uint32, error)
-	SaveCache(cache *NodeCache) error
-	SaveCaches(cache []*NodeCache) error
-	RemoveAllCaches() error
-	ToBeCached() ([]uint32, error)
-	AddNodeToCachedStack(id uint32) error
-	GetCache(id uint32) (*NodeCache, error)
-	GetCaches(ids []uint32) (map[uint32]*NodeCache, error)
-	ClearCacheStack() error
-	GenerateID() (uint32, error)
-	GetCustomData(tag, key string) (map[string][]byte, error)
-	AddOrUpdateCustomData(tag, key string, datakey string, data []byte) error
+	NameToID(ctx context.Context, name string) (uint32, error)
+	SaveNode(ctx context.Context, node *Node) error
+	// CreateNode saves the new node unless its name is taken, claiming the name and saving the
+	// node in one atomic step, and returns the ID the name maps to: the node's if it was saved,
+	// or else the ID of the existing node. Concurrent callers never get two nodes of one name.
+	CreateNode(ctx context.Context, node *Node) (uint32, error)
+	GetNode(ctx context.Context, id uint32) (*Node, error)
+	GetNodes(ctx context.Context, ids []uint32) (map[uint32]*Node, error)
+	GetNodesByGlob(ctx context.Context, pattern string) ([]*Node, error)
+	GetAllKeys(ctx context.Context) ([]uint32, error)
+	SaveCache(ctx context.Context, cache *NodeCache) error
+	SaveCaches(ctx context.Context, cache []*NodeCache) error
+	RemoveAllCaches(ctx context.Context) error
+	ToBeCached(ctx context.Context) ([]uint32, error)
+	AddNodeToCachedStack(ctx context.Context, id uint32) error
+	GetCache(ctx context.Context, id uint32) (*NodeCache, error)
+	GetCaches(ctx context.Context, ids []uint32) (map[uint32]*NodeCache, error)
+	ClearCacheStack(ctx context.Context) error
+	GenerateID(ctx context.Context) (uint32, error)
+	GetCustomData(ctx context.Context, tag, key string) (map[string][]byte, error)
+	AddOrUpdateCustomData(ctx context.Context, tag, key string, datakey string, data []byte) error
+	// GetCustomDataKeys returns the keys that have custom data stored, grouped by tag.
+	GetCustomDataKeys(ctx context.Context) (map[string][]string, error)
+	// GetIDCounter returns the last ID handed out by GenerateID, or 0 if none was.
+	GetIDCounter(ctx context.Context) (uint32, error)
+	// SetIDCounter moves the ID counter forward to id, so GenerateID continues after it.
+	// It never moves the counter backwards.
+	SetIDCounter(ctx context.Context, id uint32) error
+	// AddDependency atomically records the edge from -> to on both nodes, so
+	// concurrent writers touching the same node cannot lose each other's edges.
+	AddDependency(ctx context.Context, from, to uint32) error
+	// RemoveDependency atomically removes the edge from -> to from both nodes.
+	RemoveDependency(ctx context.Context, from, to uint32) error
+	// AddAliases makes the aliases resolve to the node, in addition to its name. An alias that
+	// already resolves to a node keeps resolving to it. It returns an error wrapping
+	// ErrNodeNotFound if the node doesn't exist.
+	AddAliases(ctx context.Context, id uint32, aliases []string) error
+	// AliasToID returns the ID of the node the alias resolves to.
+	AliasToID(ctx context.Context, alias string) (uint32, error)
+	// GetAliases returns the sorted aliases of the node.
+	GetAliases(ctx context.Context, id uint32) ([]string, error)
+	// DeleteNode removes the node, its name, its aliases and its cache. It returns ErrNodeHasEdges if the
+	// node still has children or parents, which have to be removed with RemoveDependency first.
+	DeleteNode(ctx context.Context, id uint32) error
+	// UpdateNodeMetadata atomically replaces the metadata of the node with what update returns
+	// for the current metadata, leaving its edges alone, and returns the updated node. update
+	// may be called more than once if the node is modified concurrently.
+	UpdateNodeMetadata(ctx context.Context, id uint32, update func(metadata any) (any, error)) (*Node, error)
+	// GetSchemaVersion returns the version of the format the data is stored in, or 0 if no
+	// version was recorded.
+	GetSchemaVersion(ctx context.Context) (int, error)
+	// SetSchemaVersion records the version of the format the data is stored in.
+	SetSchemaVersion(ctx context.Context, version int) error
 }
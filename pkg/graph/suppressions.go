@@ -0,0 +1,86 @@
+package graph
+
+import "github.com/RoaringBitmap/roaring"
+
+// Suppressions are the vulnerabilities VEX statements say don't affect a product, by the ID of
+// the product node.
+type Suppressions map[uint32]*roaring.Bitmap
+
+// Suppressed reports whether the vulnerability doesn't affect the node, given the dependencies
+// of the node. It doesn't if a statement says so about the node itself, or about every node
+// among the node and its dependencies through which the vulnerability reaches it.
+func (s Suppressions) Suppressed(id, vuln uint32, dependencies *roaring.Bitmap, nodes map[uint32]*Node) bool {
+	if len(s) == 0 {
+		return false
+	}
+	if s[id] != nil && s[id].Contains(vuln) {
+		return true
+	}
+	node := nodes[vuln]
+	if node == nil || node.Parents == nil {
+		return false
+	}
+	reached := false
+	for _, parent := range node.Parents.ToArray() {
+		if parent != id && (dependencies == nil || !dependencies.Contains(parent)) {
+			continue
+		}
+		if s[parent] == nil || !s[parent].Contains(vuln) {
+			return false
+		}
+		reached = true
+	}
+	return reached
+}
+
+// SuppressedDependents returns the dependents of the vulnerability it doesn't affect, as
+// Suppressed decides for each of them but without their dependencies: a dependent isn't
+// affected if a statement says so about it, or if it reaches none of the parents of the
+// vulnerability no statement covers. dependentsOf returns the dependents of those parents, it
+// is only called if a statement covers another parent.
+func (s Suppressions) SuppressedDependents(vuln uint32, dependents *roaring.Bitmap, nodes map[uint32]*Node, dependentsOf func([]*Node) (map[uint32]*roaring.Bitmap, error)) (*roaring.Bitmap, error) {
+	suppressed := roaring.New()
+	if len(s) == 0 || dependents == nil {
+		return suppressed, nil
+	}
+	for product, vulns := range s {
+		if vulns.Contains(vuln) && dependents.Contains(product) {
+			suppressed.Add(product)
+		}
+	}
+
+	node := nodes[vuln]
+	if node == nil || node.Parents == nil {
+		return suppressed, nil
+	}
+	covered := false
+	var uncovered []*Node
+	for _, parent := range node.Parents.ToArray() {
+		switch {
+		case s[parent] != nil && s[parent].Contains(vuln):
+			covered = true
+		case nodes[parent] == nil:
+			// The dependents reaching a parent that isn't known can't be told apart.
+			return suppressed, nil
+		default:
+			uncovered = append(uncovered, nodes[parent])
+		}
+	}
+	if !covered {
+		return suppressed, nil
+	}
+
+	affected := roaring.New()
+	for _, parent := range uncovered {
+		affected.Add(parent.ID)
+	}
+	reaching, err := dependentsOf(uncovered)
+	if err != nil {
+		return nil, err
+	}
+	for _, ids := range reaching {
+		affected.Or(ids)
+	}
+	suppressed.Or(roaring.AndNot(dependents, affected))
+	return suppressed, nil
+}
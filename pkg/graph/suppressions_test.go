@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+)
+
+func TestSuppressedDependents(t *testing.T) {
+	// app depends on b and c, which both have the vulnerability 4.
+	nodes := map[uint32]*Node{
+		1: {ID: 1, Parents: roaring.New()},
+		2: {ID: 2, Parents: roaring.BitmapOf(1)},
+		3: {ID: 3, Parents: roaring.BitmapOf(1)},
+		4: {ID: 4, Parents: roaring.BitmapOf(2, 3)},
+	}
+	dependents := roaring.BitmapOf(1, 2, 3)
+	dependencies := map[uint32]*roaring.Bitmap{1: roaring.BitmapOf(2, 3, 4), 2: roaring.BitmapOf(4), 3: roaring.BitmapOf(4)}
+	queried := 0
+	dependentsOf := func(parents []*Node) (map[uint32]*roaring.Bitmap, error) {
+		queried++
+		result := map[uint32]*roaring.Bitmap{}
+		for _, parent := range parents {
+			result[parent.ID] = nodes[parent.ID].Parents.Clone()
+		}
+		return result, nil
+	}
+
+	tests := []struct {
+		name         string
+		suppressions Suppressions
+		want         *roaring.Bitmap
+		queried      int
+	}{
+		{name: "none", want: roaring.New()},
+		{name: "product", suppressions: Suppressions{1: roaring.BitmapOf(4)}, want: roaring.BitmapOf(1)},
+		{name: "another vulnerability", suppressions: Suppressions{2: roaring.BitmapOf(5)}, want: roaring.New()},
+		{name: "one parent", suppressions: Suppressions{2: roaring.BitmapOf(4)}, want: roaring.BitmapOf(2), queried: 1},
+		{name: "every parent", suppressions: Suppressions{2: roaring.BitmapOf(4), 3: roaring.BitmapOf(4)}, want: roaring.BitmapOf(1, 2, 3), queried: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queried = 0
+			got, err := tt.suppressions.SuppressedDependents(4, dependents, nodes, dependentsOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equals(tt.want) {
+				t.Errorf("SuppressedDependents() = %v, want %v", got, tt.want)
+			}
+			// The dependents of the parents are only needed if a statement covers a parent.
+			if queried != tt.queried {
+				t.Errorf("queried the dependents of parents %d times, want %d", queried, tt.queried)
+			}
+			for id, dependencies := range dependencies {
+				if suppressed := tt.suppressions.Suppressed(id, 4, dependencies, nodes); suppressed != tt.want.Contains(id) {
+					t.Errorf("Suppressed(%d) = %v, disagrees with SuppressedDependents", id, suppressed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package migrations registers the storage schema migrations of the data the ingestion tools
+// write. The storage layer only versions the schema, the commands that open a storage call
+// Register before they check or migrate it.
+package migrations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+)
+
+var registerOnce sync.Once
+
+// Register registers the migrations with storages.RegisterMigration. Calling it again does
+// nothing.
+func Register() {
+	registerOnce.Do(func() {
+		storages.RegisterMigration(storages.Migration{
+			Version:     2,
+			Description: "Merge library nodes whose purls only differ in encoding, case or qualifiers",
+			Migrate: func(ctx context.Context, storage graph.Storage) error {
+				_, err := ingest.MergeDuplicatePURLs(ctx, storage, purl.DefaultNormalizer)
+				return err
+			},
+		})
+		storages.RegisterMigration(storages.Migration{
+			Version:     3,
+			Description: "Index library nodes by OSV ecosystem and package name",
+			Migrate:     ingest.IndexPackages,
+		})
+		storages.RegisterMigration(storages.Migration{
+			Version:     4,
+			Description: "Score vulnerability nodes by their CVSS vectors",
+			Migrate:     ingest.NormalizeVulnerabilities,
+		})
+		storages.RegisterMigration(storages.Migration{
+			Version:     5,
+			Description: "Keep nodes and edges ingested before provenance was tracked when documents are re-ingested",
+			Migrate:     ingest.RecordUnknownProvenance,
+		})
+		storages.RegisterMigration(storages.Migration{
+			Version:     6,
+			Description: "Index VEX statements by product and vulnerability",
+			Migrate:     ingest.IndexVEXStatements,
+		})
+	})
+}
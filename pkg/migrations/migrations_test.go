@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/storages"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	Register()
+	os.Exit(m.Run())
+}
+
+func TestRegister(t *testing.T) {
+	Register()
+	assert.Equal(t, 6, storages.CurrentSchemaVersion(), "registering again does nothing")
+}
+
+func TestMigrateDuplicatePURLs(t *testing.T) {
+	storage, err := storages.SetupSQLTestDB(filepath.Join(t.TempDir(), "graph.db"))
+	require.NoError(t, err)
+	ctx := context.Background()
+	app, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/app@1.0.0")
+	require.NoError(t, err)
+	duplicate, err := graph.AddNode(ctx, storage, "library", map[string]any{"name": "x"}, "pkg:npm/@scope/x@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, app.SetDependency(ctx, storage, duplicate))
+	require.NoError(t, storage.SetSchemaVersion(ctx, 1))
+
+	_, err = storages.MigrateSchema(ctx, storage)
+	require.NoError(t, err)
+
+	_, err = storage.NameToID(ctx, duplicate.Name)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	id, err := storage.NameToID(ctx, "pkg:npm/%40scope/x@1.0.0")
+	require.NoError(t, err)
+	x, err := storage.GetNode(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{app.ID}, x.Parents.ToArray())
+	assert.Equal(t, map[string]any{"name": "x"}, x.Metadata)
+}
+
+func TestImportGraphMigratesOldSchema(t *testing.T) {
+	ctx := context.Background()
+	// Data written before the schema was versioned lacks the package index of migration 3.
+	src := graph.NewMockStorage()
+	_, err := graph.AddNode(ctx, src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	var archive bytes.Buffer
+	_, err = storages.ExportGraph(ctx, src, &archive, 0)
+	require.NoError(t, err)
+
+	// The server stamps an empty storage with the current version when it starts.
+	dst := graph.NewMockStorage()
+	status, err := storages.GetSchemaStatus(ctx, dst)
+	require.NoError(t, err)
+	require.Equal(t, storages.CurrentSchemaVersion(), status.Version)
+
+	_, err = storages.ImportGraph(ctx, dst, bytes.NewReader(archive.Bytes()), 0)
+	require.NoError(t, err)
+	version, err := dst.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, storages.CurrentSchemaVersion(), version)
+	keys, err := dst.GetCustomDataKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npm/a"}, keys[ingest.PackagesTag])
+}
+
+func TestMigrateVEXIndex(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	// VEX documents ingested before statements were indexed only stored their statements.
+	statements := `[{"vulnerability": "CVE-2024-0001", "product": "pkg:npm/B@1.0.0", "status": "not_affected"}]`
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, ingest.VEXTag, "vex:https://example.com/vex-b", "statements", []byte(statements)))
+	require.NoError(t, storage.SetSchemaVersion(ctx, 5))
+
+	_, err := storages.MigrateSchema(ctx, storage)
+	require.NoError(t, err)
+
+	statement, ok, err := ingest.VEXStatementAbout(ctx, storage, "pkg:npm/b@1.0.0", "CVE-2024-0001")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, ingest.VEXNotAffected, statement.Status)
+}
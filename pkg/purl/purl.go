@@ -0,0 +1,51 @@
+// Package purl normalizes package URLs into the canonical form used as node names, so the
+// same package gets the same node no matter how an SBOM spelled its purl.
+package purl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultIdentityQualifiers are the qualifiers that tell different packages apart. The others,
+// like repository_url, download_url or checksum, describe where a package came from and are
+// dropped from the node name.
+var DefaultIdentityQualifiers = []string{"arch", "classifier", "distro", "epoch", "os", "type"}
+
+// DefaultNormalizer keeps the DefaultIdentityQualifiers.
+var DefaultNormalizer = NewNormalizer(DefaultIdentityQualifiers)
+
+// Normalizer turns purls into their canonical form.
+type Normalizer struct {
+	identityQualifiers map[string]bool
+}
+
+// NewNormalizer returns a normalizer that keeps only the given qualifiers.
+func NewNormalizer(identityQualifiers []string) *Normalizer {
+	n := &Normalizer{identityQualifiers: make(map[string]bool, len(identityQualifiers))}
+	for _, qualifier := range identityQualifiers {
+		n.identityQualifiers[strings.ToLower(qualifier)] = true
+	}
+	return n
+}
+
+// Normalize returns the canonical form of the purl: the type specific case rules applied, the
+// namespace, name and version percent-encoded the same way, and only the identity qualifiers
+// kept, sorted by key.
+func (n *Normalizer) Normalize(purl string) (string, error) {
+	// FromString already lowercases and sorts what the purl spec asks for.
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse purl %q: %w", purl, err)
+	}
+	qualifiers := packageurl.Qualifiers{}
+	for _, qualifier := range parsed.Qualifiers {
+		if n.identityQualifiers[qualifier.Key] {
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	parsed.Qualifiers = qualifiers
+	return parsed.ToString(), nil
+}
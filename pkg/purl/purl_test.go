@@ -0,0 +1,52 @@
+package purl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		purl string
+		want string
+	}{
+		{"unchanged", "pkg:npm/lodash@4.17.21", "pkg:npm/lodash@4.17.21"},
+		{"escaped npm scope", "pkg:npm/%40angular/core@17.0.0", "pkg:npm/%40angular/core@17.0.0"},
+		{"unescaped npm scope", "pkg:npm/@angular/core@17.0.0", "pkg:npm/%40angular/core@17.0.0"},
+		{"npm case", "pkg:NPM/@Angular/Core@17.0.0", "pkg:npm/%40angular/core@17.0.0"},
+		{"pypi name", "pkg:pypi/Typing_Extensions@4.4.0", "pkg:pypi/typing-extensions@4.4.0"},
+		{"github case", "pkg:github/Package-URL/PURL-Spec@1.0", "pkg:github/package-url/purl-spec@1.0"},
+		{"maven keeps case", "pkg:maven/org.Apache/Commons@1.0", "pkg:maven/org.Apache/Commons@1.0"},
+		{"qualifier order", "pkg:deb/debian/curl@7.50.3-1?distro=jessie&arch=i386", "pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie"},
+		{"qualifier key case", "pkg:deb/debian/curl@7.50.3-1?ARCH=i386", "pkg:deb/debian/curl@7.50.3-1?arch=i386"},
+		{"non identity qualifiers", "pkg:maven/org.apache/commons@1.0?repository_url=https://repo1.maven.org&classifier=sources", "pkg:maven/org.apache/commons@1.0?classifier=sources"},
+		{"empty qualifier", "pkg:deb/debian/curl@7.50.3-1?arch=", "pkg:deb/debian/curl@7.50.3-1"},
+		{"subpath", "pkg:golang/github.com/Google/go-cmp@v0.6.0#/cmp/", "pkg:golang/github.com/google/go-cmp@v0.6.0#cmp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultNormalizer.Normalize(tt.purl)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := DefaultNormalizer.Normalize("pkg:lodash@4.17.21")
+	assert.Error(t, err)
+	_, err = DefaultNormalizer.Normalize("npm/lodash@4.17.21")
+	assert.Error(t, err)
+}
+
+func TestNormalizeIdentityQualifiers(t *testing.T) {
+	normalizer := NewNormalizer([]string{"Repository_URL"})
+	got, err := normalizer.Normalize("pkg:maven/org.apache/commons@1.0?repository_url=https://repo.example.com&classifier=sources")
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:maven/org.apache/commons@1.0?repository_url=https%3A%2F%2Frepo.example.com", got)
+
+	got, err = NewNormalizer(nil).Normalize("pkg:deb/debian/curl@7.50.3-1?arch=i386")
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:deb/debian/curl@7.50.3-1", got)
+}
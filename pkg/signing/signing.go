@@ -0,0 +1,260 @@
+// Package signing signs and verifies the payloads moved into minefield, like SBOMs,
+// vulnerability and scorecard files or graph archives, with locally held ed25519 keys.
+//
+// A signature is a small JSON document stored next to the signed file with the
+// SignatureExt extension. It signs the SHA-256 digest of the payload, so large payloads
+// can be verified while they are streamed.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// SignatureExt is appended to the path of a signed file to get the path of its signature.
+	SignatureExt = ".sig"
+
+	// Algorithm is the only signature algorithm that is supported.
+	Algorithm = "ed25519"
+
+	privateKeyPEMType = "PRIVATE KEY"
+	publicKeyPEMType  = "PUBLIC KEY"
+
+	// signatureContext is prepended to the digest before signing, so minefield signatures
+	// can't be confused with signatures made by the same key for anything else.
+	signatureContext = "minefield-signature-v1\n"
+)
+
+var (
+	ErrUnsigned         = errors.New("payload is not signed")
+	ErrUntrustedKey     = errors.New("payload is signed by an untrusted key")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signature is the signature of a payload, as stored in a signature file.
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"keyId"`
+	// Digest is the hex encoded SHA-256 digest of the signed payload.
+	Digest    string `json:"digest"`
+	Signature []byte `json:"signature"`
+}
+
+// KeyID returns the identifier of a public key, the hex encoded SHA-256 of its encoding.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateKey returns a new ed25519 key pair.
+func GenerateKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return public, private, nil
+}
+
+// Sign signs payload with key and returns the encoded signature.
+func Sign(key ed25519.PrivateKey, payload []byte) ([]byte, error) {
+	return SignDigest(key, sha256.Sum256(payload))
+}
+
+// SignDigest signs the SHA-256 digest of a payload with key and returns the encoded signature.
+func SignDigest(key ed25519.PrivateKey, digest [sha256.Size]byte) ([]byte, error) {
+	public, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid ed25519 private key")
+	}
+	signature := Signature{
+		Algorithm: Algorithm,
+		KeyID:     KeyID(public),
+		Digest:    hex.EncodeToString(digest[:]),
+		Signature: ed25519.Sign(key, signedMessage(digest)),
+	}
+	data, err := json.Marshal(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signature: %w", err)
+	}
+	return data, nil
+}
+
+func signedMessage(digest [sha256.Size]byte) []byte {
+	return append([]byte(signatureContext), digest[:]...)
+}
+
+// Keyring holds the public keys trusted to sign payloads, by key ID.
+type Keyring map[string]ed25519.PublicKey
+
+// NewKeyring returns a keyring trusting the given keys.
+func NewKeyring(keys ...ed25519.PublicKey) Keyring {
+	keyring := make(Keyring, len(keys))
+	for _, key := range keys {
+		keyring[KeyID(key)] = key
+	}
+	return keyring
+}
+
+// KeyIDs returns the sorted IDs of all keys in the keyring.
+func (k Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k))
+	for id := range k {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Verify checks that signature is a valid signature of payload by one of the trusted keys.
+func (k Keyring) Verify(payload, signature []byte) error {
+	return k.VerifyDigest(sha256.Sum256(payload), signature)
+}
+
+// VerifyDigest checks that signature is a valid signature, by one of the trusted keys, of the
+// payload with the given SHA-256 digest. It returns ErrUnsigned for an empty signature.
+func (k Keyring) VerifyDigest(digest [sha256.Size]byte, signature []byte) error {
+	if len(signature) == 0 {
+		return ErrUnsigned
+	}
+	var sig Signature
+	if err := json.Unmarshal(signature, &sig); err != nil {
+		return fmt.Errorf("%w: failed to decode signature: %v", ErrInvalidSignature, err)
+	}
+	if sig.Algorithm != Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, sig.Algorithm)
+	}
+	key, ok := k[sig.KeyID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUntrustedKey, sig.KeyID)
+	}
+	if sig.Digest != hex.EncodeToString(digest[:]) {
+		return fmt.Errorf("%w: signature is for a different payload", ErrInvalidSignature)
+	}
+	if !ed25519.Verify(key, signedMessage(digest), sig.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// EncodePrivateKey returns the PEM encoded PKCS #8 form of key.
+func EncodePrivateKey(key ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}), nil
+}
+
+// EncodePublicKey returns the PEM encoded PKIX form of key.
+func EncodePublicKey(key ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: der}), nil
+}
+
+// ParsePrivateKey parses a PEM encoded ed25519 private key.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != privateKeyPEMType {
+		return nil, fmt.Errorf("no %s PEM block found", privateKeyPEMType)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is a %T, not an ed25519 key", key)
+	}
+	return private, nil
+}
+
+// ParsePublicKey parses a PEM encoded ed25519 public key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != publicKeyPEMType {
+		return nil, fmt.Errorf("no %s PEM block found", publicKeyPEMType)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is a %T, not an ed25519 key", key)
+	}
+	return public, nil
+}
+
+// LoadPrivateKey reads a PEM encoded ed25519 private key from path.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadKeyring reads the PEM encoded ed25519 public keys at paths into a keyring.
+func LoadKeyring(paths ...string) (Keyring, error) {
+	keys := make([]ed25519.PublicKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		key, err := ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyring(keys...), nil
+}
+
+// ReadSignature returns the signature stored next to path, or nil if there is none.
+func ReadSignature(path string) ([]byte, error) {
+	data, err := os.ReadFile(path + SignatureExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	return data, nil
+}
+
+// DigestFile returns the SHA-256 digest of the file at path, without reading it into memory.
+func DigestFile(path string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	file, err := os.Open(path)
+	if err != nil {
+		return digest, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return digest, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	copy(digest[:], hash.Sum(nil))
+	return digest, nil
+}
@@ -0,0 +1,122 @@
+package signing
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+	otherPublic, otherPrivate, err := GenerateKey()
+	require.NoError(t, err)
+
+	payload := []byte(`{"bomFormat":"CycloneDX"}`)
+	signature, err := Sign(private, payload)
+	require.NoError(t, err)
+
+	keyring := NewKeyring(public)
+	assert.NoError(t, keyring.Verify(payload, signature))
+	assert.NoError(t, keyring.VerifyDigest(sha256.Sum256(payload), signature))
+	assert.NoError(t, NewKeyring(otherPublic, public).Verify(payload, signature))
+
+	otherSignature, err := Sign(otherPrivate, payload)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		keyring   Keyring
+		payload   []byte
+		signature []byte
+		err       error
+	}{
+		{name: "unsigned", keyring: keyring, payload: payload, err: ErrUnsigned},
+		{name: "untrusted key", keyring: keyring, payload: payload, signature: otherSignature, err: ErrUntrustedKey},
+		{name: "modified payload", keyring: keyring, payload: []byte(`{"bomFormat":"SPDX"}`), signature: signature, err: ErrInvalidSignature},
+		{name: "garbage signature", keyring: keyring, payload: payload, signature: []byte("garbage"), err: ErrInvalidSignature},
+		{name: "empty keyring", keyring: NewKeyring(), payload: payload, signature: signature, err: ErrUntrustedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.keyring.Verify(tt.payload, tt.signature), tt.err)
+		})
+	}
+}
+
+func TestTamperedSignature(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+	payload := []byte("payload")
+
+	digest := sha256.Sum256(payload)
+	signature, err := SignDigest(private, digest)
+	require.NoError(t, err)
+
+	var sig Signature
+	require.NoError(t, json.Unmarshal(signature, &sig))
+	sig.Signature[0] ^= 1
+	tampered, err := json.Marshal(sig)
+	require.NoError(t, err)
+	assert.ErrorIs(t, NewKeyring(public).Verify(payload, tampered), ErrInvalidSignature)
+}
+
+func TestKeyEncoding(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privateData, err := EncodePrivateKey(private)
+	require.NoError(t, err)
+	publicData, err := EncodePublicKey(public)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key"), privateData, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pub"), publicData, 0o644))
+
+	loadedPrivate, err := LoadPrivateKey(filepath.Join(dir, "key"))
+	require.NoError(t, err)
+	assert.Equal(t, private, loadedPrivate)
+
+	keyring, err := LoadKeyring(filepath.Join(dir, "key.pub"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{KeyID(public)}, keyring.KeyIDs())
+
+	// A public key is not a private key and the other way around.
+	_, err = LoadPrivateKey(filepath.Join(dir, "key.pub"))
+	assert.Error(t, err)
+	_, err = LoadKeyring(filepath.Join(dir, "key"))
+	assert.Error(t, err)
+	_, err = LoadKeyring(filepath.Join(dir, "missing.pub"))
+	assert.Error(t, err)
+}
+
+func TestReadSignature(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sbom.json")
+
+	signature, err := ReadSignature(path)
+	require.NoError(t, err)
+	assert.Nil(t, signature)
+
+	require.NoError(t, os.WriteFile(path+SignatureExt, []byte("sig"), 0o644))
+	signature, err = ReadSignature(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("sig"), signature)
+}
+
+func TestDigestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.archive.gz")
+	require.NoError(t, os.WriteFile(path, []byte("archive"), 0o644))
+
+	digest, err := DigestFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, sha256.Sum256([]byte("archive")), digest)
+
+	_, err = DigestFile(path + ".missing")
+	assert.Error(t, err)
+}
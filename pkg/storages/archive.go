@@ -0,0 +1,355 @@
+package storages
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/goccy/go-json"
+)
+
+const (
+	// ArchiveFormat identifies a minefield graph archive in its header record.
+	ArchiveFormat = "minefield-graph-archive"
+	// ArchiveVersion is the version of the archive layout written by ExportGraph. Version 2
+	// records the schema version of the exported data in the header.
+	ArchiveVersion = 2
+)
+
+// The archive is a gzip compressed stream of JSON records, one per line. It starts with a
+// header, followed by the nodes in ID order, the cache stack, the custom data and the ID
+// counter, and ends with a summary of everything before it.
+const (
+	recordHeader     = "header"
+	recordNode       = "node"
+	recordCacheStack = "cache_stack"
+	recordCustomData = "custom_data"
+	recordIDCounter  = "id_counter"
+	recordSummary    = "summary"
+)
+
+type archiveRecord struct {
+	Kind string `json:"kind"`
+
+	// header
+	Format    string     `json:"format,omitempty"`
+	Version   int        `json:"version,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// SchemaVersion is the storage schema version of the exported data. Archives of version 1
+	// don't have it, their data is migrated from before the schema was versioned.
+	SchemaVersion int `json:"schema_version,omitempty"`
+
+	// node
+	Node  *archiveNode  `json:"node,omitempty"`
+	Cache *archiveCache `json:"cache,omitempty"`
+
+	// cache_stack
+	CacheStack []uint32 `json:"cache_stack,omitempty"`
+
+	// custom_data
+	CustomData *archiveCustomData `json:"custom_data,omitempty"`
+
+	// id_counter
+	IDCounter *uint32 `json:"id_counter,omitempty"`
+
+	// summary
+	Summary *GraphSummary `json:"summary,omitempty"`
+}
+
+type archiveNode struct {
+	ID       uint32          `json:"id"`
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Metadata json.RawMessage `json:"metadata"`
+	Children []uint32        `json:"children"`
+	Parents  []uint32        `json:"parents"`
+	Aliases  []string        `json:"aliases,omitempty"`
+}
+
+type archiveCache struct {
+	AllParents  []uint32 `json:"all_parents"`
+	AllChildren []uint32 `json:"all_children"`
+}
+
+type archiveCustomData struct {
+	Tag     string `json:"tag"`
+	Key     string `json:"key"`
+	DataKey string `json:"data_key"`
+	Value   []byte `json:"value"`
+}
+
+// ExportGraph writes the whole graph in storage to w as a self-describing archive, which
+// ImportGraph can read back into any storage backend. It returns the summary stored in the
+// archive.
+func ExportGraph(ctx context.Context, storage graph.Storage, w io.Writer, batchSize int) (*GraphSummary, error) {
+	zw := gzip.NewWriter(w)
+	writer := &archiveWriter{
+		encoder: json.NewEncoder(zw),
+		summary: newSummaryBuilder(),
+	}
+
+	schemaVersion, err := storage.GetSchemaVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	now := time.Now().UTC()
+	if err := writer.write(&archiveRecord{Kind: recordHeader, Format: ArchiveFormat, Version: ArchiveVersion, CreatedAt: &now, SchemaVersion: schemaVersion}); err != nil {
+		return nil, err
+	}
+	if err := walkGraph(ctx, storage, batchSize, writer); err != nil {
+		return nil, err
+	}
+	summary := writer.summary.summary()
+	if err := writer.write(&archiveRecord{Kind: recordSummary, Summary: summary}); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return summary, nil
+}
+
+// archiveWriter is a graphVisitor that writes everything it visits as archive records.
+type archiveWriter struct {
+	encoder *json.Encoder
+	summary *summaryBuilder
+}
+
+func (a *archiveWriter) write(record *archiveRecord) error {
+	if err := a.encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", record.Kind, err)
+	}
+	return nil
+}
+
+func (a *archiveWriter) visitNode(node *graph.Node, cache *graph.NodeCache, aliases []string) error {
+	if err := a.summary.visitNode(node, cache, aliases); err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(node.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata of node %d: %w", node.ID, err)
+	}
+	record := &archiveRecord{
+		Kind: recordNode,
+		Node: &archiveNode{
+			ID:       node.ID,
+			Type:     node.Type,
+			Name:     node.Name,
+			Metadata: metadata,
+			Children: node.Children.ToArray(),
+			Parents:  node.Parents.ToArray(),
+			Aliases:  aliases,
+		},
+	}
+	if cache != nil {
+		record.Cache = &archiveCache{
+			AllParents:  cache.AllParents.ToArray(),
+			AllChildren: cache.AllChildren.ToArray(),
+		}
+	}
+	return a.write(record)
+}
+
+func (a *archiveWriter) visitCacheStack(ids []uint32) error {
+	if err := a.summary.visitCacheStack(ids); err != nil {
+		return err
+	}
+	return a.write(&archiveRecord{Kind: recordCacheStack, CacheStack: ids})
+}
+
+func (a *archiveWriter) visitCustomData(tag, key, dataKey string, value []byte) error {
+	if err := a.summary.visitCustomData(tag, key, dataKey, value); err != nil {
+		return err
+	}
+	return a.write(&archiveRecord{
+		Kind:       recordCustomData,
+		CustomData: &archiveCustomData{Tag: tag, Key: key, DataKey: dataKey, Value: value},
+	})
+}
+
+func (a *archiveWriter) visitIDCounter(id uint32) error {
+	if err := a.summary.visitIDCounter(id); err != nil {
+		return err
+	}
+	return a.write(&archiveRecord{Kind: recordIDCounter, IDCounter: &id})
+}
+
+// ImportGraph reads an archive written by ExportGraph into storage, which has to be empty
+// since node IDs are preserved. The archive is checked against its own summary while it is
+// read, and the storage is checked against it afterwards. The storage then gets the schema
+// version of the archive and the migrations the data is missing are applied, so data exported
+// by an older version of minefield is upgraded. Archives of a newer schema are rejected with
+// ErrSchemaNewer. It returns the archive's summary.
+func ImportGraph(ctx context.Context, storage graph.Storage, r io.Reader, batchSize int) (*GraphSummary, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer zr.Close()
+
+	writer, err := newStorageWriter(ctx, storage, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	// Both the storage and the checksums see every record, in the order of the archive.
+	builder := newSummaryBuilder()
+	visitors := []graphVisitor{writer, builder}
+
+	decoder := json.NewDecoder(bufio.NewReader(zr))
+	last := -1
+	schemaVersion := 0
+	var summary *GraphSummary
+	for summary == nil {
+		var record archiveRecord
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("archive is truncated, it has no %s record", recordSummary)
+			}
+			return nil, fmt.Errorf("failed to decode archive record: %w", err)
+		}
+		rank, err := checkRecordOrder(record.Kind, last)
+		if err != nil {
+			return nil, err
+		}
+		last = rank
+
+		switch record.Kind {
+		case recordHeader:
+			if record.Format != ArchiveFormat {
+				return nil, fmt.Errorf("not a minefield graph archive, format is %q", record.Format)
+			}
+			if record.Version > ArchiveVersion {
+				return nil, fmt.Errorf("archive version %d is newer than the supported version %d", record.Version, ArchiveVersion)
+			}
+			if current := CurrentSchemaVersion(); record.SchemaVersion > current {
+				return nil, fmt.Errorf("%w: archive has version %d, supported up to %d", ErrSchemaNewer, record.SchemaVersion, current)
+			}
+			schemaVersion = record.SchemaVersion
+		case recordNode:
+			if record.Node == nil {
+				return nil, fmt.Errorf("node record without a node")
+			}
+			node, cache, err := record.Node.toGraph(record.Cache)
+			if err != nil {
+				return nil, err
+			}
+			for _, visitor := range visitors {
+				if err := visitor.visitNode(node, cache, record.Node.Aliases); err != nil {
+					return nil, err
+				}
+			}
+		case recordCacheStack:
+			for _, visitor := range visitors {
+				if err := visitor.visitCacheStack(record.CacheStack); err != nil {
+					return nil, err
+				}
+			}
+		case recordCustomData:
+			data := record.CustomData
+			if data == nil {
+				return nil, fmt.Errorf("custom_data record without data")
+			}
+			for _, visitor := range visitors {
+				if err := visitor.visitCustomData(data.Tag, data.Key, data.DataKey, data.Value); err != nil {
+					return nil, err
+				}
+			}
+		case recordIDCounter:
+			if record.IDCounter == nil {
+				return nil, fmt.Errorf("id_counter record without a value")
+			}
+			for _, visitor := range visitors {
+				if err := visitor.visitIDCounter(*record.IDCounter); err != nil {
+					return nil, err
+				}
+			}
+		case recordSummary:
+			if record.Summary == nil {
+				return nil, fmt.Errorf("summary record without a summary")
+			}
+			summary = record.Summary
+		}
+	}
+
+	if diffs := builder.summary().Diff(summary); len(diffs) > 0 {
+		return nil, fmt.Errorf("archive does not match its summary:\n  %s", strings.Join(diffs, "\n  "))
+	}
+	imported, err := Summarize(ctx, storage, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify imported graph: %w", err)
+	}
+	if diffs := imported.Diff(summary); len(diffs) > 0 {
+		return nil, fmt.Errorf("imported graph does not match the archive:\n  %s", strings.Join(diffs, "\n  "))
+	}
+
+	// The storage may have been stamped with the current version while it was empty.
+	if err := storage.SetSchemaVersion(ctx, schemaVersion); err != nil {
+		return nil, fmt.Errorf("failed to set schema version: %w", err)
+	}
+	if _, err := MigrateSchema(ctx, storage); err != nil {
+		return nil, fmt.Errorf("failed to migrate imported graph: %w", err)
+	}
+	return summary, nil
+}
+
+// recordOrder lists the record kinds in the order they appear in an archive.
+var recordOrder = []struct {
+	kind     string
+	repeated bool // the record can appear any number of times, including none
+}{
+	{kind: recordHeader},
+	{kind: recordNode, repeated: true},
+	{kind: recordCacheStack},
+	{kind: recordCustomData, repeated: true},
+	{kind: recordIDCounter},
+	{kind: recordSummary},
+}
+
+// checkRecordOrder returns the position of kind in recordOrder, and an error if a record of
+// that kind can't follow the record at position last.
+func checkRecordOrder(kind string, last int) (int, error) {
+	for rank, entry := range recordOrder {
+		if entry.kind != kind {
+			continue
+		}
+		if rank < last || (rank == last && !entry.repeated) {
+			return 0, fmt.Errorf("unexpected %s record in archive", kind)
+		}
+		for skipped := last + 1; skipped < rank; skipped++ {
+			if !recordOrder[skipped].repeated {
+				return 0, fmt.Errorf("archive has no %s record before %s", recordOrder[skipped].kind, kind)
+			}
+		}
+		return rank, nil
+	}
+	return 0, fmt.Errorf("unknown record kind %q in archive", kind)
+}
+
+func (n *archiveNode) toGraph(c *archiveCache) (*graph.Node, *graph.NodeCache, error) {
+	var metadata any
+	if len(n.Metadata) > 0 {
+		if err := json.Unmarshal(n.Metadata, &metadata); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal metadata of node %d: %w", n.ID, err)
+		}
+	}
+	node := &graph.Node{
+		ID:       n.ID,
+		Type:     n.Type,
+		Name:     n.Name,
+		Metadata: metadata,
+		Children: roaring.BitmapOf(n.Children...),
+		Parents:  roaring.BitmapOf(n.Parents...),
+	}
+	if c == nil {
+		return node, nil, nil
+	}
+	return node, graph.NewNodeCache(n.ID, roaring.BitmapOf(c.AllParents...), roaring.BitmapOf(c.AllChildren...)), nil
+}
@@ -0,0 +1,169 @@
+package storages
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportGraph(t *testing.T) {
+	src, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "src.db"))
+	require.NoError(t, err)
+	populateForCopy(t, src)
+	require.NoError(t, src.SetSchemaVersion(context.Background(), CurrentSchemaVersion()))
+	want, err := Summarize(context.Background(), src, 0)
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	exported, err := ExportGraph(context.Background(), src, &archive, 5)
+	require.NoError(t, err)
+	assert.Equal(t, want, exported)
+
+	t.Run("redis", func(t *testing.T) {
+		dst, err := SetupRedisTestDB(context.Background())
+		require.NoError(t, err)
+		imported, err := ImportGraph(context.Background(), dst, bytes.NewReader(archive.Bytes()), 0)
+		require.NoError(t, err)
+		assert.Equal(t, want, imported)
+
+		got, err := Summarize(context.Background(), dst, 0)
+		require.NoError(t, err)
+		assert.Empty(t, want.Diff(got))
+
+		// Importing into a storage that already has a graph is refused.
+		_, err = ImportGraph(context.Background(), dst, bytes.NewReader(archive.Bytes()), 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		dst := graph.NewMockStorage()
+		_, err := ImportGraph(context.Background(), dst, bytes.NewReader(archive.Bytes()), 0)
+		require.NoError(t, err)
+		id, err := dst.NameToID(context.Background(), "pkg:npm/uncached@1.0.0")
+		require.NoError(t, err)
+		node, err := dst.GetNode(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"version": "1.0.0"}, node.Metadata)
+		aliases, err := dst.GetAliases(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sha256:uncached"}, aliases)
+	})
+}
+
+func TestImportGraphRejectsBadArchives(t *testing.T) {
+	src := graph.NewMockStorage()
+	a, err := graph.AddNode(context.Background(), src, "library", nil, "a")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), src, "library", nil, "b")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), src, b))
+
+	var archive bytes.Buffer
+	_, err = ExportGraph(context.Background(), src, &archive, 0)
+	require.NoError(t, err)
+	lines := decompressLines(t, archive.Bytes())
+
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{
+			name:  "truncated",
+			lines: lines[:len(lines)-1],
+			err:   "truncated",
+		},
+		{
+			name:  "missing header",
+			lines: lines[1:],
+			err:   "no header record",
+		},
+		{
+			name:  "tampered node",
+			lines: replaceLine(lines, 1, strings.Replace(lines[1], `"name":"a"`, `"name":"c"`, 1)),
+			err:   "does not match its summary",
+		},
+		{
+			name:  "wrong format",
+			lines: replaceLine(lines, 0, strings.Replace(lines[0], ArchiveFormat, "something-else", 1)),
+			err:   "not a minefield graph archive",
+		},
+		{
+			name:  "newer schema",
+			lines: replaceLine(lines, 0, strings.Replace(lines[0], `"kind":"header"`, `"kind":"header","schema_version":999`, 1)),
+			err:   ErrSchemaNewer.Error(),
+		},
+		{
+			name:  "records out of order",
+			lines: append([]string{lines[0], lines[3]}, lines[1:]...),
+			err:   "unexpected node record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportGraph(context.Background(), graph.NewMockStorage(), bytes.NewReader(compressLines(t, tt.lines)), 0)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+
+	_, err = ImportGraph(context.Background(), graph.NewMockStorage(), strings.NewReader("not gzip"), 0)
+	assert.Error(t, err)
+}
+
+func TestImportGraphMigratesOldSchema(t *testing.T) {
+	ctx := context.Background()
+	// Data written before the schema was versioned.
+	src := graph.NewMockStorage()
+	_, err := graph.AddNode(ctx, src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	var archive bytes.Buffer
+	_, err = ExportGraph(ctx, src, &archive, 0)
+	require.NoError(t, err)
+
+	// The server stamps an empty storage with the current version when it starts.
+	dst := graph.NewMockStorage()
+	status, err := GetSchemaStatus(ctx, dst)
+	require.NoError(t, err)
+	require.Equal(t, CurrentSchemaVersion(), status.Version)
+
+	_, err = ImportGraph(ctx, dst, bytes.NewReader(archive.Bytes()), 0)
+	require.NoError(t, err)
+	version, err := dst.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion(), version)
+}
+
+func decompressLines(t *testing.T, data []byte) []string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func compressLines(t *testing.T, lines []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func replaceLine(lines []string, i int, line string) []string {
+	result := append([]string(nil), lines...)
+	result[i] = line
+	return result
+}
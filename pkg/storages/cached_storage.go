@@ -0,0 +1,320 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// CachedStorage is a read-through cache in front of another storage. It keeps the most
+// recently used nodes and node caches decoded in memory, so hot nodes don't have to be read
+// and unmarshalled again for every request.
+//
+// Writes go straight to the wrapped storage and invalidate the affected entries. Every
+// method that isn't overridden is passed through unchanged, so the wrapped storage must not
+// be written to by anything else while the CachedStorage is in use.
+type CachedStorage struct {
+	graph.Storage
+
+	nodes  *lruCache[*graph.Node]
+	caches *lruCache[*graph.NodeCache]
+}
+
+// CacheStats are the counters of one of the LRU caches of a CachedStorage.
+type CacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Entries  int    `json:"entries"`
+	Capacity int    `json:"capacity"`
+}
+
+// HitRate returns the fraction of lookups that were served from the cache.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// CachedStorageStats are the counters of the node and node cache LRUs of a CachedStorage.
+type CachedStorageStats struct {
+	Nodes  CacheStats `json:"nodes"`
+	Caches CacheStats `json:"caches"`
+}
+
+// NewCachedStorage wraps storage with LRU caches holding up to size nodes and size node caches.
+func NewCachedStorage(storage graph.Storage, size int) (*CachedStorage, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("cache size must be greater than zero")
+	}
+	nodes, err := newLRUCache(size, cloneNode)
+	if err != nil {
+		return nil, err
+	}
+	caches, err := newLRUCache(size, cloneNodeCache)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedStorage{Storage: storage, nodes: nodes, caches: caches}, nil
+}
+
+// Stats returns the current hit and miss counters of the caches.
+func (c *CachedStorage) Stats() CachedStorageStats {
+	return CachedStorageStats{Nodes: c.nodes.stats(), Caches: c.caches.stats()}
+}
+
+func (c *CachedStorage) GetNode(ctx context.Context, id uint32) (*graph.Node, error) {
+	if node, ok := c.nodes.get(id); ok {
+		return node, nil
+	}
+	generation := c.nodes.generation()
+	node, err := c.Storage.GetNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.nodes.add(generation, id, node)
+	return node, nil
+}
+
+func (c *CachedStorage) GetNodes(ctx context.Context, ids []uint32) (map[uint32]*graph.Node, error) {
+	result := make(map[uint32]*graph.Node, len(ids))
+	var missing []uint32
+	for _, id := range ids {
+		if node, ok := c.nodes.get(id); ok {
+			result[id] = node
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	generation := c.nodes.generation()
+	nodes, err := c.Storage.GetNodes(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, node := range nodes {
+		c.nodes.add(generation, id, node)
+		result[id] = node
+	}
+	return result, nil
+}
+
+func (c *CachedStorage) SaveNode(ctx context.Context, node *graph.Node) error {
+	defer c.nodes.remove(node.ID)
+	return c.Storage.SaveNode(ctx, node)
+}
+
+func (c *CachedStorage) CreateNode(ctx context.Context, node *graph.Node) (uint32, error) {
+	defer c.nodes.remove(node.ID)
+	return c.Storage.CreateNode(ctx, node)
+}
+
+func (c *CachedStorage) AddDependency(ctx context.Context, from, to uint32) error {
+	defer c.nodes.remove(from, to)
+	return c.Storage.AddDependency(ctx, from, to)
+}
+
+func (c *CachedStorage) RemoveDependency(ctx context.Context, from, to uint32) error {
+	defer c.nodes.remove(from, to)
+	return c.Storage.RemoveDependency(ctx, from, to)
+}
+
+func (c *CachedStorage) DeleteNode(ctx context.Context, id uint32) error {
+	defer c.caches.remove(id)
+	defer c.nodes.remove(id)
+	return c.Storage.DeleteNode(ctx, id)
+}
+
+func (c *CachedStorage) UpdateNodeMetadata(ctx context.Context, id uint32, update func(metadata any) (any, error)) (*graph.Node, error) {
+	defer c.nodes.remove(id)
+	return c.Storage.UpdateNodeMetadata(ctx, id, update)
+}
+
+func (c *CachedStorage) GetCache(ctx context.Context, id uint32) (*graph.NodeCache, error) {
+	if cache, ok := c.caches.get(id); ok {
+		return cache, nil
+	}
+	generation := c.caches.generation()
+	cache, err := c.Storage.GetCache(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.caches.add(generation, id, cache)
+	return cache, nil
+}
+
+func (c *CachedStorage) GetCaches(ctx context.Context, ids []uint32) (map[uint32]*graph.NodeCache, error) {
+	result := make(map[uint32]*graph.NodeCache, len(ids))
+	var missing []uint32
+	for _, id := range ids {
+		if cache, ok := c.caches.get(id); ok {
+			result[id] = cache
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	generation := c.caches.generation()
+	caches, err := c.Storage.GetCaches(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, cache := range caches {
+		c.caches.add(generation, id, cache)
+		result[id] = cache
+	}
+	return result, nil
+}
+
+func (c *CachedStorage) SaveCache(ctx context.Context, cache *graph.NodeCache) error {
+	defer c.caches.remove(cache.ID)
+	return c.Storage.SaveCache(ctx, cache)
+}
+
+func (c *CachedStorage) SaveCaches(ctx context.Context, caches []*graph.NodeCache) error {
+	ids := make([]uint32, 0, len(caches))
+	for _, cache := range caches {
+		ids = append(ids, cache.ID)
+	}
+	defer c.caches.remove(ids...)
+	return c.Storage.SaveCaches(ctx, caches)
+}
+
+func (c *CachedStorage) RemoveAllCaches(ctx context.Context) error {
+	defer c.caches.purge()
+	return c.Storage.RemoveAllCaches(ctx)
+}
+
+// lruCache is a bounded cache of decoded values with hit and miss counters. Values are
+// cloned on the way in and out, since callers modify the nodes they get from a storage
+// before saving them.
+type lruCache[V any] struct {
+	// mu makes invalidations and the check for them in add atomic, so a value read from the
+	// storage before an invalidation is never added after it.
+	mu    sync.Mutex
+	gen   uint64
+	cache *lru.Cache[uint32, V]
+	size  int
+	clone func(V) V
+
+	hits, misses atomic.Uint64
+}
+
+func newLRUCache[V any](size int, clone func(V) V) (*lruCache[V], error) {
+	cache, err := lru.New[uint32, V](size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %w", err)
+	}
+	return &lruCache[V]{cache: cache, size: size, clone: clone}, nil
+}
+
+func (c *lruCache[V]) get(id uint32) (V, bool) {
+	value, ok := c.cache.Get(id)
+	if !ok {
+		c.misses.Add(1)
+		return value, false
+	}
+	c.hits.Add(1)
+	return c.clone(value), true
+}
+
+// generation returns a token to pass to add for values read from the storage after this call.
+func (c *lruCache[V]) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// add caches value unless an invalidation happened since generation was called.
+func (c *lruCache[V]) add(generation uint64, id uint32, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == generation {
+		c.cache.Add(id, c.clone(value))
+	}
+}
+
+func (c *lruCache[V]) remove(ids ...uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, id := range ids {
+		c.cache.Remove(id)
+	}
+}
+
+func (c *lruCache[V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.cache.Purge()
+}
+
+func (c *lruCache[V]) stats() CacheStats {
+	return CacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Entries:  c.cache.Len(),
+		Capacity: c.size,
+	}
+}
+
+func cloneNode(node *graph.Node) *graph.Node {
+	if node == nil {
+		return nil
+	}
+	clone := *node
+	if node.Children != nil {
+		clone.Children = node.Children.Clone()
+	}
+	if node.Parents != nil {
+		clone.Parents = node.Parents.Clone()
+	}
+	clone.Metadata = cloneMetadata(node.Metadata)
+	return &clone
+}
+
+// cloneMetadata deep copies metadata decoded from JSON. Other values are shared.
+func cloneMetadata(metadata any) any {
+	switch m := metadata.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(m))
+		for k, v := range m {
+			clone[k] = cloneMetadata(v)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(m))
+		for i, v := range m {
+			clone[i] = cloneMetadata(v)
+		}
+		return clone
+	default:
+		return metadata
+	}
+}
+
+func cloneNodeCache(cache *graph.NodeCache) *graph.NodeCache {
+	if cache == nil {
+		return nil
+	}
+	clone := *cache
+	if cache.AllParents != nil {
+		clone.AllParents = cache.AllParents.Clone()
+	}
+	if cache.AllChildren != nil {
+		clone.AllChildren = cache.AllChildren.Clone()
+	}
+	return &clone
+}
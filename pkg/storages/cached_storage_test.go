@@ -0,0 +1,230 @@
+package storages
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStorage counts the node and cache reads that reach the wrapped storage.
+type countingStorage struct {
+	graph.Storage
+	mu                    sync.Mutex
+	nodeReads, cacheReads int
+}
+
+func (s *countingStorage) GetNode(ctx context.Context, id uint32) (*graph.Node, error) {
+	s.mu.Lock()
+	s.nodeReads++
+	s.mu.Unlock()
+	return s.Storage.GetNode(context.Background(), id)
+}
+
+func (s *countingStorage) GetNodes(ctx context.Context, ids []uint32) (map[uint32]*graph.Node, error) {
+	s.mu.Lock()
+	s.nodeReads += len(ids)
+	s.mu.Unlock()
+	return s.Storage.GetNodes(context.Background(), ids)
+}
+
+func (s *countingStorage) GetCaches(ctx context.Context, ids []uint32) (map[uint32]*graph.NodeCache, error) {
+	s.mu.Lock()
+	s.cacheReads += len(ids)
+	s.mu.Unlock()
+	return s.Storage.GetCaches(context.Background(), ids)
+}
+
+func newCountedCachedStorage(t *testing.T, size int) (*CachedStorage, *countingStorage) {
+	t.Helper()
+	backend := &countingStorage{Storage: graph.NewMockStorage()}
+	cached, err := NewCachedStorage(backend, size)
+	require.NoError(t, err)
+	return cached, backend
+}
+
+func TestCachedStorageGetNode(t *testing.T) {
+	cached, backend := newCountedCachedStorage(t, 10)
+	node, err := graph.AddNode(context.Background(), cached, "library", map[string]any{"version": "1.0.0"}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		got, err := cached.GetNode(context.Background(), node.ID)
+		require.NoError(t, err)
+		assert.Equal(t, node.Name, got.Name)
+	}
+	assert.Equal(t, 1, backend.nodeReads)
+	stats := cached.Stats().Nodes
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, 10, stats.Capacity)
+	assert.InDelta(t, 2.0/3.0, stats.HitRate(), 0.001)
+
+	// Callers modify the nodes they get, that must not change the cached node.
+	got, err := cached.GetNode(context.Background(), node.ID)
+	require.NoError(t, err)
+	got.Children.Add(42)
+	got.Metadata.(map[string]any)["version"] = "2.0.0"
+	got, err = cached.GetNode(context.Background(), node.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Children.Contains(42))
+	assert.Equal(t, "1.0.0", got.Metadata.(map[string]any)["version"])
+
+	_, err = cached.GetNode(context.Background(), 1000)
+	assert.Error(t, err)
+}
+
+func TestCachedStorageInvalidatesNodes(t *testing.T) {
+	cached, backend := newCountedCachedStorage(t, 10)
+	a, err := graph.AddNode(context.Background(), cached, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), cached, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+
+	nodes, err := cached.GetNodes(context.Background(), []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.Len(t, nodes, 2)
+
+	require.NoError(t, a.SetDependency(context.Background(), cached, b))
+	got, err := cached.GetNode(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Children.Contains(b.ID))
+	got, err = cached.GetNode(context.Background(), b.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Parents.Contains(a.ID))
+
+	require.NoError(t, cached.RemoveDependency(context.Background(), a.ID, b.ID))
+	nodes, err = cached.GetNodes(context.Background(), []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.False(t, nodes[a.ID].Children.Contains(b.ID))
+	assert.False(t, nodes[b.ID].Parents.Contains(a.ID))
+
+	got.Name = "renamed"
+	require.NoError(t, cached.SaveNode(context.Background(), got))
+	reads := backend.nodeReads
+	nodes, err = cached.GetNodes(context.Background(), []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", nodes[b.ID].Name)
+	assert.Equal(t, reads+1, backend.nodeReads, "only the saved node is read again")
+}
+
+func TestCachedStorageInvalidatesCaches(t *testing.T) {
+	cached, backend := newCountedCachedStorage(t, 10)
+	a, err := graph.AddNode(context.Background(), cached, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), cached, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, cached.SaveCaches(context.Background(), []*graph.NodeCache{
+		graph.NewNodeCache(a.ID, roaring.New(), roaring.BitmapOf(b.ID)),
+		graph.NewNodeCache(b.ID, roaring.BitmapOf(a.ID), roaring.New()),
+	}))
+
+	for i := 0; i < 2; i++ {
+		caches, err := cached.GetCaches(context.Background(), []uint32{a.ID, b.ID})
+		require.NoError(t, err)
+		assert.True(t, caches[a.ID].AllChildren.Contains(b.ID))
+	}
+	assert.Equal(t, 2, backend.cacheReads)
+	got, err := cached.GetCache(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.True(t, got.AllChildren.Contains(b.ID))
+	assert.Equal(t, uint64(3), cached.Stats().Caches.Hits)
+
+	require.NoError(t, cached.SaveCache(context.Background(), graph.NewNodeCache(a.ID, roaring.New(), roaring.New())))
+	got, err = cached.GetCache(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.False(t, got.AllChildren.Contains(b.ID))
+
+	require.NoError(t, cached.SaveCaches(context.Background(), []*graph.NodeCache{graph.NewNodeCache(b.ID, roaring.New(), roaring.New())}))
+	got, err = cached.GetCache(context.Background(), b.ID)
+	require.NoError(t, err)
+	assert.False(t, got.AllParents.Contains(a.ID))
+
+	require.NoError(t, cached.RemoveAllCaches(context.Background()))
+	assert.Equal(t, 0, cached.Stats().Caches.Entries)
+	_, err = cached.GetCache(context.Background(), a.ID)
+	assert.Error(t, err)
+}
+
+func TestCachedStorageEvicts(t *testing.T) {
+	cached, _ := newCountedCachedStorage(t, 2)
+	var ids []uint32
+	for _, name := range []string{"a", "b", "c"} {
+		node, err := graph.AddNode(context.Background(), cached, "library", nil, name)
+		require.NoError(t, err)
+		ids = append(ids, node.ID)
+	}
+	_, err := cached.GetNodes(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cached.Stats().Nodes.Entries)
+
+	_, err = NewCachedStorage(graph.NewMockStorage(), 0)
+	assert.Error(t, err)
+}
+
+func TestCachedStorageCacheGraph(t *testing.T) {
+	cached, _ := newCountedCachedStorage(t, 100)
+	a, err := graph.AddNode(context.Background(), cached, "library", nil, "a")
+	require.NoError(t, err)
+	b, err := graph.AddNode(context.Background(), cached, "library", nil, "b")
+	require.NoError(t, err)
+	c, err := graph.AddNode(context.Background(), cached, "library", nil, "c")
+	require.NoError(t, err)
+	require.NoError(t, a.SetDependency(context.Background(), cached, b))
+	require.NoError(t, b.SetDependency(context.Background(), cached, c))
+	require.NoError(t, graph.Cache(context.Background(), cached))
+
+	cache, err := cached.GetCache(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.True(t, cache.AllChildren.Contains(c.ID))
+
+	// Adding an edge and caching again must not serve the old caches.
+	d, err := graph.AddNode(context.Background(), cached, "library", nil, "d")
+	require.NoError(t, err)
+	require.NoError(t, c.SetDependency(context.Background(), cached, d))
+	require.NoError(t, graph.Cache(context.Background(), cached))
+	cache, err = cached.GetCache(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.True(t, cache.AllChildren.Contains(d.ID))
+}
+
+func TestCachedStorageConcurrentReadsAndWrites(t *testing.T) {
+	// The mock storage shares its nodes with callers, so a real backend is used here.
+	backend, err := NewSQLStorage("", true)
+	require.NoError(t, err)
+	cached, err := NewCachedStorage(backend, 10)
+	require.NoError(t, err)
+	a, err := graph.AddNode(context.Background(), cached, "library", nil, "a")
+	require.NoError(t, err)
+
+	var children []uint32
+	for i := 0; i < 20; i++ {
+		child, err := graph.AddNode(context.Background(), cached, "library", nil, string(rune('b'+i)))
+		require.NoError(t, err)
+		children = append(children, child.ID)
+	}
+
+	var wg sync.WaitGroup
+	for _, child := range children {
+		wg.Add(2)
+		go func(child uint32) {
+			defer wg.Done()
+			assert.NoError(t, cached.AddDependency(context.Background(), a.ID, child))
+		}(child)
+		go func() {
+			defer wg.Done()
+			_, err := cached.GetNode(context.Background(), a.ID)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	got, err := cached.GetNode(context.Background(), a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(children)), got.Children.GetCardinality())
+}
@@ -0,0 +1,100 @@
+package storages
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/storages/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	backends := []struct {
+		name  string
+		setup storagetest.Factory
+	}{
+		{
+			name: "mock",
+			setup: func(t *testing.T) graph.Storage {
+				return graph.NewMockStorage()
+			},
+		},
+		{
+			name: "sqlite in memory",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := NewSQLStorage("", true)
+				require.NoError(t, err)
+				return s
+			},
+		},
+		{
+			name: "sqlite file",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "conformance.db"))
+				require.NoError(t, err)
+				return s
+			},
+		},
+		{
+			name: "redis",
+			setup: func(t *testing.T) graph.Storage {
+				r, err := SetupRedisTestDB(context.Background())
+				require.NoError(t, err)
+				return r
+			},
+		},
+		{
+			name: "cached sqlite",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := NewSQLStorage("", true)
+				require.NoError(t, err)
+				cached, err := NewCachedStorage(s, 100)
+				require.NoError(t, err)
+				return cached
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storagetest.Run(t, backend.setup)
+		})
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	backends := map[string]func(t *testing.T) graph.Storage{
+		"sqlite": func(t *testing.T) graph.Storage {
+			s, err := NewSQLStorage("", true)
+			require.NoError(t, err)
+			return s
+		},
+		"redis": func(t *testing.T) graph.Storage {
+			r, err := SetupRedisTestDB(context.Background())
+			require.NoError(t, err)
+			return r
+		},
+	}
+
+	for name, setup := range backends {
+		t.Run(name, func(t *testing.T) {
+			storage := setup(t)
+			node, err := graph.AddNode(context.Background(), storage, "library", nil, "pkg:npm/a@1.0.0")
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = storage.GetNode(ctx, node.ID)
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = storage.GetAllKeys(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.ErrorIs(t, storage.SaveNode(ctx, node), context.Canceled)
+			_, err = graph.AddNode(ctx, storage, "library", nil, "pkg:npm/b@1.0.0")
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.ErrorIs(t, graph.Cache(ctx, storage), context.Canceled)
+		})
+	}
+}
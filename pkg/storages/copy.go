@@ -0,0 +1,363 @@
+package storages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/goccy/go-json"
+)
+
+// DefaultCopyBatchSize is the number of nodes and caches read and written at once by CopyGraph.
+const DefaultCopyBatchSize = 1000
+
+// GraphSummary describes the contents of a storage, so two storages can be compared
+// without holding both graphs in memory.
+type GraphSummary struct {
+	Nodes              int    `json:"nodes"`
+	Caches             int    `json:"caches"`
+	CacheStack         int    `json:"cache_stack"`
+	CustomData         int    `json:"custom_data"`
+	Aliases            int    `json:"aliases,omitempty"`
+	IDCounter          uint32 `json:"id_counter"`
+	NodesChecksum      string `json:"nodes_checksum"`
+	CachesChecksum     string `json:"caches_checksum"`
+	CacheStackChecksum string `json:"cache_stack_checksum"`
+	CustomDataChecksum string `json:"custom_data_checksum"`
+}
+
+// graphVisitor receives the contents of a storage from walkGraph.
+type graphVisitor interface {
+	// visitNode is called for every node in ascending ID order with its sorted aliases, cache
+	// is nil for nodes without a cache.
+	visitNode(node *graph.Node, cache *graph.NodeCache, aliases []string) error
+	// visitCacheStack is called once with the sorted IDs on the cache stack, after all nodes.
+	visitCacheStack(ids []uint32) error
+	// visitCustomData is called for every custom data field, sorted by tag, key and data key.
+	visitCustomData(tag, key, dataKey string, value []byte) error
+	// visitIDCounter is called last with the ID counter of the storage.
+	visitIDCounter(id uint32) error
+}
+
+// walkGraph reads the whole storage batchSize nodes at a time and hands it to the visitor in
+// a deterministic order, so the same graph is visited the same way in every backend.
+func walkGraph(ctx context.Context, storage graph.Storage, batchSize int, visitor graphVisitor) error {
+	if batchSize <= 0 {
+		batchSize = DefaultCopyBatchSize
+	}
+
+	ids, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all keys: %w", err)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for start := 0; start < len(ids); start += batchSize {
+		batch := ids[start:min(start+batchSize, len(ids))]
+		nodes, err := storage.GetNodes(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("failed to get nodes: %w", err)
+		}
+		caches, err := storage.GetCaches(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("failed to get caches: %w", err)
+		}
+		for _, id := range batch {
+			node, ok := nodes[id]
+			if !ok {
+				return fmt.Errorf("node %d was removed while reading the graph", id)
+			}
+			aliases, err := storage.GetAliases(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get aliases of node %d: %w", id, err)
+			}
+			if err := visitor.visitNode(node, caches[id], aliases); err != nil {
+				return err
+			}
+		}
+	}
+
+	stack, err := storage.ToBeCached(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get cache stack: %w", err)
+	}
+	if err := visitor.visitCacheStack(uniqueSorted(stack)); err != nil {
+		return err
+	}
+
+	customKeys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	tags := make([]string, 0, len(customKeys))
+	for tag := range customKeys {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		keys := append([]string(nil), customKeys[tag]...)
+		sort.Strings(keys)
+		for _, key := range keys {
+			data, err := storage.GetCustomData(ctx, tag, key)
+			if err != nil {
+				return fmt.Errorf("failed to get custom data for %s:%s: %w", tag, key, err)
+			}
+			dataKeys := make([]string, 0, len(data))
+			for dataKey := range data {
+				dataKeys = append(dataKeys, dataKey)
+			}
+			sort.Strings(dataKeys)
+			for _, dataKey := range dataKeys {
+				if err := visitor.visitCustomData(tag, key, dataKey, data[dataKey]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	counter, err := storage.GetIDCounter(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ID counter: %w", err)
+	}
+	return visitor.visitIDCounter(counter)
+}
+
+// CopyGraph copies the nodes, aliases, caches, cache stack, custom data, ID counter and schema version
+// of src into dst, batchSize nodes at a time. Node IDs are preserved, so dst has to be empty.
+func CopyGraph(ctx context.Context, src, dst graph.Storage, batchSize int) error {
+	writer, err := newStorageWriter(ctx, dst, batchSize)
+	if err != nil {
+		return err
+	}
+	if err := walkGraph(ctx, src, batchSize, writer); err != nil {
+		return err
+	}
+	version, err := src.GetSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	return dst.SetSchemaVersion(ctx, version)
+}
+
+// Summarize counts and checksums everything CopyGraph copies. The checksums only depend on
+// the logical contents, so the same graph gives the same summary in every backend.
+func Summarize(ctx context.Context, storage graph.Storage, batchSize int) (*GraphSummary, error) {
+	builder := newSummaryBuilder()
+	if err := walkGraph(ctx, storage, batchSize, builder); err != nil {
+		return nil, err
+	}
+	return builder.summary(), nil
+}
+
+// Diff returns a description of every field in which the two summaries differ.
+func (s *GraphSummary) Diff(other *GraphSummary) []string {
+	var diffs []string
+	check := func(field string, a, b any) {
+		if a != b {
+			diffs = append(diffs, fmt.Sprintf("%s: %v != %v", field, a, b))
+		}
+	}
+	check("nodes", s.Nodes, other.Nodes)
+	check("caches", s.Caches, other.Caches)
+	check("cache stack", s.CacheStack, other.CacheStack)
+	check("custom data", s.CustomData, other.CustomData)
+	check("aliases", s.Aliases, other.Aliases)
+	check("ID counter", s.IDCounter, other.IDCounter)
+	check("nodes checksum", s.NodesChecksum, other.NodesChecksum)
+	check("caches checksum", s.CachesChecksum, other.CachesChecksum)
+	check("cache stack checksum", s.CacheStackChecksum, other.CacheStackChecksum)
+	check("custom data checksum", s.CustomDataChecksum, other.CustomDataChecksum)
+	return diffs
+}
+
+// storageWriter is a graphVisitor that writes everything it visits into a storage. It only
+// lives for a single copy or import, whose context it keeps.
+type storageWriter struct {
+	ctx       context.Context
+	storage   graph.Storage
+	batchSize int
+	caches    []*graph.NodeCache
+}
+
+// newStorageWriter returns a storageWriter for dst, which has to be empty since the written
+// nodes keep their IDs.
+func newStorageWriter(ctx context.Context, dst graph.Storage, batchSize int) (*storageWriter, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultCopyBatchSize
+	}
+	existing, err := dst.GetAllKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keys of destination: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("destination storage is not empty, it has %d nodes", len(existing))
+	}
+	return &storageWriter{ctx: ctx, storage: dst, batchSize: batchSize}, nil
+}
+
+func (w *storageWriter) visitNode(node *graph.Node, cache *graph.NodeCache, aliases []string) error {
+	if err := w.storage.SaveNode(w.ctx, node); err != nil {
+		return fmt.Errorf("failed to save node %d: %w", node.ID, err)
+	}
+	if len(aliases) > 0 {
+		if err := w.storage.AddAliases(w.ctx, node.ID, aliases); err != nil {
+			return fmt.Errorf("failed to add aliases of node %d: %w", node.ID, err)
+		}
+	}
+	if cache != nil {
+		w.caches = append(w.caches, cache)
+		if len(w.caches) >= w.batchSize {
+			return w.flushCaches()
+		}
+	}
+	return nil
+}
+
+func (w *storageWriter) flushCaches() error {
+	if len(w.caches) == 0 {
+		return nil
+	}
+	if err := w.storage.SaveCaches(w.ctx, w.caches); err != nil {
+		return fmt.Errorf("failed to save caches: %w", err)
+	}
+	w.caches = w.caches[:0]
+	return nil
+}
+
+func (w *storageWriter) visitCacheStack(ids []uint32) error {
+	if err := w.flushCaches(); err != nil {
+		return err
+	}
+	// SaveNode puts every node on the cache stack, replace that with the visited stack.
+	if err := w.storage.ClearCacheStack(w.ctx); err != nil {
+		return fmt.Errorf("failed to clear cache stack: %w", err)
+	}
+	for _, id := range ids {
+		if err := w.storage.AddNodeToCachedStack(w.ctx, id); err != nil {
+			return fmt.Errorf("failed to add node %d to cache stack: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (w *storageWriter) visitCustomData(tag, key, dataKey string, value []byte) error {
+	if err := w.storage.AddOrUpdateCustomData(w.ctx, tag, key, dataKey, value); err != nil {
+		return fmt.Errorf("failed to save custom data for %s:%s: %w", tag, key, err)
+	}
+	return nil
+}
+
+func (w *storageWriter) visitIDCounter(id uint32) error {
+	if err := w.storage.SetIDCounter(w.ctx, id); err != nil {
+		return fmt.Errorf("failed to set ID counter: %w", err)
+	}
+	return nil
+}
+
+// summaryBuilder is a graphVisitor that counts and hashes everything it visits.
+type summaryBuilder struct {
+	result                                   GraphSummary
+	nodesHash, cachesHash, stackHash, custom hash.Hash
+}
+
+func newSummaryBuilder() *summaryBuilder {
+	return &summaryBuilder{
+		nodesHash:  sha256.New(),
+		cachesHash: sha256.New(),
+		stackHash:  sha256.New(),
+		custom:     sha256.New(),
+	}
+}
+
+func (b *summaryBuilder) visitNode(node *graph.Node, cache *graph.NodeCache, aliases []string) error {
+	metadata, err := json.Marshal(node.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata of node %d: %w", node.ID, err)
+	}
+	writeUint32s(b.nodesHash, node.ID)
+	writeStrings(b.nodesHash, node.Type, node.Name, string(metadata))
+	writeUint32s(b.nodesHash, node.Children.ToArray()...)
+	writeUint32s(b.nodesHash, node.Parents.ToArray()...)
+	// Nodes without aliases hash like they did before aliases existed, so the summaries of
+	// older archives still match.
+	if len(aliases) > 0 {
+		writeStrings(b.nodesHash, aliases...)
+		b.result.Aliases += len(aliases)
+	}
+	b.result.Nodes++
+
+	if cache != nil {
+		writeUint32s(b.cachesHash, node.ID)
+		writeUint32s(b.cachesHash, cache.AllParents.ToArray()...)
+		writeUint32s(b.cachesHash, cache.AllChildren.ToArray()...)
+		b.result.Caches++
+	}
+	return nil
+}
+
+func (b *summaryBuilder) visitCacheStack(ids []uint32) error {
+	writeUint32s(b.stackHash, ids...)
+	b.result.CacheStack = len(ids)
+	return nil
+}
+
+func (b *summaryBuilder) visitCustomData(tag, key, dataKey string, value []byte) error {
+	writeStrings(b.custom, tag, key, dataKey, string(value))
+	b.result.CustomData++
+	return nil
+}
+
+func (b *summaryBuilder) visitIDCounter(id uint32) error {
+	b.result.IDCounter = id
+	return nil
+}
+
+func (b *summaryBuilder) summary() *GraphSummary {
+	result := b.result
+	result.NodesChecksum = hex.EncodeToString(b.nodesHash.Sum(nil))
+	result.CachesChecksum = hex.EncodeToString(b.cachesHash.Sum(nil))
+	result.CacheStackChecksum = hex.EncodeToString(b.stackHash.Sum(nil))
+	result.CustomDataChecksum = hex.EncodeToString(b.custom.Sum(nil))
+	return &result
+}
+
+// uniqueSorted returns the sorted, de-duplicated IDs. Some backends keep duplicates on the
+// cache stack, but they carry no meaning.
+func uniqueSorted(ids []uint32) []uint32 {
+	seen := make(map[uint32]bool, len(ids))
+	result := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// writeUint32s writes the length of values followed by the values, so that consecutive
+// lists can't be confused with each other.
+func writeUint32s(h hash.Hash, values ...uint32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(values)))
+	h.Write(buf[:])
+	for _, v := range values {
+		binary.BigEndian.PutUint32(buf[:], v)
+		h.Write(buf[:])
+	}
+}
+
+func writeStrings(h hash.Hash, values ...string) {
+	var buf [8]byte
+	for _, v := range values {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(v)))
+		h.Write(buf[:])
+		h.Write([]byte(v))
+	}
+}
@@ -0,0 +1,130 @@
+package storages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// populateForCopy ingests a few SBOMs, caches the graph and adds some custom data and an
+// uncached node with an alias, so every part of the storage has something in it.
+func populateForCopy(t *testing.T, storage graph.Storage) {
+	t.Helper()
+	for _, name := range overlappingSBOMs[:3] {
+		data, err := os.ReadFile(filepath.Join("../../testdata/sboms", name))
+		require.NoError(t, err)
+		require.NoError(t, ingest.SBOM(context.Background(), storage, data))
+	}
+	require.NoError(t, graph.Cache(context.Background(), storage))
+	uncached, err := graph.AddNode(context.Background(), storage, "library", map[string]any{"version": "1.0.0"}, "pkg:npm/uncached@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, storage.AddAliases(context.Background(), uncached.ID, []string{"sha256:uncached"}))
+	require.NoError(t, storage.AddOrUpdateCustomData(context.Background(), "scores", "pkg:npm/uncached", "score", []byte("7.5")))
+	require.NoError(t, storage.AddOrUpdateCustomData(context.Background(), "scores", "pkg:npm/other", "score", []byte("3")))
+}
+
+func TestCopyGraph(t *testing.T) {
+	src, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "src.db"))
+	require.NoError(t, err)
+	populateForCopy(t, src)
+
+	srcSummary, err := Summarize(context.Background(), src, 0)
+	require.NoError(t, err)
+	assert.NotZero(t, srcSummary.Nodes)
+	assert.NotZero(t, srcSummary.Caches)
+	assert.Equal(t, 1, srcSummary.CacheStack)
+	// The two scores, the contribution and payload record of every SBOM, and the package index.
+	customDataKeys, err := src.GetCustomDataKeys(context.Background())
+	require.NoError(t, err)
+	packages := 0
+	for _, key := range customDataKeys[ingest.PackagesTag] {
+		entries, err := src.GetCustomData(context.Background(), ingest.PackagesTag, key)
+		require.NoError(t, err)
+		packages += len(entries)
+	}
+	assert.NotZero(t, packages)
+	assert.Equal(t, 2+3*2+packages, srcSummary.CustomData)
+	assert.NotZero(t, srcSummary.Aliases)
+	assert.Equal(t, uint32(srcSummary.Nodes), srcSummary.IDCounter)
+
+	destinations := []struct {
+		name  string
+		setup func(t *testing.T) graph.Storage
+	}{
+		{
+			name: "sqlite",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "dst.db"))
+				require.NoError(t, err)
+				return s
+			},
+		},
+		{
+			name: "redis",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := SetupRedisTestDB(context.Background())
+				require.NoError(t, err)
+				return s
+			},
+		},
+	}
+
+	for _, destination := range destinations {
+		t.Run(destination.name, func(t *testing.T) {
+			dst := destination.setup(t)
+			// A small batch size makes sure the copy works across batch boundaries.
+			require.NoError(t, CopyGraph(context.Background(), src, dst, 7))
+
+			dstSummary, err := Summarize(context.Background(), dst, 0)
+			require.NoError(t, err)
+			assert.Empty(t, srcSummary.Diff(dstSummary))
+
+			// Node IDs are preserved and new IDs continue after the copied ones.
+			id, err := dst.NameToID(context.Background(), "pkg:npm/uncached@1.0.0")
+			require.NoError(t, err)
+			srcID, err := src.NameToID(context.Background(), "pkg:npm/uncached@1.0.0")
+			require.NoError(t, err)
+			assert.Equal(t, srcID, id)
+			aliasID, err := dst.AliasToID(context.Background(), "sha256:uncached")
+			require.NoError(t, err)
+			assert.Equal(t, srcID, aliasID)
+			next, err := dst.GenerateID(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, srcSummary.IDCounter+1, next)
+
+			// The destination is not empty anymore, so copying again is refused.
+			assert.Error(t, CopyGraph(context.Background(), src, dst, 0))
+		})
+	}
+}
+
+func TestGraphSummaryDiff(t *testing.T) {
+	a := &GraphSummary{Nodes: 2, NodesChecksum: "abc", IDCounter: 2}
+	b := &GraphSummary{Nodes: 2, NodesChecksum: "abd", IDCounter: 3}
+	assert.Empty(t, a.Diff(a))
+	assert.Equal(t, []string{"ID counter: 2 != 3", "nodes checksum: abc != abd"}, a.Diff(b))
+}
+
+func TestOpen(t *testing.T) {
+	assert.Contains(t, Backends(), "sqlite")
+	assert.Contains(t, Backends(), "redis")
+
+	s, err := Open("sqlite:" + filepath.Join(t.TempDir(), "open.db"))
+	require.NoError(t, err)
+	assert.IsType(t, &SQLStorage{}, s)
+
+	s, err = Open("sqlite::memory:")
+	require.NoError(t, err)
+	assert.IsType(t, &SQLStorage{}, s)
+
+	_, err = Open("unknown:somewhere")
+	assert.Error(t, err)
+	_, err = Open("sqlite")
+	assert.Error(t, err)
+}
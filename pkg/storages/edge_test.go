@@ -0,0 +1,174 @@
+package storages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// overlappingSBOMs share a large number of Go dependencies, so ingesting them at the same time
+// makes many goroutines add edges to the same nodes.
+var overlappingSBOMs = []string{
+	"google_addlicense.sbom.json",
+	"google_buzzer.sbom.json",
+	"google_cabbie.sbom.json",
+	"google_capslock.sbom.json",
+	"google_cel-policy-templates-go.sbom.json",
+	"google_certtostore.sbom.json",
+}
+
+func TestAddAndRemoveDependency(t *testing.T) {
+	s, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "edges.db"))
+	require.NoError(t, err)
+
+	from, err := graph.AddNode(context.Background(), s, "library", nil, "from")
+	require.NoError(t, err)
+	to, err := graph.AddNode(context.Background(), s, "library", nil, "to")
+	require.NoError(t, err)
+	require.NoError(t, s.ClearCacheStack(context.Background()))
+
+	require.NoError(t, s.AddDependency(context.Background(), from.ID, to.ID))
+	fromNode, err := s.GetNode(context.Background(), from.ID)
+	require.NoError(t, err)
+	toNode, err := s.GetNode(context.Background(), to.ID)
+	require.NoError(t, err)
+	assert.True(t, fromNode.Children.Contains(to.ID))
+	assert.True(t, toNode.Parents.Contains(from.ID))
+
+	toBeCached, err := s.ToBeCached(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{from.ID, to.ID}, toBeCached)
+
+	require.NoError(t, s.RemoveDependency(context.Background(), from.ID, to.ID))
+	fromNode, err = s.GetNode(context.Background(), from.ID)
+	require.NoError(t, err)
+	toNode, err = s.GetNode(context.Background(), to.ID)
+	require.NoError(t, err)
+	assert.False(t, fromNode.Children.Contains(to.ID))
+	assert.False(t, toNode.Parents.Contains(from.ID))
+
+	assert.ErrorIs(t, s.AddDependency(context.Background(), from.ID, from.ID), graph.ErrSelfDependency)
+	assert.Error(t, s.AddDependency(context.Background(), from.ID, 42))
+}
+
+func TestConcurrentIngestEdgeSymmetry(t *testing.T) {
+	sbomDir := "../../testdata/sboms"
+	var sboms [][]byte
+	for _, name := range overlappingSBOMs {
+		data, err := os.ReadFile(filepath.Join(sbomDir, name))
+		require.NoError(t, err)
+		sboms = append(sboms, data)
+	}
+
+	// Ingesting sequentially gives the set of edges every backend has to end up with.
+	reference := graph.NewMockStorage()
+	for _, data := range sboms {
+		require.NoError(t, ingest.SBOM(context.Background(), reference, data))
+	}
+	want := edgesByName(t, reference)
+	require.NotEmpty(t, want)
+	wantNodes, err := reference.GetAllKeys(context.Background())
+	require.NoError(t, err)
+
+	backends := []struct {
+		name  string
+		setup func(t *testing.T) graph.Storage
+	}{
+		{
+			name: "sqlite",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := SetupSQLTestDB(filepath.Join(t.TempDir(), "concurrent.db"))
+				require.NoError(t, err)
+				return s
+			},
+		},
+		{
+			name: "redis",
+			setup: func(t *testing.T) graph.Storage {
+				s, err := SetupRedisTestDB(context.Background())
+				require.NoError(t, err)
+				return s
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storage := backend.setup(t)
+
+			// Every SBOM is ingested twice, so that the same edges are written concurrently as well.
+			var wg sync.WaitGroup
+			errs := make(chan error, 2*len(sboms))
+			for i := 0; i < 2; i++ {
+				for _, data := range sboms {
+					wg.Add(1)
+					go func(data []byte) {
+						defer wg.Done()
+						errs <- ingest.SBOM(context.Background(), storage, data)
+					}(data)
+				}
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			keys, err := storage.GetAllKeys(context.Background())
+			require.NoError(t, err)
+			nodes, err := storage.GetNodes(context.Background(), keys)
+			require.NoError(t, err)
+			for id, node := range nodes {
+				for _, child := range node.Children.ToArray() {
+					require.Contains(t, nodes, child)
+					assert.Truef(t, nodes[child].Parents.Contains(id), "node %d is a child of %d, but %d is not its parent", child, id, id)
+				}
+				for _, parent := range node.Parents.ToArray() {
+					require.Contains(t, nodes, parent)
+					assert.Truef(t, nodes[parent].Children.Contains(id), "node %d is a parent of %d, but %d is not its child", parent, id, id)
+				}
+			}
+
+			// Concurrent writers adding the same package must share one node, rather than split
+			// its edges between copies.
+			assert.Len(t, nodes, len(wantNodes))
+			ids := map[string][]uint32{}
+			for id, node := range nodes {
+				ids[node.Name] = append(ids[node.Name], id)
+			}
+			for name, nameIDs := range ids {
+				assert.Lenf(t, nameIDs, 1, "name %s has nodes %v", name, nameIDs)
+				id, err := storage.NameToID(context.Background(), name)
+				require.NoError(t, err)
+				assert.Equal(t, nameIDs[0], id)
+			}
+
+			assert.Equal(t, want, edgesByName(t, storage))
+		})
+	}
+}
+
+// edgesByName returns all edges of the graph keyed by the names of their nodes, so graphs
+// built with different ID assignments can be compared.
+func edgesByName(t *testing.T, storage graph.Storage) map[[2]string]bool {
+	t.Helper()
+	keys, err := storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	nodes, err := storage.GetNodes(context.Background(), keys)
+	require.NoError(t, err)
+
+	edges := map[[2]string]bool{}
+	for _, node := range nodes {
+		for _, child := range node.Children.ToArray() {
+			edges[[2]string{node.Name, nodes[child].Name}] = true
+		}
+	}
+	return edges
+}
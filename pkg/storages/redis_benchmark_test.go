@@ -0,0 +1,168 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/go-redis/redis/v8"
+)
+
+// benchmarkNodeCount returns the number of nodes the Redis benchmarks run against. It defaults
+// to one million and can be lowered with MINEFIELD_BENCH_NODES for quicker runs.
+func benchmarkNodeCount(b *testing.B) int {
+	count := 1_000_000
+	if env := os.Getenv("MINEFIELD_BENCH_NODES"); env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil {
+			b.Fatalf("invalid MINEFIELD_BENCH_NODES %q: %v", env, err)
+		}
+		count = n
+	}
+	return count
+}
+
+// setupRedisBenchmarkDB fills Redis with count nodes, spread over a few ecosystems. The data is
+// reused between benchmarks as long as the node count matches.
+func setupRedisBenchmarkDB(b *testing.B, count int) *RedisStorage {
+	b.Helper()
+	ctx := context.Background()
+	s, err := NewRedisStorage(benchmarkRedisAddr())
+	if err != nil {
+		b.Skipf("redis is not available: %v", err)
+	}
+	r := s.(*RedisStorage)
+
+	existing, err := r.Client.SCard(ctx, NodeIDsKey).Result()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if existing == int64(count) {
+		return r
+	}
+	if err := r.Client.FlushDB(ctx).Err(); err != nil {
+		b.Fatal(err)
+	}
+
+	ecosystems := []string{"npm", "pypi", "golang", "maven", "cargo"}
+	pipe := r.Client.Pipeline()
+	for i := 1; i <= count; i++ {
+		node := &graph.Node{
+			ID:       uint32(i),
+			Type:     "library",
+			Name:     fmt.Sprintf("pkg:%s/package-%d@1.0.0", ecosystems[i%len(ecosystems)], i),
+			Children: roaring.New(),
+			Parents:  roaring.New(),
+		}
+		data, err := node.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+		id := strconv.Itoa(i)
+		pipe.Set(ctx, NodeKeyPrefix+id, data, 0)
+		pipe.Set(ctx, NameToIDKey+node.Name, id, 0)
+		pipe.SAdd(ctx, NodeIDsKey, id)
+		pipe.ZAdd(ctx, NameIndexKey, &redis.Z{Member: node.Name})
+		if i%scanBatchSize == 0 || i == count {
+			if _, err := pipe.Exec(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	if err := r.Client.Set(ctx, IndexesBuiltMarker, 1, 0).Err(); err != nil {
+		b.Fatal(err)
+	}
+	return r
+}
+
+func benchmarkRedisAddr() string {
+	if addr := os.Getenv("TEST_REDIS_URL"); addr != "" {
+		return addr
+	}
+	return "localhost:6379"
+}
+
+// keysGetNodesByGlob is the KEYS based implementation GetNodesByGlob used before the name index.
+func keysGetNodesByGlob(r *RedisStorage, pattern string) ([]*graph.Node, error) {
+	keys, err := r.Client.Keys(context.Background(), fmt.Sprintf("%s%s", NameToIDKey, pattern)).Result()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*graph.Node, 0, len(keys))
+	for _, key := range keys {
+		id, err := r.NameToID(context.Background(), strings.TrimPrefix(key, NameToIDKey))
+		if err != nil {
+			return nil, err
+		}
+		node, err := r.GetNode(context.Background(), id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// keysGetAllKeys is the KEYS based implementation GetAllKeys used before the node ID set.
+func keysGetAllKeys(r *RedisStorage) ([]uint32, error) {
+	keys, err := r.Client.Keys(context.Background(), fmt.Sprintf("%s*", NodeKeyPrefix)).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]uint32, 0, len(keys))
+	for _, key := range keys {
+		id, err := strconv.ParseUint(strings.TrimPrefix(key, NodeKeyPrefix), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, uint32(id))
+	}
+	return result, nil
+}
+
+func BenchmarkRedisGetNodesByGlob(b *testing.B) {
+	count := benchmarkNodeCount(b)
+	r := setupRedisBenchmarkDB(b, count)
+	// Matches a handful of nodes, which is the common case for queries from the CLI.
+	pattern := "pkg:npm/package-1234*"
+
+	b.Run("keys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := keysGetNodesByGlob(r, pattern); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := r.GetNodesByGlob(context.Background(), pattern); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkRedisGetAllKeys(b *testing.B) {
+	count := benchmarkNodeCount(b)
+	r := setupRedisBenchmarkDB(b, count)
+
+	b.Run("keys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := keysGetAllKeys(r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := r.GetAllKeys(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,15 +2,26 @@ package storages
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/RoaringBitmap/roaring"
 	"github.com/bitbomdev/minefield/pkg/graph"
 	"github.com/bitbomdev/minefield/pkg/utils"
 	"github.com/go-redis/redis/v8"
 )
 
+const (
+	// maxTxRetries bounds how often an optimistic WATCH transaction is retried when another
+	// client modified one of the watched keys before it could be committed.
+	maxTxRetries = 100
+	// scanBatchSize is the COUNT hint for SCAN style commands and the page size for index reads.
+	scanBatchSize = 1000
+)
+
 type RedisStorage struct {
 	Client *redis.Client
 }
@@ -22,38 +33,123 @@ func NewRedisStorage(addr string) (graph.Storage, error) {
 	if err := rdb.Ping(context.Background()).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
-	return &RedisStorage{Client: rdb}, nil
+	storage := &RedisStorage{Client: rdb}
+	if err := storage.ensureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to build indexes: %w", err)
+	}
+	return storage, nil
 }
 
-func (r *RedisStorage) GenerateID() (uint32, error) {
-	id, err := r.Client.Incr(context.Background(), IDCounterKey).Result()
+// ensureIndexes builds the node ID set and the name index for databases that were written
+// before they existed. It only runs once per database.
+func (r *RedisStorage) ensureIndexes() error {
+	ctx := context.Background()
+	built, err := r.Client.Exists(ctx, IndexesBuiltMarker).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check for indexes: %w", err)
+	}
+	if built > 0 {
+		return nil
+	}
+
+	if err := r.scanKeys(ctx, NodeKeyPrefix, func(pipe redis.Pipeliner, suffix string) {
+		pipe.SAdd(ctx, NodeIDsKey, suffix)
+	}); err != nil {
+		return fmt.Errorf("failed to index node IDs: %w", err)
+	}
+	if err := r.scanKeys(ctx, NameToIDKey, func(pipe redis.Pipeliner, suffix string) {
+		pipe.ZAdd(ctx, NameIndexKey, &redis.Z{Member: suffix})
+	}); err != nil {
+		return fmt.Errorf("failed to index node names: %w", err)
+	}
+
+	return r.Client.Set(ctx, IndexesBuiltMarker, 1, 0).Err()
+}
+
+// scanKeys iterates over all keys with the given prefix using SCAN and calls fn with the key
+// suffix for each of them. The commands queued by fn are executed in one pipeline per batch.
+func (r *RedisStorage) scanKeys(ctx context.Context, prefix string, fn func(pipe redis.Pipeliner, suffix string)) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.Client.Scan(ctx, cursor, prefix+"*", scanBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan keys with prefix %s: %w", prefix, err)
+		}
+		if len(keys) > 0 {
+			pipe := r.Client.Pipeline()
+			for _, key := range keys {
+				fn(pipe, strings.TrimPrefix(key, prefix))
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return fmt.Errorf("failed to process keys with prefix %s: %w", prefix, err)
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func (r *RedisStorage) GenerateID(ctx context.Context) (uint32, error) {
+	id, err := r.Client.Incr(ctx, IDCounterKey).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to generate ID: %w", err)
 	}
 	return utils.IntToUint32(int(id))
 }
 
-func (r *RedisStorage) SaveNode(node *graph.Node) error {
+func (r *RedisStorage) SaveNode(ctx context.Context, node *graph.Node) error {
 	data, err := node.MarshalJSON()
 	if err != nil {
 		return fmt.Errorf("failed to marshal node: %w", err)
 	}
-	if err := r.Client.Set(context.Background(), fmt.Sprintf("%s%d", NodeKeyPrefix, node.ID), data, 0).Err(); err != nil {
-		return fmt.Errorf("failed to save node data: %w", err)
-	}
 	nodeIDStr := utils.Uint32ToStr(node.ID)
-	if err := r.Client.Set(context.Background(), fmt.Sprintf("%s%s", NameToIDKey, node.Name), nodeIDStr, 0).Err(); err != nil {
-		return fmt.Errorf("failed to save node name to ID mapping: %w", err)
-	}
-	if err := r.AddNodeToCachedStack(node.ID); err != nil {
-		return fmt.Errorf("failed to add node ID to %s set: %w", CacheStackKey, err)
+	_, err = r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, fmt.Sprintf("%s%d", NodeKeyPrefix, node.ID), data, 0)
+		pipe.Set(ctx, fmt.Sprintf("%s%s", NameToIDKey, node.Name), nodeIDStr, 0)
+		pipe.SAdd(ctx, NodeIDsKey, nodeIDStr)
+		pipe.ZAdd(ctx, NameIndexKey, &redis.Z{Member: node.Name})
+		pipe.RPush(ctx, CacheStackKey, node.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save node data: %w", err)
 	}
 	return nil
 }
 
-func (r *RedisStorage) NameToID(name string) (uint32, error) {
-	id, err := r.Client.Get(context.Background(), fmt.Sprintf("%s%s", NameToIDKey, name)).Result()
+// createNodeScript claims the name with SETNX and saves the node in the same step, so the name
+// never maps to a node that isn't saved yet. It returns the ID the name maps to.
+var createNodeScript = redis.NewScript(`
+if redis.call("SETNX", KEYS[1], ARGV[1]) == 0 then
+	return redis.call("GET", KEYS[1])
+end
+redis.call("SET", KEYS[2], ARGV[2])
+redis.call("SADD", KEYS[3], ARGV[1])
+redis.call("ZADD", KEYS[4], 0, ARGV[3])
+redis.call("RPUSH", KEYS[5], ARGV[1])
+return ARGV[1]
+`)
+
+func (r *RedisStorage) CreateNode(ctx context.Context, node *graph.Node) (uint32, error) {
+	data, err := node.MarshalJSON()
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal node: %w", err)
+	}
+	keys := []string{NameToIDKey + node.Name, fmt.Sprintf("%s%d", NodeKeyPrefix, node.ID), NodeIDsKey, NameIndexKey, CacheStackKey}
+	id, err := createNodeScript.Run(ctx, r.Client, keys, utils.Uint32ToStr(node.ID), data, node.Name).Text()
 	if err != nil {
+		return 0, fmt.Errorf("failed to create node %s: %w", node.Name, err)
+	}
+	return utils.StrToUint32(id)
+}
+
+func (r *RedisStorage) NameToID(ctx context.Context, name string) (uint32, error) {
+	id, err := r.Client.Get(ctx, fmt.Sprintf("%s%s", NameToIDKey, name)).Result()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("node %s: %w", name, graph.ErrNodeNotFound)
+	} else if err != nil {
 		return 0, fmt.Errorf("failed to get ID for name %s: %w", name, err)
 	}
 
@@ -64,10 +160,11 @@ func (r *RedisStorage) NameToID(name string) (uint32, error) {
 	return uint32(idInt), nil
 }
 
-func (r *RedisStorage) GetNode(id uint32) (*graph.Node, error) {
-	ctx := context.Background()
+func (r *RedisStorage) GetNode(ctx context.Context, id uint32) (*graph.Node, error) {
 	data, err := r.Client.Get(ctx, fmt.Sprintf("%s%d", NodeKeyPrefix, id)).Result()
-	if err != nil {
+	if err == redis.Nil {
+		return nil, fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to get node data for ID %d: %w", id, err)
 	}
 	var node graph.Node
@@ -77,52 +174,105 @@ func (r *RedisStorage) GetNode(id uint32) (*graph.Node, error) {
 	return &node, nil
 }
 
-func (r *RedisStorage) GetNodesByGlob(pattern string) ([]*graph.Node, error) {
-	// Use pattern matching for Redis keys
-	keys, err := r.Client.Keys(context.Background(), fmt.Sprintf("%s%s", NameToIDKey, pattern)).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes by pattern %s: %w", pattern, err)
-	}
+// GetNodesByGlob walks the part of the name index that shares the pattern's literal prefix,
+// matches the names against the pattern and fetches the matching nodes in pipelines.
+func (r *RedisStorage) GetNodesByGlob(ctx context.Context, pattern string) ([]*graph.Node, error) {
+	prefix := utils.GlobPrefix(pattern)
 
-	nodes := make([]*graph.Node, 0, len(keys))
-	for _, key := range keys {
-		// Extract the name from the key
-		name := strings.TrimPrefix(key, NameToIDKey)
+	min, max := "-", "+"
+	if prefix != "" {
+		// \xff sorts after every byte of a UTF-8 name, so this range covers all names with the prefix.
+		min, max = "["+prefix, "["+prefix+"\xff"
+	}
 
-		// Get the ID using the name
-		id, err := r.NameToID(name)
+	var names []string
+	for {
+		batch, err := r.Client.ZRangeByLex(ctx, NameIndexKey, &redis.ZRangeBy{
+			Min:   min,
+			Max:   max,
+			Count: scanBatchSize,
+		}).Result()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ID for name %s: %w", name, err)
+			return nil, fmt.Errorf("failed to get nodes by pattern %s: %w", pattern, err)
+		}
+		for _, name := range batch {
+			if utils.MatchGlob(pattern, name) {
+				names = append(names, name)
+			}
 		}
+		if len(batch) < scanBatchSize {
+			break
+		}
+		min = "(" + batch[len(batch)-1]
+	}
 
-		node, err := r.GetNode(id)
+	if len(names) == 0 {
+		return []*graph.Node{}, nil
+	}
+
+	pipe := r.Client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(names))
+	for i, name := range names {
+		cmds[i] = pipe.Get(ctx, fmt.Sprintf("%s%s", NameToIDKey, name))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get IDs for pattern %s: %w", pattern, err)
+	}
+
+	ids := make([]uint32, 0, len(names))
+	for i, cmd := range cmds {
+		id, err := cmd.Result()
+		if err == redis.Nil {
+			continue // The name index can be ahead of a concurrent SaveNode
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get ID for name %s: %w", names[i], err)
+		}
+		idInt, err := utils.StrToUint32(id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get node for ID %d: %w", id, err)
+			return nil, fmt.Errorf("failed to convert ID to integer: %w", err)
+		}
+		ids = append(ids, idInt)
+	}
+
+	nodesByID, err := r.GetNodes(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes for pattern %s: %w", pattern, err)
+	}
+	nodes := make([]*graph.Node, 0, len(nodesByID))
+	for _, id := range ids {
+		if node, ok := nodesByID[id]; ok {
+			nodes = append(nodes, node)
 		}
-		nodes = append(nodes, node)
 	}
 
 	return nodes, nil
 }
 
-func (r *RedisStorage) GetAllKeys() ([]uint32, error) {
-	keys, err := r.Client.Keys(context.Background(), fmt.Sprintf("%s*", NodeKeyPrefix)).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get all keys: %w", err)
-	}
-	var result []uint32
-	for _, key := range keys {
-		id, err := strconv.ParseUint(strings.TrimPrefix(key, NodeKeyPrefix), 10, 32)
+func (r *RedisStorage) GetAllKeys(ctx context.Context) ([]uint32, error) {
+	// SSCAN may return a member more than once while the set is resized.
+	result := roaring.New()
+	var cursor uint64
+	for {
+		members, next, err := r.Client.SScan(ctx, NodeIDsKey, cursor, "", scanBatchSize).Result()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse key %s: %w", key, err)
+			return nil, fmt.Errorf("failed to get all keys: %w", err)
+		}
+		for _, member := range members {
+			id, err := strconv.ParseUint(member, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse node ID %s: %w", member, err)
+			}
+			result.Add(uint32(id))
+		}
+		cursor = next
+		if cursor == 0 {
+			break
 		}
-		result = append(result, uint32(id))
 	}
-	return result, nil
+	return result.ToArray(), nil
 }
 
-func (r *RedisStorage) SaveCache(cache *graph.NodeCache) error {
-	ctx := context.Background()
+func (r *RedisStorage) SaveCache(ctx context.Context, cache *graph.NodeCache) error {
 	data, err := cache.MarshalJSON()
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache: %w", err)
@@ -130,8 +280,7 @@ func (r *RedisStorage) SaveCache(cache *graph.NodeCache) error {
 	return r.Client.Set(ctx, fmt.Sprintf("%s%d", CacheKeyPrefix, cache.ID), data, 0).Err()
 }
 
-func (r *RedisStorage) ToBeCached() ([]uint32, error) {
-	ctx := context.Background()
+func (r *RedisStorage) ToBeCached(ctx context.Context) ([]uint32, error) {
 	data, err := r.Client.LRange(ctx, CacheStackKey, 0, -1).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s data: %w", CacheStackKey, err)
@@ -149,8 +298,7 @@ func (r *RedisStorage) ToBeCached() ([]uint32, error) {
 	return result, nil
 }
 
-func (r *RedisStorage) AddNodeToCachedStack(nodeID uint32) error {
-	ctx := context.Background()
+func (r *RedisStorage) AddNodeToCachedStack(ctx context.Context, nodeID uint32) error {
 	err := r.Client.RPush(ctx, CacheStackKey, nodeID).Err()
 	if err != nil {
 		return fmt.Errorf("failed to add node %d to cached stack: %w", nodeID, err)
@@ -158,8 +306,7 @@ func (r *RedisStorage) AddNodeToCachedStack(nodeID uint32) error {
 	return nil
 }
 
-func (r *RedisStorage) ClearCacheStack() error {
-	ctx := context.Background()
+func (r *RedisStorage) ClearCacheStack(ctx context.Context) error {
 	err := r.Client.Del(ctx, CacheStackKey).Err()
 	if err != nil {
 		return fmt.Errorf("failed to clear cache stack: %w", err)
@@ -167,10 +314,11 @@ func (r *RedisStorage) ClearCacheStack() error {
 	return nil
 }
 
-func (r *RedisStorage) GetCache(nodeID uint32) (*graph.NodeCache, error) {
-	ctx := context.Background()
+func (r *RedisStorage) GetCache(ctx context.Context, nodeID uint32) (*graph.NodeCache, error) {
 	data, err := r.Client.Get(ctx, fmt.Sprintf("%s%d", CacheKeyPrefix, nodeID)).Result()
-	if err != nil {
+	if err == redis.Nil {
+		return nil, fmt.Errorf("cache %d: %w", nodeID, graph.ErrCacheNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to get cache for node %d: %w", nodeID, err)
 	}
 	var cache graph.NodeCache
@@ -180,8 +328,7 @@ func (r *RedisStorage) GetCache(nodeID uint32) (*graph.NodeCache, error) {
 	return &cache, nil
 }
 
-func (r *RedisStorage) GetNodes(ids []uint32) (map[uint32]*graph.Node, error) {
-	ctx := context.Background()
+func (r *RedisStorage) GetNodes(ctx context.Context, ids []uint32) (map[uint32]*graph.Node, error) {
 	pipe := r.Client.Pipeline()
 
 	cmds := make([]*redis.StringCmd, len(ids))
@@ -213,8 +360,7 @@ func (r *RedisStorage) GetNodes(ids []uint32) (map[uint32]*graph.Node, error) {
 	return nodes, nil
 }
 
-func (r *RedisStorage) SaveCaches(caches []*graph.NodeCache) error {
-	ctx := context.Background()
+func (r *RedisStorage) SaveCaches(ctx context.Context, caches []*graph.NodeCache) error {
 	pipe := r.Client.Pipeline()
 
 	for _, cache := range caches {
@@ -232,8 +378,7 @@ func (r *RedisStorage) SaveCaches(caches []*graph.NodeCache) error {
 	return nil
 }
 
-func (r *RedisStorage) GetCaches(ids []uint32) (map[uint32]*graph.NodeCache, error) {
-	ctx := context.Background()
+func (r *RedisStorage) GetCaches(ctx context.Context, ids []uint32) (map[uint32]*graph.NodeCache, error) {
 	pipe := r.Client.Pipeline()
 
 	cmds := make([]*redis.StringCmd, len(ids))
@@ -265,8 +410,7 @@ func (r *RedisStorage) GetCaches(ids []uint32) (map[uint32]*graph.NodeCache, err
 	return caches, nil
 }
 
-func (r *RedisStorage) RemoveAllCaches() error {
-	ctx := context.Background()
+func (r *RedisStorage) RemoveAllCaches(ctx context.Context) error {
 	var cursor uint64
 	var err error
 
@@ -303,12 +447,17 @@ func (r *RedisStorage) RemoveAllCaches() error {
 	return nil
 }
 
-func (r *RedisStorage) AddOrUpdateCustomData(tag, key string, datakey string, data []byte) error {
-	ctx := context.Background()
+func (r *RedisStorage) AddOrUpdateCustomData(ctx context.Context, tag, key string, datakey string, data []byte) error {
 	redisKey := fmt.Sprintf("%s:%s", tag, key)
 
-	// Use HSet to add or update the field in the hash
-	err := r.Client.HSet(ctx, redisKey, datakey, data).Err()
+	// Use HSet to add or update the field in the hash, and record the tag and key so the
+	// custom data can be enumerated without scanning the keyspace.
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, redisKey, datakey, data)
+		pipe.SAdd(ctx, CustomDataTagsKey, tag)
+		pipe.SAdd(ctx, CustomDataKeysPrefix+tag, key)
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set hash field: %w", err)
 	}
@@ -317,8 +466,7 @@ func (r *RedisStorage) AddOrUpdateCustomData(tag, key string, datakey string, da
 }
 
 // GetCustomData gets data from the database.
-func (r *RedisStorage) GetCustomData(tag, key string) (map[string][]byte, error) {
-	ctx := context.Background()
+func (r *RedisStorage) GetCustomData(ctx context.Context, tag, key string) (map[string][]byte, error) {
 	redisKey := fmt.Sprintf("%s:%s", tag, key)
 
 	data, err := r.Client.HGetAll(ctx, redisKey).Result()
@@ -333,3 +481,314 @@ func (r *RedisStorage) GetCustomData(tag, key string) (map[string][]byte, error)
 
 	return result, nil
 }
+
+// GetCustomDataKeys returns all keys with custom data, grouped by tag.
+func (r *RedisStorage) GetCustomDataKeys(ctx context.Context) (map[string][]string, error) {
+	tags, err := r.Client.SMembers(ctx, CustomDataTagsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data tags: %w", err)
+	}
+
+	pipe := r.Client.Pipeline()
+	cmds := make(map[string]*redis.StringSliceCmd, len(tags))
+	for _, tag := range tags {
+		cmds[tag] = pipe.SMembers(ctx, CustomDataKeysPrefix+tag)
+	}
+	if len(tags) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+		}
+	}
+
+	result := make(map[string][]string, len(tags))
+	for tag, cmd := range cmds {
+		result[tag] = cmd.Val()
+	}
+	return result, nil
+}
+
+func (r *RedisStorage) GetIDCounter(ctx context.Context) (uint32, error) {
+	id, err := r.Client.Get(ctx, IDCounterKey).Result()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get ID counter: %w", err)
+	}
+	return utils.StrToUint32(id)
+}
+
+// setIDCounterScript raises the counter in a single step, so a concurrent INCR can't be lost.
+var setIDCounterScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if tonumber(ARGV[1]) > current then
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+func (r *RedisStorage) SetIDCounter(ctx context.Context, id uint32) error {
+	if err := setIDCounterScript.Run(ctx, r.Client, []string{IDCounterKey}, id).Err(); err != nil {
+		return fmt.Errorf("failed to set ID counter: %w", err)
+	}
+	return nil
+}
+
+// AddAliases sets the alias keys that don't exist yet in an optimistic transaction that watches
+// the node and the alias keys, so an alias is never added to a node that is being deleted.
+func (r *RedisStorage) AddAliases(ctx context.Context, id uint32, aliases []string) error {
+	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
+	aliasKeys := make([]string, len(aliases))
+	for i, alias := range aliases {
+		aliasKeys[i] = AliasToIDKey + alias
+	}
+	txf := func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, nodeKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", id, err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		}
+		if len(aliasKeys) == 0 {
+			return nil
+		}
+		current, err := tx.MGet(ctx, aliasKeys...).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get aliases: %w", err)
+		}
+		var added []any
+		for i, value := range current {
+			if value == nil {
+				added = append(added, aliases[i])
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+
+		idStr := utils.Uint32ToStr(id)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, alias := range added {
+				pipe.Set(ctx, AliasToIDKey+alias.(string), idStr, 0)
+			}
+			pipe.SAdd(ctx, fmt.Sprintf("%s%d", AliasesKeyPrefix, id), added...)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxRetries; i++ {
+		err := r.Client.Watch(ctx, txf, append([]string{nodeKey}, aliasKeys...)...)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to add aliases to node %d: exceeded %d transaction retries", id, maxTxRetries)
+}
+
+func (r *RedisStorage) AliasToID(ctx context.Context, alias string) (uint32, error) {
+	id, err := r.Client.Get(ctx, AliasToIDKey+alias).Result()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("alias %s: %w", alias, graph.ErrNodeNotFound)
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get ID for alias %s: %w", alias, err)
+	}
+	return utils.StrToUint32(id)
+}
+
+func (r *RedisStorage) GetAliases(ctx context.Context, id uint32) ([]string, error) {
+	aliases, err := r.Client.SMembers(ctx, fmt.Sprintf("%s%d", AliasesKeyPrefix, id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases of node %d: %w", id, err)
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+// DeleteNode removes the node and its index entries in an optimistic transaction that watches
+// the node and its aliases, so an edge or alias added concurrently is never left pointing at a
+// deleted node.
+func (r *RedisStorage) DeleteNode(ctx context.Context, id uint32) error {
+	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
+	aliasesKey := fmt.Sprintf("%s%d", AliasesKeyPrefix, id)
+	txf := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, nodeKey).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		} else if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", id, err)
+		}
+		var node graph.Node
+		if err := node.UnmarshalJSON([]byte(data)); err != nil {
+			return fmt.Errorf("failed to unmarshal node data: %w", err)
+		}
+		if !node.Children.IsEmpty() || !node.Parents.IsEmpty() {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeHasEdges)
+		}
+		aliases, err := tx.SMembers(ctx, aliasesKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get aliases of node %d: %w", id, err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, nodeKey, NameToIDKey+node.Name, fmt.Sprintf("%s%d", CacheKeyPrefix, id), aliasesKey)
+			for _, alias := range aliases {
+				pipe.Del(ctx, AliasToIDKey+alias)
+			}
+			pipe.SRem(ctx, NodeIDsKey, utils.Uint32ToStr(id))
+			pipe.ZRem(ctx, NameIndexKey, node.Name)
+			pipe.LRem(ctx, CacheStackKey, 0, id)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxRetries; i++ {
+		err := r.Client.Watch(ctx, txf, nodeKey, aliasesKey)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to delete node %d: exceeded %d transaction retries", id, maxTxRetries)
+}
+
+// UpdateNodeMetadata replaces the metadata of the node in an optimistic transaction, so edges
+// added concurrently are kept.
+func (r *RedisStorage) UpdateNodeMetadata(ctx context.Context, id uint32, update func(metadata any) (any, error)) (*graph.Node, error) {
+	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
+	var node graph.Node
+	txf := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, nodeKey).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		} else if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", id, err)
+		}
+		if err := node.UnmarshalJSON([]byte(data)); err != nil {
+			return fmt.Errorf("failed to unmarshal node data: %w", err)
+		}
+		if node.Metadata, err = update(node.Metadata); err != nil {
+			return err
+		}
+		updated, err := node.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, nodeKey, updated, 0)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxRetries; i++ {
+		err := r.Client.Watch(ctx, txf, nodeKey)
+		if err == nil {
+			return &node, nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("failed to update metadata of node %d: exceeded %d transaction retries", id, maxTxRetries)
+}
+
+func (r *RedisStorage) GetSchemaVersion(ctx context.Context) (int, error) {
+	version, err := r.Client.Get(ctx, SchemaVersionKey).Int()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
+
+func (r *RedisStorage) SetSchemaVersion(ctx context.Context, version int) error {
+	if err := r.Client.Set(ctx, SchemaVersionKey, version, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
+
+// AddDependency adds the edge from -> to to both nodes in a single optimistic transaction.
+func (r *RedisStorage) AddDependency(ctx context.Context, from, to uint32) error {
+	return r.updateEdge(ctx, from, to, func(fromNode, toNode *graph.Node) {
+		fromNode.Children.Add(to)
+		toNode.Parents.Add(from)
+	})
+}
+
+// RemoveDependency removes the edge from -> to from both nodes in a single optimistic transaction.
+func (r *RedisStorage) RemoveDependency(ctx context.Context, from, to uint32) error {
+	return r.updateEdge(ctx, from, to, func(fromNode, toNode *graph.Node) {
+		fromNode.Children.Remove(to)
+		toNode.Parents.Remove(from)
+	})
+}
+
+// updateEdge WATCHes both node keys, applies update to the decoded nodes and writes them back
+// in a MULTI/EXEC block. If either node is modified by another client in the meantime, the
+// transaction is aborted by Redis and retried against the fresh values.
+func (r *RedisStorage) updateEdge(ctx context.Context, from, to uint32, update func(fromNode, toNode *graph.Node)) error {
+	if from == to {
+		return graph.ErrSelfDependency
+	}
+	fromKey := fmt.Sprintf("%s%d", NodeKeyPrefix, from)
+	toKey := fmt.Sprintf("%s%d", NodeKeyPrefix, to)
+
+	txf := func(tx *redis.Tx) error {
+		values, err := tx.MGet(ctx, fromKey, toKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get nodes %d and %d: %w", from, to, err)
+		}
+		nodes := make([]*graph.Node, len(values))
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("node %d: %w", []uint32{from, to}[i], graph.ErrNodeNotFound)
+			}
+			var node graph.Node
+			if err := node.UnmarshalJSON([]byte(data)); err != nil {
+				return fmt.Errorf("failed to unmarshal node data: %w", err)
+			}
+			nodes[i] = &node
+		}
+
+		update(nodes[0], nodes[1])
+
+		fromData, err := nodes[0].MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+		toData, err := nodes[1].MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, fromKey, fromData, 0)
+			pipe.Set(ctx, toKey, toData, 0)
+			pipe.RPush(ctx, CacheStackKey, from, to)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxRetries; i++ {
+		err := r.Client.Watch(ctx, txf, fromKey, toKey)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return fmt.Errorf("failed to update edge %d -> %d: %w", from, to, err)
+		}
+	}
+	return fmt.Errorf("failed to update edge %d -> %d: exceeded %d transaction retries", from, to, maxTxRetries)
+}
@@ -2,6 +2,7 @@ package storages
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/RoaringBitmap/roaring"
@@ -14,7 +15,7 @@ func TestGenerateID(t *testing.T) {
 
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
-	id, err := r.GenerateID()
+	id, err := r.GenerateID(context.Background())
 	assert.NoError(t, err)
 	assert.NotEqual(t, 0, id)
 }
@@ -23,11 +24,11 @@ func TestSaveNode(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
 	node := &graph.Node{ID: 1, Name: "test_node", Children: roaring.New(), Parents: roaring.New()}
-	err = r.SaveNode(node)
+	err = r.SaveNode(context.Background(), node)
 	assert.NoError(t, err)
 
 	// Verify node data is saved
-	savedNode, err := r.GetNode(node.ID)
+	savedNode, err := r.GetNode(context.Background(), node.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, node.ID, savedNode.ID)
 	assert.Equal(t, node.Name, savedNode.Name)
@@ -37,10 +38,10 @@ func TestNameToID(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
 	node := &graph.Node{ID: 1, Name: "test_node", Children: roaring.New(), Parents: roaring.New()}
-	err = r.SaveNode(node)
+	err = r.SaveNode(context.Background(), node)
 	assert.NoError(t, err)
 
-	id, err := r.NameToID(node.Name)
+	id, err := r.NameToID(context.Background(), node.Name)
 	assert.NoError(t, err)
 	assert.Equal(t, node.ID, id)
 }
@@ -50,25 +51,51 @@ func TestGetAllKeys(t *testing.T) {
 	assert.NoError(t, err)
 	node1 := &graph.Node{ID: 1, Name: "node1", Children: roaring.New(), Parents: roaring.New()}
 	node2 := &graph.Node{ID: 2, Name: "node2", Children: roaring.New(), Parents: roaring.New()}
-	err = r.SaveNode(node1)
+	err = r.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = r.SaveNode(node2)
+	err = r.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
 
-	keys, err := r.GetAllKeys()
+	keys, err := r.GetAllKeys(context.Background())
 	assert.NoError(t, err)
 	assert.Contains(t, keys, node1.ID)
 	assert.Contains(t, keys, node2.ID)
 }
 
+func TestGetAllKeysWhileGrowing(t *testing.T) {
+	ctx := context.Background()
+	r, err := SetupRedisTestDB(ctx)
+	assert.NoError(t, err)
+	const count = 4 * scanBatchSize
+	ids := make([]any, 0, 2*count)
+	for i := 1; i <= count; i++ {
+		ids = append(ids, i)
+	}
+	assert.NoError(t, r.Client.SAdd(ctx, NodeIDsKey, ids...).Err())
+
+	// The set is rehashed while it is scanned, which makes SSCAN return members again.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := count + 1; i <= 2*count; i++ {
+			r.Client.SAdd(ctx, NodeIDsKey, i)
+		}
+	}()
+	keys, err := r.GetAllKeys(ctx)
+	<-done
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), int(roaring.BitmapOf(keys...).GetCardinality()), "every ID is returned once")
+	assert.GreaterOrEqual(t, len(keys), count)
+}
+
 func TestSaveCache(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
 	cache := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = r.SaveCache(cache)
+	err = r.SaveCache(context.Background(), cache)
 	assert.NoError(t, err)
 
-	savedCache, err := r.GetCache(cache.ID)
+	savedCache, err := r.GetCache(context.Background(), cache.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, cache.ID, savedCache.ID)
 }
@@ -77,10 +104,10 @@ func TestToBeCached(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
 	nodeID := uint32(1)
-	err = r.AddNodeToCachedStack(nodeID)
+	err = r.AddNodeToCachedStack(context.Background(), nodeID)
 	assert.NoError(t, err)
 
-	toBeCached, err := r.ToBeCached()
+	toBeCached, err := r.ToBeCached(context.Background())
 	assert.NoError(t, err)
 	assert.Contains(t, toBeCached, nodeID)
 }
@@ -89,13 +116,13 @@ func TestClearCacheStack(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
 	nodeID := uint32(1)
-	err = r.AddNodeToCachedStack(nodeID)
+	err = r.AddNodeToCachedStack(context.Background(), nodeID)
 	assert.NoError(t, err)
 
-	err = r.ClearCacheStack()
+	err = r.ClearCacheStack(context.Background())
 	assert.NoError(t, err)
 
-	toBeCached, err := r.ToBeCached()
+	toBeCached, err := r.ToBeCached(context.Background())
 	assert.NoError(t, err)
 	assert.NotContains(t, toBeCached, nodeID)
 }
@@ -106,13 +133,13 @@ func TestGetNodes(t *testing.T) {
 	// Add test data
 	node1 := &graph.Node{ID: 1, Name: "test_node1", Children: roaring.New(), Parents: roaring.New()}
 	node2 := &graph.Node{ID: 2, Name: "test_node2", Children: roaring.New(), Parents: roaring.New()}
-	err = r.SaveNode(node1)
+	err = r.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = r.SaveNode(node2)
+	err = r.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
 
 	// Test GetNodes
-	nodes, err := r.GetNodes([]uint32{1, 2})
+	nodes, err := r.GetNodes(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.NotNil(t, nodes[1])
 	assert.Equal(t, "test_node1", nodes[1].Name)
@@ -125,14 +152,14 @@ func TestSaveCaches(t *testing.T) {
 	assert.NoError(t, err)
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = r.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = r.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Verify caches saved
-	savedCache1, err := r.GetCache(1)
+	savedCache1, err := r.GetCache(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, cache1.ID, savedCache1.ID)
-	savedCache2, err := r.GetCache(2)
+	savedCache2, err := r.GetCache(context.Background(), 2)
 	assert.NoError(t, err)
 	assert.Equal(t, cache2.ID, savedCache2.ID)
 }
@@ -142,11 +169,11 @@ func TestGetCaches(t *testing.T) {
 	assert.NoError(t, err)
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = r.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = r.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Test GetCaches
-	caches, err := r.GetCaches([]uint32{1, 2})
+	caches, err := r.GetCaches(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.NotNil(t, caches[1])
 	assert.Equal(t, cache1.ID, caches[1].ID)
@@ -159,15 +186,15 @@ func TestRemoveAllCaches(t *testing.T) {
 	assert.NoError(t, err)
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = r.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = r.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Test RemoveAllCaches
-	err = r.RemoveAllCaches()
+	err = r.RemoveAllCaches(context.Background())
 	assert.NoError(t, err)
 
 	// Verify caches removed
-	caches, err := r.GetCaches([]uint32{1, 2})
+	caches, err := r.GetCaches(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.Nil(t, caches[1])
 	assert.Nil(t, caches[2])
@@ -176,13 +203,13 @@ func TestRemoveAllCaches(t *testing.T) {
 func TestAddAndGetDataToDB(t *testing.T) {
 	r, err := SetupRedisTestDB(context.Background())
 	assert.NoError(t, err)
-	err = r.AddOrUpdateCustomData("test_tag", "test_key1", "test_data1", []byte("test_data1"))
+	err = r.AddOrUpdateCustomData(context.Background(), "test_tag", "test_key1", "test_data1", []byte("test_data1"))
 	assert.NoError(t, err)
-	err = r.AddOrUpdateCustomData("test_tag", "test_key1", "test_data2", []byte("test_data2"))
+	err = r.AddOrUpdateCustomData(context.Background(), "test_tag", "test_key1", "test_data2", []byte("test_data2"))
 	assert.NoError(t, err)
 
 	// Verify data added
-	data, err := r.GetCustomData("test_tag", "test_key1")
+	data, err := r.GetCustomData(context.Background(), "test_tag", "test_key1")
 	assert.NoError(t, err)
 
 	t1, err := json.Marshal("test_data1")
@@ -201,15 +228,15 @@ func TestGetNodesByGlob(t *testing.T) {
 	node1 := &graph.Node{ID: 1, Name: "test_node1", Children: roaring.New(), Parents: roaring.New()}
 	node2 := &graph.Node{ID: 2, Name: "test_node2", Children: roaring.New(), Parents: roaring.New()}
 	node3 := &graph.Node{ID: 3, Name: "other_node", Children: roaring.New(), Parents: roaring.New()}
-	err = r.SaveNode(node1)
+	err = r.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = r.SaveNode(node2)
+	err = r.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
-	err = r.SaveNode(node3)
+	err = r.SaveNode(context.Background(), node3)
 	assert.NoError(t, err)
 
 	// Test GetNodesByGlob with pattern "test_*"
-	nodes, err := r.GetNodesByGlob("test_*")
+	nodes, err := r.GetNodesByGlob(context.Background(), "test_*")
 	assert.NoError(t, err)
 	assert.Len(t, nodes, 2)
 
@@ -219,12 +246,137 @@ func TestGetNodesByGlob(t *testing.T) {
 	assert.Contains(t, nodeNames, "test_node2")
 
 	// Test with a pattern that matches no nodes
-	nodes, err = r.GetNodesByGlob("nonexistent_*")
+	nodes, err = r.GetNodesByGlob(context.Background(), "nonexistent_*")
 	assert.NoError(t, err)
 	assert.Len(t, nodes, 0)
 
 	// Simulate an error by closing the Redis client
 	r.Client.Close()
-	_, err = r.GetNodesByGlob("test_*")
+	_, err = r.GetNodesByGlob(context.Background(), "test_*")
 	assert.Error(t, err)
 }
+
+func TestGetNodesByGlobPatterns(t *testing.T) {
+	r, err := SetupRedisTestDB(context.Background())
+	assert.NoError(t, err)
+
+	names := []string{
+		"pkg:npm/lodash@4.17.21",
+		"pkg:npm/left-pad@1.3.0",
+		"pkg:golang/github.com/bitbomdev/minefield@v1.0.0",
+		"pkg:golang/github.com/google/uuid@v1.6.0",
+		"pkg:pypi/requests@2.32.0",
+	}
+	for i, name := range names {
+		node := &graph.Node{ID: uint32(i + 1), Name: name, Children: roaring.New(), Parents: roaring.New()}
+		assert.NoError(t, r.SaveNode(context.Background(), node))
+	}
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"pkg:npm/*", []string{"pkg:npm/lodash@4.17.21", "pkg:npm/left-pad@1.3.0"}},
+		{"pkg:npm/l?dash*", []string{"pkg:npm/lodash@4.17.21"}},
+		{"pkg:golang/*@v1.6.0", []string{"pkg:golang/github.com/google/uuid@v1.6.0"}},
+		{"*minefield*", []string{"pkg:golang/github.com/bitbomdev/minefield@v1.0.0"}},
+		{"pkg:[np]*@*", []string{"pkg:npm/lodash@4.17.21", "pkg:npm/left-pad@1.3.0", "pkg:pypi/requests@2.32.0"}},
+		{"pkg:pypi/requests@2.32.0", []string{"pkg:pypi/requests@2.32.0"}},
+		{"pkg:pypi/requests", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nodes, err := r.GetNodesByGlob(context.Background(), tt.pattern)
+			assert.NoError(t, err)
+			var got []string
+			for _, node := range nodes {
+				got = append(got, node.Name)
+			}
+			assert.ElementsMatch(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGetNodesByGlobManyNodes(t *testing.T) {
+	r, err := SetupRedisTestDB(context.Background())
+	assert.NoError(t, err)
+
+	// More nodes than a single page of the name index.
+	const count = 2*scanBatchSize + 17
+	for i := 1; i <= count; i++ {
+		node := &graph.Node{ID: uint32(i), Name: fmt.Sprintf("pkg:npm/package-%d", i), Children: roaring.New(), Parents: roaring.New()}
+		assert.NoError(t, r.SaveNode(context.Background(), node))
+	}
+
+	nodes, err := r.GetNodesByGlob(context.Background(), "pkg:npm/*")
+	assert.NoError(t, err)
+	assert.Len(t, nodes, count)
+
+	keys, err := r.GetAllKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, keys, count)
+}
+
+func TestEnsureIndexesBackfillsExistingData(t *testing.T) {
+	ctx := context.Background()
+	r, err := SetupRedisTestDB(ctx)
+	assert.NoError(t, err)
+
+	// Write nodes the way they were stored before the indexes existed.
+	for i, name := range []string{"legacy1", "legacy2", "other"} {
+		node := &graph.Node{ID: uint32(i + 1), Name: name, Children: roaring.New(), Parents: roaring.New()}
+		data, err := node.MarshalJSON()
+		assert.NoError(t, err)
+		assert.NoError(t, r.Client.Set(ctx, fmt.Sprintf("%s%d", NodeKeyPrefix, node.ID), data, 0).Err())
+		assert.NoError(t, r.Client.Set(ctx, fmt.Sprintf("%s%s", NameToIDKey, node.Name), node.ID, 0).Err())
+	}
+
+	assert.NoError(t, r.ensureIndexes())
+
+	keys, err := r.GetAllKeys(context.Background())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{1, 2, 3}, keys)
+
+	nodes, err := r.GetNodesByGlob(context.Background(), "legacy*")
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+
+	built, err := r.Client.Exists(ctx, IndexesBuiltMarker).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), built)
+}
+
+func TestGetCustomDataKeys(t *testing.T) {
+	r, err := SetupRedisTestDB(context.Background())
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.AddOrUpdateCustomData(context.Background(), "tag1", "key1", "field", []byte("a")))
+	assert.NoError(t, r.AddOrUpdateCustomData(context.Background(), "tag1", "key2", "field", []byte("b")))
+	assert.NoError(t, r.AddOrUpdateCustomData(context.Background(), "tag2", "key1", "field", []byte("c")))
+
+	keys, err := r.GetCustomDataKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.ElementsMatch(t, []string{"key1", "key2"}, keys["tag1"])
+	assert.ElementsMatch(t, []string{"key1"}, keys["tag2"])
+}
+
+func TestIDCounter(t *testing.T) {
+	r, err := SetupRedisTestDB(context.Background())
+	assert.NoError(t, err)
+
+	id, err := r.GetIDCounter(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), id)
+
+	assert.NoError(t, r.SetIDCounter(context.Background(), 41))
+	id, err = r.GenerateID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
+
+	// The counter is never moved backwards.
+	assert.NoError(t, r.SetIDCounter(context.Background(), 10))
+	id, err = r.GetIDCounter(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
+}
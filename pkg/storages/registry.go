@@ -0,0 +1,80 @@
+package storages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+)
+
+// Opener opens a storage backend from the location part of a storage URI, which is
+// everything after the "<backend>:" prefix.
+type Opener func(location string) (graph.Storage, error)
+
+var (
+	openersMu sync.RWMutex
+	openers   = map[string]Opener{}
+)
+
+const (
+	sqliteBackend = "sqlite"
+	redisBackend  = "redis"
+)
+
+func init() {
+	Register(sqliteBackend, func(location string) (graph.Storage, error) {
+		if location == "" || location == ":memory:" {
+			return NewSQLStorage("", true)
+		}
+		return NewSQLStorage(location, false)
+	})
+	Register(redisBackend, NewRedisStorage)
+}
+
+// Register makes a storage backend available to Open under the given name.
+// It panics if a backend with that name is already registered.
+func Register(name string, opener Opener) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if opener == nil {
+		panic("storages: Register opener is nil")
+	}
+	if _, exists := openers[name]; exists {
+		panic("storages: Register called twice for backend " + name)
+	}
+	openers[name] = opener
+}
+
+// Backends returns the sorted names of all registered storage backends.
+func Backends() []string {
+	openersMu.RLock()
+	defer openersMu.RUnlock()
+	names := make([]string, 0, len(openers))
+	for name := range openers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the storage described by uri, which has the form "<backend>:<location>",
+// e.g. "sqlite:/var/lib/minefield.db", "sqlite::memory:" or "redis:localhost:6379".
+func Open(uri string) (graph.Storage, error) {
+	name, location, found := strings.Cut(uri, ":")
+	if !found {
+		return nil, fmt.Errorf("invalid storage %q: expected <backend>:<location>", uri)
+	}
+	openersMu.RLock()
+	opener, exists := openers[name]
+	openersMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown storage backend %q: must be one of %v", name, Backends())
+	}
+	storage, err := opener(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s storage: %w", name, err)
+	}
+	return storage, nil
+}
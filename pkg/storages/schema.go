@@ -0,0 +1,162 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+)
+
+var (
+	// ErrSchemaNewer is returned for data written by a newer version of minefield.
+	ErrSchemaNewer = errors.New("storage schema is newer than this version of minefield supports")
+	// ErrSchemaUnknown is returned for a schema version that no migration produces.
+	ErrSchemaUnknown = errors.New("unknown storage schema version")
+	// ErrSchemaOutdated is returned by CheckSchema for data that has to be migrated first.
+	ErrSchemaOutdated = errors.New("storage schema is outdated, run minefield storage migrate")
+)
+
+// Migration converts stored data from the previous schema version to Version. Migrations
+// work through the graph.Storage interface, so they run against every backend; the ones that
+// only concern a single backend type assert the storage and skip the others. Only the first
+// one, which versions the schema, is registered here; the migrations of the ingested data are
+// registered by the migrations package.
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(ctx context.Context, storage graph.Storage) error
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   []Migration
+)
+
+func init() {
+	RegisterMigration(Migration{
+		Version:     1,
+		Description: "Record the schema version of data written before it was versioned",
+		Migrate:     func(context.Context, graph.Storage) error { return nil },
+	})
+}
+
+// RegisterMigration adds a migration to the end of the registry. It panics if the version
+// doesn't directly follow the last registered one, so the order of the migrations never
+// depends on the order of package initialization.
+func RegisterMigration(migration Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if migration.Migrate == nil {
+		panic("storages: RegisterMigration migrate function is nil")
+	}
+	if want := len(migrations) + 1; migration.Version != want {
+		panic(fmt.Sprintf("storages: RegisterMigration called with version %d, expected %d", migration.Version, want))
+	}
+	migrations = append(migrations, migration)
+}
+
+// Migrations returns all registered migrations in the order they are applied.
+func Migrations() []Migration {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+	return append([]Migration(nil), migrations...)
+}
+
+// CurrentSchemaVersion returns the schema version this version of minefield writes.
+func CurrentSchemaVersion() int {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+	return len(migrations)
+}
+
+// SchemaStatus describes the schema version of a storage and the migrations it is missing.
+type SchemaStatus struct {
+	Version int
+	Current int
+	Pending []Migration
+}
+
+// GetSchemaStatus reads the schema version of storage. An empty storage without a recorded
+// version is stamped with the current version, since there is nothing to migrate. It returns
+// ErrSchemaNewer or ErrSchemaUnknown if this version of minefield can't migrate the data.
+func GetSchemaStatus(ctx context.Context, storage graph.Storage) (*SchemaStatus, error) {
+	version, err := storage.GetSchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered := Migrations()
+	current := len(registered)
+
+	if version == 0 {
+		empty, err := isEmpty(ctx, storage)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			if err := storage.SetSchemaVersion(ctx, current); err != nil {
+				return nil, err
+			}
+			version = current
+		}
+	}
+
+	switch {
+	case version > current:
+		return nil, fmt.Errorf("%w: version %d, supported up to %d", ErrSchemaNewer, version, current)
+	case version < 0:
+		return nil, fmt.Errorf("%w: %d", ErrSchemaUnknown, version)
+	}
+	return &SchemaStatus{Version: version, Current: current, Pending: registered[version:]}, nil
+}
+
+// CheckSchema returns an error unless storage is at the current schema version, see
+// GetSchemaStatus. Outdated data gives ErrSchemaOutdated.
+func CheckSchema(ctx context.Context, storage graph.Storage) error {
+	status, err := GetSchemaStatus(ctx, storage)
+	if err != nil {
+		return err
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: version %d, current is %d", ErrSchemaOutdated, status.Version, status.Current)
+	}
+	return nil
+}
+
+// MigrateSchema applies the pending migrations to storage in order. The schema version is
+// recorded after every migration, so a failed run continues where it stopped. It returns the
+// migrations that were applied.
+func MigrateSchema(ctx context.Context, storage graph.Storage) ([]Migration, error) {
+	status, err := GetSchemaStatus(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+	applied := make([]Migration, 0, len(status.Pending))
+	for _, migration := range status.Pending {
+		if err := migration.Migrate(ctx, storage); err != nil {
+			return applied, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+		if err := storage.SetSchemaVersion(ctx, migration.Version); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// isEmpty reports whether nothing was ever written to storage.
+func isEmpty(ctx context.Context, storage graph.Storage) (bool, error) {
+	counter, err := storage.GetIDCounter(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get ID counter: %w", err)
+	}
+	if counter > 0 {
+		return false, nil
+	}
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	return len(keys) == 0, nil
+}
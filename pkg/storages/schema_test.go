@@ -0,0 +1,100 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaEmptyStorage(t *testing.T) {
+	storage := graph.NewMockStorage()
+	require.NoError(t, CheckSchema(context.Background(), storage))
+
+	version, err := storage.GetSchemaVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion(), version, "an empty storage is stamped with the current version")
+}
+
+func TestSchemaUnversionedStorage(t *testing.T) {
+	storage := graph.NewMockStorage()
+	_, err := graph.AddNode(context.Background(), storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, CheckSchema(context.Background(), storage), ErrSchemaOutdated)
+	status, err := GetSchemaStatus(context.Background(), storage)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Version)
+	assert.Len(t, status.Pending, CurrentSchemaVersion())
+
+	applied, err := MigrateSchema(context.Background(), storage)
+	require.NoError(t, err)
+	assert.Len(t, applied, CurrentSchemaVersion())
+	require.NoError(t, CheckSchema(context.Background(), storage))
+
+	applied, err = MigrateSchema(context.Background(), storage)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "migrating twice is a no-op")
+}
+
+func TestSchemaUnsupportedVersions(t *testing.T) {
+	storage := graph.NewMockStorage()
+	require.NoError(t, storage.SetSchemaVersion(context.Background(), CurrentSchemaVersion()+1))
+	assert.ErrorIs(t, CheckSchema(context.Background(), storage), ErrSchemaNewer)
+	_, err := MigrateSchema(context.Background(), storage)
+	assert.ErrorIs(t, err, ErrSchemaNewer)
+
+	require.NoError(t, storage.SetSchemaVersion(context.Background(), -1))
+	assert.ErrorIs(t, CheckSchema(context.Background(), storage), ErrSchemaUnknown)
+
+	storage.GetSchemaVersionErr = errors.New("unavailable")
+	assert.Error(t, CheckSchema(context.Background(), storage))
+}
+
+func TestMigrateSchemaStopsAtFailure(t *testing.T) {
+	registered := Migrations()
+	t.Cleanup(func() {
+		migrationsMu.Lock()
+		migrations = registered
+		migrationsMu.Unlock()
+	})
+	var ran []int
+	RegisterMigration(Migration{Version: len(registered) + 1, Description: "fails", Migrate: func(context.Context, graph.Storage) error {
+		ran = append(ran, len(registered)+1)
+		return errors.New("boom")
+	}})
+	RegisterMigration(Migration{Version: len(registered) + 2, Description: "never runs", Migrate: func(context.Context, graph.Storage) error {
+		ran = append(ran, len(registered)+2)
+		return nil
+	}})
+
+	storage := graph.NewMockStorage()
+	_, err := graph.AddNode(context.Background(), storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+
+	applied, err := MigrateSchema(context.Background(), storage)
+	assert.ErrorContains(t, err, "boom")
+	assert.Len(t, applied, len(registered))
+	assert.Equal(t, []int{len(registered) + 1}, ran)
+	version, err := storage.GetSchemaVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, len(registered), version, "the version of the last successful migration is recorded")
+
+	assert.Panics(t, func() {
+		RegisterMigration(Migration{Version: 1, Migrate: func(context.Context, graph.Storage) error { return nil }})
+	})
+}
+
+func TestCopyGraphSchemaVersion(t *testing.T) {
+	src := graph.NewMockStorage()
+	require.NoError(t, CheckSchema(context.Background(), src))
+	_, err := graph.AddNode(context.Background(), src, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+
+	dst := graph.NewMockStorage()
+	require.NoError(t, CopyGraph(context.Background(), src, dst, DefaultCopyBatchSize))
+	require.NoError(t, CheckSchema(context.Background(), dst))
+}
@@ -1,14 +1,17 @@
 package storages
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/utils"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -17,7 +20,7 @@ import (
 
 const (
 	key     = "key = ?"
-	KeyLike = "key LIKE ?"
+	KeyLike = "key LIKE ? ESCAPE '\\'"
 	KeyIN   = "key IN ?"
 
 	maxConnections        = 10
@@ -43,9 +46,21 @@ type GlobalCounter struct {
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
+// CustomData holds one field of the custom data stored for a tag and key.
+type CustomData struct {
+	Tag     string `gorm:"primaryKey"`
+	Key     string `gorm:"primaryKey"`
+	DataKey string `gorm:"primaryKey"`
+	Value   []byte
+}
+
 // SQLStorage represents the storage backed by a SQL database.
 type SQLStorage struct {
 	DB *gorm.DB
+
+	// edgeMu serializes edge updates, since SQLite has no row-level locks to order the
+	// read-modify-write of two nodes between concurrent transactions.
+	edgeMu sync.Mutex
 }
 
 // NewSQLStorage initializes a new SQLStorage with a SQLite database.
@@ -64,9 +79,17 @@ func NewSQLStorage(dsn string, useInMemory bool) (*SQLStorage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SQLDB: %w", err)
 	}
-	sqlDB.SetMaxIdleConns(maxConnections)
-	sqlDB.SetMaxOpenConns(maxOpenConnections)
-	sqlDB.SetConnMaxLifetime(connectionMaxLifetime)
+	if useInMemory {
+		// Every connection to "file::memory:" opens its own empty database, so concurrent
+		// requests must share a single connection that is never recycled.
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	} else {
+		sqlDB.SetMaxIdleConns(maxConnections)
+		sqlDB.SetMaxOpenConns(maxOpenConnections)
+		sqlDB.SetConnMaxLifetime(connectionMaxLifetime)
+	}
 
 	storage := &SQLStorage{DB: db}
 
@@ -79,13 +102,15 @@ func NewSQLStorage(dsn string, useInMemory bool) (*SQLStorage, error) {
 
 // Migrate performs the database migrations for SQLStorage.
 func (s *SQLStorage) Migrate() error {
-	return s.DB.AutoMigrate(&KVStore{}, &CacheStack{}, &GlobalCounter{})
+	return s.DB.AutoMigrate(&KVStore{}, &CacheStack{}, &GlobalCounter{}, &CustomData{})
 }
 
 // NameToID converts a node name to its corresponding ID.
-func (s *SQLStorage) NameToID(name string) (uint32, error) {
+func (s *SQLStorage) NameToID(ctx context.Context, name string) (uint32, error) {
 	var kv KVStore
-	if err := s.DB.First(&kv, key, NameToIDKey+name).Error; err != nil {
+	if err := s.DB.WithContext(ctx).First(&kv, key, NameToIDKey+name).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("node %s: %w", name, graph.ErrNodeNotFound)
+	} else if err != nil {
 		return 0, fmt.Errorf("failed to get name-to-ID mapping: %w", err)
 	}
 	id, err := strconv.ParseUint(kv.Value, 10, 32)
@@ -99,7 +124,7 @@ func (s *SQLStorage) NameToID(name string) (uint32, error) {
 }
 
 // SaveNode saves a node to the SQLite storage, handling node data, name-to-ID mapping, and caching.
-func (s *SQLStorage) SaveNode(node *graph.Node) error {
+func (s *SQLStorage) SaveNode(ctx context.Context, node *graph.Node) error {
 	if node == nil {
 		return fmt.Errorf("node cannot be nil")
 	}
@@ -115,7 +140,7 @@ func (s *SQLStorage) SaveNode(node *graph.Node) error {
 	nameToIDKey := fmt.Sprintf("%s%s", NameToIDKey, node.Name)
 
 	// Start a transaction to ensure atomicity
-	return s.DB.Transaction(func(tx *gorm.DB) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Save the node data
 		kvNode := KVStore{
 			Key:   nodeKey,
@@ -150,12 +175,58 @@ func (s *SQLStorage) SaveNode(node *graph.Node) error {
 	})
 }
 
+// CreateNode inserts the name-to-ID mapping with ON CONFLICT DO NOTHING, relying on the unique
+// key, and saves the node in the same transaction only if the mapping was inserted.
+func (s *SQLStorage) CreateNode(ctx context.Context, node *graph.Node) (uint32, error) {
+	if node == nil {
+		return 0, fmt.Errorf("node cannot be nil")
+	}
+	data, err := node.MarshalJSON()
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal node: %w", err)
+	}
+
+	id := node.ID
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		mapping := KVStore{Key: NameToIDKey + node.Name, Value: fmt.Sprintf("%d", node.ID)}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&mapping)
+		if result.Error != nil {
+			return fmt.Errorf("failed to save name-to-ID mapping: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var existing KVStore
+			if err := tx.First(&existing, key, NameToIDKey+node.Name).Error; err != nil {
+				return fmt.Errorf("failed to get name-to-ID mapping: %w", err)
+			}
+			parsed, err := strconv.ParseUint(existing.Value, 10, 32)
+			if err != nil {
+				return fmt.Errorf("failed to convert ID to integer: %w", err)
+			}
+			id = uint32(parsed)
+			return nil
+		}
+		if err := tx.Create(&KVStore{Key: fmt.Sprintf("%s%d", NodeKeyPrefix, node.ID), Value: string(data)}).Error; err != nil {
+			return fmt.Errorf("failed to save node data: %w", err)
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&CacheStack{ID: node.ID}).Error; err != nil {
+			return fmt.Errorf("failed to add node ID to cache stack: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 // GetNode retrieves a node by its ID from the SQLite storage.
-func (s *SQLStorage) GetNode(id uint32) (*graph.Node, error) {
+func (s *SQLStorage) GetNode(ctx context.Context, id uint32) (*graph.Node, error) {
 	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
 
 	var kvNode KVStore
-	if err := s.DB.First(&kvNode, "key = ?", nodeKey).Error; err != nil {
+	if err := s.DB.WithContext(ctx).First(&kvNode, key, nodeKey).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to get node data: %w", err)
 	}
 
@@ -168,10 +239,10 @@ func (s *SQLStorage) GetNode(id uint32) (*graph.Node, error) {
 }
 
 // GetNodes retrieves multiple nodes by their IDs.
-func (s *SQLStorage) GetNodes(ids []uint32) (map[uint32]*graph.Node, error) {
+func (s *SQLStorage) GetNodes(ctx context.Context, ids []uint32) (map[uint32]*graph.Node, error) {
 	nodeKeys := generateNodeKeys(ids)
 	var kvNodes []KVStore
-	if err := s.DB.Where(KeyIN, nodeKeys).Find(&kvNodes).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where(KeyIN, nodeKeys).Find(&kvNodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to get nodes: %w", err)
 	}
 	nodes := make(map[uint32]*graph.Node)
@@ -190,16 +261,21 @@ func (s *SQLStorage) GetNodes(ids []uint32) (map[uint32]*graph.Node, error) {
 	return nodes, nil
 }
 
-// GetNodesByGlob retrieves nodes matching a glob pattern using SQL queries.
-func (s *SQLStorage) GetNodesByGlob(pattern string) ([]*graph.Node, error) {
-	// Construct the SQL LIKE pattern
-	sqlPattern := convertGlobToSQLPattern(pattern)
-
-	// Retrieve all name-to-ID mappings that match the pattern
-	var mappings []KVStore
-	if err := s.DB.Where(KeyLike, fmt.Sprintf("%s%s", NameToIDKey, sqlPattern)).Find(&mappings).Error; err != nil {
+// GetNodesByGlob retrieves nodes matching a glob pattern. Only the names sharing the
+// pattern's literal prefix are read, and matched against the pattern with utils.MatchGlob,
+// since SQL LIKE patterns are case insensitive and have no character classes.
+func (s *SQLStorage) GetNodesByGlob(ctx context.Context, pattern string) ([]*graph.Node, error) {
+	query := s.DB.WithContext(ctx).Where(KeyLike, escapeLike(NameToIDKey+utils.GlobPrefix(pattern))+"%")
+	var candidates []KVStore
+	if err := query.Find(&candidates).Error; err != nil {
 		return nil, fmt.Errorf("failed to get name-to-ID mappings with pattern %s: %w", pattern, err)
 	}
+	var mappings []KVStore
+	for _, candidate := range candidates {
+		if utils.MatchGlob(pattern, strings.TrimPrefix(candidate.Key, NameToIDKey)) {
+			mappings = append(mappings, candidate)
+		}
+	}
 
 	if len(mappings) == 0 {
 		return []*graph.Node{}, nil // No matches found
@@ -220,7 +296,7 @@ func (s *SQLStorage) GetNodesByGlob(pattern string) ([]*graph.Node, error) {
 	var nodes []KVStore
 	var resultNodes []*graph.Node
 	// Retrieve nodes with the extracted IDs
-	if err := s.DB.Where("key IN ?", generateNodeKeys(ids)).Find(&nodes).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where("key IN ?", generateNodeKeys(ids)).Find(&nodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to retrieve nodes for IDs %v: %w", ids, err)
 	}
 	for _, node := range nodes {
@@ -244,9 +320,9 @@ func generateNodeKeys(ids []uint32) []string {
 }
 
 // GetAllKeys retrieves all node IDs.
-func (s *SQLStorage) GetAllKeys() ([]uint32, error) {
+func (s *SQLStorage) GetAllKeys(ctx context.Context) ([]uint32, error) {
 	var kvNodes []KVStore
-	if err := s.DB.Where(KeyLike, NodeKeyPrefix+"%").Find(&kvNodes).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where(KeyLike, NodeKeyPrefix+"%").Find(&kvNodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to get all node IDs: %w", err)
 	}
 	ids := make([]uint32, len(kvNodes))
@@ -261,7 +337,7 @@ func (s *SQLStorage) GetAllKeys() ([]uint32, error) {
 }
 
 // SaveCache saves a node cache.
-func (s *SQLStorage) SaveCache(cache *graph.NodeCache) error {
+func (s *SQLStorage) SaveCache(ctx context.Context, cache *graph.NodeCache) error {
 	cacheKey := fmt.Sprintf("%s%d", CacheKeyPrefix, cache.ID)
 	data, err := cache.MarshalJSON()
 	if err != nil {
@@ -271,14 +347,14 @@ func (s *SQLStorage) SaveCache(cache *graph.NodeCache) error {
 		Key:   cacheKey,
 		Value: string(data),
 	}
-	if err := s.DB.Save(&kvCache).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Save(&kvCache).Error; err != nil {
 		return fmt.Errorf("failed to save cache: %w", err)
 	}
 	return nil
 }
 
 // SaveCaches saves multiple node caches.
-func (s *SQLStorage) SaveCaches(caches []*graph.NodeCache) error {
+func (s *SQLStorage) SaveCaches(ctx context.Context, caches []*graph.NodeCache) error {
 	const batchSize = 500 // Safe batch size considering SQLite's limits
 
 	// Process caches in batches
@@ -302,7 +378,7 @@ func (s *SQLStorage) SaveCaches(caches []*graph.NodeCache) error {
 			}
 		}
 
-		if err := s.DB.Save(&kvCaches).Error; err != nil {
+		if err := s.DB.WithContext(ctx).Save(&kvCaches).Error; err != nil {
 			return fmt.Errorf("failed to save caches batch: %w", err)
 		}
 	}
@@ -310,18 +386,38 @@ func (s *SQLStorage) SaveCaches(caches []*graph.NodeCache) error {
 	return nil
 }
 
-// RemoveAllCaches removes all caches from the database.
-func (s *SQLStorage) RemoveAllCaches() error {
-	if err := s.DB.Delete(&KVStore{}, "key LIKE ?", CacheKeyPrefix+"%").Error; err != nil {
-		return fmt.Errorf("failed to remove all caches: %w", err)
-	}
-	return nil
+// RemoveAllCaches removes all caches from the database and puts their nodes on the cache
+// stack, so they are cached again.
+func (s *SQLStorage) RemoveAllCaches(ctx context.Context) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var keys []string
+		if err := tx.Model(&KVStore{}).Where(KeyLike, CacheKeyPrefix+"%").Pluck("key", &keys).Error; err != nil {
+			return fmt.Errorf("failed to get cache keys: %w", err)
+		}
+		stack := make([]CacheStack, 0, len(keys))
+		for _, cacheKey := range keys {
+			id, err := strconv.ParseUint(strings.TrimPrefix(cacheKey, CacheKeyPrefix), 10, 32)
+			if err != nil {
+				return fmt.Errorf("failed to parse ID from key %s: %w", cacheKey, err)
+			}
+			stack = append(stack, CacheStack{ID: uint32(id)})
+		}
+		if len(stack) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(stack, 500).Error; err != nil {
+				return fmt.Errorf("failed to add node IDs to cache stack: %w", err)
+			}
+		}
+		if err := tx.Delete(&KVStore{}, KeyLike, CacheKeyPrefix+"%").Error; err != nil {
+			return fmt.Errorf("failed to remove all caches: %w", err)
+		}
+		return nil
+	})
 }
 
 // ToBeCached retrieves IDs of nodes to be cached.
-func (s *SQLStorage) ToBeCached() ([]uint32, error) {
+func (s *SQLStorage) ToBeCached(ctx context.Context) ([]uint32, error) {
 	var cacheStack []CacheStack
-	if err := s.DB.Find(&cacheStack).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Find(&cacheStack).Error; err != nil {
 		return nil, fmt.Errorf("failed to get cache stack: %w", err)
 	}
 	ids := make([]uint32, len(cacheStack))
@@ -332,24 +428,24 @@ func (s *SQLStorage) ToBeCached() ([]uint32, error) {
 }
 
 // AddNodeToCachedStack adds a node ID to the cached stack.
-func (s *SQLStorage) AddNodeToCachedStack(id uint32) error {
+func (s *SQLStorage) AddNodeToCachedStack(ctx context.Context, id uint32) error {
 	cacheEntry := CacheStack{
 		ID: id,
 	}
-	if err := s.DB.Create(&cacheEntry).Error; err != nil {
+	// The stack holds every ID only once, so adding a node that is already on it is a no-op.
+	if err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&cacheEntry).Error; err != nil {
 		return fmt.Errorf("failed to add node ID to cache stack: %w", err)
 	}
 	return nil
 }
 
 // GetCache retrieves a cache by its ID.
-func (s *SQLStorage) GetCache(id uint32) (*graph.NodeCache, error) {
+func (s *SQLStorage) GetCache(ctx context.Context, id uint32) (*graph.NodeCache, error) {
 	cacheKey := fmt.Sprintf("%s%d", CacheKeyPrefix, id)
 	var kvCache KVStore
-	if err := s.DB.First(&kvCache, key, cacheKey).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil // Return nil if the cache does not exist
-		}
+	if err := s.DB.WithContext(ctx).First(&kvCache, key, cacheKey).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("cache %d: %w", id, graph.ErrCacheNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to get cache: %w", err)
 	}
 	var cache graph.NodeCache
@@ -360,13 +456,13 @@ func (s *SQLStorage) GetCache(id uint32) (*graph.NodeCache, error) {
 }
 
 // GetCaches retrieves multiple caches by their IDs.
-func (s *SQLStorage) GetCaches(ids []uint32) (map[uint32]*graph.NodeCache, error) {
+func (s *SQLStorage) GetCaches(ctx context.Context, ids []uint32) (map[uint32]*graph.NodeCache, error) {
 	cacheKeys := make([]string, len(ids))
 	for i, id := range ids {
 		cacheKeys[i] = fmt.Sprintf("%s%d", CacheKeyPrefix, id)
 	}
 	var kvCaches []KVStore
-	if err := s.DB.Where(KeyIN, cacheKeys).Find(&kvCaches).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where(KeyIN, cacheKeys).Find(&kvCaches).Error; err != nil {
 		return nil, fmt.Errorf("failed to get caches: %w", err)
 	}
 	caches := make(map[uint32]*graph.NodeCache, len(kvCaches))
@@ -389,50 +485,341 @@ func (s *SQLStorage) GetCaches(ids []uint32) (map[uint32]*graph.NodeCache, error
 }
 
 // ClearCacheStack clears the cache stack.
-func (s *SQLStorage) ClearCacheStack() error {
-	if err := s.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CacheStack{}).Error; err != nil {
+func (s *SQLStorage) ClearCacheStack(ctx context.Context) error {
+	if err := s.DB.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CacheStack{}).Error; err != nil {
 		return fmt.Errorf("failed to clear cache stack: %w", err)
 	}
 	return nil
 }
 
 // GenerateID generates a new unique ID by inserting a new GlobalCounter and retrieving its ID.
-func (s *SQLStorage) GenerateID() (uint32, error) {
+func (s *SQLStorage) GenerateID(ctx context.Context) (uint32, error) {
 	counter := GlobalCounter{}
-	if err := s.DB.Create(&counter).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Create(&counter).Error; err != nil {
 		return 0, fmt.Errorf("failed to generate ID: %w", err)
 	}
 	return counter.ID, nil
 }
 
 // GetCustomData retrieves custom data based on tag and key.
-func (s *SQLStorage) GetCustomData(tag, key string) (map[string][]byte, error) {
-	// TODO: Implement using GORM
-	return nil, fmt.Errorf("not implemented")
+func (s *SQLStorage) GetCustomData(ctx context.Context, tag, key string) (map[string][]byte, error) {
+	var rows []CustomData
+	if err := s.DB.WithContext(ctx).Where("tag = ? AND key = ?", tag, key).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get data from DB: %w", err)
+	}
+	result := make(map[string][]byte, len(rows))
+	for _, row := range rows {
+		result[row.DataKey] = row.Value
+	}
+	return result, nil
 }
 
 // AddOrUpdateCustomData adds or updates custom data based on tag, key, and data key.
-func (s *SQLStorage) AddOrUpdateCustomData(tag, key string, dataKey string, data []byte) error {
-	// TODO: Implement using GORM
-	return fmt.Errorf("not implemented")
+func (s *SQLStorage) AddOrUpdateCustomData(ctx context.Context, tag, key string, dataKey string, data []byte) error {
+	row := CustomData{
+		Tag:     tag,
+		Key:     key,
+		DataKey: dataKey,
+		Value:   data,
+	}
+	if err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to save custom data: %w", err)
+	}
+	return nil
+}
+
+// GetCustomDataKeys returns all keys with custom data, grouped by tag.
+func (s *SQLStorage) GetCustomDataKeys(ctx context.Context) (map[string][]string, error) {
+	var rows []CustomData
+	if err := s.DB.WithContext(ctx).Model(&CustomData{}).Distinct("tag", "key").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	result := make(map[string][]string)
+	for _, row := range rows {
+		result[row.Tag] = append(result[row.Tag], row.Key)
+	}
+	return result, nil
+}
+
+// GetIDCounter returns the highest ID generated so far.
+func (s *SQLStorage) GetIDCounter(ctx context.Context) (uint32, error) {
+	var id uint32
+	if err := s.DB.WithContext(ctx).Model(&GlobalCounter{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to get ID counter: %w", err)
+	}
+	return id, nil
+}
+
+// SetIDCounter inserts a counter row with the given ID, which makes the auto increment
+// continue after it.
+func (s *SQLStorage) SetIDCounter(ctx context.Context, id uint32) error {
+	current, err := s.GetIDCounter(ctx)
+	if err != nil {
+		return err
+	}
+	if id <= current {
+		return nil
+	}
+	if err := s.DB.WithContext(ctx).Create(&GlobalCounter{ID: id}).Error; err != nil {
+		return fmt.Errorf("failed to set ID counter: %w", err)
+	}
+	return nil
+}
+
+// nodeAliasesKey returns the prefix of the rows holding the aliases of the node, one per alias.
+func nodeAliasesKey(id uint32) string {
+	return fmt.Sprintf("%s%d:", AliasesKeyPrefix, id)
+}
+
+// AddAliases inserts the alias-to-ID mappings that don't exist yet, and a row for each of them
+// under the node, within a single transaction.
+func (s *SQLStorage) AddAliases(ctx context.Context, id uint32, aliases []string) error {
+	// Holding edgeMu keeps DeleteNode from deleting the node before its aliases are added.
+	s.edgeMu.Lock()
+	defer s.edgeMu.Unlock()
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The aliases are written before the node is looked up, see updateEdge.
+		for _, alias := range aliases {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&KVStore{Key: AliasToIDKey + alias, Value: strconv.FormatUint(uint64(id), 10)})
+			if result.Error != nil {
+				return fmt.Errorf("failed to save alias %s: %w", alias, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			if err := tx.Create(&KVStore{Key: nodeAliasesKey(id) + alias, Value: alias}).Error; err != nil {
+				return fmt.Errorf("failed to save alias %s: %w", alias, err)
+			}
+		}
+		var count int64
+		if err := tx.Model(&KVStore{}).Where(key, fmt.Sprintf("%s%d", NodeKeyPrefix, id)).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		}
+		return nil
+	})
+}
+
+// AliasToID returns the ID of the node the alias resolves to.
+func (s *SQLStorage) AliasToID(ctx context.Context, alias string) (uint32, error) {
+	var kv KVStore
+	if err := s.DB.WithContext(ctx).First(&kv, key, AliasToIDKey+alias).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("alias %s: %w", alias, graph.ErrNodeNotFound)
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get alias-to-ID mapping: %w", err)
+	}
+	return utils.StrToUint32(kv.Value)
+}
+
+// GetAliases returns the sorted aliases of the node.
+func (s *SQLStorage) GetAliases(ctx context.Context, id uint32) ([]string, error) {
+	aliases := []string{}
+	if err := s.DB.WithContext(ctx).Model(&KVStore{}).Where(KeyLike, escapeLike(nodeAliasesKey(id))+"%").Order("value").Pluck("value", &aliases).Error; err != nil {
+		return nil, fmt.Errorf("failed to get aliases of node %d: %w", id, err)
+	}
+	return aliases, nil
+}
+
+// DeleteNode removes the node, its name-to-ID mapping, its aliases, its cache and its cache
+// stack entry within a single transaction.
+func (s *SQLStorage) DeleteNode(ctx context.Context, id uint32) error {
+	// Edge updates check for the nodes inside their transaction, holding edgeMu keeps them
+	// from adding an edge to the node while it is deleted.
+	s.edgeMu.Lock()
+	defer s.edgeMu.Unlock()
+
+	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Writing first takes the write lock before the node is read, see updateEdge.
+		if err := tx.Delete(&CacheStack{}, id).Error; err != nil {
+			return fmt.Errorf("failed to remove node from cache stack: %w", err)
+		}
+		var kvNode KVStore
+		if err := tx.First(&kvNode, key, nodeKey).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		} else if err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		var node graph.Node
+		if err := node.UnmarshalJSON([]byte(kvNode.Value)); err != nil {
+			return fmt.Errorf("failed to unmarshal node data: %w", err)
+		}
+		if !node.Children.IsEmpty() || !node.Parents.IsEmpty() {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeHasEdges)
+		}
+
+		var aliases []string
+		if err := tx.Model(&KVStore{}).Where(KeyLike, escapeLike(nodeAliasesKey(id))+"%").Pluck("value", &aliases).Error; err != nil {
+			return fmt.Errorf("failed to get aliases: %w", err)
+		}
+
+		keys := []string{nodeKey, NameToIDKey + node.Name, fmt.Sprintf("%s%d", CacheKeyPrefix, id)}
+		for _, alias := range aliases {
+			keys = append(keys, AliasToIDKey+alias, nodeAliasesKey(id)+alias)
+		}
+		if err := tx.Delete(&KVStore{}, KeyIN, keys).Error; err != nil {
+			return fmt.Errorf("failed to delete node: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateNodeMetadata replaces the metadata of the node within a single transaction.
+func (s *SQLStorage) UpdateNodeMetadata(ctx context.Context, id uint32, update func(metadata any) (any, error)) (*graph.Node, error) {
+	// Holding edgeMu keeps edge updates from writing the node between reading and saving it.
+	s.edgeMu.Lock()
+	defer s.edgeMu.Unlock()
+
+	nodeKey := fmt.Sprintf("%s%d", NodeKeyPrefix, id)
+	var node graph.Node
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Rewriting the row first takes the write lock before the node is read, like updateEdge
+		// does, and tells whether the node exists.
+		result := tx.Model(&KVStore{}).Where(key, nodeKey).Update("value", gorm.Expr("value"))
+		if result.Error != nil {
+			return fmt.Errorf("failed to lock node: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("node %d: %w", id, graph.ErrNodeNotFound)
+		}
+		var kvNode KVStore
+		if err := tx.First(&kvNode, key, nodeKey).Error; err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+		if err := node.UnmarshalJSON([]byte(kvNode.Value)); err != nil {
+			return fmt.Errorf("failed to unmarshal node data: %w", err)
+		}
+		var err error
+		if node.Metadata, err = update(node.Metadata); err != nil {
+			return err
+		}
+		data, err := node.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+		if err := tx.Save(&KVStore{Key: nodeKey, Value: string(data)}).Error; err != nil {
+			return fmt.Errorf("failed to save node: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// GetSchemaVersion returns the schema version recorded in the key-value table.
+func (s *SQLStorage) GetSchemaVersion(ctx context.Context) (int, error) {
+	var kv KVStore
+	if err := s.DB.WithContext(ctx).First(&kv, key, SchemaVersionKey).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	version, err := strconv.Atoi(kv.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", kv.Value, err)
+	}
+	return version, nil
+}
+
+// SetSchemaVersion records the schema version in the key-value table.
+func (s *SQLStorage) SetSchemaVersion(ctx context.Context, version int) error {
+	kv := KVStore{Key: SchemaVersionKey, Value: strconv.Itoa(version)}
+	if err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&kv).Error; err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
+
+// AddDependency adds the edge from -> to to both nodes within a single transaction.
+func (s *SQLStorage) AddDependency(ctx context.Context, from, to uint32) error {
+	return s.updateEdge(ctx, from, to, func(fromNode, toNode *graph.Node) {
+		fromNode.Children.Add(to)
+		toNode.Parents.Add(from)
+	})
+}
+
+// RemoveDependency removes the edge from -> to from both nodes within a single transaction.
+func (s *SQLStorage) RemoveDependency(ctx context.Context, from, to uint32) error {
+	return s.updateEdge(ctx, from, to, func(fromNode, toNode *graph.Node) {
+		fromNode.Children.Remove(to)
+		toNode.Parents.Remove(from)
+	})
+}
+
+// updateEdge adds both nodes to the cache stack, reads them, applies update and writes them
+// back, all inside one transaction.
+func (s *SQLStorage) updateEdge(ctx context.Context, from, to uint32, update func(fromNode, toNode *graph.Node)) error {
+	if from == to {
+		return graph.ErrSelfDependency
+	}
+
+	s.edgeMu.Lock()
+	defer s.edgeMu.Unlock()
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Writing first takes the write lock before the nodes are read. A transaction that
+		// starts with a read cannot be upgraded while another connection is writing.
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoNothing: true,
+		}).Create(&[]CacheStack{{ID: from}, {ID: to}}).Error; err != nil {
+			return fmt.Errorf("failed to add node IDs to cache stack: %w", err)
+		}
+
+		var kvNodes []KVStore
+		if err := tx.Where(KeyIN, generateNodeKeys([]uint32{from, to})).Find(&kvNodes).Error; err != nil {
+			return fmt.Errorf("failed to get nodes %d and %d: %w", from, to, err)
+		}
+		nodes := make(map[string]*graph.Node, len(kvNodes))
+		for _, kvNode := range kvNodes {
+			var node graph.Node
+			if err := node.UnmarshalJSON([]byte(kvNode.Value)); err != nil {
+				return fmt.Errorf("failed to unmarshal node data: %w", err)
+			}
+			nodes[kvNode.Key] = &node
+		}
+		fromKey := fmt.Sprintf("%s%d", NodeKeyPrefix, from)
+		toKey := fmt.Sprintf("%s%d", NodeKeyPrefix, to)
+		fromNode, ok := nodes[fromKey]
+		if !ok {
+			return fmt.Errorf("node %d: %w", from, graph.ErrNodeNotFound)
+		}
+		toNode, ok := nodes[toKey]
+		if !ok {
+			return fmt.Errorf("node %d: %w", to, graph.ErrNodeNotFound)
+		}
+
+		update(fromNode, toNode)
+
+		fromData, err := fromNode.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+		toData, err := toNode.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+		if err := tx.Save(&[]KVStore{{Key: fromKey, Value: string(fromData)}, {Key: toKey, Value: string(toData)}}).Error; err != nil {
+			return fmt.Errorf("failed to save nodes: %w", err)
+		}
+		return nil
+	})
 }
 
-// convertGlobToSQLPattern converts a glob pattern to a SQL LIKE pattern.
-// It replaces '*' with '%' and '?' with '_'. It also escapes existing '%' and '_' characters.
-func convertGlobToSQLPattern(pattern string) string {
+// escapeLike escapes the wildcards of a SQL LIKE pattern, for use with KeyLike.
+func escapeLike(literal string) string {
 	var sb strings.Builder
-	for _, char := range pattern {
+	for _, char := range literal {
 		switch char {
-		case '*':
-			sb.WriteByte('%')
-		case '?':
-			sb.WriteByte('_')
-		case '%', '_':
+		case '%', '_', '\\':
 			sb.WriteByte('\\')
-			sb.WriteRune(char)
-		default:
-			sb.WriteRune(char)
 		}
+		sb.WriteRune(char)
 	}
 	return sb.String()
 }
@@ -1,6 +1,7 @@
 package storages
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestSQLGenerateID_InMemory(t *testing.T) {
 	ids := make(map[uint32]bool)
 
 	for i := 1; i <= numIDs; i++ {
-		id, err := storage.GenerateID()
+		id, err := storage.GenerateID(context.Background())
 		if err != nil {
 			t.Fatalf("GenerateID failed at iteration %d: %v", i, err)
 		}
@@ -49,7 +50,7 @@ func TestGenerateID_FileBased(t *testing.T) {
 	ids := make(map[uint32]bool)
 
 	for i := 1; i <= numIDs; i++ {
-		id, err := storage.GenerateID()
+		id, err := storage.GenerateID(context.Background())
 		if err != nil {
 			t.Fatalf("GenerateID failed at iteration %d: %v", i, err)
 		}
@@ -70,7 +71,7 @@ func TestGenerateID_FileBased(t *testing.T) {
 
 	// Generate additional IDs and ensure they continue from the last value
 	for i := numIDs + 1; i <= numIDs*2; i++ {
-		id, err := storage.GenerateID()
+		id, err := storage.GenerateID(context.Background())
 		if err != nil {
 			t.Fatalf("GenerateID failed at iteration %d: %v", i, err)
 		}
@@ -91,11 +92,11 @@ func TestSQLSaveNode(t *testing.T) {
 		t.Fatalf("Setup failed: %v", err)
 	}
 	node := &graph.Node{ID: 1, Name: "test_node", Children: roaring.New(), Parents: roaring.New()}
-	err = s.SaveNode(node)
+	err = s.SaveNode(context.Background(), node)
 	assert.NoError(t, err)
 
 	// Verify node data is saved
-	savedNode, err := s.GetNode(node.ID)
+	savedNode, err := s.GetNode(context.Background(), node.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, node.ID, savedNode.ID)
 	assert.Equal(t, node.Name, savedNode.Name)
@@ -113,15 +114,15 @@ func TestSQLGetNodes(t *testing.T) {
 	node1.Children.Add(2)
 	node3.Parents.Add(1)
 	node1.Children.Add(3)
-	err = s.SaveNode(node1)
+	err = s.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = s.SaveNode(node2)
+	err = s.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
-	err = s.SaveNode(node3)
+	err = s.SaveNode(context.Background(), node3)
 	assert.NoError(t, err)
 
 	// Test GetNodes
-	nodes, err := s.GetNodes([]uint32{1, 2})
+	nodes, err := s.GetNodes(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.NotNil(t, nodes[1])
 	assert.Equal(t, "test_node1", nodes[1].Name)
@@ -137,10 +138,10 @@ func TestSQLNameToID(t *testing.T) {
 		t.Fatalf("Setup failed: %v", err)
 	}
 	node := &graph.Node{ID: 1, Name: "test_node", Children: roaring.New(), Parents: roaring.New()}
-	err = s.SaveNode(node)
+	err = s.SaveNode(context.Background(), node)
 	assert.NoError(t, err)
 
-	id, err := s.NameToID(node.Name)
+	id, err := s.NameToID(context.Background(), node.Name)
 	assert.NoError(t, err)
 	assert.Equal(t, node.ID, id)
 }
@@ -151,12 +152,12 @@ func TestSQLGetAllKeys(t *testing.T) {
 	}
 	node1 := &graph.Node{ID: 1, Name: "node1", Children: roaring.New(), Parents: roaring.New()}
 	node2 := &graph.Node{ID: 2, Name: "node2", Children: roaring.New(), Parents: roaring.New()}
-	err = s.SaveNode(node1)
+	err = s.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = s.SaveNode(node2)
+	err = s.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
 
-	keys, err := s.GetAllKeys()
+	keys, err := s.GetAllKeys(context.Background())
 	assert.NoError(t, err)
 	assert.Contains(t, keys, node1.ID)
 	assert.Contains(t, keys, node2.ID)
@@ -168,10 +169,10 @@ func TestSQLSaveCache(t *testing.T) {
 		t.Fatalf("Setup failed: %v", err)
 	}
 	cache := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = s.SaveCache(cache)
+	err = s.SaveCache(context.Background(), cache)
 	assert.NoError(t, err)
 
-	savedCache, err := s.GetCache(cache.ID)
+	savedCache, err := s.GetCache(context.Background(), cache.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, cache.ID, savedCache.ID)
 }
@@ -181,10 +182,10 @@ func TestSQLToBeCached(t *testing.T) {
 		t.Fatalf("Setup failed: %v", err)
 	}
 	nodeID := uint32(1)
-	err = s.AddNodeToCachedStack(nodeID)
+	err = s.AddNodeToCachedStack(context.Background(), nodeID)
 	assert.NoError(t, err)
 
-	toBeCached, err := s.ToBeCached()
+	toBeCached, err := s.ToBeCached(context.Background())
 	assert.NoError(t, err)
 	assert.Contains(t, toBeCached, nodeID)
 }
@@ -194,13 +195,13 @@ func TestSQLClearCacheStack(t *testing.T) {
 		t.Fatalf("Setup failed: %v", err)
 	}
 	nodeID := uint32(1)
-	err = s.AddNodeToCachedStack(nodeID)
+	err = s.AddNodeToCachedStack(context.Background(), nodeID)
 	assert.NoError(t, err)
 
-	err = s.ClearCacheStack()
+	err = s.ClearCacheStack(context.Background())
 	assert.NoError(t, err)
 
-	toBeCached, err := s.ToBeCached()
+	toBeCached, err := s.ToBeCached(context.Background())
 	assert.NoError(t, err)
 	assert.NotContains(t, toBeCached, nodeID)
 }
@@ -211,14 +212,14 @@ func TestSQLSaveCaches(t *testing.T) {
 	}
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = s.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = s.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Verify caches saved
-	savedCache1, err := s.GetCache(1)
+	savedCache1, err := s.GetCache(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, cache1.ID, savedCache1.ID)
-	savedCache2, err := s.GetCache(2)
+	savedCache2, err := s.GetCache(context.Background(), 2)
 	assert.NoError(t, err)
 	assert.Equal(t, cache2.ID, savedCache2.ID)
 }
@@ -229,11 +230,11 @@ func TestSQLGetCaches(t *testing.T) {
 	}
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = s.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = s.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Test GetCaches
-	caches, err := s.GetCaches([]uint32{1, 2})
+	caches, err := s.GetCaches(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.NotNil(t, caches[1])
 	assert.Equal(t, cache1.ID, caches[1].ID)
@@ -247,15 +248,15 @@ func TestSQLRemoveAllCaches(t *testing.T) {
 	}
 	cache1 := &graph.NodeCache{ID: 1, AllParents: roaring.New(), AllChildren: roaring.New()}
 	cache2 := &graph.NodeCache{ID: 2, AllParents: roaring.New(), AllChildren: roaring.New()}
-	err = s.SaveCaches([]*graph.NodeCache{cache1, cache2})
+	err = s.SaveCaches(context.Background(), []*graph.NodeCache{cache1, cache2})
 	assert.NoError(t, err)
 
 	// Test RemoveAllCaches
-	err = s.RemoveAllCaches()
+	err = s.RemoveAllCaches(context.Background())
 	assert.NoError(t, err)
 
 	// Verify caches removed
-	caches, err := s.GetCaches([]uint32{1, 2})
+	caches, err := s.GetCaches(context.Background(), []uint32{1, 2})
 	assert.NoError(t, err)
 	assert.Nil(t, caches[1])
 	assert.Nil(t, caches[2])
@@ -265,8 +266,41 @@ func TestSQLAddAndGetDataToDB(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Setup failed: %v", err)
 	}
-	err = s.AddOrUpdateCustomData("test_tag", "test_key1", "test_data1", []byte("test_data1"))
-	assert.Error(t, err)
+	err = s.AddOrUpdateCustomData(context.Background(), "test_tag", "test_key1", "test_data1", []byte("test_data1"))
+	assert.NoError(t, err)
+	err = s.AddOrUpdateCustomData(context.Background(), "test_tag", "test_key1", "test_data2", []byte("test_data2"))
+	assert.NoError(t, err)
+	err = s.AddOrUpdateCustomData(context.Background(), "test_tag", "test_key1", "test_data1", []byte("updated"))
+	assert.NoError(t, err)
+
+	data, err := s.GetCustomData(context.Background(), "test_tag", "test_key1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"test_data1": []byte("updated"), "test_data2": []byte("test_data2")}, data)
+
+	keys, err := s.GetCustomDataKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"test_tag": {"test_key1"}}, keys)
+}
+
+func TestSQLIDCounter(t *testing.T) {
+	s, err := SetupSQLTestDB("file::memory:")
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	id, err := s.GetIDCounter(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), id)
+
+	assert.NoError(t, s.SetIDCounter(context.Background(), 41))
+	id, err = s.GenerateID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
+
+	// The counter is never moved backwards.
+	assert.NoError(t, s.SetIDCounter(context.Background(), 10))
+	id, err = s.GetIDCounter(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
 }
 
 func TestSQLGetAllKeysByGlob(t *testing.T) {
@@ -276,18 +310,18 @@ func TestSQLGetAllKeysByGlob(t *testing.T) {
 	}
 	node1 := &graph.Node{ID: 1, Name: "node1", Children: roaring.New(), Parents: roaring.New()}
 	node2 := &graph.Node{ID: 2, Name: "node2", Children: roaring.New(), Parents: roaring.New()}
-	err = s.SaveNode(node1)
+	err = s.SaveNode(context.Background(), node1)
 	assert.NoError(t, err)
-	err = s.SaveNode(node2)
+	err = s.SaveNode(context.Background(), node2)
 	assert.NoError(t, err)
 
-	nodes, err := s.GetNodesByGlob("node*")
+	nodes, err := s.GetNodesByGlob(context.Background(), "node*")
 	assert.NoError(t, err)
 	nodeIDs := []uint32{nodes[0].ID, nodes[1].ID}
 	assert.Contains(t, nodeIDs, node1.ID)
 	assert.Contains(t, nodeIDs, node2.ID)
 
-	nodes, err = s.GetNodesByGlob("i*")
+	nodes, err = s.GetNodesByGlob(context.Background(), "i*")
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(nodes))
 }
@@ -14,6 +14,24 @@ const (
 	CacheKeyPrefix = "cache:"
 	IDCounterKey   = "id_counter"
 	CacheStackKey  = "to_be_cached"
+	// SchemaVersionKey holds the version of the format the data is stored in.
+	SchemaVersionKey = "schema_version"
+
+	// AliasToIDKey followed by an alias holds the ID of the node the alias resolves to, and
+	// AliasesKeyPrefix followed by a node ID the aliases of that node.
+	AliasToIDKey     = "alias_to_id:"
+	AliasesKeyPrefix = "aliases:"
+
+	// NodeIDsKey is a set of all node IDs and NameIndexKey a sorted set of all node names,
+	// so the Redis backend never has to run KEYS over the whole keyspace.
+	NodeIDsKey         = "node_ids"
+	NameIndexKey       = "name_index"
+	IndexesBuiltMarker = "indexes_built"
+
+	// CustomDataTagsKey is a set of all custom data tags, and CustomDataKeysPrefix followed by a
+	// tag is a set of all keys with custom data for that tag.
+	CustomDataTagsKey    = "custom_data_tags"
+	CustomDataKeysPrefix = "custom_data_keys:"
 )
 
 // SetupSQLTestDB initializes a new SQLStorage with the given DSN.
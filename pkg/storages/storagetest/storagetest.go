@@ -0,0 +1,503 @@
+// Package storagetest provides a conformance suite for graph.Storage implementations, so
+// every backend behaves the same way for the code built on top of it.
+//
+// A backend runs the suite from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) graph.Storage {
+//			return newEmptyStorage(t)
+//		})
+//	}
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns a new, empty storage. It is called once for every test of the suite.
+type Factory func(t *testing.T) graph.Storage
+
+// Run runs every conformance test against storages returned by newStorage.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, storage graph.Storage)
+	}{
+		{"IDGeneration", testIDGeneration},
+		{"SaveAndGetNodes", testSaveAndGetNodes},
+		{"CreateNode", testCreateNode},
+		{"MissingKeys", testMissingKeys},
+		{"Glob", testGlob},
+		{"CacheStack", testCacheStack},
+		{"Caches", testCaches},
+		{"CustomData", testCustomData},
+		{"Dependencies", testDependencies},
+		{"Aliases", testAliases},
+		{"DeleteNode", testDeleteNode},
+		{"UpdateNodeMetadata", testUpdateNodeMetadata},
+		{"SchemaVersion", testSchemaVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+func testIDGeneration(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	counter, err := storage.GetIDCounter(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), counter, "a new storage has not handed out any IDs")
+
+	var last uint32
+	for i := 0; i < 5; i++ {
+		id, err := storage.GenerateID(ctx)
+		require.NoError(t, err)
+		assert.Greater(t, id, last, "IDs are increasing")
+		last = id
+	}
+	assert.Equal(t, uint32(5), last, "IDs start at 1 and have no gaps")
+	counter, err = storage.GetIDCounter(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, last, counter)
+
+	require.NoError(t, storage.SetIDCounter(ctx, 100))
+	id, err := storage.GenerateID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), id)
+
+	require.NoError(t, storage.SetIDCounter(ctx, 10))
+	counter, err = storage.GetIDCounter(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), counter, "the ID counter never moves backwards")
+}
+
+func testSaveAndGetNodes(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", map[string]any{"version": "1.0.0"}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "vuln", nil, "GHSA-1234")
+	require.NoError(t, err)
+
+	node, err := storage.GetNode(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, node.ID)
+	assert.Equal(t, "library", node.Type)
+	assert.Equal(t, "pkg:npm/a@1.0.0", node.Name)
+	assert.Equal(t, map[string]any{"version": "1.0.0"}, node.Metadata)
+	assert.True(t, node.Children.IsEmpty())
+	assert.True(t, node.Parents.IsEmpty())
+
+	id, err := storage.NameToID(ctx, "GHSA-1234")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, id)
+
+	again, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, again.ID, "adding a node with an existing name returns the existing node")
+
+	keys, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{a.ID, b.ID}, keys)
+
+	nodes, err := storage.GetNodes(ctx, []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "GHSA-1234", nodes[b.ID].Name)
+
+	// Saving a node again replaces it.
+	node.Metadata = map[string]any{"version": "2.0.0"}
+	node.Children.Add(b.ID)
+	require.NoError(t, storage.SaveNode(ctx, node))
+	node, err = storage.GetNode(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"version": "2.0.0"}, node.Metadata)
+	assert.True(t, node.Children.Contains(b.ID))
+}
+
+func testCreateNode(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	const writers = 16
+	ids := make([]uint32, writers)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id, err := storage.GenerateID(ctx)
+			assert.NoError(t, err)
+			node := &graph.Node{ID: id, Type: "library", Name: "pkg:npm/a@1.0.0", Children: roaring.New(), Parents: roaring.New()}
+			ids[i], err = storage.CreateNode(ctx, node)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every writer gets the ID of the one node that claimed the name.
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+	keys, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{ids[0]}, keys)
+	node, err := storage.GetNode(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:npm/a@1.0.0", node.Name)
+	id, err := storage.NameToID(ctx, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], id)
+}
+
+func testMissingKeys(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", nil, "a")
+	require.NoError(t, err)
+	const missing = 1000
+
+	_, err = storage.GetNode(ctx, missing)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	_, err = storage.NameToID(ctx, "missing")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	_, err = storage.GetCache(ctx, missing)
+	assert.ErrorIs(t, err, graph.ErrCacheNotFound)
+	assert.ErrorIs(t, storage.AddDependency(ctx, a.ID, missing), graph.ErrNodeNotFound)
+	assert.ErrorIs(t, storage.AddDependency(ctx, missing, a.ID), graph.ErrNodeNotFound)
+	assert.ErrorIs(t, storage.RemoveDependency(ctx, a.ID, missing), graph.ErrNodeNotFound)
+
+	// Batch lookups skip missing entries.
+	nodes, err := storage.GetNodes(ctx, []uint32{a.ID, missing})
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Contains(t, nodes, a.ID)
+	nodes, err = storage.GetNodes(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+	caches, err := storage.GetCaches(ctx, []uint32{a.ID, missing})
+	require.NoError(t, err)
+	assert.Len(t, caches, 1)
+	assert.Contains(t, caches, a.ID)
+
+	data, err := storage.GetCustomData(ctx, "missing", "missing")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func testGlob(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	names := []string{
+		"pkg:npm/a@1.0.0",
+		"pkg:npm/ab@1.0.0",
+		"pkg:npm/%40scope/c@1.0.0",
+		"pkg:golang/github.com/org/a@v1.0.0",
+		"PKG:NPM/A@1.0.0",
+		"a_b",
+		"a%b",
+		"axb",
+		"x[1]",
+	}
+	for _, name := range names {
+		_, err := graph.AddNode(ctx, storage, "library", nil, name)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*", want: names},
+		{pattern: "pkg:npm/*", want: []string{"pkg:npm/a@1.0.0", "pkg:npm/ab@1.0.0", "pkg:npm/%40scope/c@1.0.0"}},
+		{pattern: "pkg:npm/a@1.0.0", want: []string{"pkg:npm/a@1.0.0"}},
+		{pattern: "pkg:npm/a?1.0.0", want: []string{"pkg:npm/a@1.0.0"}},
+		{pattern: "*/a@*", want: []string{"pkg:npm/a@1.0.0", "pkg:golang/github.com/org/a@v1.0.0"}},
+		{pattern: "pkg:NPM/*", want: nil},
+		{pattern: "a_b", want: []string{"a_b"}},
+		{pattern: "a%b", want: []string{"a%b"}},
+		{pattern: "a?b", want: []string{"a_b", "a%b", "axb"}},
+		{pattern: "a[_x]b", want: []string{"a_b", "axb"}},
+		{pattern: "a[^_x]b", want: []string{"a%b"}},
+		{pattern: `x\[1\]`, want: []string{"x[1]"}},
+		{pattern: "missing*", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nodes, err := storage.GetNodesByGlob(ctx, tt.pattern)
+			require.NoError(t, err)
+			got := make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				got = append(got, node.Name)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func testCacheStack(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", nil, "a")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "library", nil, "b")
+	require.NoError(t, err)
+
+	// The stack is a set of IDs, some backends keep duplicates but they carry no meaning.
+	assert.Equal(t, []uint32{a.ID, b.ID}, cacheStack(t, storage), "saved nodes are on the cache stack")
+
+	require.NoError(t, storage.ClearCacheStack(ctx))
+	assert.Empty(t, cacheStack(t, storage))
+
+	require.NoError(t, storage.AddNodeToCachedStack(ctx, b.ID))
+	require.NoError(t, storage.AddNodeToCachedStack(ctx, b.ID))
+	assert.Equal(t, []uint32{b.ID}, cacheStack(t, storage))
+
+	require.NoError(t, storage.ClearCacheStack(ctx))
+	require.NoError(t, storage.AddDependency(ctx, a.ID, b.ID))
+	assert.Equal(t, []uint32{a.ID, b.ID}, cacheStack(t, storage), "both ends of a new edge are on the cache stack")
+
+	require.NoError(t, graph.Cache(ctx, storage))
+	assert.Empty(t, cacheStack(t, storage), "caching the graph empties the cache stack")
+
+	require.NoError(t, storage.RemoveAllCaches(ctx))
+	assert.Equal(t, []uint32{a.ID, b.ID}, cacheStack(t, storage), "removed caches are on the cache stack")
+}
+
+func testCaches(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	require.NoError(t, storage.SaveCache(ctx, graph.NewNodeCache(1, roaring.BitmapOf(2), roaring.BitmapOf(3))))
+	require.NoError(t, storage.SaveCaches(ctx, []*graph.NodeCache{
+		graph.NewNodeCache(2, roaring.New(), roaring.BitmapOf(1, 3)),
+		graph.NewNodeCache(3, roaring.BitmapOf(1, 2), roaring.New()),
+	}))
+
+	cache, err := storage.GetCache(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), cache.ID)
+	assert.Equal(t, []uint32{2}, cache.AllParents.ToArray())
+	assert.Equal(t, []uint32{3}, cache.AllChildren.ToArray())
+
+	caches, err := storage.GetCaches(ctx, []uint32{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, caches, 3)
+	assert.Equal(t, []uint32{1, 3}, caches[2].AllChildren.ToArray())
+
+	// Saving a cache again replaces it.
+	require.NoError(t, storage.SaveCache(ctx, graph.NewNodeCache(1, roaring.New(), roaring.New())))
+	cache, err = storage.GetCache(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, cache.AllParents.IsEmpty())
+
+	require.NoError(t, storage.RemoveAllCaches(ctx))
+	_, err = storage.GetCache(ctx, 1)
+	assert.ErrorIs(t, err, graph.ErrCacheNotFound)
+	caches, err = storage.GetCaches(ctx, []uint32{1, 2, 3})
+	require.NoError(t, err)
+	assert.Empty(t, caches)
+}
+
+func testCustomData(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, "tag1", "key1", "field1", []byte("value1")))
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, "tag1", "key1", "field2", []byte("value2")))
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, "tag1", "key1", "field1", []byte("updated")))
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, "tag1", "key2", "field1", []byte("other")))
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, "tag2", "key1", "field1", []byte("tag2")))
+
+	data, err := storage.GetCustomData(ctx, "tag1", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"field1": []byte("updated"), "field2": []byte("value2")}, data)
+	data, err = storage.GetCustomData(ctx, "tag2", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"field1": []byte("tag2")}, data)
+
+	keys, err := storage.GetCustomDataKeys(ctx)
+	require.NoError(t, err)
+	for tag := range keys {
+		sort.Strings(keys[tag])
+	}
+	assert.Equal(t, map[string][]string{"tag1": {"key1", "key2"}, "tag2": {"key1"}}, keys)
+}
+
+func testDependencies(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", nil, "a")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "library", nil, "b")
+	require.NoError(t, err)
+
+	require.NoError(t, storage.AddDependency(ctx, a.ID, b.ID))
+	require.NoError(t, storage.AddDependency(ctx, a.ID, b.ID), "adding an existing edge is a no-op")
+	nodes, err := storage.GetNodes(ctx, []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{b.ID}, nodes[a.ID].Children.ToArray())
+	assert.Equal(t, []uint32{a.ID}, nodes[b.ID].Parents.ToArray())
+
+	require.NoError(t, storage.RemoveDependency(ctx, a.ID, b.ID))
+	nodes, err = storage.GetNodes(ctx, []uint32{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.True(t, nodes[a.ID].Children.IsEmpty())
+	assert.True(t, nodes[b.ID].Parents.IsEmpty())
+
+	assert.ErrorIs(t, storage.AddDependency(ctx, a.ID, a.ID), graph.ErrSelfDependency)
+}
+
+func testAliases(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+
+	require.NoError(t, storage.AddAliases(ctx, a.ID, []string{"cpe:2.3:a:a:a:1.0.0:*:*:*:*:*:*:*", "sha256:aa", "sha256:aa"}))
+	require.NoError(t, storage.AddAliases(ctx, a.ID, nil))
+	// An alias keeps resolving to the node it was first added to.
+	require.NoError(t, storage.AddAliases(ctx, b.ID, []string{"sha256:aa", "sha256:bb"}))
+	assert.ErrorIs(t, storage.AddAliases(ctx, 1000, []string{"sha256:cc"}), graph.ErrNodeNotFound)
+
+	for alias, want := range map[string]uint32{"cpe:2.3:a:a:a:1.0.0:*:*:*:*:*:*:*": a.ID, "sha256:aa": a.ID, "sha256:bb": b.ID} {
+		id, err := storage.AliasToID(ctx, alias)
+		require.NoError(t, err, alias)
+		assert.Equal(t, want, id, alias)
+	}
+	_, err = storage.AliasToID(ctx, "sha256:cc")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	_, err = storage.AliasToID(ctx, a.Name)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound, "names aren't aliases")
+	_, err = storage.NameToID(ctx, "sha256:aa")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound, "aliases aren't names")
+
+	aliases, err := storage.GetAliases(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cpe:2.3:a:a:a:1.0.0:*:*:*:*:*:*:*", "sha256:aa"}, aliases)
+	aliases, err = storage.GetAliases(ctx, 1000)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	id, err := graph.ResolveName(ctx, storage, "sha256:bb")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, id)
+	id, err = graph.ResolveName(ctx, storage, a.Name)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, id)
+
+	require.NoError(t, storage.DeleteNode(ctx, a.ID))
+	_, err = storage.AliasToID(ctx, "sha256:aa")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound, "the aliases of a deleted node are removed")
+	aliases, err = storage.GetAliases(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	// The aliases of a deleted node can be added to another one.
+	require.NoError(t, storage.AddAliases(ctx, b.ID, []string{"sha256:aa"}))
+	aliases, err = storage.GetAliases(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha256:aa", "sha256:bb"}, aliases)
+}
+
+func testDeleteNode(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, storage.AddDependency(ctx, a.ID, b.ID))
+
+	assert.ErrorIs(t, storage.DeleteNode(ctx, b.ID), graph.ErrNodeHasEdges)
+	assert.ErrorIs(t, storage.DeleteNode(ctx, 1000), graph.ErrNodeNotFound)
+
+	require.NoError(t, graph.DeleteNode(ctx, storage, b.ID))
+	_, err = storage.GetNode(ctx, b.ID)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	_, err = storage.NameToID(ctx, b.Name)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	_, err = storage.GetCache(ctx, b.ID)
+	assert.ErrorIs(t, err, graph.ErrCacheNotFound)
+	keys, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{a.ID}, keys)
+	nodes, err := storage.GetNodesByGlob(ctx, "pkg:npm/*")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, a.ID, nodes[0].ID)
+	assert.NotContains(t, cacheStack(t, storage), b.ID)
+
+	got, err := storage.GetNode(ctx, a.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Children.IsEmpty(), "the edges of a deleted node are removed")
+
+	// The name can be used again and gets a new ID.
+	again, err := graph.AddNode(ctx, storage, "library", nil, b.Name)
+	require.NoError(t, err)
+	assert.NotEqual(t, b.ID, again.ID)
+}
+
+func testUpdateNodeMetadata(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	a, err := graph.AddNode(ctx, storage, "library", map[string]any{"name": "a"}, "pkg:npm/a@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, "library", nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, storage.AddDependency(ctx, a.ID, b.ID))
+
+	updated, err := storage.UpdateNodeMetadata(ctx, a.ID, func(metadata any) (any, error) {
+		assert.Equal(t, "a", metadata.(map[string]any)["name"])
+		return map[string]any{"name": "a", "licenses": []any{"MIT"}}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, updated.ID)
+	assert.Equal(t, []uint32{b.ID}, updated.Children.ToArray(), "the edges are kept")
+
+	got, err := storage.GetNode(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{b.ID}, got.Children.ToArray())
+	data, err := json.Marshal(got.Metadata)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"a","licenses":["MIT"]}`, string(data))
+
+	failed := errors.New("failed")
+	_, err = storage.UpdateNodeMetadata(ctx, a.ID, func(any) (any, error) { return nil, failed })
+	assert.ErrorIs(t, err, failed)
+	_, err = storage.UpdateNodeMetadata(ctx, 1000, func(metadata any) (any, error) { return metadata, nil })
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+}
+
+func testSchemaVersion(t *testing.T, storage graph.Storage) {
+	ctx := context.Background()
+	version, err := storage.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version, "a new storage has no schema version")
+
+	require.NoError(t, storage.SetSchemaVersion(ctx, 3))
+	require.NoError(t, storage.SetSchemaVersion(ctx, 2))
+	version, err = storage.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version, "the version can be moved back by migrations that are undone")
+
+	// The version is not part of the graph.
+	keys, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	customKeys, err := storage.GetCustomDataKeys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, customKeys)
+	nodes, err := storage.GetNodesByGlob(ctx, "*")
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+}
+
+// cacheStack returns the sorted, de-duplicated IDs on the cache stack.
+func cacheStack(t *testing.T, storage graph.Storage) []uint32 {
+	ctx := context.Background()
+	t.Helper()
+	ids, err := storage.ToBeCached(ctx)
+	require.NoError(t, err)
+	return roaring.BitmapOf(ids...).ToArray()
+}
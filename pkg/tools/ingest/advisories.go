@@ -0,0 +1,209 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+const (
+	// AdvisoriesTag is the custom data tag under which every ingested OSV record is stored,
+	// keyed by its ID, so it can be matched against library nodes added after it.
+	AdvisoriesTag = "advisories"
+
+	advisoryRecordKey = "record"
+	advisorySHA256Key = "sha256"
+
+	// AdvisoryPackagesTag is the custom data tag of the advisory index, which finds the
+	// advisories affecting a package. Its keys are packageKey, and every entry maps the ID of
+	// an advisory to its modified timestamp.
+	AdvisoryPackagesTag = "advisory-packages"
+)
+
+// RelinkResult counts the advisories re-matched by Relink and the edges they asserted.
+type RelinkResult struct {
+	// Advisories is the number of stored advisories that were matched.
+	Advisories int
+	// Links is the number of edges from library nodes to vulnerabilities.
+	Links int
+}
+
+// storeAdvisory stores the OSV record with the given SHA-256 digest and adds it to the
+// advisory index under the packages it affects.
+func storeAdvisory(ctx context.Context, storage graph.Storage, vuln Vulnerability, sha256 string) error {
+	record, err := json.Marshal(vuln)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vulnerabilityType data: %w", err)
+	}
+	if err := storage.AddOrUpdateCustomData(ctx, AdvisoriesTag, vuln.ID, advisoryRecordKey, record); err != nil {
+		return fmt.Errorf("failed to store advisory %s: %w", vuln.ID, err)
+	}
+	if err := storage.AddOrUpdateCustomData(ctx, AdvisoriesTag, vuln.ID, advisorySHA256Key, []byte(sha256)); err != nil {
+		return fmt.Errorf("failed to store advisory %s: %w", vuln.ID, err)
+	}
+	for _, affected := range vuln.Affected {
+		key := affectedPackageKey(affected)
+		if key == "" {
+			continue
+		}
+		if err := storage.AddOrUpdateCustomData(ctx, AdvisoryPackagesTag, key, vuln.ID, []byte(vuln.Modified)); err != nil {
+			return fmt.Errorf("failed to index advisory %s: %w", vuln.ID, err)
+		}
+	}
+	return nil
+}
+
+// loadAdvisory returns the stored OSV record with the given ID and the SHA-256 digest of the
+// payload it was ingested from, or false if no such record was ingested.
+func loadAdvisory(ctx context.Context, storage graph.Storage, id string) (Vulnerability, string, bool, error) {
+	vuln := Vulnerability{}
+	data, err := storage.GetCustomData(ctx, AdvisoriesTag, id)
+	if err != nil {
+		return vuln, "", false, fmt.Errorf("failed to get advisory %s: %w", id, err)
+	}
+	record, ok := data[advisoryRecordKey]
+	if !ok {
+		return vuln, "", false, nil
+	}
+	if err := json.Unmarshal(record, &vuln); err != nil {
+		return vuln, "", false, fmt.Errorf("failed to unmarshal advisory %s: %w", id, err)
+	}
+	return vuln, string(data[advisorySHA256Key]), true, nil
+}
+
+// affectedPackageKey returns the key of the package an affected entry names, preferring the
+// purl like affectedNodes does, or "" if it names none.
+func affectedPackageKey(affected Affected) string {
+	if affected.Package.Purl != "" {
+		if purlInfo, err := PURLToPackage(affected.Package.Purl); err == nil {
+			return packageKey(purlInfo.Ecosystem, purlInfo.Name)
+		}
+	}
+	if affected.Package.Ecosystem == "" || affected.Package.Name == "" {
+		return ""
+	}
+	return packageKey(affected.Package.Ecosystem, affected.Package.Name)
+}
+
+// linkAdvisories links the library nodes with the given IDs to the stored advisories that
+// affect them, so nodes added after an advisory are linked like the ones it was matched
+// against when it was ingested. The edges are added to the contributions of the advisories'
+// documents.
+func linkAdvisories(ctx context.Context, storage graph.Storage, o *options, ids []uint32) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	nodes, err := storage.GetNodes(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	packages := packageIndex(nodes)
+
+	advisories := map[string]bool{}
+	for key := range packages {
+		entries, err := storage.GetCustomData(ctx, AdvisoryPackagesTag, key)
+		if err != nil {
+			return fmt.Errorf("failed to look up advisories of %s: %w", key, err)
+		}
+		for id := range entries {
+			advisories[id] = true
+		}
+	}
+	if len(advisories) == 0 {
+		return nil
+	}
+	sorted := make([]string, 0, len(advisories))
+	for id := range advisories {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	lookup := indexLookup(packages)
+	for _, id := range sorted {
+		vuln, _, ok, err := loadAdvisory(ctx, storage, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		contribution := NewContribution()
+		if err := matchVulnerability(ctx, storage, o, vuln, lookup, contribution); err != nil {
+			return err
+		}
+		if contribution.Edges.IsEmpty() {
+			continue
+		}
+		if err := extendContribution(ctx, storage, tools.OSVDocumentType+":"+vuln.ID, contribution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extendContribution adds the nodes and edges of the contribution to the one of the document.
+func extendContribution(ctx context.Context, storage graph.Storage, name string, contribution *Contribution) error {
+	previous, err := loadContribution(ctx, storage, name)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		previous = NewContribution()
+	}
+	previous.Nodes.Or(contribution.Nodes)
+	previous.Edges.Or(contribution.Edges)
+	for id, expression := range contribution.Licenses {
+		previous.Licenses[id] = expression
+	}
+	return saveContribution(ctx, storage, name, previous)
+}
+
+// Relink matches every stored advisory against the library nodes currently in the graph, as if
+// it was ingested again. Edges the advisories no longer assert are removed.
+func Relink(ctx context.Context, storage graph.Storage, opts ...Option) (RelinkResult, error) {
+	o := newOptions(opts)
+	result := RelinkResult{}
+
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	ids := append([]string(nil), keys[AdvisoriesTag]...)
+	sort.Strings(ids)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	index, err := loadPackageIndex(ctx, storage)
+	if err != nil {
+		return result, err
+	}
+	lookup := indexLookup(index)
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		vuln, sha256, ok, err := loadAdvisory(ctx, storage, id)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			continue
+		}
+		contribution := NewContribution()
+		if err := matchVulnerability(ctx, storage, o, vuln, lookup, contribution); err != nil {
+			return result, fmt.Errorf("failed to relink %s: %w", id, err)
+		}
+		if err := recordVulnerability(ctx, storage, vuln, sha256, contribution); err != nil {
+			return result, fmt.Errorf("failed to relink %s: %w", id, err)
+		}
+		result.Advisories++
+		result.Links += int(contribution.Edges.GetCardinality())
+	}
+	return result, nil
+}
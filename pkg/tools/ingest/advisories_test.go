@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkAdvisoriesAfterSBOM(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+
+	// The advisories are ingested before any SBOM names their packages.
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-1", "modified": "2024-01-01T00:00:00Z",
+	  "affected": [{"package": {"ecosystem": "npm", "name": "b"}, "versions": ["1.0.0"]}]}`)))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-2", "modified": "2024-01-01T00:00:00Z",
+	  "affected": [{"package": {"ecosystem": "npm", "name": "c"}, "versions": ["2.0.0"]}]}`)))
+	_, err := storage.NameToID(ctx, "TEST-1")
+	assert.Error(t, err, "no vulnerability node without affected nodes")
+
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:11111111-1111-1111-1111-111111111111", "app", "b", "c")))
+
+	b := nodeByName(t, storage, "pkg:npm/b@1.0.0")
+	vuln := nodeByName(t, storage, "TEST-1")
+	assert.Equal(t, tools.VulnerabilityType, vuln.Type)
+	assert.Equal(t, []uint32{vuln.ID}, b.Children.ToArray())
+	assert.Empty(t, nodeByName(t, storage, "pkg:npm/c@1.0.0").Children.ToArray(), "c@1.0.0 isn't affected")
+	_, err = storage.NameToID(ctx, "TEST-2")
+	assert.Error(t, err)
+
+	// The edge is asserted by the advisory, not the SBOM.
+	_, contribution, err := GetContribution(ctx, storage, tools.OSVDocumentType+":TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, []Edge{{From: b.ID, To: vuln.ID}}, contribution.EdgeList())
+}
+
+func TestRelink(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+
+	result, err := Relink(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, RelinkResult{}, result)
+
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:11111111-1111-1111-1111-111111111111", "app", "b", "c")))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-1",
+	  "affected": [{"package": {"ecosystem": "npm", "name": "b"}, "versions": ["1.0.0"]}]}`)))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-2",
+	  "affected": [{"package": {"ecosystem": "npm", "name": "d"}, "versions": ["1.0.0"]}]}`)))
+
+	// Nodes that bypass ingestion, like ones added before advisories were stored, are only
+	// linked by relinking.
+	d, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:npm/d@1.0.0")
+	require.NoError(t, err)
+	// An edge the advisory doesn't assert is removed.
+	c := nodeByName(t, storage, "pkg:npm/c@1.0.0")
+	vuln := nodeByName(t, storage, "TEST-1")
+	require.NoError(t, c.SetDependency(ctx, storage, vuln))
+	_, contribution, err := GetContribution(ctx, storage, tools.OSVDocumentType+":TEST-1")
+	require.NoError(t, err)
+	contribution.addEdge(c.ID, vuln.ID)
+	require.NoError(t, saveContribution(ctx, storage, tools.OSVDocumentType+":TEST-1", contribution))
+
+	result, err = Relink(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, RelinkResult{Advisories: 2, Links: 2}, result)
+
+	assert.Equal(t, []uint32{vuln.ID}, nodeByName(t, storage, "pkg:npm/b@1.0.0").Children.ToArray())
+	assert.Empty(t, nodeByName(t, storage, "pkg:npm/c@1.0.0").Children.ToArray())
+	assert.Equal(t, []uint32{nodeByName(t, storage, "TEST-2").ID}, nodeByName(t, storage, d.Name).Children.ToArray())
+
+	unchanged, err := Unchanged(ctx, storage, Digest([]byte(`{"id": "TEST-1",
+	  "affected": [{"package": {"ecosystem": "npm", "name": "b"}, "versions": ["1.0.0"]}]}`)))
+	require.NoError(t, err)
+	assert.True(t, unchanged, "relinking keeps the ingested payload")
+}
@@ -0,0 +1,97 @@
+package ingest
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+// SWIDAliasPrefix is prepended to SWID tag ids to tell them apart from other aliases, since a
+// tag id can be any string.
+const SWIDAliasPrefix = "swid:"
+
+// nodeAliases returns the other identifiers of an SBOM component, by which its node can be
+// found in addition to its name: the purl as the SBOM spelled it, CPEs, gitoids, SWID tag ids
+// and hashes like sha256:<hex>. CPEs and hashes are lowercased, since they are compared
+// without case.
+func nodeAliases(node *sbom.Node, name string, swidTags map[string]string) []string {
+	aliases := map[string]bool{}
+	add := func(alias string) {
+		if alias != "" && alias != name {
+			aliases[alias] = true
+		}
+	}
+
+	for identifierType, identifier := range node.GetIdentifiers() {
+		switch sbom.SoftwareIdentifierType(identifierType) {
+		case sbom.SoftwareIdentifierType_CPE22, sbom.SoftwareIdentifierType_CPE23:
+			add(strings.ToLower(identifier))
+		default:
+			add(identifier)
+		}
+	}
+	for _, reference := range node.GetExternalReferences() {
+		if reference.GetType() == sbom.ExternalReference_SECURITY_SWID && reference.GetUrl() != "" {
+			// SPDX locators of SWID tags may already carry the prefix.
+			add(SWIDAliasPrefix + strings.TrimPrefix(reference.GetUrl(), SWIDAliasPrefix))
+		}
+	}
+	if tagID := swidTags[node.GetId()]; tagID != "" {
+		add(SWIDAliasPrefix + tagID)
+	}
+	for algorithm, value := range node.GetHashes() {
+		algorithmName, ok := sbom.HashAlgorithm_name[algorithm]
+		if !ok || algorithm == int32(sbom.HashAlgorithm_UNKNOWN) || value == "" {
+			continue
+		}
+		add(strings.ToLower(strings.ReplaceAll(algorithmName, "_", "-") + ":" + value))
+	}
+
+	result := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		result = append(result, alias)
+	}
+	sort.Strings(result)
+	return result
+}
+
+type cycloneDXComponent struct {
+	BOMRef string `json:"bom-ref"`
+	SWID   struct {
+		TagID string `json:"tagId"`
+	} `json:"swid"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// cycloneDXSWIDTags returns the SWID tag ids of the components of a CycloneDX SBOM by bom-ref,
+// which protobom uses as node ID, or nothing if the data isn't a CycloneDX JSON SBOM. protobom
+// doesn't read SWID tags of CycloneDX components.
+func cycloneDXSWIDTags(data []byte) map[string]string {
+	var bom struct {
+		Metadata struct {
+			Component *cycloneDXComponent `json:"component"`
+		} `json:"metadata"`
+		Components []cycloneDXComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return nil
+	}
+
+	tags := map[string]string{}
+	var collect func(components []cycloneDXComponent)
+	collect = func(components []cycloneDXComponent) {
+		for _, component := range components {
+			if component.BOMRef != "" && component.SWID.TagID != "" {
+				tags[component.BOMRef] = component.SWID.TagID
+			}
+			collect(component.Components)
+		}
+	}
+	if bom.Metadata.Component != nil {
+		collect([]cycloneDXComponent{*bom.Metadata.Component})
+	}
+	collect(bom.Components)
+	return tags
+}
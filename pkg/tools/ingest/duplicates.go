@@ -0,0 +1,180 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+// MergeDuplicatePURLs merges library nodes whose purls are spellings of the same normalized
+// purl into a single node named by it. The merged node gets the edges of all duplicates and
+// their metadata merged with graph.MergeDeep, lowest ID first, and takes their place in the
+// contributions of the documents. The names and aliases of the duplicates become aliases of
+// the merged node. It returns the number of removed duplicates.
+func MergeDuplicatePURLs(ctx context.Context, storage graph.Storage, normalizer *purl.Normalizer) (int, error) {
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+
+	groups := map[string][]*graph.Node{}
+	for _, node := range nodes {
+		if node.Type != tools.LibraryType || !strings.HasPrefix(node.Name, pkg) {
+			continue
+		}
+		name, err := normalizer.Normalize(node.Name)
+		if err != nil {
+			continue
+		}
+		groups[name] = append(groups[name], node)
+	}
+	names := make([]string, 0, len(groups))
+	for name, group := range groups {
+		if len(group) > 1 || group[0].Name != name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	replaced := map[uint32]uint32{}
+	survivors := make([]uint32, 0, len(names))
+	for _, name := range names {
+		group := groups[name]
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+
+		var survivor *graph.Node
+		for _, node := range group {
+			if node.Name == name {
+				survivor = node
+			}
+		}
+		if survivor == nil {
+			if survivor, err = graph.AddNode(ctx, storage, tools.LibraryType, nil, name); err != nil {
+				return 0, fmt.Errorf("failed to add node %s: %w", name, err)
+			}
+			if err := indexPackage(ctx, storage, survivor); err != nil {
+				return 0, err
+			}
+		}
+		for _, duplicate := range group {
+			if duplicate.ID == survivor.ID {
+				continue
+			}
+			if survivor, err = mergeDuplicate(ctx, storage, survivor, duplicate); err != nil {
+				return 0, err
+			}
+			replaced[duplicate.ID] = survivor.ID
+		}
+		survivors = append(survivors, survivor.ID)
+	}
+
+	if err := replaceContributionNodes(ctx, storage, replaced); err != nil {
+		return 0, err
+	}
+	// A survivor named by a purl no duplicate had may be affected by advisories none of them were.
+	if err := linkAdvisories(ctx, storage, newOptions(nil), survivors); err != nil {
+		return 0, err
+	}
+	return len(replaced), nil
+}
+
+// mergeDuplicate moves the edges and metadata of the duplicate to the survivor and deletes the
+// duplicate.
+func mergeDuplicate(ctx context.Context, storage graph.Storage, survivor, duplicate *graph.Node) (*graph.Node, error) {
+	survivor, err := graph.MergeNodeMetadata(ctx, storage, survivor, duplicate.Metadata, graph.MergeDeep)
+	if err != nil {
+		return nil, err
+	}
+	// The duplicate is read again, merging an earlier duplicate may have moved its edges.
+	duplicate, err = storage.GetNode(ctx, duplicate.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range duplicate.Children.ToArray() {
+		if err := storage.RemoveDependency(ctx, duplicate.ID, child); err != nil {
+			return nil, fmt.Errorf("failed to remove edge %d -> %d: %w", duplicate.ID, child, err)
+		}
+		if child == survivor.ID {
+			continue
+		}
+		if err := storage.AddDependency(ctx, survivor.ID, child); err != nil {
+			return nil, fmt.Errorf("failed to add edge %d -> %d: %w", survivor.ID, child, err)
+		}
+	}
+	for _, parent := range duplicate.Parents.ToArray() {
+		if err := storage.RemoveDependency(ctx, parent, duplicate.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove edge %d -> %d: %w", parent, duplicate.ID, err)
+		}
+		if parent == survivor.ID {
+			continue
+		}
+		if err := storage.AddDependency(ctx, parent, survivor.ID); err != nil {
+			return nil, fmt.Errorf("failed to add edge %d -> %d: %w", parent, survivor.ID, err)
+		}
+	}
+	aliases, err := storage.GetAliases(ctx, duplicate.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases of node %d: %w", duplicate.ID, err)
+	}
+	if err := storage.DeleteNode(ctx, duplicate.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete node %d: %w", duplicate.ID, err)
+	}
+	// The duplicate's spelling of the purl keeps finding the node, like it does for purls
+	// normalized during ingestion.
+	if err := storage.AddAliases(ctx, survivor.ID, append(aliases, duplicate.Name)); err != nil {
+		return nil, fmt.Errorf("failed to add aliases to node %d: %w", survivor.ID, err)
+	}
+	return storage.GetNode(ctx, survivor.ID)
+}
+
+// replaceContributionNodes replaces the IDs of merged nodes in the contributions of all
+// documents by the IDs of the nodes they were merged into.
+func replaceContributionNodes(ctx context.Context, storage graph.Storage, replaced map[uint32]uint32) error {
+	if len(replaced) == 0 {
+		return nil
+	}
+	contributions, err := loadContributions(ctx, storage)
+	if err != nil {
+		return err
+	}
+	replace := func(id uint32) uint32 {
+		if to, ok := replaced[id]; ok {
+			return to
+		}
+		return id
+	}
+	for name, contribution := range contributions {
+		updated := NewContribution()
+		for _, id := range contribution.Nodes.ToArray() {
+			updated.addNode(replace(id))
+		}
+		for _, edge := range contribution.EdgeList() {
+			// Edges between merged duplicates are gone.
+			if from, to := replace(edge.From), replace(edge.To); from != to {
+				updated.addEdge(from, to)
+			}
+		}
+		for id, expression := range contribution.Licenses {
+			updated.addLicense(replace(id), expression)
+		}
+		if updated.Nodes.Equals(contribution.Nodes) && updated.Edges.Equals(contribution.Edges) {
+			continue
+		}
+		if err := saveContribution(ctx, storage, name, updated); err != nil {
+			return err
+		}
+		if err := saveLicenseExpressions(ctx, storage, name, contribution, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDuplicatePURLs(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	add := func(name string, metadata any) *graph.Node {
+		node, err := graph.AddNode(ctx, storage, tools.LibraryType, metadata, name)
+		require.NoError(t, err)
+		return node
+	}
+
+	app := add("pkg:npm/app@1.0.0", nil)
+	unescaped := add("pkg:npm/@scope/x@1.0.0", map[string]any{"licenses": []any{"MIT"}})
+	canonical := add("pkg:npm/%40scope/x@1.0.0", map[string]any{"licenses": []any{"ISC"}})
+	y := add("pkg:npm/y@1.0.0", nil)
+	curl := add("pkg:deb/debian/curl@7.50.3-1?distro=jessie&arch=i386", map[string]any{"name": "curl"})
+	require.NoError(t, storage.AddAliases(ctx, curl.ID, []string{"cpe:2.3:a:haxx:curl:7.50.3:*:*:*:*:*:*:*"}))
+	curlWithRepository := add("pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie&repository_url=https://deb.debian.org", map[string]any{"version": "7.50.3-1"})
+	invalid := add("pkg:curl@7.50.3-1", nil)
+	require.NoError(t, app.SetDependency(ctx, storage, unescaped))
+	require.NoError(t, unescaped.SetDependency(ctx, storage, canonical))
+	require.NoError(t, canonical.SetDependency(ctx, storage, y))
+	require.NoError(t, app.SetDependency(ctx, storage, curl))
+	require.NoError(t, curl.SetDependency(ctx, storage, curlWithRepository))
+
+	contribution := NewContribution()
+	contribution.addEdge(app.ID, unescaped.ID)
+	contribution.addEdge(unescaped.ID, canonical.ID)
+	require.NoError(t, recordDocument(ctx, storage, tools.SBOMDocumentType, "sbom:app", DocumentMetadata{}, contribution))
+
+	merged, err := MergeDuplicatePURLs(ctx, storage, purl.DefaultNormalizer)
+	require.NoError(t, err)
+	assert.Equal(t, 3, merged)
+
+	// The duplicates of a canonical purl are merged into its node.
+	_, err = storage.GetNode(ctx, unescaped.ID)
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	x := nodeByName(t, storage, "pkg:npm/%40scope/x@1.0.0")
+	assert.Equal(t, canonical.ID, x.ID)
+	assert.Equal(t, []uint32{app.ID}, x.Parents.ToArray())
+	assert.Equal(t, []uint32{y.ID}, x.Children.ToArray())
+	assert.Equal(t, map[string]any{"licenses": []any{"ISC", "MIT"}}, x.Metadata)
+
+	// Without a canonical node, the duplicates are merged into a new one.
+	for _, id := range []uint32{curl.ID, curlWithRepository.ID} {
+		_, err = storage.GetNode(ctx, id)
+		assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+	}
+	mergedCurl := nodeByName(t, storage, "pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie")
+	assert.Equal(t, []uint32{app.ID}, mergedCurl.Parents.ToArray())
+	assert.True(t, mergedCurl.Children.IsEmpty())
+	assert.Equal(t, map[string]any{"name": "curl", "version": "7.50.3-1"}, mergedCurl.Metadata)
+	// The names and aliases of the duplicates become aliases of the merged node.
+	aliases, err := storage.GetAliases(ctx, mergedCurl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"cpe:2.3:a:haxx:curl:7.50.3:*:*:*:*:*:*:*",
+		"pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie&repository_url=https://deb.debian.org",
+		"pkg:deb/debian/curl@7.50.3-1?distro=jessie&arch=i386",
+	}, aliases)
+	app = nodeByName(t, storage, "pkg:npm/app@1.0.0")
+	assert.ElementsMatch(t, []uint32{x.ID, mergedCurl.ID}, app.Children.ToArray())
+
+	// Names that aren't valid purls are left alone.
+	_, err = storage.GetNode(ctx, invalid.ID)
+	assert.NoError(t, err)
+
+	_, updated, err := GetContribution(ctx, storage, "sbom:app")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{app.ID, x.ID}, updated.Nodes.ToArray())
+	assert.Equal(t, []Edge{{From: app.ID, To: x.ID}}, updated.EdgeList())
+
+	// A second run finds nothing to merge.
+	merged, err = MergeDuplicatePURLs(ctx, storage, purl.DefaultNormalizer)
+	require.NoError(t, err)
+	assert.Zero(t, merged)
+}
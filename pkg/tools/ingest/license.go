@@ -0,0 +1,43 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/bitbomdev/minefield/pkg/tools/license"
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+// addLicenses links the library node of an SBOM component to a license node for every license
+// of its effective license expression, and adds the expression to the contribution. License
+// nodes are named by the license, with its exception if it has one. An expression that isn't
+// a valid SPDX expression gets a single license node named by the expression.
+func addLicenses(ctx context.Context, storage graph.Storage, library *graph.Node, node *sbom.Node, contribution *Contribution) error {
+	expression := license.Effective(node.GetLicenseConcluded(), node.GetLicenses())
+	if expression == "" {
+		return nil
+	}
+	licenses := []*license.Expression{{License: expression}}
+	if parsed, err := license.Parse(expression); err == nil {
+		expression, licenses = parsed.String(), parsed.Licenses()
+	}
+	contribution.addLicense(library.ID, expression)
+
+	for _, term := range licenses {
+		licenseNode, err := graph.AddNode(ctx, storage, tools.LicenseType, term, term.String())
+		if err != nil {
+			return fmt.Errorf("failed to add license node %s: %w", term, err)
+		}
+		if licenseNode.Type != tools.LicenseType {
+			return fmt.Errorf("license %s is the name of a %s node", term, licenseNode.Type)
+		}
+		if err := library.SetDependency(ctx, storage, licenseNode); err != nil {
+			return fmt.Errorf("failed to add edge %s -> %s: %w", library.Name, licenseNode.Name, err)
+		}
+		contribution.addNode(licenseNode.ID)
+		contribution.addEdge(library.ID, licenseNode.ID)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/bitbomdev/minefield/pkg/tools/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// licensedSBOM returns a CycloneDX SBOM of pkg:npm/app@1.0.0 depending on pkg:npm/b@1.0.0,
+// with the given licenses.
+func licensedSBOM(appLicenses, bLicenses string) []byte {
+	return []byte(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "serialNumber": "urn:uuid:33333333-3333-3333-3333-333333333333",
+  "version": 1,
+  "metadata": {
+    "component": {"bom-ref": "app", "type": "application", "name": "app", "version": "1.0.0", "purl": "pkg:npm/app@1.0.0",
+      "licenses": ` + appLicenses + `}
+  },
+  "components": [{"bom-ref": "b", "type": "library", "name": "b", "version": "1.0.0", "purl": "pkg:npm/b@1.0.0",
+    "licenses": ` + bLicenses + `}],
+  "dependencies": [{"ref": "app", "dependsOn": ["b"]}]
+}`)
+}
+
+func TestSBOMLicenses(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+
+	require.NoError(t, SBOM(ctx, storage, licensedSBOM(`[{"license": {"id": "MIT"}}]`,
+		`[{"expression": "(Apache-2.0 or GPL-2.0-only WITH Classpath-exception-2.0) AND MIT"}]`)))
+
+	app, b := nodeByName(t, storage, "pkg:npm/app@1.0.0"), nodeByName(t, storage, "pkg:npm/b@1.0.0")
+	mit, apache := nodeByName(t, storage, "MIT"), nodeByName(t, storage, "Apache-2.0")
+	gpl := nodeByName(t, storage, "GPL-2.0-only WITH Classpath-exception-2.0")
+	assert.Equal(t, tools.LicenseType, gpl.Type)
+	assert.ElementsMatch(t, []uint32{b.ID, mit.ID}, app.Children.ToArray())
+	assert.ElementsMatch(t, []uint32{mit.ID, apache.ID, gpl.ID}, b.Children.ToArray())
+
+	expressions, err := license.Expressions(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"pkg:npm/app@1.0.0": {"MIT"},
+		"pkg:npm/b@1.0.0":   {"(Apache-2.0 OR GPL-2.0-only WITH Classpath-exception-2.0) AND MIT"},
+	}, expressions)
+
+	require.NoError(t, graph.Cache(ctx, storage))
+	keys, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	nodes, err := storage.GetNodes(ctx, keys)
+	require.NoError(t, err)
+	caches, err := storage.GetCaches(ctx, keys)
+	require.NoError(t, err)
+	result, err := graph.ParseAndExecute(ctx, "dependencies license pkg:npm/app@1.0.0", storage, "", nodes, caches, true, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{mit.ID, apache.ID, gpl.ID}, result.ToArray())
+
+	// A new version of the SBOM replaces the licenses it asserted. Licenses that aren't SPDX
+	// expressions get a license node of their own.
+	require.NoError(t, SBOM(ctx, storage, licensedSBOM(`[{"license": {"id": "Example Commercial License"}}]`, `[]`)))
+	app, b = nodeByName(t, storage, "pkg:npm/app@1.0.0"), nodeByName(t, storage, "pkg:npm/b@1.0.0")
+	commercial := nodeByName(t, storage, "Example Commercial License")
+	assert.Equal(t, tools.LicenseType, commercial.Type)
+	assert.ElementsMatch(t, []uint32{b.ID, commercial.ID}, app.Children.ToArray())
+	assert.Empty(t, b.Children.ToArray())
+	for _, name := range []string{"MIT", "Apache-2.0"} {
+		_, err := storage.NameToID(ctx, name)
+		assert.ErrorIs(t, err, graph.ErrNodeNotFound, "license nodes no document asserts are removed")
+	}
+	expressions, err = license.Expressions(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"pkg:npm/app@1.0.0": {"Example Commercial License"}}, expressions,
+		"expressions no document asserts are removed")
+
+	// Another SBOM that disagrees doesn't replace the expression of the first.
+	other := strings.Replace(string(licensedSBOM(`[{"license": {"id": "MIT"}}]`, `[]`)), "33333333-", "44444444-", 1)
+	require.NoError(t, SBOM(ctx, storage, []byte(other)))
+	expressions, err = license.Expressions(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"pkg:npm/app@1.0.0": {"Example Commercial License", "MIT"}}, expressions)
+}
@@ -0,0 +1,336 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/package-url/packageurl-go"
+	"golang.org/x/mod/modfile"
+)
+
+// Lockfile formats.
+const (
+	GoModFormat            = "go.mod"
+	GoSumFormat            = "go.sum"
+	GoModGraphFormat       = "go-mod-graph"
+	PackageLockFormat      = "package-lock.json"
+	PNPMLockFormat         = "pnpm-lock.yaml"
+	CargoLockFormat        = "Cargo.lock"
+	PoetryLockFormat       = "poetry.lock"
+	RequirementsFileFormat = "requirements.txt"
+)
+
+// LockfileFormats are the lockfile formats Lockfile ingests.
+var LockfileFormats = []string{
+	GoModFormat, GoSumFormat, GoModGraphFormat, PackageLockFormat, PNPMLockFormat,
+	CargoLockFormat, PoetryLockFormat, RequirementsFileFormat,
+}
+
+var lockfileParsers = map[string]func([]byte) (*lockfileGraph, error){
+	GoModFormat:            parseGoMod,
+	GoSumFormat:            parseGoSum,
+	GoModGraphFormat:       parseGoModGraph,
+	PackageLockFormat:      parsePackageLock,
+	PNPMLockFormat:         parsePNPMLock,
+	CargoLockFormat:        parseCargoLock,
+	PoetryLockFormat:       parsePoetryLock,
+	RequirementsFileFormat: parseRequirements,
+}
+
+// DetectLockfile returns the format of the lockfile with the given file name and content. The
+// file name is enough for the usual names, other files are recognized by their content, except
+// for requirements files, whose name must start with requirements and end with .txt.
+func DetectLockfile(name string, data []byte) (string, error) {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	switch {
+	case base == "go.mod":
+		return GoModFormat, nil
+	case base == "go.sum":
+		return GoSumFormat, nil
+	case base == "package-lock.json", base == "npm-shrinkwrap.json":
+		return PackageLockFormat, nil
+	case base == "pnpm-lock.yaml":
+		return PNPMLockFormat, nil
+	case base == "Cargo.lock":
+		return CargoLockFormat, nil
+	case base == "poetry.lock":
+		return PoetryLockFormat, nil
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return RequirementsFileFormat, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+	case trimmed[0] == '{':
+		var lock struct {
+			LockfileVersion *int `json:"lockfileVersion"`
+		}
+		if json.Unmarshal(trimmed, &lock) == nil && lock.LockfileVersion != nil {
+			return PackageLockFormat, nil
+		}
+	case bytes.HasPrefix(trimmed, []byte("lockfileVersion:")):
+		return PNPMLockFormat, nil
+	case bytes.Contains(trimmed, []byte("[[package]]")):
+		// Poetry records the Python versions a package supports and a hash of the project.
+		if bytes.Contains(trimmed, []byte("python-versions")) || bytes.Contains(trimmed, []byte("content-hash")) {
+			return PoetryLockFormat, nil
+		}
+		return CargoLockFormat, nil
+	case modfile.ModulePath(data) != "":
+		return GoModFormat, nil
+	default:
+		if format := detectGoModuleList(trimmed); format != "" {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%s isn't a lockfile in a supported format", name)
+}
+
+// detectGoModuleList recognizes go.sum files, whose lines are a module, a version and a hash,
+// and go mod graph output, whose lines are two modules.
+func detectGoModuleList(data []byte) string {
+	format := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		lineFormat := ""
+		switch fields := strings.Fields(line); {
+		case len(fields) == 3 && strings.HasPrefix(fields[2], "h1:"):
+			lineFormat = GoSumFormat
+		case len(fields) == 2 && strings.Contains(fields[1], "@"):
+			lineFormat = GoModGraphFormat
+		}
+		if lineFormat == "" || format != "" && lineFormat != format {
+			return ""
+		}
+		format = lineFormat
+	}
+	return format
+}
+
+// Lockfile ingests a lockfile in the given format: every package it pins becomes a library
+// node, depending on the packages the lockfile says it depends on. If the lockfile names the
+// project it belongs to, or a project purl is given, the project gets a library node as well,
+// depending on its direct dependencies and on every package nothing else depends on.
+//
+// The lockfile is recorded as a document identified by its format and project without its
+// version, so a new lockfile of the same project replaces the previous one. Lockfiles without a
+// project are identified by their content.
+//
+// Lockfile returns the entries of the lockfile that weren't ingested, with why, such as the
+// requirements a requirements file doesn't pin.
+func Lockfile(ctx context.Context, storage graph.Storage, format string, data []byte, project string, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("data is empty")
+	}
+	parse, ok := lockfileParsers[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported lockfile format %q", format)
+	}
+	lock, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+	if project != "" {
+		lock.root = project
+	}
+	root := lock.root
+	if root != "" {
+		root = o.normalizedPURL(root)
+	}
+
+	contribution := NewContribution()
+	ids := map[string]uint32{}
+	addNode := func(purl string) (uint32, error) {
+		name := o.normalizedPURL(purl)
+		if id, ok := ids[name]; ok {
+			return id, nil
+		}
+		node, err := addLockfileNode(ctx, storage, o, name)
+		if err != nil {
+			return 0, err
+		}
+		ids[name] = node.ID
+		contribution.addNode(node.ID)
+		return node.ID, nil
+	}
+	if root != "" {
+		if _, err := addNode(root); err != nil {
+			return nil, err
+		}
+	}
+	for _, purl := range lock.packages {
+		if _, err := addNode(purl); err != nil {
+			return nil, err
+		}
+	}
+
+	hasParent := map[uint32]bool{}
+	addEdge := func(from, to uint32) error {
+		if from == to || contribution.Edges.Contains(uint64(from)<<32|uint64(to)) {
+			return nil
+		}
+		fromNode, err := storage.GetNode(ctx, from)
+		if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", from, err)
+		}
+		toNode, err := storage.GetNode(ctx, to)
+		if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", to, err)
+		}
+		if err := fromNode.SetDependency(ctx, storage, toNode); err != nil {
+			return fmt.Errorf("failed to add edge %s -> %s: %w", fromNode.Name, toNode.Name, err)
+		}
+		contribution.addEdge(from, to)
+		hasParent[to] = true
+		return nil
+	}
+	for _, from := range lock.dependents {
+		if from == "" && root == "" {
+			continue
+		}
+		fromID := ids[root]
+		if from != "" {
+			if fromID, err = addNode(from); err != nil {
+				return nil, err
+			}
+		}
+		for _, to := range lock.dependencies[from] {
+			toID, err := addNode(to)
+			if err != nil {
+				return nil, err
+			}
+			if err := addEdge(fromID, toID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if root != "" {
+		for _, purl := range lock.packages {
+			if id := ids[o.normalizedPURL(purl)]; id != ids[root] && !hasParent[id] {
+				if err := addEdge(ids[root], id); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := linkAdvisories(ctx, storage, o, contribution.Nodes.ToArray()); err != nil {
+		return nil, err
+	}
+	metadata := DocumentMetadata{Name: root, SHA256: Digest(data)}
+	identity := "sha256:" + metadata.SHA256
+	if root != "" {
+		identity = format + ":" + unversioned(root)
+	}
+	if err := recordDocument(ctx, storage, tools.LockfileDocumentType, tools.LockfileDocumentType+":"+identity, metadata, contribution); err != nil {
+		return nil, err
+	}
+	return lock.skipped, nil
+}
+
+// addLockfileNode adds the library node of a package a lockfile pins, with the package the purl
+// names as its metadata.
+func addLockfileNode(ctx context.Context, storage graph.Storage, o *options, purl string) (*graph.Node, error) {
+	metadata, err := PURLToPackage(purl)
+	if err != nil {
+		metadata = PackageInfo{Name: purl}
+	}
+	node, err := graph.AddOrMergeNode(ctx, storage, o.mergePolicy, tools.LibraryType, metadata, purl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add node %s: %w", purl, err)
+	}
+	if err := indexPackage(ctx, storage, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// normalizedPURL returns the canonical form of the purl, or the purl as it is if it isn't one.
+func (o *options) normalizedPURL(purl string) string {
+	if normalized, err := o.normalizer.Normalize(purl); err == nil {
+		return normalized
+	}
+	return purl
+}
+
+// unversioned returns the purl without its version, or the name as it is if it isn't a purl.
+func unversioned(purl string) string {
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return purl
+	}
+	parsed.Version = ""
+	return parsed.ToString()
+}
+
+// lockfileGraph is what a lockfile says about the packages it pins.
+type lockfileGraph struct {
+	// root is the purl of the project the lockfile belongs to, if the lockfile names it.
+	root string
+	// packages are the purls of the pinned packages, in the order they appear.
+	packages []string
+	// dependencies are the purls of the packages each package depends on. Those of the project
+	// are under the empty purl.
+	dependencies map[string][]string
+	// dependents are the purls with dependencies, in the order they were added.
+	dependents []string
+	// skipped are the packages the lockfile names but that weren't read, with why.
+	skipped []string
+	seen    map[string]bool
+}
+
+func newLockfileGraph() *lockfileGraph {
+	return &lockfileGraph{dependencies: map[string][]string{}, seen: map[string]bool{}}
+}
+
+func (g *lockfileGraph) skip(entry, reason string) {
+	g.skipped = append(g.skipped, fmt.Sprintf("%s: %s", entry, reason))
+}
+
+// add adds a pinned package.
+func (g *lockfileGraph) add(purl string) {
+	if !g.seen[purl] {
+		g.seen[purl] = true
+		g.packages = append(g.packages, purl)
+	}
+}
+
+// depend records that from, or the project if from is empty, depends on to.
+func (g *lockfileGraph) depend(from, to string) {
+	key := from + "\x00" + to
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	if _, ok := g.dependencies[from]; !ok {
+		g.dependents = append(g.dependents, from)
+	}
+	g.dependencies[from] = append(g.dependencies[from], to)
+}
+
+// lockfilePURL returns the purl of a package of the given type. Namespaces are only split off
+// the name for golang and npm purls: Go modules are namespaced by the path up to their last
+// element, and npm packages by their scope.
+func lockfilePURL(purlType, name, version string) string {
+	namespace := ""
+	switch purlType {
+	case packageurl.TypeGolang:
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case packageurl.TypeNPM:
+		if i := strings.Index(name, "/"); strings.HasPrefix(name, "@") && i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case packageurl.TypePyPi:
+		name = pythonPackageName(name)
+	}
+	return packageurl.NewPackageURL(purlType, namespace, name, version, nil, "").ToString()
+}
@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/package-url/packageurl-go"
+)
+
+type cargoLock struct {
+	Packages []cargoPackage `toml:"package"`
+}
+
+type cargoPackage struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	// Source is where the package comes from, packages of the workspace have none.
+	Source string `toml:"source"`
+	// Dependencies are named by the package, followed by its version if several versions of
+	// the package are locked, and by its source if several sources have it.
+	Dependencies []string `toml:"dependencies"`
+}
+
+// parseCargoLock reads a Cargo.lock file. A workspace with a single package is the project.
+func parseCargoLock(data []byte) (*lockfileGraph, error) {
+	var file cargoLock
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, err
+	}
+	lock := newLockfileGraph()
+	byName := map[string][]cargoPackage{}
+	var members []string
+	for _, pkg := range file.Packages {
+		purl := lockfilePURL(packageurl.TypeCargo, pkg.Name, pkg.Version)
+		lock.add(purl)
+		byName[pkg.Name] = append(byName[pkg.Name], pkg)
+		if pkg.Source == "" {
+			members = append(members, purl)
+		}
+	}
+	if len(members) == 1 {
+		lock.root = members[0]
+	}
+
+	for _, pkg := range file.Packages {
+		from := lockfilePURL(packageurl.TypeCargo, pkg.Name, pkg.Version)
+		for _, dependency := range pkg.Dependencies {
+			fields := strings.Fields(dependency)
+			if len(fields) == 0 {
+				continue
+			}
+			candidates := byName[fields[0]]
+			if len(fields) > 1 {
+				var matching []cargoPackage
+				for _, candidate := range candidates {
+					if candidate.Version == fields[1] {
+						matching = append(matching, candidate)
+					}
+				}
+				candidates = matching
+			}
+			if len(candidates) == 0 {
+				return nil, fmt.Errorf("package %s %s depends on %q, which isn't locked", pkg.Name, pkg.Version, dependency)
+			}
+			lock.depend(from, lockfilePURL(packageurl.TypeCargo, candidates[0].Name, candidates[0].Version))
+		}
+	}
+	return lock, nil
+}
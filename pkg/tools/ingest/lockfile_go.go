@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+func goPURL(path, version string) string {
+	return lockfilePURL(packageurl.TypeGolang, path, version)
+}
+
+// parseGoMod reads the requirements of a go.mod file as the dependencies of its module. Since Go
+// 1.17 they include the indirect ones. Replaced modules are named by their replacement, unless
+// it is a directory.
+func parseGoMod(data []byte) (*lockfileGraph, error) {
+	file, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if file.Module == nil {
+		return nil, fmt.Errorf("no module statement")
+	}
+	replacements := map[module.Version]module.Version{}
+	for _, replace := range file.Replace {
+		replacements[replace.Old] = replace.New
+	}
+
+	lock := newLockfileGraph()
+	lock.root = goPURL(file.Module.Mod.Path, "")
+	for _, require := range file.Require {
+		mod := require.Mod
+		replacement, ok := replacements[mod]
+		if !ok {
+			replacement, ok = replacements[module.Version{Path: mod.Path}]
+		}
+		if ok && replacement.Version != "" {
+			mod = replacement
+		}
+		purl := goPURL(mod.Path, mod.Version)
+		lock.add(purl)
+		lock.depend("", purl)
+	}
+	return lock, nil
+}
+
+// parseGoSum reads the modules of a go.sum file. It doesn't say which module needs which, so
+// they are all dependencies of the project.
+func parseGoSum(data []byte) (*lockfileGraph, error) {
+	lock := newLockfileGraph()
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected a module, a version and a hash", i+1)
+		}
+		// The hash of the go.mod file of a module is listed apart from the hash of the module.
+		if strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		lock.add(goPURL(fields[0], fields[1]))
+	}
+	return lock, nil
+}
+
+// parseGoModGraph reads the output of go mod graph, whose lines are a module and one of its
+// requirements. The main module is the one without a version.
+func parseGoModGraph(data []byte) (*lockfileGraph, error) {
+	lock := newLockfileGraph()
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected two modules", i+1)
+		}
+		var purls []string
+		for _, field := range fields {
+			path, version, _ := strings.Cut(field, "@")
+			// The go and toolchain requirements are the versions of Go, not modules.
+			if path == "go" || path == "toolchain" {
+				break
+			}
+			if version == "" {
+				if lock.root == "" {
+					lock.root = goPURL(path, "")
+				}
+				if goPURL(path, "") == lock.root {
+					purls = append(purls, "")
+					continue
+				}
+			}
+			purls = append(purls, goPURL(path, version))
+		}
+		if len(purls) != 2 {
+			continue
+		}
+		for _, purl := range purls {
+			if purl != "" {
+				lock.add(purl)
+			}
+		}
+		lock.depend(purls[0], purls[1])
+	}
+	return lock, nil
+}
@@ -0,0 +1,337 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"gopkg.in/yaml.v3"
+)
+
+func npmPURL(name, version string) string {
+	return lockfilePURL(packageurl.TypeNPM, name, version)
+}
+
+type packageLock struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	LockfileVersion int    `json:"lockfileVersion"`
+	// Packages are the packages of lockfile version 2 and 3, by their path.
+	Packages map[string]packageLockPackage `json:"packages"`
+	// Dependencies are the packages of lockfile version 1, by their name.
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+type packageLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Link is set for symlinks to workspace packages, Resolved is then the path of the package.
+	Link                 bool              `json:"link"`
+	Resolved             string            `json:"resolved"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+type packageLockDependency struct {
+	Version      string                           `json:"version"`
+	Requires     map[string]string                `json:"requires"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// parsePackageLock reads a package-lock.json or npm-shrinkwrap.json file. Dependencies are
+// resolved the way Node.js does, from the closest node_modules directory up.
+func parsePackageLock(data []byte) (*lockfileGraph, error) {
+	var file packageLock
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	lock := newLockfileGraph()
+	if file.Name != "" {
+		lock.root = npmPURL(file.Name, file.Version)
+	}
+	if file.Packages != nil {
+		if root, ok := file.Packages[""]; ok && root.Name != "" {
+			lock.root = npmPURL(root.Name, root.Version)
+		}
+		parsePackageLockPackages(lock, file.Packages)
+		return lock, nil
+	}
+	parsePackageLockDependencies(lock, file.Dependencies, nil)
+	return lock, nil
+}
+
+// parsePackageLockPackages reads the packages of lockfile version 2 and 3.
+func parsePackageLockPackages(lock *lockfileGraph, packages map[string]packageLockPackage) {
+	paths := make([]string, 0, len(packages))
+	for path := range packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// purl returns the purl of the package at the path, following links, or nothing for the
+	// project.
+	purl := func(path string) string {
+		pkg := packages[path]
+		if pkg.Link {
+			path, pkg = pkg.Resolved, packages[pkg.Resolved]
+		}
+		if path == "" {
+			return ""
+		}
+		name := pkg.Name
+		if name == "" {
+			name = path
+			if i := strings.LastIndex(path, "node_modules/"); i >= 0 {
+				name = path[i+len("node_modules/"):]
+			}
+		}
+		return npmPURL(name, pkg.Version)
+	}
+
+	for _, path := range paths {
+		pkg := packages[path]
+		if path == "" || pkg.Link {
+			continue
+		}
+		lock.add(purl(path))
+	}
+	for _, path := range paths {
+		pkg := packages[path]
+		if pkg.Link {
+			continue
+		}
+		from := purl(path)
+		for _, dependencies := range []map[string]string{pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies, pkg.DevDependencies} {
+			names := make([]string, 0, len(dependencies))
+			for name := range dependencies {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				// Uninstalled dependencies, like the optional ones of other platforms and the
+				// development ones of dependencies, aren't in the lockfile.
+				if dependency, ok := resolveNodeModule(packages, path, name); ok {
+					if to := purl(dependency); to != "" {
+						lock.depend(from, to)
+					}
+				}
+			}
+		}
+	}
+}
+
+// resolveNodeModule returns the path of the package Node.js loads for the name from the package
+// at the path: the one in the node_modules directory of the package, or else of the packages it
+// is in, up to the project.
+func resolveNodeModule(packages map[string]packageLockPackage, path, name string) (string, bool) {
+	for {
+		candidate := "node_modules/" + name
+		if path != "" {
+			candidate = path + "/" + candidate
+		}
+		if _, ok := packages[candidate]; ok {
+			return candidate, true
+		}
+		if path == "" {
+			return "", false
+		}
+		if i := strings.LastIndex(path, "node_modules/"); i >= 0 {
+			path = strings.TrimSuffix(path[:i], "/")
+		} else {
+			// Workspace packages are in the project.
+			path = ""
+		}
+	}
+}
+
+// parsePackageLockDependencies reads the nested packages of lockfile version 1. The scopes are
+// the enclosing dependency maps, from the outermost, in which requirements are resolved.
+func parsePackageLockDependencies(lock *lockfileGraph, dependencies map[string]packageLockDependency, scopes []map[string]packageLockDependency) {
+	scopes = append(scopes, dependencies)
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dependency := dependencies[name]
+		from := npmPURL(name, dependency.Version)
+		lock.add(from)
+		inner := append(scopes, dependency.Dependencies)
+		required := make([]string, 0, len(dependency.Requires))
+		for requirement := range dependency.Requires {
+			required = append(required, requirement)
+		}
+		sort.Strings(required)
+		for _, requirement := range required {
+			for i := len(inner) - 1; i >= 0; i-- {
+				if resolved, ok := inner[i][requirement]; ok {
+					lock.depend(from, npmPURL(requirement, resolved.Version))
+					break
+				}
+			}
+		}
+		parsePackageLockDependencies(lock, dependency.Dependencies, scopes)
+	}
+}
+
+type pnpmLock struct {
+	LockfileVersion string `yaml:"lockfileVersion"`
+	// Importers are the projects of a workspace, by their directory. Lockfiles of a single
+	// project before version 9 have its dependencies at the top level instead.
+	Importers        map[string]pnpmDependencies `yaml:"importers"`
+	pnpmDependencies `yaml:",inline"`
+	Packages         map[string]pnpmDependencies `yaml:"packages"`
+	// Snapshots hold the dependencies of the packages since version 9.
+	Snapshots map[string]pnpmDependencies `yaml:"snapshots"`
+}
+
+type pnpmDependencies struct {
+	Dependencies         map[string]pnpmReference `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmReference `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmReference `yaml:"optionalDependencies"`
+}
+
+func (d pnpmDependencies) all() map[string]pnpmReference {
+	all := map[string]pnpmReference{}
+	for _, dependencies := range []map[string]pnpmReference{d.Dependencies, d.OptionalDependencies, d.DevDependencies} {
+		for name, reference := range dependencies {
+			all[name] = reference
+		}
+	}
+	return all
+}
+
+// pnpmReference is the version of a dependency, or since version 6 of the dependencies of
+// projects, its specifier and version.
+type pnpmReference string
+
+func (r *pnpmReference) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*r = pnpmReference(value.Value)
+		return nil
+	}
+	var specified struct {
+		Version string `yaml:"version"`
+	}
+	if err := value.Decode(&specified); err != nil {
+		return err
+	}
+	*r = pnpmReference(specified.Version)
+	return nil
+}
+
+// parsePNPMLock reads a pnpm-lock.yaml file. Packages are keyed by /name/version before version
+// 6, /name@version in version 6 and name@version since, followed by the versions of their peer
+// dependencies. Packages that only differ by their peer dependencies are the same package.
+func parsePNPMLock(data []byte) (*lockfileGraph, error) {
+	var file pnpmLock
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	version, err := strconv.ParseFloat(file.LockfileVersion, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockfile version %q", file.LockfileVersion)
+	}
+	slashed := version < 6
+
+	lock := newLockfileGraph()
+	keys := make([]string, 0, len(file.Packages))
+	for key := range file.Packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if purl, ok := pnpmKeyPURL(key, slashed); ok {
+			lock.add(purl)
+		}
+	}
+
+	dependOn := func(from string, dependencies pnpmDependencies) {
+		all := dependencies.all()
+		names := make([]string, 0, len(all))
+		for name := range all {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if to, ok := pnpmReferencePURL(name, string(all[name]), slashed); ok {
+				lock.depend(from, to)
+			}
+		}
+	}
+	dependents := file.Packages
+	if file.Snapshots != nil {
+		dependents = file.Snapshots
+		keys = keys[:0]
+		for key := range file.Snapshots {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+	for _, key := range keys {
+		if from, ok := pnpmKeyPURL(key, slashed); ok {
+			dependOn(from, dependents[key])
+		}
+	}
+	dependOn("", file.pnpmDependencies)
+	importers := make([]string, 0, len(file.Importers))
+	for importer := range file.Importers {
+		importers = append(importers, importer)
+	}
+	sort.Strings(importers)
+	for _, importer := range importers {
+		dependOn("", file.Importers[importer])
+	}
+	return lock, nil
+}
+
+// pnpmKeyPURL returns the purl of the package with the key, if it is a package of the registry.
+func pnpmKeyPURL(key string, slashed bool) (string, bool) {
+	key = strings.TrimPrefix(key, "/")
+	var name, version string
+	if slashed {
+		i := strings.LastIndex(key, "/")
+		if i <= 0 {
+			return "", false
+		}
+		name, version = key[:i], key[i+1:]
+		version, _, _ = strings.Cut(version, "_")
+	} else {
+		key, _, _ = strings.Cut(key, "(")
+		i := strings.LastIndex(key, "@")
+		if i <= 0 {
+			return "", false
+		}
+		name, version = key[:i], key[i+1:]
+	}
+	// Packages from tarballs, directories and git repositories aren't named by a version.
+	if version == "" || strings.ContainsAny(version, ":/") {
+		return "", false
+	}
+	return npmPURL(name, version), true
+}
+
+// pnpmReferencePURL returns the purl of the package a dependency refers to by a version with
+// its peer dependencies, by the key or, since version 9, the name and version of the package
+// for aliases, or by a link to a workspace package, which is left out.
+func pnpmReferencePURL(name, reference string, slashed bool) (string, bool) {
+	switch {
+	case strings.HasPrefix(reference, "link:"):
+		return "", false
+	case strings.HasPrefix(reference, "/"):
+		return pnpmKeyPURL(reference, slashed)
+	}
+	if slashed {
+		return pnpmKeyPURL(name+"/"+reference, true)
+	}
+	if version, _, _ := strings.Cut(reference, "("); strings.LastIndex(version, "@") > 0 {
+		return pnpmKeyPURL(reference, false)
+	}
+	return pnpmKeyPURL(name+"@"+reference, false)
+}
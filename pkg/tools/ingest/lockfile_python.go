@@ -0,0 +1,99 @@
+package ingest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/package-url/packageurl-go"
+)
+
+func pypiPURL(name, version string) string {
+	return lockfilePURL(packageurl.TypePyPi, name, version)
+}
+
+type poetryLock struct {
+	Packages []poetryPackage `toml:"package"`
+}
+
+type poetryPackage struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	// Dependencies map the names of the dependencies to their constraints, which are a string,
+	// a table or a list of tables with markers.
+	Dependencies map[string]any `toml:"dependencies"`
+}
+
+// parsePoetryLock reads a poetry.lock file. Dependencies are named without a version, a package
+// locked in several versions for different environments depends on the first one.
+func parsePoetryLock(data []byte) (*lockfileGraph, error) {
+	var file poetryLock
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, err
+	}
+	lock := newLockfileGraph()
+	byName := map[string]string{}
+	for _, pkg := range file.Packages {
+		purl := pypiPURL(pkg.Name, pkg.Version)
+		lock.add(purl)
+		if _, ok := byName[pythonPackageName(pkg.Name)]; !ok {
+			byName[pythonPackageName(pkg.Name)] = purl
+		}
+	}
+	for _, pkg := range file.Packages {
+		names := make([]string, 0, len(pkg.Dependencies))
+		for name := range pkg.Dependencies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			// Dependencies of extras that weren't requested, and of other environments, aren't
+			// locked.
+			if to, ok := byName[pythonPackageName(name)]; ok {
+				lock.depend(pypiPURL(pkg.Name, pkg.Version), to)
+			}
+		}
+	}
+	return lock, nil
+}
+
+// parseRequirements reads the requirements pinned with == or === in a pip requirements file as
+// dependencies of the project. Options, like includes of other files, are left out. Editable,
+// URL and unpinned requirements are left out as skipped.
+func parseRequirements(data []byte) (*lockfileGraph, error) {
+	lock := newLockfileGraph()
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\\\n", ""), "\n") {
+		if j := strings.Index(line, " #"); j >= 0 {
+			line = line[:j]
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-e ") || strings.HasPrefix(line, "--editable") {
+			lock.skip(line, "editable requirement")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// Environment markers follow a semicolon and pip options like --hash follow the
+		// requirement.
+		line, _, _ = strings.Cut(line, ";")
+		line, _, _ = strings.Cut(line, " -")
+		line = strings.TrimSpace(line)
+		name, version, pinned := strings.Cut(line, "==")
+		if strings.Contains(name, "@") || strings.Contains(line, "://") {
+			lock.skip(line, "URL requirement")
+			continue
+		}
+		name, _, _ = strings.Cut(name, "[")
+		name = strings.TrimSpace(name)
+		version = strings.TrimSpace(strings.TrimPrefix(version, "="))
+		if !pinned || name == "" || version == "" || strings.ContainsAny(version, "*,<>!~ ") {
+			lock.skip(line, "not pinned to a single version with == or ===")
+			continue
+		}
+		purl := pypiPURL(name, version)
+		lock.add(purl)
+		lock.depend("", purl)
+	}
+	return lock, nil
+}
@@ -0,0 +1,435 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goMod = `module example.com/app
+
+go 1.22
+
+require (
+	github.com/google/uuid v1.6.0
+	golang.org/x/text v0.3.0 // indirect
+	example.com/old v1.0.0
+	example.com/local v1.0.0
+)
+
+replace example.com/old => example.com/new v1.1.0
+
+replace example.com/local => ../local
+`
+
+const goSum = `github.com/google/uuid v1.6.0 h1:NIvaJDMOsjHA8n1jAhLSgzrAzy1Hgr+hNrb57e+94F0=
+github.com/google/uuid v1.6.0/go.mod h1:TIyPZe4MgqvfeYDBFedMoGGpEw/LqOeaOT+nhxU+yHo=
+golang.org/x/text v0.3.0/go.mod h1:NqM8EUOU14njkJ3fqMW+pc6Ldnwhi/IjpwHt7yyuwOQ=
+`
+
+const goModGraph = `example.com/app github.com/google/uuid@v1.6.0
+example.com/app go@1.22
+example.com/app golang.org/x/text@v0.3.0
+github.com/google/uuid@v1.6.0 golang.org/x/text@v0.3.0
+golang.org/x/text@v0.3.0 toolchain@go1.22.0
+`
+
+const packageLockV3 = `{
+  "name": "app",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app", "version": "1.0.0", "dependencies": {"@babel/core": "^7.0.0", "a": "^1.0.0", "ws": "*"}, "devDependencies": {"b": "^2.0.0"}},
+    "node_modules/@babel/core": {"version": "7.24.0", "dependencies": {"b": "^1.0.0"}},
+    "node_modules/@babel/core/node_modules/b": {"version": "1.0.0", "dependencies": {"a": "^1.0.0"}},
+    "node_modules/a": {"version": "1.2.0", "optionalDependencies": {"fsevents": "^2.0.0"}},
+    "node_modules/b": {"version": "2.0.0", "dev": true},
+    "node_modules/ws": {"resolved": "packages/ws", "link": true},
+    "packages/ws": {"name": "ws", "version": "0.1.0", "dependencies": {"a": "^1.0.0"}}
+  }
+}`
+
+const packageLockV1 = `{
+  "name": "app",
+  "version": "1.0.0",
+  "lockfileVersion": 1,
+  "dependencies": {
+    "a": {"version": "1.2.0", "requires": {"b": "^1.0.0"}, "dependencies": {"b": {"version": "1.0.0"}}},
+    "b": {"version": "2.0.0"},
+    "c": {"version": "3.0.0", "requires": {"b": "^2.0.0"}}
+  }
+}`
+
+const pnpmLockV9 = `lockfileVersion: '9.0'
+
+importers:
+  .:
+    dependencies:
+      '@babel/core':
+        specifier: ^7.0.0
+        version: 7.24.0(react@18.0.0)
+      local:
+        specifier: workspace:*
+        version: link:packages/local
+
+packages:
+  '@babel/core@7.24.0':
+    resolution: {integrity: sha512-x}
+  react@18.0.0:
+    resolution: {integrity: sha512-y}
+  string-width@4.2.3:
+    resolution: {integrity: sha512-z}
+
+snapshots:
+  '@babel/core@7.24.0(react@18.0.0)':
+    dependencies:
+      react: 18.0.0
+      string-width-cjs: string-width@4.2.3
+  react@18.0.0: {}
+  string-width@4.2.3: {}
+`
+
+const pnpmLockV5 = `lockfileVersion: 5.4
+
+specifiers:
+  '@babel/core': ^7.0.0
+
+dependencies:
+  '@babel/core': 7.24.0_react@18.0.0
+
+packages:
+  /@babel/core/7.24.0_react@18.0.0:
+    resolution: {integrity: sha512-x}
+    dependencies:
+      react: 18.0.0
+  /react/18.0.0:
+    resolution: {integrity: sha512-y}
+`
+
+const cargoLockfile = `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "rand 0.8.5",
+ "serde",
+]
+
+[[package]]
+name = "rand"
+version = "0.7.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "serde",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+
+const poetryLockfile = `# This file is automatically @generated by Poetry and should not be changed by hand.
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+description = "Python HTTP for Humans."
+optional = false
+python-versions = ">=3.7"
+
+[package.dependencies]
+charset-normalizer = ">=2,<4"
+PySocks = {version = ">=1.5.6,!=1.5.7", optional = true, markers = "extra == \"socks\""}
+
+[[package]]
+name = "charset_normalizer"
+version = "3.3.2"
+description = "The Real First Universal Charset Detector."
+optional = false
+python-versions = ">=3.7.0"
+
+[metadata]
+lock-version = "2.0"
+python-versions = "^3.11"
+content-hash = "abc"
+`
+
+const requirementsTxt = `# Pinned dependencies
+-r base.txt
+--index-url https://pypi.org/simple
+Django[argon2]==4.2.1 ; python_version >= "3.8"
+requests==2.31.0 \
+    --hash=sha256:abc
+Zope.Interface===6.0  # comment
+flask>=2.0
+numpy==1.*
+-e git+https://github.com/example/lib.git#egg=lib
+pkg @ https://example.com/pkg-1.0.tar.gz
+`
+
+func TestDetectLockfile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "repo/go.mod", want: GoModFormat},
+		{name: "go.sum", want: GoSumFormat},
+		{name: `C:\repo\npm-shrinkwrap.json`, want: PackageLockFormat},
+		{name: "pnpm-lock.yaml", want: PNPMLockFormat},
+		{name: "Cargo.lock", want: CargoLockFormat},
+		{name: "poetry.lock", want: PoetryLockFormat},
+		{name: "requirements-dev.txt", want: RequirementsFileFormat},
+		{name: "modules.txt", data: goModGraph, want: GoModGraphFormat},
+		{name: "checksums", data: goSum, want: GoSumFormat},
+		{name: "app.mod", data: goMod, want: GoModFormat},
+		{name: "lock.json", data: packageLockV1, want: PackageLockFormat},
+		{name: "lock.yaml", data: pnpmLockV9, want: PNPMLockFormat},
+		{name: "rust.lock", data: cargoLockfile, want: CargoLockFormat},
+		{name: "python.lock", data: poetryLockfile, want: PoetryLockFormat},
+		{name: "sbom.json", data: `{"bomFormat": "CycloneDX"}`},
+		{name: "README.md", data: "# app\n"},
+		{name: "empty"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			format, err := DetectLockfile(test.name, []byte(test.data))
+			if test.want == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, format)
+		})
+	}
+}
+
+func TestParseLockfiles(t *testing.T) {
+	tests := []struct {
+		format       string
+		data         string
+		root         string
+		packages     []string
+		dependencies map[string][]string
+		skipped      []string
+	}{
+		{
+			format: GoModFormat,
+			data:   goMod,
+			root:   "pkg:golang/example.com/app",
+			packages: []string{
+				"pkg:golang/github.com/google/uuid@v1.6.0",
+				"pkg:golang/golang.org/x/text@v0.3.0",
+				"pkg:golang/example.com/new@v1.1.0",
+				"pkg:golang/example.com/local@v1.0.0",
+			},
+			dependencies: map[string][]string{"": {
+				"pkg:golang/github.com/google/uuid@v1.6.0",
+				"pkg:golang/golang.org/x/text@v0.3.0",
+				"pkg:golang/example.com/new@v1.1.0",
+				"pkg:golang/example.com/local@v1.0.0",
+			}},
+		},
+		{
+			format:       GoSumFormat,
+			data:         goSum,
+			packages:     []string{"pkg:golang/github.com/google/uuid@v1.6.0"},
+			dependencies: map[string][]string{},
+		},
+		{
+			format:   GoModGraphFormat,
+			data:     goModGraph,
+			root:     "pkg:golang/example.com/app",
+			packages: []string{"pkg:golang/github.com/google/uuid@v1.6.0", "pkg:golang/golang.org/x/text@v0.3.0"},
+			dependencies: map[string][]string{
+				"": {"pkg:golang/github.com/google/uuid@v1.6.0", "pkg:golang/golang.org/x/text@v0.3.0"},
+				"pkg:golang/github.com/google/uuid@v1.6.0": {"pkg:golang/golang.org/x/text@v0.3.0"},
+			},
+		},
+		{
+			format: PackageLockFormat,
+			data:   packageLockV3,
+			root:   "pkg:npm/app@1.0.0",
+			packages: []string{
+				"pkg:npm/%40babel/core@7.24.0",
+				"pkg:npm/b@1.0.0",
+				"pkg:npm/a@1.2.0",
+				"pkg:npm/b@2.0.0",
+				"pkg:npm/ws@0.1.0",
+			},
+			dependencies: map[string][]string{
+				"":                             {"pkg:npm/%40babel/core@7.24.0", "pkg:npm/a@1.2.0", "pkg:npm/ws@0.1.0", "pkg:npm/b@2.0.0"},
+				"pkg:npm/%40babel/core@7.24.0": {"pkg:npm/b@1.0.0"},
+				"pkg:npm/b@1.0.0":              {"pkg:npm/a@1.2.0"},
+				"pkg:npm/ws@0.1.0":             {"pkg:npm/a@1.2.0"},
+			},
+		},
+		{
+			format:   PackageLockFormat,
+			data:     packageLockV1,
+			root:     "pkg:npm/app@1.0.0",
+			packages: []string{"pkg:npm/a@1.2.0", "pkg:npm/b@1.0.0", "pkg:npm/b@2.0.0", "pkg:npm/c@3.0.0"},
+			dependencies: map[string][]string{
+				"pkg:npm/a@1.2.0": {"pkg:npm/b@1.0.0"},
+				"pkg:npm/c@3.0.0": {"pkg:npm/b@2.0.0"},
+			},
+		},
+		{
+			format:   PNPMLockFormat,
+			data:     pnpmLockV9,
+			packages: []string{"pkg:npm/%40babel/core@7.24.0", "pkg:npm/react@18.0.0", "pkg:npm/string-width@4.2.3"},
+			dependencies: map[string][]string{
+				"":                             {"pkg:npm/%40babel/core@7.24.0"},
+				"pkg:npm/%40babel/core@7.24.0": {"pkg:npm/react@18.0.0", "pkg:npm/string-width@4.2.3"},
+			},
+		},
+		{
+			format:   PNPMLockFormat,
+			data:     pnpmLockV5,
+			packages: []string{"pkg:npm/%40babel/core@7.24.0", "pkg:npm/react@18.0.0"},
+			dependencies: map[string][]string{
+				"":                             {"pkg:npm/%40babel/core@7.24.0"},
+				"pkg:npm/%40babel/core@7.24.0": {"pkg:npm/react@18.0.0"},
+			},
+		},
+		{
+			format:   CargoLockFormat,
+			data:     cargoLockfile,
+			root:     "pkg:cargo/app@0.1.0",
+			packages: []string{"pkg:cargo/app@0.1.0", "pkg:cargo/rand@0.7.3", "pkg:cargo/rand@0.8.5", "pkg:cargo/serde@1.0.200"},
+			dependencies: map[string][]string{
+				"pkg:cargo/app@0.1.0":  {"pkg:cargo/rand@0.8.5", "pkg:cargo/serde@1.0.200"},
+				"pkg:cargo/rand@0.8.5": {"pkg:cargo/serde@1.0.200"},
+			},
+		},
+		{
+			format:   PoetryLockFormat,
+			data:     poetryLockfile,
+			packages: []string{"pkg:pypi/requests@2.31.0", "pkg:pypi/charset-normalizer@3.3.2"},
+			dependencies: map[string][]string{
+				"pkg:pypi/requests@2.31.0": {"pkg:pypi/charset-normalizer@3.3.2"},
+			},
+		},
+		{
+			format:   RequirementsFileFormat,
+			data:     requirementsTxt,
+			packages: []string{"pkg:pypi/django@4.2.1", "pkg:pypi/requests@2.31.0", "pkg:pypi/zope-interface@6.0"},
+			dependencies: map[string][]string{
+				"": {"pkg:pypi/django@4.2.1", "pkg:pypi/requests@2.31.0", "pkg:pypi/zope-interface@6.0"},
+			},
+			skipped: []string{
+				"flask>=2.0: not pinned to a single version with == or ===",
+				"numpy==1.*: not pinned to a single version with == or ===",
+				"-e git+https://github.com/example/lib.git#egg=lib: editable requirement",
+				"pkg @ https://example.com/pkg-1.0.tar.gz: URL requirement",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			lock, err := lockfileParsers[test.format]([]byte(test.data))
+			require.NoError(t, err)
+			assert.Equal(t, test.root, lock.root)
+			assert.Equal(t, test.packages, lock.packages)
+			assert.Equal(t, test.dependencies, lock.dependencies)
+			assert.Equal(t, test.skipped, lock.skipped)
+			for _, purl := range lock.packages {
+				_, err := PURLToPackage(purl)
+				assert.NoError(t, err, purl)
+			}
+		})
+	}
+}
+
+func TestParseLockfileErrors(t *testing.T) {
+	for format, data := range map[string]string{
+		GoModFormat:       "go 1.22\n",
+		GoSumFormat:       "github.com/google/uuid v1.6.0\n",
+		GoModGraphFormat:  "example.com/app\n",
+		PackageLockFormat: "{",
+		PNPMLockFormat:    "packages: {}\n",
+		CargoLockFormat:   "[[package]]\nname = \"app\"\nversion = \"0.1.0\"\ndependencies = [\"missing\"]\n",
+		PoetryLockFormat:  "[[package]\n",
+	} {
+		_, err := lockfileParsers[format]([]byte(data))
+		assert.Error(t, err, format)
+	}
+}
+
+func TestLockfile(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+
+	skipped, err := Lockfile(ctx, storage, GoModGraphFormat, []byte(goModGraph), "")
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	app, err := graph.ResolveName(ctx, storage, "pkg:golang/example.com/app")
+	require.NoError(t, err)
+	uuid, err := graph.ResolveName(ctx, storage, "pkg:golang/github.com/google/uuid@v1.6.0")
+	require.NoError(t, err)
+	text, err := graph.ResolveName(ctx, storage, "pkg:golang/golang.org/x/text@v0.3.0")
+	require.NoError(t, err)
+
+	node, err := storage.GetNode(ctx, uuid)
+	require.NoError(t, err)
+	assert.Equal(t, tools.LibraryType, node.Type)
+	assert.Equal(t, PackageInfo{Name: "github.com/google/uuid", Version: "v1.6.0", Ecosystem: string(EcosystemGo)}, node.Metadata)
+	assert.ElementsMatch(t, []uint32{text}, node.Children.ToArray())
+	node, err = storage.GetNode(ctx, app)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{uuid, text}, node.Children.ToArray())
+
+	document, err := graph.ResolveName(ctx, storage, "lockfile:go-mod-graph:pkg:golang/example.com/app")
+	require.NoError(t, err)
+	contribution, err := loadContribution(ctx, storage, "lockfile:go-mod-graph:pkg:golang/example.com/app")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{app, uuid, text}, contribution.Nodes.ToArray())
+	node, err = storage.GetNode(ctx, document)
+	require.NoError(t, err)
+	assert.Equal(t, tools.LockfileDocumentType, node.Type)
+
+	// A new lockfile of the project replaces the previous one.
+	_, err = Lockfile(ctx, storage, GoModGraphFormat, []byte("example.com/app github.com/google/uuid@v1.6.0\n"), "")
+	require.NoError(t, err)
+	node, err = storage.GetNode(ctx, app)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{uuid}, node.Children.ToArray())
+	node, err = storage.GetNode(ctx, uuid)
+	require.NoError(t, err)
+	assert.Empty(t, node.Children.ToArray())
+
+	// Lockfiles without a project depend on the given one.
+	// The requirements that aren't pinned are returned.
+	skipped, err = Lockfile(ctx, storage, RequirementsFileFormat, []byte(requirementsTxt), "pkg:github/example/service@v2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"flask>=2.0: not pinned to a single version with == or ===",
+		"numpy==1.*: not pinned to a single version with == or ===",
+		"-e git+https://github.com/example/lib.git#egg=lib: editable requirement",
+		"pkg @ https://example.com/pkg-1.0.tar.gz: URL requirement",
+	}, skipped)
+	service, err := graph.ResolveName(ctx, storage, "pkg:github/example/service@v2")
+	require.NoError(t, err)
+	node, err = storage.GetNode(ctx, service)
+	require.NoError(t, err)
+	assert.Len(t, node.Children.ToArray(), 3)
+	_, err = graph.ResolveName(ctx, storage, "lockfile:requirements.txt:pkg:github/example/service")
+	require.NoError(t, err)
+
+	_, err = Lockfile(ctx, storage, "yarn.lock", []byte("a"), "")
+	assert.Error(t, err)
+	_, err = Lockfile(ctx, storage, GoModFormat, nil, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+)
+
+// Option configures how documents are ingested.
+type Option func(*options)
+
+type options struct {
+	mergePolicy graph.MergePolicy
+	normalizer  *purl.Normalizer
+}
+
+// WithMergePolicy merges the metadata a document has for nodes that already exist into them by
+// the strategy the policy has for their type. Without it, nodes keep the metadata of the first
+// document that described them.
+func WithMergePolicy(policy graph.MergePolicy) Option {
+	return func(o *options) {
+		o.mergePolicy = policy
+	}
+}
+
+// WithIdentityQualifiers keeps the given purl qualifiers in node names instead of
+// purl.DefaultIdentityQualifiers.
+func WithIdentityQualifiers(qualifiers []string) Option {
+	return func(o *options) {
+		o.normalizer = purl.NewNormalizer(qualifiers)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{normalizer: purl.DefaultNormalizer}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,113 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+// PackagesTag is the custom data tag of the package index, which finds the library nodes of a
+// package by its OSV ecosystem and name, so advisories can be matched without reading every
+// node. Its keys are packageKey, and every entry maps a node ID to the node name.
+const PackagesTag = "packages"
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// pythonPackageName returns the normalized name of a Python package, as PEP 503 defines it.
+func pythonPackageName(name string) string {
+	return pypiSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// packageKey returns the key of a package in the package index. Ecosystems may name a
+// release, like "Debian:11", which isn't part of the key. PyPI names are normalized as
+// PEP 503 describes, since pip treats their spellings as the same package. Go module paths are
+// lowercased, since golang purls lowercase them while OSV names them with their case.
+func packageKey(ecosystem, name string) string {
+	ecosystem, _, _ = strings.Cut(ecosystem, ":")
+	switch Ecosystem(ecosystem) { //nolint:exhaustive
+	case EcosystemPyPI:
+		name = pythonPackageName(name)
+	case EcosystemGo:
+		name = strings.ToLower(name)
+	}
+	return ecosystem + "/" + name
+}
+
+// indexPackage adds the node to the package index if it is a library with a purl.
+func indexPackage(ctx context.Context, storage graph.Storage, node *graph.Node) error {
+	if node.Type != tools.LibraryType || !strings.HasPrefix(node.Name, pkg) {
+		return nil
+	}
+	pkgInfo, err := PURLToPackage(node.Name)
+	if err != nil {
+		return nil
+	}
+	key := packageKey(pkgInfo.Ecosystem, pkgInfo.Name)
+	if err := storage.AddOrUpdateCustomData(ctx, PackagesTag, key, strconv.FormatUint(uint64(node.ID), 10), []byte(node.Name)); err != nil {
+		return fmt.Errorf("failed to index package %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// IndexPackages adds every library node to the package index, for graphs built before the
+// index was maintained.
+func IndexPackages(ctx context.Context, storage graph.Storage) error {
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	for _, node := range nodes {
+		if err := indexPackage(ctx, storage, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageNodes returns the library nodes of the package with the given OSV ecosystem and name
+// and their package information. Index entries of nodes that were deleted or merged into
+// others are ignored.
+func packageNodes(ctx context.Context, storage graph.Storage, ecosystem, name string) (map[*graph.Node]PackageInfo, error) {
+	key := packageKey(ecosystem, name)
+	entries, err := storage.GetCustomData(ctx, PackagesTag, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up package %s: %w", key, err)
+	}
+	ids := make([]uint32, 0, len(entries))
+	for field := range entries {
+		id, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint32(id))
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	nodes, err := storage.GetNodes(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes of package %s: %w", key, err)
+	}
+
+	result := map[*graph.Node]PackageInfo{}
+	for _, node := range nodes {
+		if node.Type != tools.LibraryType {
+			continue
+		}
+		pkgInfo, err := PURLToPackage(node.Name)
+		if err != nil || packageKey(pkgInfo.Ecosystem, pkgInfo.Name) != key {
+			continue
+		}
+		result[node] = pkgInfo
+	}
+	return result, nil
+}
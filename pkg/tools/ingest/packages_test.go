@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/purl"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packageNodeNames returns the sorted names of the nodes of the package.
+func packageNodeNames(t *testing.T, storage graph.Storage, ecosystem, name string) []string {
+	t.Helper()
+	nodes, err := packageNodes(context.Background(), storage, ecosystem, name)
+	require.NoError(t, err)
+	names := []string{}
+	for node := range nodes {
+		names = append(names, node.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPackageIndex(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	for _, name := range []string{
+		"pkg:pypi/Foo_Bar@1.0",
+		"pkg:pypi/foo.bar@2.0",
+		"pkg:npm/foo-bar@1.0.0",
+		"pkg:npm/%40scope/x@1.0.0",
+		"pkg:deb/debian/curl@7.0-1",
+		"pkg:maven/org.example/lib@1.0",
+		"pkg:no-purl@1.0",
+	} {
+		node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, name)
+		require.NoError(t, err)
+		require.NoError(t, indexPackage(ctx, storage, node))
+	}
+
+	assert.Equal(t, []string{"pkg:pypi/Foo_Bar@1.0", "pkg:pypi/foo.bar@2.0"}, packageNodeNames(t, storage, "PyPI", "foo-bar"))
+	assert.Equal(t, []string{"pkg:npm/foo-bar@1.0.0"}, packageNodeNames(t, storage, "npm", "foo-bar"))
+	assert.Equal(t, []string{"pkg:npm/%40scope/x@1.0.0"}, packageNodeNames(t, storage, "npm", "@scope/x"))
+	assert.Equal(t, []string{"pkg:deb/debian/curl@7.0-1"}, packageNodeNames(t, storage, "Debian:11", "curl"))
+	assert.Equal(t, []string{"pkg:maven/org.example/lib@1.0"}, packageNodeNames(t, storage, "Maven", "org.example:lib"))
+	assert.Empty(t, packageNodeNames(t, storage, "Go", "foo-bar"))
+
+	// Golang purls are lowercased, OSV names Go modules with their case.
+	name, err := purl.DefaultNormalizer.Normalize("pkg:golang/github.com/BurntSushi/toml@v1.3.2")
+	require.NoError(t, err)
+	node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, name)
+	require.NoError(t, err)
+	require.NoError(t, indexPackage(ctx, storage, node))
+	assert.Equal(t, []string{name}, packageNodeNames(t, storage, "Go", "github.com/BurntSushi/toml"))
+
+	// Entries of deleted nodes are ignored.
+	id, err := storage.NameToID(ctx, "pkg:npm/foo-bar@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, storage.DeleteNode(ctx, id))
+	assert.Empty(t, packageNodeNames(t, storage, "npm", "foo-bar"))
+}
+
+func TestIndexPackages(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	data, err := os.ReadFile("../../../testdata/osv-sboms/google_cadvisor.sbom.json")
+	require.NoError(t, err)
+	require.NoError(t, SBOM(ctx, storage, data))
+
+	// SBOM ingestion maintains the index.
+	indexed := packageNodeNames(t, storage, "Go", "github.com/opencontainers/runc")
+	assert.NotEmpty(t, indexed)
+
+	// Nodes added without it are found once the index is rebuilt.
+	node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:golang/github.com/opencontainers/runc@v0.0.1")
+	require.NoError(t, err)
+	assert.NotContains(t, packageNodeNames(t, storage, "Go", "github.com/opencontainers/runc"), node.Name)
+	require.NoError(t, IndexPackages(ctx, storage))
+	assert.ElementsMatch(t, append(indexed, node.Name), packageNodeNames(t, storage, "Go", "github.com/opencontainers/runc"))
+}
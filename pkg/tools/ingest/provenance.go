@@ -0,0 +1,456 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools/license"
+)
+
+const (
+	// ProvenanceTag is the custom data tag under which the contribution of every document is
+	// stored, keyed by the name of the document node.
+	ProvenanceTag = "provenance"
+
+	provenanceContributionKey = "contribution"
+	// Contributions used to be saved as two fields, one write after the other.
+	provenanceNodesKey = "nodes"
+	provenanceEdgesKey = "edges"
+
+	// PayloadsTag is the custom data tag under which the name of the document every ingested
+	// payload became is stored, keyed by the SHA-256 digest of the payload.
+	PayloadsTag = "payloads"
+
+	payloadDocumentKey = "document"
+
+	// UnknownProvenanceTag is the custom data tag under which the nodes and edges that were in
+	// the graph before provenance was tracked are stored, as a contribution no document owns.
+	// Re-ingesting a document never removes them.
+	UnknownProvenanceTag = "provenance-unknown"
+
+	unknownProvenanceKey = "unknown"
+)
+
+// ErrDocumentNotFound is returned for names that aren't the name of an ingested document.
+var ErrDocumentNotFound = errors.New("document not found")
+
+// DocumentMetadata is the metadata of a document node. It describes the ingested document,
+// the nodes and edges it asserted are its Contribution.
+type DocumentMetadata struct {
+	Name         string   `json:"name,omitempty"`
+	SerialNumber string   `json:"serial_number,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Tools        []string `json:"tools,omitempty"`
+	Timestamp    string   `json:"timestamp,omitempty"`
+	SHA256       string   `json:"sha256"`
+}
+
+// Contribution is what a document asserted: the nodes it described and the edges between them.
+type Contribution struct {
+	Nodes *roaring.Bitmap
+	// Edges holds every edge as from<<32 | to.
+	Edges *roaring64.Bitmap
+	// Licenses holds the license expression of every library that has one, by node ID.
+	Licenses map[uint32]string
+}
+
+// Edge is an edge of a Contribution.
+type Edge struct {
+	From, To uint32
+}
+
+func NewContribution() *Contribution {
+	return &Contribution{Nodes: roaring.New(), Edges: roaring64.New(), Licenses: map[uint32]string{}}
+}
+
+func (c *Contribution) addNode(id uint32) {
+	c.Nodes.Add(id)
+}
+
+func (c *Contribution) addEdge(from, to uint32) {
+	c.Nodes.Add(from)
+	c.Nodes.Add(to)
+	c.Edges.Add(uint64(from)<<32 | uint64(to))
+}
+
+func (c *Contribution) addLicense(id uint32, expression string) {
+	c.Nodes.Add(id)
+	c.Licenses[id] = expression
+}
+
+// EdgeList returns the edges of the contribution sorted by from and to.
+func (c *Contribution) EdgeList() []Edge {
+	edges := make([]Edge, 0, c.Edges.GetCardinality())
+	for it := c.Edges.Iterator(); it.HasNext(); {
+		edge := it.Next()
+		edges = append(edges, Edge{From: uint32(edge >> 32), To: uint32(edge)})
+	}
+	return edges
+}
+
+// Documents returns the nodes of all ingested documents, sorted by name.
+func Documents(ctx context.Context, storage graph.Storage) ([]*graph.Node, error) {
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	names := append([]string(nil), keys[ProvenanceTag]...)
+	sort.Strings(names)
+
+	documents := make([]*graph.Node, 0, len(names))
+	for _, name := range names {
+		document, err := getDocument(ctx, storage, name)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// GetContribution returns the document node with the given name and what it contributed.
+func GetContribution(ctx context.Context, storage graph.Storage, name string) (*graph.Node, *Contribution, error) {
+	contribution, err := loadContribution(ctx, storage, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if contribution == nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, ErrDocumentNotFound)
+	}
+	document, err := getDocument(ctx, storage, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, contribution, nil
+}
+
+// DocumentsForNode returns the nodes of the documents that asserted the node or one of its
+// edges, sorted by name.
+func DocumentsForNode(ctx context.Context, storage graph.Storage, id uint32) ([]*graph.Node, error) {
+	contributions, err := loadContributions(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name, contribution := range contributions {
+		// The nodes of a contribution include the ends of its edges.
+		if contribution.Nodes.Contains(id) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	documents := make([]*graph.Node, 0, len(names))
+	for _, name := range names {
+		document, err := getDocument(ctx, storage, name)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+func getDocument(ctx context.Context, storage graph.Storage, name string) (*graph.Node, error) {
+	id, err := storage.NameToID(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document %s: %w", name, err)
+	}
+	return storage.GetNode(ctx, id)
+}
+
+// Digest returns the hex encoded sha256 of an ingested payload.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordDocument saves the document node and its contribution. If the document was ingested
+// before, the edges and nodes that only its previous version asserted are removed, so the
+// graph looks as if the previous version was never ingested.
+func recordDocument(ctx context.Context, storage graph.Storage, documentType, name string, metadata DocumentMetadata, contribution *Contribution) error {
+	document, err := graph.AddNode(ctx, storage, documentType, metadata, name)
+	if err != nil {
+		return fmt.Errorf("failed to add document node: %w", err)
+	}
+	// AddNode returns the existing node for a re-ingested document, update its metadata. Only
+	// the metadata is written, saving the copy AddNode returned would undo edges added since.
+	if _, err := storage.UpdateNodeMetadata(ctx, document.ID, func(any) (any, error) { return metadata, nil }); err != nil {
+		return fmt.Errorf("failed to update document node: %w", err)
+	}
+
+	previous, err := loadContribution(ctx, storage, name)
+	if err != nil {
+		return err
+	}
+	if err := saveContribution(ctx, storage, name, contribution); err != nil {
+		return err
+	}
+	if err := saveLicenseExpressions(ctx, storage, name, previous, contribution); err != nil {
+		return err
+	}
+	if previous != nil {
+		if err := removeStaleContribution(ctx, storage, name, previous, contribution); err != nil {
+			return err
+		}
+	}
+	// The payload is only recorded once it is fully ingested, so an interrupted ingestion is
+	// never taken for a finished one.
+	if metadata.SHA256 == "" {
+		return nil
+	}
+	if err := storage.AddOrUpdateCustomData(ctx, PayloadsTag, metadata.SHA256, payloadDocumentKey, []byte(name)); err != nil {
+		return fmt.Errorf("failed to record payload of %s: %w", name, err)
+	}
+	return nil
+}
+
+// Unchanged reports whether a payload with the given SHA-256 digest was ingested and the
+// document it became still holds what it asserted, so ingesting it again can be skipped. A
+// document replaced by a different version since isn't unchanged, ingesting the payload
+// again restores it.
+func Unchanged(ctx context.Context, storage graph.Storage, sha256 string) (bool, error) {
+	data, err := storage.GetCustomData(ctx, PayloadsTag, sha256)
+	if err != nil {
+		return false, fmt.Errorf("failed to get payload %s: %w", sha256, err)
+	}
+	name, ok := data[payloadDocumentKey]
+	if !ok {
+		return false, nil
+	}
+	document, err := getDocument(ctx, storage, string(name))
+	if errors.Is(err, graph.ErrNodeNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	encoded, err := json.Marshal(document.Metadata)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode metadata of %s: %w", name, err)
+	}
+	var metadata DocumentMetadata
+	if err := json.Unmarshal(encoded, &metadata); err != nil {
+		return false, fmt.Errorf("failed to decode metadata of %s: %w", name, err)
+	}
+	return metadata.SHA256 == sha256, nil
+}
+
+// removeStaleContribution removes what the previous version of a document asserted and the
+// current one doesn't, unless another document, or data of unknown provenance, asserts it too.
+func removeStaleContribution(ctx context.Context, storage graph.Storage, name string, previous, current *Contribution) error {
+	contributions, err := loadContributions(ctx, storage)
+	if err != nil {
+		return err
+	}
+	unknown, err := loadContributionAs(ctx, storage, UnknownProvenanceTag, unknownProvenanceKey)
+	if err != nil {
+		return err
+	}
+	if unknown != nil {
+		contributions[unknownProvenanceKey] = unknown
+	}
+	others := NewContribution()
+	for other, contribution := range contributions {
+		if other != name {
+			others.Nodes.Or(contribution.Nodes)
+			others.Edges.Or(contribution.Edges)
+		}
+	}
+
+	staleEdges := roaring64.AndNot(previous.Edges, current.Edges)
+	staleEdges.AndNot(others.Edges)
+	for it := staleEdges.Iterator(); it.HasNext(); {
+		edge := it.Next()
+		from, to := uint32(edge>>32), uint32(edge)
+		if err := storage.RemoveDependency(ctx, from, to); err != nil && !errors.Is(err, graph.ErrNodeNotFound) {
+			return fmt.Errorf("failed to remove edge %d -> %d: %w", from, to, err)
+		}
+	}
+
+	staleNodes := roaring.AndNot(previous.Nodes, current.Nodes)
+	staleNodes.AndNot(others.Nodes)
+	for _, id := range staleNodes.ToArray() {
+		// Nodes that still have edges, e.g. ones ingested before provenance was tracked, stay.
+		err := storage.DeleteNode(ctx, id)
+		if err != nil && !errors.Is(err, graph.ErrNodeNotFound) && !errors.Is(err, graph.ErrNodeHasEdges) {
+			return fmt.Errorf("failed to delete node %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// RecordUnknownProvenance records the nodes and edges no document asserted, like those of data
+// ingested before provenance was tracked, as owned by an unknown document.
+func RecordUnknownProvenance(ctx context.Context, storage graph.Storage) error {
+	contributions, err := loadContributions(ctx, storage)
+	if err != nil {
+		return err
+	}
+	known := NewContribution()
+	for _, contribution := range contributions {
+		known.Nodes.Or(contribution.Nodes)
+		known.Edges.Or(contribution.Edges)
+	}
+	unknown, err := loadContributionAs(ctx, storage, UnknownProvenanceTag, unknownProvenanceKey)
+	if err != nil {
+		return err
+	}
+	if unknown == nil {
+		unknown = NewContribution()
+	}
+
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	for id, node := range nodes {
+		if !known.Nodes.Contains(id) {
+			unknown.addNode(id)
+		}
+		if node.Children == nil {
+			continue
+		}
+		for _, child := range node.Children.ToArray() {
+			if !known.Edges.Contains(uint64(id)<<32 | uint64(child)) {
+				unknown.addEdge(id, child)
+			}
+		}
+	}
+	if unknown.Nodes.IsEmpty() {
+		return nil
+	}
+	return saveContributionAs(ctx, storage, UnknownProvenanceTag, unknownProvenanceKey, unknown)
+}
+
+// savedContribution is how a contribution is saved, as a single value so that it is never seen
+// half written.
+type savedContribution struct {
+	Nodes    []byte            `json:"nodes"`
+	Edges    []byte            `json:"edges"`
+	Licenses map[uint32]string `json:"licenses,omitempty"`
+}
+
+// saveLicenseExpressions stores the license expressions the document asserts, for license
+// policies, and clears the ones its previous version asserted that it no longer does.
+func saveLicenseExpressions(ctx context.Context, storage graph.Storage, name string, previous, current *Contribution) error {
+	if previous == nil {
+		previous = NewContribution()
+	}
+	updates := map[uint32]string{}
+	for id, expression := range current.Licenses {
+		if previous.Licenses[id] != expression {
+			updates[id] = expression
+		}
+	}
+	for id := range previous.Licenses {
+		if _, ok := current.Licenses[id]; !ok {
+			updates[id] = ""
+		}
+	}
+	for id, expression := range updates {
+		library, err := storage.GetNode(ctx, id)
+		if errors.Is(err, graph.ErrNodeNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get library %d: %w", id, err)
+		}
+		if err := storage.AddOrUpdateCustomData(ctx, license.ExpressionsTag, library.Name, name, []byte(expression)); err != nil {
+			return fmt.Errorf("failed to store the license expression of %s: %w", library.Name, err)
+		}
+	}
+	return nil
+}
+
+func saveContribution(ctx context.Context, storage graph.Storage, name string, contribution *Contribution) error {
+	return saveContributionAs(ctx, storage, ProvenanceTag, name, contribution)
+}
+
+// saveContributionAs saves the contribution under the custom data tag and key.
+func saveContributionAs(ctx context.Context, storage graph.Storage, tag, name string, contribution *Contribution) error {
+	nodes, err := contribution.Nodes.ToBytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode nodes of %s: %w", name, err)
+	}
+	edges, err := contribution.Edges.ToBytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode edges of %s: %w", name, err)
+	}
+	data, err := json.Marshal(savedContribution{Nodes: nodes, Edges: edges, Licenses: contribution.Licenses})
+	if err != nil {
+		return fmt.Errorf("failed to encode contribution of %s: %w", name, err)
+	}
+	if err := storage.AddOrUpdateCustomData(ctx, tag, name, provenanceContributionKey, data); err != nil {
+		return fmt.Errorf("failed to save contribution of %s: %w", name, err)
+	}
+	return nil
+}
+
+// loadContribution returns the contribution of the document, or nil if it wasn't ingested.
+func loadContribution(ctx context.Context, storage graph.Storage, name string) (*Contribution, error) {
+	return loadContributionAs(ctx, storage, ProvenanceTag, name)
+}
+
+// loadContributionAs returns the contribution saved under the custom data tag and key, or nil
+// if there is none.
+func loadContributionAs(ctx context.Context, storage graph.Storage, tag, name string) (*Contribution, error) {
+	data, err := storage.GetCustomData(ctx, tag, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contribution of %s: %w", name, err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	saved := savedContribution{Nodes: data[provenanceNodesKey], Edges: data[provenanceEdgesKey]}
+	if value, ok := data[provenanceContributionKey]; ok {
+		if err := json.Unmarshal(value, &saved); err != nil {
+			return nil, fmt.Errorf("failed to decode contribution of %s: %w", name, err)
+		}
+	}
+	contribution := NewContribution()
+	for id, expression := range saved.Licenses {
+		contribution.Licenses[id] = expression
+	}
+	if len(saved.Nodes) > 0 {
+		if err := contribution.Nodes.UnmarshalBinary(saved.Nodes); err != nil {
+			return nil, fmt.Errorf("failed to decode nodes of %s: %w", name, err)
+		}
+	}
+	if len(saved.Edges) > 0 {
+		if err := contribution.Edges.UnmarshalBinary(saved.Edges); err != nil {
+			return nil, fmt.Errorf("failed to decode edges of %s: %w", name, err)
+		}
+	}
+	return contribution, nil
+}
+
+// loadContributions returns the contributions of all documents by document name.
+func loadContributions(ctx context.Context, storage graph.Storage) (map[string]*Contribution, error) {
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	contributions := make(map[string]*Contribution, len(keys[ProvenanceTag]))
+	for _, name := range keys[ProvenanceTag] {
+		contribution, err := loadContribution(ctx, storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if contribution != nil {
+			contributions[name] = contribution
+		}
+	}
+	return contributions, nil
+}
@@ -0,0 +1,229 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cycloneDX returns a CycloneDX SBOM in which app depends on every given package.
+func cycloneDX(serialNumber, app string, dependencies ...string) []byte {
+	var components, refs []string
+	for _, name := range dependencies {
+		components = append(components, fmt.Sprintf(`{"bom-ref":%q,"type":"library","name":%q,"version":"1.0.0","purl":"pkg:npm/%s@1.0.0"}`, name, name, name))
+		refs = append(refs, fmt.Sprintf("%q", name))
+	}
+	return []byte(fmt.Sprintf(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "serialNumber": %q,
+  "version": 1,
+  "metadata": {
+    "timestamp": "2024-05-01T10:00:00Z",
+    "tools": [{"vendor": "anchore", "name": "syft", "version": "1.0.0"}],
+    "component": {"bom-ref": %q, "type": "application", "name": %q, "version": "1.0.0", "purl": "pkg:npm/%s@1.0.0"}
+  },
+  "components": [%s],
+  "dependencies": [{"ref": %q, "dependsOn": [%s]}]
+}`, serialNumber, app, app, app, strings.Join(components, ","), app, strings.Join(refs, ",")))
+}
+
+func nodeByName(t *testing.T, storage graph.Storage, name string) *graph.Node {
+	t.Helper()
+	id, err := storage.NameToID(context.Background(), name)
+	require.NoError(t, err)
+	node, err := storage.GetNode(context.Background(), id)
+	require.NoError(t, err)
+	return node
+}
+
+func TestSBOMProvenance(t *testing.T) {
+	storage := graph.NewMockStorage()
+	const appDocument = "sbom:urn:uuid:11111111-1111-1111-1111-111111111111"
+	const otherDocument = "sbom:urn:uuid:22222222-2222-2222-2222-222222222222"
+
+	require.NoError(t, SBOM(context.Background(), storage, cycloneDX(strings.TrimPrefix(appDocument, "sbom:"), "app", "b", "c", "e")))
+	require.NoError(t, SBOM(context.Background(), storage, cycloneDX(strings.TrimPrefix(otherDocument, "sbom:"), "other", "c")))
+
+	documents, err := Documents(context.Background(), storage)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	assert.Equal(t, appDocument, documents[0].Name)
+	assert.Equal(t, otherDocument, documents[1].Name)
+	assert.Equal(t, tools.SBOMDocumentType, documents[0].Type)
+	metadata, ok := documents[0].Metadata.(DocumentMetadata)
+	require.True(t, ok)
+	assert.Equal(t, "urn:uuid:11111111-1111-1111-1111-111111111111", metadata.SerialNumber)
+	assert.Equal(t, []string{"anchore syft 1.0.0"}, metadata.Tools)
+	assert.Equal(t, "2024-05-01T10:00:00Z", metadata.Timestamp)
+	assert.Len(t, metadata.SHA256, 64)
+
+	app := nodeByName(t, storage, "pkg:npm/app@1.0.0")
+	c := nodeByName(t, storage, "pkg:npm/c@1.0.0")
+	e := nodeByName(t, storage, "pkg:npm/e@1.0.0")
+	_, contribution, err := GetContribution(context.Background(), storage, appDocument)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), contribution.Nodes.GetCardinality())
+	assert.Len(t, contribution.EdgeList(), 3)
+	assert.Contains(t, contribution.EdgeList(), Edge{From: app.ID, To: e.ID})
+
+	shared, err := DocumentsForNode(context.Background(), storage, c.ID)
+	require.NoError(t, err)
+	require.Len(t, shared, 2)
+
+	// The new version of the SBOM drops c and e and adds d. The edge to c and the node e, which
+	// only the previous version asserted, are gone, c stays because the other SBOM asserts it.
+	require.NoError(t, SBOM(context.Background(), storage, cycloneDX(strings.TrimPrefix(appDocument, "sbom:"), "app", "b", "d")))
+
+	documents, err = Documents(context.Background(), storage)
+	require.NoError(t, err)
+	assert.Len(t, documents, 2)
+
+	app = nodeByName(t, storage, "pkg:npm/app@1.0.0")
+	b := nodeByName(t, storage, "pkg:npm/b@1.0.0")
+	d := nodeByName(t, storage, "pkg:npm/d@1.0.0")
+	assert.ElementsMatch(t, []uint32{b.ID, d.ID}, app.Children.ToArray())
+	c = nodeByName(t, storage, "pkg:npm/c@1.0.0")
+	assert.Equal(t, []uint32{nodeByName(t, storage, "pkg:npm/other@1.0.0").ID}, c.Parents.ToArray())
+	_, err = storage.NameToID(context.Background(), "pkg:npm/e@1.0.0")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+
+	_, contribution, err = GetContribution(context.Background(), storage, appDocument)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{app.ID, b.ID, d.ID}, contribution.Nodes.ToArray())
+
+	shared, err = DocumentsForNode(context.Background(), storage, c.ID)
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+	assert.Equal(t, otherDocument, shared[0].Name)
+
+	_, _, err = GetContribution(context.Background(), storage, "sbom:missing")
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+}
+
+// documentSavingStorage fails whenever a whole SBOM document node is saved.
+type documentSavingStorage struct {
+	*graph.MockStorage
+}
+
+func (s documentSavingStorage) SaveNode(ctx context.Context, node *graph.Node) error {
+	if node.Type == tools.SBOMDocumentType {
+		return fmt.Errorf("saved the whole document node %s", node.Name)
+	}
+	return s.MockStorage.SaveNode(ctx, node)
+}
+
+func TestRecordDocumentUpdatesMetadataOnly(t *testing.T) {
+	storage := documentSavingStorage{graph.NewMockStorage()}
+	const serialNumber = "urn:uuid:33333333-3333-3333-3333-333333333333"
+	require.NoError(t, SBOM(context.Background(), storage, cycloneDX(serialNumber, "app", "b")))
+	updated := cycloneDX(serialNumber, "app", "c")
+	require.NoError(t, SBOM(context.Background(), storage, updated))
+
+	document := nodeByName(t, storage, "sbom:"+serialNumber)
+	metadata, ok := document.Metadata.(DocumentMetadata)
+	require.True(t, ok)
+	assert.Equal(t, Digest(updated), metadata.SHA256)
+}
+
+func TestSBOMWithoutSerialNumber(t *testing.T) {
+	storage := graph.NewMockStorage()
+	data := cycloneDX("", "app", "b")
+	require.NoError(t, SBOM(context.Background(), storage, data))
+	// Ingesting the same content again doesn't add another document.
+	require.NoError(t, SBOM(context.Background(), storage, data))
+
+	documents, err := Documents(context.Background(), storage)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.Equal(t, "sbom:sha256:"+Digest(data), documents[0].Name)
+}
+
+func TestUnchanged(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	const serialNumber = "urn:uuid:11111111-1111-1111-1111-111111111111"
+	first := cycloneDX(serialNumber, "app", "b")
+	second := cycloneDX(serialNumber, "app", "c")
+
+	unchanged, err := Unchanged(ctx, storage, Digest(first))
+	require.NoError(t, err)
+	assert.False(t, unchanged)
+
+	require.NoError(t, SBOM(ctx, storage, first))
+	unchanged, err = Unchanged(ctx, storage, Digest(first))
+	require.NoError(t, err)
+	assert.True(t, unchanged)
+
+	// Once a new version replaced the document, the first version isn't unchanged anymore.
+	require.NoError(t, SBOM(ctx, storage, second))
+	unchanged, err = Unchanged(ctx, storage, Digest(first))
+	require.NoError(t, err)
+	assert.False(t, unchanged)
+	unchanged, err = Unchanged(ctx, storage, Digest(second))
+	require.NoError(t, err)
+	assert.True(t, unchanged)
+
+	storage.GetCustomDataErr = assert.AnError
+	_, err = Unchanged(ctx, storage, Digest(second))
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestSaveContribution(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	contribution := NewContribution()
+	contribution.addEdge(1, 2)
+	contribution.addNode(3)
+	require.NoError(t, saveContribution(ctx, storage, "sbom:a", contribution))
+
+	// The nodes and edges are a single value, never seen one without the other.
+	data, err := storage.GetCustomData(ctx, ProvenanceTag, "sbom:a")
+	require.NoError(t, err)
+	assert.Len(t, data, 1)
+	loaded, err := loadContribution(ctx, storage, "sbom:a")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{1, 2, 3}, loaded.Nodes.ToArray())
+	assert.Equal(t, []Edge{{From: 1, To: 2}}, loaded.EdgeList())
+
+	// Contributions saved as separate nodes and edges still load.
+	nodes, err := contribution.Nodes.ToBytes()
+	require.NoError(t, err)
+	edges, err := contribution.Edges.ToBytes()
+	require.NoError(t, err)
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, ProvenanceTag, "sbom:b", provenanceNodesKey, nodes))
+	require.NoError(t, storage.AddOrUpdateCustomData(ctx, ProvenanceTag, "sbom:b", provenanceEdgesKey, edges))
+	loaded, err = loadContribution(ctx, storage, "sbom:b")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint32{1, 2, 3}, loaded.Nodes.ToArray())
+	assert.Equal(t, []Edge{{From: 1, To: 2}}, loaded.EdgeList())
+}
+
+func TestUnknownProvenance(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	// The edge from app to b was ingested before provenance was tracked.
+	app, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:npm/app@1.0.0")
+	require.NoError(t, err)
+	b, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:npm/b@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, app.SetDependency(ctx, storage, b))
+	require.NoError(t, RecordUnknownProvenance(ctx, storage))
+
+	const serialNumber = "urn:uuid:55555555-5555-5555-5555-555555555555"
+	require.NoError(t, SBOM(ctx, storage, cycloneDX(serialNumber, "app", "b", "c")))
+	require.NoError(t, RecordUnknownProvenance(ctx, storage), "nodes and edges of documents aren't of unknown provenance")
+	require.NoError(t, SBOM(ctx, storage, cycloneDX(serialNumber, "app")))
+
+	// The document no longer asserts the edges, but only what it introduced is removed.
+	app = nodeByName(t, storage, "pkg:npm/app@1.0.0")
+	assert.Equal(t, []uint32{b.ID}, app.Children.ToArray())
+	_, err = storage.NameToID(ctx, "pkg:npm/c@1.0.0")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+}
@@ -2,14 +2,24 @@ package ingest
 
 import (
 	"bytes"
-	"errors"
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
 	"github.com/protobom/protobom/pkg/reader"
+	"github.com/protobom/protobom/pkg/sbom"
 )
 
-func SBOM(storage graph.Storage, data []byte) error {
+// generatedSPDXNamespace is the prefix of the identifier protobom generates for SPDX documents
+// without a namespace. It is random, so it can't identify a re-ingested document.
+const generatedSPDXNamespace = "https://spdx.org/spdxdocs/protobom-"
+
+func SBOM(ctx context.Context, storage graph.Storage, data []byte, opts ...Option) error {
+	o := newOptions(opts)
 	if len(data) == 0 {
 		return fmt.Errorf("data is empty")
 	}
@@ -22,55 +32,188 @@ func SBOM(storage graph.Storage, data []byte) error {
 		return fmt.Errorf("failed to parse SBOM file: %w", err)
 	}
 
+	metadata := sbomDocumentMetadata(document.GetMetadata(), Digest(data))
+	if metadata.Timestamp == "" && len(metadata.Tools) == 0 {
+		// protobom doesn't read the timestamp and tools of CycloneDX SBOMs.
+		metadata.Timestamp, metadata.Tools = cycloneDXMetadata(data)
+	}
+	contribution := NewContribution()
 	// Get the node list from the document
 	nodeList := document.GetNodeList()
 	if nodeList == nil {
-		return nil
+		return recordSBOM(ctx, storage, o, metadata, contribution)
 	}
 
 	// Process each node in the SBOM
 
 	nameToId := map[string]uint32{}
+	swidTags := cycloneDXSWIDTags(data)
 
 	for _, node := range nodeList.GetNodes() {
-		purl := string(node.Purl())
-		if purl == "" {
-			purl = fmt.Sprintf("pkg:%s@%s", node.GetName(), node.GetVersion())
-		}
-
-		graphNode, err := graph.AddNode(storage, "library", node, purl)
+		graphNode, err := addSBOMNode(ctx, storage, o, node, swidTags)
 		if err != nil {
-			if errors.Is(err, graph.ErrNodeAlreadyExists) {
-				// log.Printf("Skipping node %s: %s\n", node.GetName(), err)
-			} else {
-				return fmt.Errorf("failed to add node: %w", err)
-			}
+			return err
 		}
 
 		nameToId[node.Id] = graphNode.ID
+		contribution.addNode(graphNode.ID)
+		if err := addLicenses(ctx, storage, graphNode, node, contribution); err != nil {
+			return err
+		}
 	}
 
 	for _, edge := range nodeList.Edges {
-		fromNode, err := storage.GetNode(nameToId[edge.From])
+		fromNode, err := storage.GetNode(ctx, nameToId[edge.From])
 		if err != nil {
 			return fmt.Errorf("failed to get from node %s: %w", edge.From, err)
 		}
 
 		for _, to := range edge.To {
 
-			toNode, err := storage.GetNode(nameToId[to])
+			toNode, err := storage.GetNode(ctx, nameToId[to])
 			if err != nil {
 				return fmt.Errorf("failed to to get node %s: %w", edge.To, err)
 			}
 
 			if fromNode.ID != toNode.ID {
-				if err := fromNode.SetDependency(storage, toNode); err != nil {
+				if err := fromNode.SetDependency(ctx, storage, toNode); err != nil {
 					return fmt.Errorf("failed to add edge %s -> %s: %w", edge.From, to, err)
 				}
+				contribution.addEdge(fromNode.ID, toNode.ID)
 			}
 
 		}
 	}
 
-	return nil
+	return recordSBOM(ctx, storage, o, metadata, contribution)
+}
+
+// addSBOMNode adds the library node of an SBOM component, named by its normalized purl, along
+// with its aliases.
+func addSBOMNode(ctx context.Context, storage graph.Storage, o *options, node *sbom.Node, swidTags map[string]string) (*graph.Node, error) {
+	purl := string(node.Purl())
+	if purl == "" {
+		purl = fmt.Sprintf("pkg:%s@%s", node.GetName(), node.GetVersion())
+	} else if normalized, err := o.normalizer.Normalize(purl); err == nil {
+		// Spellings of the same purl get the same node, invalid purls are kept as they are.
+		purl = normalized
+	}
+
+	graphNode, err := graph.AddOrMergeNode(ctx, storage, o.mergePolicy, "library", node, purl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add node: %w", err)
+	}
+
+	if err := indexPackage(ctx, storage, graphNode); err != nil {
+		return nil, err
+	}
+	if aliases := nodeAliases(node, purl, swidTags); len(aliases) > 0 {
+		if err := storage.AddAliases(ctx, graphNode.ID, aliases); err != nil {
+			return nil, fmt.Errorf("failed to add aliases of node %s: %w", purl, err)
+		}
+	}
+	return graphNode, nil
+}
+
+// sbomDocumentMetadata returns the metadata of the document node of an SBOM with the given
+// protobom metadata and SHA-256 digest.
+func sbomDocumentMetadata(metadata *sbom.Metadata, sha256 string) DocumentMetadata {
+	documentMetadata := DocumentMetadata{
+		Name:         metadata.GetName(),
+		SerialNumber: metadata.GetId(),
+		Version:      metadata.GetVersion(),
+		SHA256:       sha256,
+	}
+	if date := metadata.GetDate(); date.IsValid() && (date.GetSeconds() != 0 || date.GetNanos() != 0) {
+		documentMetadata.Timestamp = date.AsTime().UTC().Format(time.RFC3339)
+	}
+	for _, tool := range metadata.GetTools() {
+		documentMetadata.Tools = appendTool(documentMetadata.Tools, tool.GetVendor(), tool.GetName(), tool.GetVersion())
+	}
+	return documentMetadata
+}
+
+// recordSBOM records the SBOM as a document node. SBOMs are identified by their serial number
+// or SPDX namespace, so a new version of the same SBOM replaces the previous one. SBOMs without
+// one are identified by their content. Its library nodes are linked to the stored advisories
+// affecting them first, since advisories may have been ingested before the SBOM.
+func recordSBOM(ctx context.Context, storage graph.Storage, o *options, metadata DocumentMetadata, contribution *Contribution) error {
+	if err := linkAdvisories(ctx, storage, o, contribution.Nodes.ToArray()); err != nil {
+		return err
+	}
+	identity := metadata.SerialNumber
+	if identity == "" || strings.HasPrefix(identity, generatedSPDXNamespace) {
+		metadata.SerialNumber = ""
+		identity = "sha256:" + metadata.SHA256
+	}
+	return recordDocument(ctx, storage, tools.SBOMDocumentType, tools.SBOMDocumentType+":"+identity, metadata, contribution)
+}
+
+type cycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Tools listed as components since CycloneDX 1.5 name their vendor as author or group.
+	Author string `json:"author"`
+	Group  string `json:"group"`
+}
+
+// cycloneDXBOMMetadata holds the parts of the metadata of a CycloneDX SBOM that protobom
+// doesn't read.
+type cycloneDXBOMMetadata struct {
+	Timestamp string          `json:"timestamp"`
+	Tools     json.RawMessage `json:"tools"`
+}
+
+// cycloneDXMetadata returns the timestamp and tools of a CycloneDX SBOM, or nothing if the
+// data isn't a CycloneDX JSON SBOM.
+func cycloneDXMetadata(data []byte) (string, []string) {
+	var bom struct {
+		Metadata cycloneDXBOMMetadata `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return "", nil
+	}
+	return bom.Metadata.Timestamp, bom.Metadata.tools()
+}
+
+// tools returns the names of the tools that created the SBOM.
+func (m cycloneDXBOMMetadata) tools() []string {
+	// Tools are a list before CycloneDX 1.5 and a list of components and services since.
+	var list []cycloneDXTool
+	if err := json.Unmarshal(m.Tools, &list); err != nil {
+		var toolsByKind struct {
+			Components []cycloneDXTool `json:"components"`
+			Services   []cycloneDXTool `json:"services"`
+		}
+		if err := json.Unmarshal(m.Tools, &toolsByKind); err == nil {
+			list = append(toolsByKind.Components, toolsByKind.Services...)
+		}
+	}
+	var names []string
+	for _, tool := range list {
+		vendor := tool.Vendor
+		if vendor == "" {
+			vendor = tool.Author
+		}
+		if vendor == "" {
+			vendor = tool.Group
+		}
+		names = appendTool(names, vendor, tool.Name, tool.Version)
+	}
+	return names
+}
+
+// appendTool appends the name of a tool made of its non empty vendor, name and version.
+func appendTool(names []string, vendor, name, version string) []string {
+	var parts []string
+	for _, part := range []string{vendor, name, version} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return names
+	}
+	return append(names, strings.Join(parts, " "))
 }
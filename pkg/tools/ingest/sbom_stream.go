@@ -0,0 +1,634 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/protobom/protobom/pkg/reader"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sbomStreamBatchSize is the number of SBOM elements SBOMStream converts to nodes at once.
+const sbomStreamBatchSize = 256
+
+// streamBatchRoot is the bom-ref of the component SBOMStream wraps batches of CycloneDX
+// components in. It never becomes a node.
+const streamBatchRoot = "minefield:stream-batch-root"
+
+type sbomFormat int
+
+const (
+	unknownFormat sbomFormat = iota
+	cycloneDXFormat
+	spdxFormat
+)
+
+// ErrSBOMNotJSON is returned by SBOMStream for SBOMs that aren't JSON documents.
+var ErrSBOMNotJSON = errors.New("only JSON SBOMs can be streamed")
+
+// SBOMStream ingests a CycloneDX or SPDX JSON SBOM read from r like SBOM does, without holding
+// the whole document in memory. Only the parsing is bounded: elements are decoded and converted
+// to nodes a batch at a time, but the node IDs of all elements, the top-level CycloneDX
+// components and the SPDX relationships naming elements not read yet are kept until the end of
+// the document, so memory use still grows with the number of elements. Other encodings are
+// rejected with ErrSBOMNotJSON before anything is ingested; SBOM can't parse them either.
+func SBOMStream(ctx context.Context, storage graph.Storage, r io.Reader, opts ...Option) error {
+	hash := sha256.New()
+	buffered := bufio.NewReader(io.TeeReader(r, hash))
+
+	first, err := firstNonSpace(buffered)
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("data is empty")
+	} else if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+		return fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	if first != '{' {
+		return ErrSBOMNotJSON
+	}
+
+	s := &sbomStream{
+		ctx:          ctx,
+		storage:      storage,
+		options:      newOptions(opts),
+		contribution: NewContribution(),
+		ids:          map[string]uint32{},
+	}
+	if err := s.decode(json.NewDecoder(buffered)); err != nil {
+		return err
+	}
+	if err := s.finish(); err != nil {
+		return err
+	}
+
+	// The digest covers everything after the document too, like it does for SBOM.
+	if _, err := io.Copy(io.Discard, buffered); err != nil {
+		return fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	return recordSBOM(ctx, storage, s.options, s.documentMetadata(hex.EncodeToString(hash.Sum(nil))), s.contribution)
+}
+
+// firstNonSpace returns the first byte of r that isn't whitespace without consuming it.
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		peeked, err := r.Peek(n)
+		if len(peeked) < n {
+			return 0, err
+		}
+		switch c := peeked[n-1]; c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return c, nil
+		}
+	}
+}
+
+// sbomStream holds the state of an SBOM being ingested by SBOMStream.
+type sbomStream struct {
+	ctx          context.Context
+	storage      graph.Storage
+	options      *options
+	contribution *Contribution
+
+	format sbomFormat
+	// ids maps the IDs protobom gives the elements of the SBOM to their graph node IDs. It holds
+	// every element read so far.
+	ids   map[string]uint32
+	batch []json.RawMessage
+
+	// CycloneDX
+	specVersion  string
+	serialNumber string
+	version      int64
+	metadata     cycloneDXBOMMetadata
+	root         *uint32
+	// components counts the components read so far, to number those without a bom-ref.
+	components int
+	// topLevel holds the components listed at the top of the components array, which the root
+	// contains once the whole document is read.
+	topLevel []uint32
+
+	// SPDX
+	spdxVersion       string
+	spdxID            string
+	name              string
+	documentNamespace string
+	creationInfo      struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+	// batchKey is the key of the SPDX element list being batched, "packages" or "files".
+	batchKey string
+	// pending holds relationships between elements that haven't been read yet.
+	pending []spdxRelationship
+}
+
+type spdxRelationship struct {
+	Element          string `json:"spdxElementId"`
+	RelationshipType string `json:"relationshipType"`
+	RelatedElement   string `json:"relatedSpdxElement"`
+}
+
+// decode reads the top-level object of a JSON SBOM, adding the nodes and edges of its elements
+// as they are read.
+func (s *sbomStream) decode(dec *json.Decoder) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse SBOM file: %w", err)
+		}
+		key, _ := token.(string)
+
+		switch key {
+		case "bomFormat", "specVersion", "serialNumber", "version", "metadata", "components":
+			if err := s.setFormat(cycloneDXFormat); err != nil {
+				return err
+			}
+		case "spdxVersion", "SPDXID", "name", "documentNamespace", "creationInfo", "packages", "files", "relationships":
+			if err := s.setFormat(spdxFormat); err != nil {
+				return err
+			}
+		}
+
+		switch key {
+		case "specVersion":
+			err = dec.Decode(&s.specVersion)
+		case "serialNumber":
+			err = dec.Decode(&s.serialNumber)
+		case "version":
+			err = dec.Decode(&s.version)
+		case "metadata":
+			err = s.decodeCycloneDXMetadata(dec)
+		case "components":
+			err = decodeArray(dec, s.addCycloneDXComponent)
+			if err == nil {
+				err = s.flushCycloneDX()
+			}
+		case "spdxVersion":
+			err = dec.Decode(&s.spdxVersion)
+		case "SPDXID":
+			err = dec.Decode(&s.spdxID)
+		case "name":
+			err = dec.Decode(&s.name)
+		case "documentNamespace":
+			err = dec.Decode(&s.documentNamespace)
+		case "creationInfo":
+			err = dec.Decode(&s.creationInfo)
+		case "packages", "files":
+			s.batchKey = key
+			err = decodeArray(dec, s.addSPDXElement)
+			if err == nil {
+				err = s.flushSPDX()
+			}
+		case "relationships":
+			err = decodeArray(dec, s.addSPDXRelationship)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse SBOM file: %w", err)
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func (s *sbomStream) setFormat(format sbomFormat) error {
+	if s.format != unknownFormat && s.format != format {
+		return fmt.Errorf("failed to parse SBOM file: mixes CycloneDX and SPDX fields")
+	}
+	s.format = format
+	return nil
+}
+
+// decodeCycloneDXMetadata reads the metadata of a CycloneDX SBOM, adding the node of the
+// component it describes.
+func (s *sbomStream) decodeCycloneDXMetadata(dec *json.Decoder) error {
+	var metadata struct {
+		cycloneDXBOMMetadata
+		Component json.RawMessage `json:"component"`
+	}
+	if err := dec.Decode(&metadata); err != nil {
+		return err
+	}
+	s.metadata = metadata.cycloneDXBOMMetadata
+	if len(metadata.Component) == 0 || string(metadata.Component) == "null" {
+		return nil
+	}
+
+	component, err := s.numberCycloneDXComponent(metadata.Component)
+	if err != nil {
+		return err
+	}
+	document, err := s.cycloneDXDocument(component, nil)
+	if err != nil {
+		return err
+	}
+	nodeList := document.GetNodeList()
+	if err := s.addNodeList(nodeList, document.swidTags); err != nil {
+		return err
+	}
+	if roots := nodeList.GetRootElements(); len(roots) > 0 {
+		root := s.ids[roots[0]]
+		s.root = &root
+	}
+	return nil
+}
+
+func (s *sbomStream) addCycloneDXComponent(component json.RawMessage) error {
+	component, err := s.numberCycloneDXComponent(component)
+	if err != nil {
+		return err
+	}
+	s.batch = append(s.batch, component)
+	if len(s.batch) < sbomStreamBatchSize {
+		return nil
+	}
+	return s.flushCycloneDX()
+}
+
+// flushCycloneDX adds the nodes of the batched top-level components and their nested ones.
+func (s *sbomStream) flushCycloneDX() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	root, err := json.Marshal(map[string]string{"bom-ref": streamBatchRoot, "type": "application", "name": streamBatchRoot})
+	if err != nil {
+		return err
+	}
+	document, err := s.cycloneDXDocument(root, s.batch)
+	if err != nil {
+		return err
+	}
+	s.batch = s.batch[:0]
+
+	// The batch root contains the top-level components.
+	nodeList := document.GetNodeList()
+	var topLevel []string
+	for _, edge := range nodeList.GetEdges() {
+		if edge.GetFrom() == streamBatchRoot {
+			topLevel = append(topLevel, edge.GetTo()...)
+		}
+	}
+	if err := s.addNodeList(nodeList, document.swidTags); err != nil {
+		return err
+	}
+	for _, id := range topLevel {
+		s.topLevel = append(s.topLevel, s.ids[id])
+	}
+	return nil
+}
+
+// numberCycloneDXComponent gives the component and its nested components without a bom-ref
+// the ID protobom would give them in the whole document, since it numbers them in the order
+// they are listed.
+func (s *sbomStream) numberCycloneDXComponent(data json.RawMessage) (json.RawMessage, error) {
+	var component map[string]json.RawMessage
+	if err := json.Unmarshal(data, &component); err != nil {
+		return nil, err
+	}
+	s.components++
+	changed := false
+
+	var bomRef string
+	if raw, ok := component["bom-ref"]; ok {
+		if err := json.Unmarshal(raw, &bomRef); err != nil {
+			return nil, err
+		}
+	}
+	if bomRef == "" {
+		raw, err := json.Marshal(sbom.NewNodeIdentifier("auto", fmt.Sprintf("%09d", s.components)))
+		if err != nil {
+			return nil, err
+		}
+		component["bom-ref"] = raw
+		changed = true
+	}
+
+	if raw, ok := component["components"]; ok {
+		var nested []json.RawMessage
+		if err := json.Unmarshal(raw, &nested); err != nil {
+			return nil, err
+		}
+		for i := range nested {
+			numbered, err := s.numberCycloneDXComponent(nested[i])
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(numbered, nested[i]) {
+				nested[i] = numbered
+				changed = true
+			}
+		}
+		if changed {
+			raw, err := json.Marshal(nested)
+			if err != nil {
+				return nil, err
+			}
+			component["components"] = raw
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(component)
+}
+
+type parsedDocument struct {
+	*sbom.Document
+	swidTags map[string]string
+}
+
+// cycloneDXDocument parses a CycloneDX document made of the given metadata component and
+// components.
+func (s *sbomStream) cycloneDXDocument(root json.RawMessage, components []json.RawMessage) (*parsedDocument, error) {
+	specVersion := s.specVersion
+	if specVersion == "" {
+		specVersion = "1.5"
+	}
+	data, err := json.Marshal(map[string]any{
+		"bomFormat":   "CycloneDX",
+		"specVersion": specVersion,
+		"version":     1,
+		"metadata":    map[string]json.RawMessage{"component": root},
+		"components":  components,
+	})
+	if err != nil {
+		return nil, err
+	}
+	document, err := reader.New().ParseStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &parsedDocument{document, cycloneDXSWIDTags(data)}, nil
+}
+
+func (s *sbomStream) addSPDXElement(element json.RawMessage) error {
+	s.batch = append(s.batch, element)
+	if len(s.batch) < sbomStreamBatchSize {
+		return nil
+	}
+	return s.flushSPDX()
+}
+
+// flushSPDX adds the nodes of the batched SPDX packages or files, and the relationships
+// between them that were read before them.
+func (s *sbomStream) flushSPDX() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	spdxVersion := s.spdxVersion
+	if spdxVersion == "" {
+		spdxVersion = "SPDX-2.3"
+	}
+	data, err := json.Marshal(map[string]any{
+		"spdxVersion":       spdxVersion,
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              streamBatchRoot,
+		"documentNamespace": "https://spdx.org/spdxdocs/" + streamBatchRoot,
+		"creationInfo": map[string]any{
+			"created":  "1970-01-01T00:00:00Z",
+			"creators": []string{"Tool: minefield"},
+		},
+		s.batchKey: s.batch,
+	})
+	if err != nil {
+		return err
+	}
+	document, err := reader.New().ParseStream(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	s.batch = s.batch[:0]
+
+	if err := s.addNodeList(document.GetNodeList(), nil); err != nil {
+		return err
+	}
+	pending := s.pending
+	s.pending = nil
+	for _, relationship := range pending {
+		if err := s.relate(relationship); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sbomStream) addSPDXRelationship(data json.RawMessage) error {
+	var relationship spdxRelationship
+	if err := json.Unmarshal(data, &relationship); err != nil {
+		return err
+	}
+	// Like protobom, DOCUMENT DESCRIBES relationships mark root elements rather than edges.
+	if spdxElementID(relationship.Element) == "DOCUMENT" && strings.EqualFold(relationship.RelationshipType, "DESCRIBES") {
+		return nil
+	}
+	return s.relate(relationship)
+}
+
+// relate adds the edge of an SPDX relationship, or keeps it until both of its elements have
+// been read.
+func (s *sbomStream) relate(relationship spdxRelationship) error {
+	from, fromOK := s.ids[spdxElementID(relationship.Element)]
+	to, toOK := s.ids[spdxElementID(relationship.RelatedElement)]
+	if !fromOK || !toOK {
+		s.pending = append(s.pending, relationship)
+		return nil
+	}
+	return s.addEdge(from, to)
+}
+
+// spdxElementID returns the ID protobom gives the SPDX element with the given identifier.
+func spdxElementID(identifier string) string {
+	if strings.HasPrefix(identifier, "DocumentRef-") {
+		if _, element, ok := strings.Cut(identifier, ":"); ok {
+			identifier = element
+		}
+	}
+	return strings.TrimPrefix(identifier, "SPDXRef-")
+}
+
+// addNodeList adds the nodes of a parsed batch and the edges between them, leaving out the
+// batch root.
+func (s *sbomStream) addNodeList(nodeList *sbom.NodeList, swidTags map[string]string) error {
+	for _, node := range nodeList.GetNodes() {
+		if node.GetId() == streamBatchRoot {
+			continue
+		}
+		graphNode, err := addSBOMNode(s.ctx, s.storage, s.options, node, swidTags)
+		if err != nil {
+			return err
+		}
+		s.ids[node.GetId()] = graphNode.ID
+		s.contribution.addNode(graphNode.ID)
+		if err := addLicenses(s.ctx, s.storage, graphNode, node, s.contribution); err != nil {
+			return err
+		}
+	}
+	for _, edge := range nodeList.GetEdges() {
+		if edge.GetFrom() == streamBatchRoot {
+			continue
+		}
+		for _, to := range edge.GetTo() {
+			if err := s.addEdge(s.ids[edge.GetFrom()], s.ids[to]); err != nil {
+				return fmt.Errorf("failed to add edge %s -> %s: %w", edge.GetFrom(), to, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *sbomStream) addEdge(from, to uint32) error {
+	if from == to {
+		return nil
+	}
+	if err := s.storage.AddDependency(s.ctx, from, to); err != nil {
+		return err
+	}
+	s.contribution.addEdge(from, to)
+	return nil
+}
+
+// finish adds the edges that need the whole document: the root of a CycloneDX SBOM contains
+// its top-level components, and SPDX relationships may name elements listed after them.
+func (s *sbomStream) finish() error {
+	switch s.format {
+	case cycloneDXFormat:
+		if err := s.flushCycloneDX(); err != nil {
+			return err
+		}
+		// Like protobom, without a metadata component the first component is the root.
+		topLevel := s.topLevel
+		if s.root == nil && len(topLevel) > 0 {
+			s.root, topLevel = &topLevel[0], topLevel[1:]
+		}
+		for _, id := range topLevel {
+			if err := s.addEdge(*s.root, id); err != nil {
+				return fmt.Errorf("failed to add edge: %w", err)
+			}
+		}
+	case spdxFormat:
+		if err := s.flushSPDX(); err != nil {
+			return err
+		}
+		for _, relationship := range s.pending {
+			from, ok := s.ids[spdxElementID(relationship.Element)]
+			if !ok {
+				return fmt.Errorf("failed to get from node %s: %w", relationship.Element, graph.ErrNodeNotFound)
+			}
+			to, ok := s.ids[spdxElementID(relationship.RelatedElement)]
+			if !ok {
+				return fmt.Errorf("failed to to get node %s: %w", relationship.RelatedElement, graph.ErrNodeNotFound)
+			}
+			if err := s.addEdge(from, to); err != nil {
+				return fmt.Errorf("failed to add edge %s -> %s: %w", relationship.Element, relationship.RelatedElement, err)
+			}
+		}
+		s.pending = nil
+	default:
+		return fmt.Errorf("failed to parse SBOM file: unknown SBOM format")
+	}
+	return nil
+}
+
+// documentMetadata returns the metadata of the document node, as SBOM would for the same
+// document.
+func (s *sbomStream) documentMetadata(sha256 string) DocumentMetadata {
+	// protobom gives SPDX documents version 0 too.
+	metadata := &sbom.Metadata{Version: fmt.Sprintf("%d", s.version)}
+	switch s.format {
+	case cycloneDXFormat:
+		metadata.Id = s.serialNumber
+	case spdxFormat:
+		if s.documentNamespace != "" {
+			metadata.Id = s.documentNamespace + "#" + spdxElementID(s.spdxID)
+		}
+		metadata.Name = s.name
+		if created, err := time.Parse(time.RFC3339, s.creationInfo.Created); err == nil {
+			metadata.Date = timestamppb.New(created)
+		}
+		for _, creator := range s.creationInfo.Creators {
+			if creatorType, name, ok := strings.Cut(creator, ":"); ok && strings.TrimSpace(creatorType) == "Tool" {
+				metadata.Tools = append(metadata.Tools, &sbom.Tool{Name: strings.TrimSpace(name)})
+			}
+		}
+	}
+
+	documentMetadata := sbomDocumentMetadata(metadata, sha256)
+	if documentMetadata.Timestamp == "" && len(documentMetadata.Tools) == 0 {
+		documentMetadata.Timestamp, documentMetadata.Tools = s.metadata.Timestamp, s.metadata.tools()
+	}
+	return documentMetadata
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse SBOM file: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to parse SBOM file: expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+// decodeArray calls fn with each element of the JSON array read by dec. A null array has no
+// elements.
+func decodeArray(dec *json.Decoder, fn func(json.RawMessage) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("expected an array, got %v", token)
+	}
+	for dec.More() {
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			return err
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// skipValue skips the JSON value read by dec one token at a time, so large values that aren't
+// ingested are never held in memory.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
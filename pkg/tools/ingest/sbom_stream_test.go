@@ -0,0 +1,191 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphSnapshot describes a storage by node names, so graphs built in a different order
+// compare equal.
+func graphSnapshot(t *testing.T, storage graph.Storage) map[string]any {
+	t.Helper()
+	ctx := context.Background()
+	ids, err := storage.GetAllKeys(ctx)
+	require.NoError(t, err)
+	nodes, err := storage.GetNodes(ctx, ids)
+	require.NoError(t, err)
+	names := map[uint32]string{}
+	for id, node := range nodes {
+		names[id] = node.Name
+	}
+	namesOf := func(ids []uint32) []string {
+		result := []string{}
+		for _, id := range ids {
+			result = append(result, names[id])
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	snapshot := map[string]any{}
+	for _, node := range nodes {
+		metadata, err := json.Marshal(node.Metadata)
+		require.NoError(t, err)
+		aliases, err := storage.GetAliases(ctx, node.ID)
+		require.NoError(t, err)
+		snapshot[node.Name] = []any{node.Type, string(metadata), namesOf(node.Children.ToArray()), aliases}
+	}
+
+	documents, err := Documents(ctx, storage)
+	require.NoError(t, err)
+	for _, document := range documents {
+		_, contribution, err := GetContribution(ctx, storage, document.Name)
+		require.NoError(t, err)
+		var edges []string
+		for _, edge := range contribution.EdgeList() {
+			edges = append(edges, names[edge.From]+" -> "+names[edge.To])
+		}
+		sort.Strings(edges)
+		snapshot["contribution "+document.Name] = []any{namesOf(contribution.Nodes.ToArray()), edges}
+	}
+	return snapshot
+}
+
+// testdataSBOMs returns the SBOMs in testdata by their path below it.
+func testdataSBOMs(t *testing.T) ([]string, map[string][]byte) {
+	t.Helper()
+	var names []string
+	sboms := map[string][]byte{}
+	for _, dir := range []string{"sboms", "small", "osv-sboms"} {
+		matches, err := filepath.Glob(filepath.Join("../../../testdata", dir, "*.json"))
+		require.NoError(t, err)
+		for _, file := range matches {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			name := filepath.Join(dir, filepath.Base(file))
+			names = append(names, name)
+			sboms[name] = data
+		}
+	}
+	require.NotEmpty(t, names)
+	return names, sboms
+}
+
+func TestSBOMStream(t *testing.T) {
+	_, documents := testdataSBOMs(t)
+	documents["spdx.json"] = []byte(`{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "app",
+  "documentNamespace": "https://example.com/app",
+  "creationInfo": {"created": "2024-05-01T10:00:00Z", "creators": ["Tool: test-1.0", "Organization: example"]},
+  "relationships": [
+    {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-app"},
+    {"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-lib"}
+  ],
+  "packages": [
+    {"SPDXID": "SPDXRef-app", "name": "app", "versionInfo": "1.0.0", "downloadLocation": "NOASSERTION",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/app@1.0.0"}]},
+    {"SPDXID": "SPDXRef-lib", "name": "lib", "versionInfo": "2.0.0", "downloadLocation": "NOASSERTION",
+     "checksums": [{"algorithm": "SHA256", "checksumValue": "abcd"}]}
+  ],
+  "files": [{"SPDXID": "SPDXRef-file", "fileName": "./main.js", "checksums": [{"algorithm": "SHA1", "checksumValue": "ef01"}]}]
+}`)
+	documents["cyclonedx-without-root.json"] = []byte(`{
+  "bomFormat": "CycloneDX", "specVersion": "1.4", "version": 3,
+  "components": [
+    {"bom-ref": "a", "type": "library", "name": "a", "version": "1", "purl": "pkg:npm/a@1"},
+    {"bom-ref": "b", "type": "library", "name": "b", "version": "1", "purl": "pkg:npm/b@1",
+     "components": [{"bom-ref": "c", "type": "library", "name": "c", "version": "1", "purl": "pkg:npm/c@1"}]}
+  ],
+  "dependencies": [{"ref": "a", "dependsOn": ["b"]}],
+  "metadata": {"timestamp": "2024-05-01T10:00:00Z", "tools": [{"vendor": "example", "name": "gen", "version": "1"}]}
+}`)
+
+	for name, data := range documents {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			expected := graph.NewMockStorage()
+			require.NoError(t, SBOM(ctx, expected, data))
+			actual := graph.NewMockStorage()
+			require.NoError(t, SBOMStream(ctx, actual, bytes.NewReader(data)))
+			assert.Equal(t, graphSnapshot(t, expected), graphSnapshot(t, actual))
+		})
+	}
+}
+
+func TestSBOMStreamTestdata(t *testing.T) {
+	// All SBOMs in one graph, so nodes shared between them and documents ingested again are
+	// handled the same way too.
+	names, sboms := testdataSBOMs(t)
+	ctx := context.Background()
+	expected := graph.NewMockStorage()
+	actual := graph.NewMockStorage()
+	for _, name := range names {
+		require.NoError(t, SBOM(ctx, expected, sboms[name]), name)
+		require.NoError(t, SBOMStream(ctx, actual, bytes.NewReader(sboms[name])), name)
+	}
+	assert.Equal(t, graphSnapshot(t, expected), graphSnapshot(t, actual))
+}
+
+func TestSBOMStreamBatches(t *testing.T) {
+	// More components than fit in a batch, with relationships naming packages listed later.
+	var packages, relationships []string
+	count := 2*sbomStreamBatchSize + 1
+	for i := 0; i < count; i++ {
+		packages = append(packages, fmt.Sprintf(`{"SPDXID": "SPDXRef-p%d", "name": "p%d", "versionInfo": "1", "downloadLocation": "NOASSERTION"}`, i, i))
+		if i > 0 {
+			relationships = append(relationships, fmt.Sprintf(`{"spdxElementId": "SPDXRef-p%d", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-p%d"}`, i-1, i))
+		}
+	}
+	data := []byte(fmt.Sprintf(`{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "name": "chain",
+  "relationships": [%s], "packages": [%s]}`, strings.Join(relationships, ","), strings.Join(packages, ",")))
+
+	ctx := context.Background()
+	expected := graph.NewMockStorage()
+	require.NoError(t, SBOM(ctx, expected, data))
+	actual := graph.NewMockStorage()
+	require.NoError(t, SBOMStream(ctx, actual, bytes.NewReader(data)))
+	assert.Equal(t, graphSnapshot(t, expected), graphSnapshot(t, actual))
+	assert.Equal(t, []uint32{nodeByName(t, actual, "pkg:p1@1").ID}, nodeByName(t, actual, "pkg:p0@1").Children.ToArray())
+}
+
+func TestSBOMStreamErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", "  \n"},
+		{"truncated", `{"bomFormat": "CycloneDX", "components": [{"bom-ref": "a"`},
+		{"unknown format", `{"foo": "bar"}`},
+		{"unknown element", `{"spdxVersion": "SPDX-2.3", "relationships": [
+  {"spdxElementId": "SPDXRef-a", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-b"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, SBOMStream(ctx, graph.NewMockStorage(), strings.NewReader(tt.data)))
+		})
+	}
+}
+
+func TestSBOMStreamRejectsOtherEncodings(t *testing.T) {
+	data := "SPDXVersion: SPDX-2.3\nDataLicense: CC0-1.0\nSPDXID: SPDXRef-DOCUMENT\nDocumentName: app\n"
+	storage := graph.NewMockStorage()
+	assert.ErrorIs(t, SBOMStream(context.Background(), storage, strings.NewReader(data)), ErrSBOMNotJSON)
+	keys, err := storage.GetAllKeys(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
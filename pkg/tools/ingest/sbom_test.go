@@ -1,12 +1,17 @@
 package ingest
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIngestSBOM(t *testing.T) {
@@ -30,19 +35,118 @@ func TestIngestSBOM(t *testing.T) {
 			t.Fatalf("Failed to read SBOM file %s: %v", file.Name(), err)
 		}
 
-		if err := SBOM(storage, data); err != nil {
+		if err := SBOM(context.Background(), storage, data); err != nil {
 			t.Fatalf("Failed to process SBOM from file %s: %v", file.Name(), err)
 		}
 	}
 
-	keys, err := storage.GetAllKeys()
+	keys, err := storage.GetAllKeys(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get all keys: %v", err)
 	}
 
-	// Verify we have the expected number of nodes
-	if len(keys) != 1600 {
-		t.Fatalf("Expected 1600 nodes to be created from SBOM ingestion, got %d", len(keys))
+	// Verify we have the expected number of nodes, 1600 libraries, a document node per SBOM and
+	// the Apache-2.0 license node
+	if len(keys) != 1631 {
+		t.Fatalf("Expected 1631 nodes to be created from SBOM ingestion, got %d", len(keys))
 	}
 
 }
+
+// sbomWithComponent returns a CycloneDX SBOM describing pkg:npm/lib@1.0.0 with the given
+// license and SHA-256 hash.
+func sbomWithComponent(license, hash string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [{
+    "bom-ref": "lib", "type": "library", "name": "lib", "version": "1.0.0", "purl": "pkg:npm/lib@1.0.0",
+    "licenses": [{"license": {"id": %q}}],
+    "hashes": [{"alg": "SHA-256", "content": %q}]
+  }]
+}`, license, hash))
+}
+
+func TestSBOMMergePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		licenses []any
+	}{
+		{"first wins by default", nil, []any{"MIT"}},
+		{"last wins", []Option{WithMergePolicy(graph.MergePolicy{"library": graph.MergeLastWins})}, []any{"Apache-2.0"}},
+		{"deep merge", []Option{WithMergePolicy(graph.MergePolicy{"library": graph.MergeDeep})}, []any{"MIT", "Apache-2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := graph.NewMockStorage()
+			require.NoError(t, SBOM(context.Background(), storage, sbomWithComponent("MIT", "aaaa"), tt.opts...))
+			require.NoError(t, SBOM(context.Background(), storage, sbomWithComponent("Apache-2.0", "bbbb"), tt.opts...))
+
+			id, err := storage.NameToID(context.Background(), "pkg:npm/lib@1.0.0")
+			require.NoError(t, err)
+			node, err := storage.GetNode(context.Background(), id)
+			require.NoError(t, err)
+			data, err := json.Marshal(node.Metadata)
+			require.NoError(t, err)
+			var metadata map[string]any
+			require.NoError(t, json.Unmarshal(data, &metadata))
+			assert.Equal(t, tt.licenses, metadata["licenses"])
+		})
+	}
+}
+
+func TestSBOMAliases(t *testing.T) {
+	storage := graph.NewMockStorage()
+	cdx := []byte(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [{
+    "bom-ref": "core", "type": "library", "name": "core", "version": "17.0.0",
+    "purl": "pkg:npm/@angular/core@17.0.0",
+    "cpe": "cpe:2.3:a:Google:Angular:17.0.0:*:*:*:*:*:*:*",
+    "swid": {"tagId": "swidgen-angular-core-17.0.0", "name": "core"},
+    "hashes": [{"alg": "SHA-256", "content": "AB12"}, {"alg": "SHA3-256", "content": "cd34"}]
+  }]
+}`)
+	require.NoError(t, SBOM(context.Background(), storage, cdx))
+
+	core := nodeByName(t, storage, "pkg:npm/%40angular/core@17.0.0")
+	aliases, err := storage.GetAliases(context.Background(), core.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"cpe:2.3:a:google:angular:17.0.0:*:*:*:*:*:*:*",
+		"pkg:npm/@angular/core@17.0.0",
+		"sha256:ab12",
+		"sha3-256:cd34",
+		"swid:swidgen-angular-core-17.0.0",
+	}, aliases)
+
+	spdx := []byte(`{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "curl",
+  "documentNamespace": "https://example.com/curl",
+  "creationInfo": {"created": "2024-05-01T10:00:00Z", "creators": ["Tool: test"]},
+  "packages": [{
+    "SPDXID": "SPDXRef-curl", "name": "curl", "versionInfo": "7.50.3", "downloadLocation": "NOASSERTION",
+    "externalRefs": [
+      {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:generic/curl@7.50.3"},
+      {"referenceCategory": "SECURITY", "referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:haxx:curl:7.50.3:*:*:*:*:*:*:*"},
+      {"referenceCategory": "SECURITY", "referenceType": "swid", "referenceLocator": "swid:curl-7.50.3"}
+    ]
+  }]
+}`)
+	require.NoError(t, SBOM(context.Background(), storage, spdx))
+
+	curl := nodeByName(t, storage, "pkg:generic/curl@7.50.3")
+	aliases, err = storage.GetAliases(context.Background(), curl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cpe:2.3:a:haxx:curl:7.50.3:*:*:*:*:*:*:*", "swid:curl-7.50.3"}, aliases)
+	id, err := graph.ResolveName(context.Background(), storage, "cpe:2.3:a:haxx:curl:7.50.3:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	assert.Equal(t, curl.ID, id)
+}
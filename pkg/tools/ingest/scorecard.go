@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -46,7 +47,8 @@ type ScorecardResult struct {
 }
 
 // Scorecard processes the Scorecard JSON data and stores it in the graph.
-func Scorecards(storage graph.Storage, data []byte) error {
+func Scorecards(ctx context.Context, storage graph.Storage, data []byte, opts ...Option) error {
+	o := newOptions(opts)
 	if len(data) == 0 {
 		return fmt.Errorf("data is empty")
 	}
@@ -77,16 +79,17 @@ func Scorecards(storage graph.Storage, data []byte) error {
 		scorecardResults[purl.Name] = append(scorecardResults[purl.Name], result)
 	}
 
-	keys, err := storage.GetAllKeys()
+	keys, err := storage.GetAllKeys(ctx)
 	if err != nil {
 		return err
 	}
 
-	nodes, err := storage.GetNodes(keys)
+	nodes, err := storage.GetNodes(ctx, keys)
 	if err != nil {
 		return fmt.Errorf("failed to get nodes from storage: %w", err)
 	}
 
+	contribution := NewContribution()
 	for _, node := range nodes {
 		if node.Type == tools.LibraryType && strings.HasPrefix(node.Name, pkg) {
 			purl, err := PURLToPackage(node.Name)
@@ -110,21 +113,25 @@ func Scorecards(storage graph.Storage, data []byte) error {
 					// The scorecard data is found based on the packages name, but then we need
 					// to check whether the scorecard data is for the current packages version
 					if scorecardPurl.Version == purl.Version {
-						scorecardNode, err := graph.AddNode(storage, tools.ScorecardType, scorecardResult, getScorecardNodeName(scorecardResult.PURL))
+						scorecardNode, err := graph.AddOrMergeNode(ctx, storage, o.mergePolicy, tools.ScorecardType, scorecardResult, getScorecardNodeName(scorecardResult.PURL))
 						if err != nil {
 							return fmt.Errorf("failed to add Scorecard node to storage: %w", err)
 						}
 
-						if err := node.SetDependency(storage, scorecardNode); err != nil {
+						if err := node.SetDependency(ctx, storage, scorecardNode); err != nil {
 							return fmt.Errorf("failed to add dependency edge to Scorecard node: %w", err)
 						}
+						contribution.addEdge(node.ID, scorecardNode.ID)
 					}
 				}
 			}
 		}
 	}
 
-	return nil
+	// Scorecard result files have no identity of their own, so they are identified by their content.
+	sha256 := Digest(data)
+	metadata := DocumentMetadata{SHA256: sha256}
+	return recordDocument(ctx, storage, tools.ScorecardDocumentType, tools.ScorecardDocumentType+":sha256:"+sha256, metadata, contribution)
 }
 
 func getScorecardNodeName(name string) string {
@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,12 +32,12 @@ func TestScorecards(t *testing.T) {
 			t.Fatalf("Failed to read SBOM file %s: %v", file.Name(), err)
 		}
 
-		if err := SBOM(storage, data); err != nil {
+		if err := SBOM(context.Background(), storage, data); err != nil {
 			t.Fatalf("Failed to load SBOM from file %s: %v", file.Name(), err)
 		}
 	}
 
-	keys, err := storage.GetAllKeys()
+	keys, err := storage.GetAllKeys(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get all keys, %v", err)
 	}
@@ -60,7 +61,7 @@ func TestScorecards(t *testing.T) {
 			t.Fatalf("Failed to read scorecard file %s: %v", file.Name(), err)
 		}
 
-		if err := Scorecards(storage, data); err != nil {
+		if err := Scorecards(context.Background(), storage, data); err != nil {
 			t.Fatalf("Failed to load scorecard from file %s: %v", file.Name(), err)
 		}
 		scorecardCount++
@@ -70,12 +71,13 @@ func TestScorecards(t *testing.T) {
 		t.Fatal("Expected scorecards to be ingested, got 0")
 	}
 
-	keys, err = storage.GetAllKeys()
+	keys, err = storage.GetAllKeys(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get all keys, %v", err)
 	}
 
-	if len(keys) != numberOfNodes+1 {
-		t.Fatalf("Expected number of nodes to be %d, got %d", numberOfNodes+1, len(keys))
+	// One scorecard node and one document node for every result file.
+	if len(keys) != numberOfNodes+1+scorecardCount {
+		t.Fatalf("Expected number of nodes to be %d, got %d", numberOfNodes+1+scorecardCount, len(keys))
 	}
 }
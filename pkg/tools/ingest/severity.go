@@ -0,0 +1,156 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	gocvss20 "github.com/pandatix/go-cvss/20"
+	gocvss30 "github.com/pandatix/go-cvss/30"
+	gocvss31 "github.com/pandatix/go-cvss/31"
+	gocvss40 "github.com/pandatix/go-cvss/40"
+)
+
+// Severity types of OSV records, and the one of severities taken from database_specific.
+const (
+	SeverityCVSSV2           = "CVSS_V2"
+	SeverityCVSSV3           = "CVSS_V3"
+	SeverityCVSSV4           = "CVSS_V4"
+	SeverityDatabaseSpecific = "DATABASE_SPECIFIC"
+)
+
+// Qualitative severity ratings, as CVSS v3 and v4 define them.
+const (
+	RatingNone     = "NONE"
+	RatingLow      = "LOW"
+	RatingMedium   = "MEDIUM"
+	RatingHigh     = "HIGH"
+	RatingCritical = "CRITICAL"
+)
+
+// severityPreference orders the severity types by preference, newer CVSS versions first.
+var severityPreference = []string{SeverityCVSSV4, SeverityCVSSV3, SeverityCVSSV2}
+
+// normalizeSeverity sets the normalized severity fields of the vulnerability. The score is the
+// base score of the newest CVSS version the record has a valid vector of, the top-level
+// severities before the ones of affected packages. Records without one are rated by the
+// severity of database_specific, like GitHub advisories are, without a score.
+func normalizeSeverity(vuln *Vulnerability) {
+	vuln.SeverityType, vuln.SeverityScore, vuln.SeverityRating = "", 0, ""
+
+	severities := append([]Severity(nil), vuln.Severity...)
+	for _, affected := range vuln.Affected {
+		severities = append(severities, affected.Severity...)
+	}
+	for _, severityType := range severityPreference {
+		for _, severity := range severities {
+			if severity.Type != severityType {
+				continue
+			}
+			score, rating, err := ParseCVSS(severity.Score)
+			if err != nil {
+				continue
+			}
+			vuln.SeverityType, vuln.SeverityScore, vuln.SeverityRating = severityType, score, rating
+			return
+		}
+	}
+
+	if severity, ok := vuln.DatabaseSpecific["severity"].(string); ok {
+		if rating := normalizeRating(severity); rating != "" {
+			vuln.SeverityType, vuln.SeverityRating = SeverityDatabaseSpecific, rating
+		}
+	}
+}
+
+// ParseCVSS returns the base score and the qualitative rating of a CVSS v2, v3.0, v3.1 or v4.0
+// vector. CVSS v2 has no ratings of its own, its scores are rated as the NVD does.
+func ParseCVSS(vector string) (float64, string, error) {
+	switch {
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		cvss, err := gocvss40.ParseVector(vector)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid CVSS v4.0 vector %q: %w", vector, err)
+		}
+		score := cvss.Score()
+		rating, err := gocvss40.Rating(score)
+		return score, rating, err
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		cvss, err := gocvss31.ParseVector(vector)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid CVSS v3.1 vector %q: %w", vector, err)
+		}
+		score := cvss.BaseScore()
+		rating, err := gocvss31.Rating(score)
+		return score, rating, err
+	case strings.HasPrefix(vector, "CVSS:3.0/"):
+		cvss, err := gocvss30.ParseVector(vector)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid CVSS v3.0 vector %q: %w", vector, err)
+		}
+		score := cvss.BaseScore()
+		rating, err := gocvss30.Rating(score)
+		return score, rating, err
+	default:
+		cvss, err := gocvss20.ParseVector(strings.TrimPrefix(vector, "CVSS:2.0/"))
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid CVSS v2 vector %q: %w", vector, err)
+		}
+		score := cvss.BaseScore()
+		switch {
+		case score >= 7.0:
+			return score, RatingHigh, nil
+		case score >= 4.0:
+			return score, RatingMedium, nil
+		default:
+			return score, RatingLow, nil
+		}
+	}
+}
+
+// normalizeRating returns the rating a database names, or "" if it isn't one. GitHub calls
+// medium severities moderate.
+func normalizeRating(severity string) string {
+	rating := strings.ToUpper(strings.TrimSpace(severity))
+	switch rating {
+	case "MODERATE":
+		return RatingMedium
+	case RatingNone, RatingLow, RatingMedium, RatingHigh, RatingCritical:
+		return rating
+	default:
+		return ""
+	}
+}
+
+// NormalizeVulnerabilities sets the normalized severity of every vulnerability node, for
+// graphs built before vulnerabilities were scored.
+func NormalizeVulnerabilities(ctx context.Context, storage graph.Storage) error {
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	for _, node := range nodes {
+		if node.Type != tools.VulnerabilityType {
+			continue
+		}
+		_, err := storage.UpdateNodeMetadata(ctx, node.ID, func(metadata any) (any, error) {
+			vuln, err := NodeVulnerability(metadata)
+			if err != nil {
+				// Metadata that isn't an OSV record has no severity to normalize.
+				return metadata, nil
+			}
+			normalizeSeverity(&vuln)
+			return vuln, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to normalize vulnerability %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
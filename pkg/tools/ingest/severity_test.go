@@ -0,0 +1,130 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCVSS(t *testing.T) {
+	tests := []struct {
+		vector     string
+		wantScore  float64
+		wantRating string
+		wantErr    bool
+	}{
+		{vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantScore: 7.5, wantRating: RatingHigh},
+		{vector: "AV:L/AC:H/Au:N/C:N/I:P/A:N", wantScore: 1.2, wantRating: RatingLow},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantScore: 9.8, wantRating: RatingCritical},
+		{vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N", wantScore: 3.1, wantRating: RatingLow},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", wantScore: 0, wantRating: RatingNone},
+		{vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", wantScore: 9.3, wantRating: RatingCritical},
+		{vector: "CVSS:3.1/AV:X", wantErr: true},
+		{vector: "HIGH", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			score, rating, err := ParseCVSS(tt.vector)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantScore, score, 0.001)
+			assert.Equal(t, tt.wantRating, rating)
+		})
+	}
+}
+
+func TestNormalizeSeverity(t *testing.T) {
+	tests := []struct {
+		name       string
+		vuln       Vulnerability
+		wantType   string
+		wantScore  float64
+		wantRating string
+	}{
+		{
+			name: "newest CVSS version",
+			vuln: Vulnerability{Severity: []Severity{
+				{Type: SeverityCVSSV2, Score: "AV:N/AC:L/Au:N/C:P/I:P/A:P"},
+				{Type: SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N"},
+			}},
+			wantType: SeverityCVSSV3, wantScore: 3.1, wantRating: RatingLow,
+		},
+		{
+			name: "invalid vectors are skipped",
+			vuln: Vulnerability{Severity: []Severity{
+				{Type: SeverityCVSSV4, Score: "CVSS:4.0/AV:Z"},
+				{Type: SeverityCVSSV2, Score: "AV:N/AC:L/Au:N/C:P/I:P/A:P"},
+			}},
+			wantType: SeverityCVSSV2, wantScore: 7.5, wantRating: RatingHigh,
+		},
+		{
+			name: "severity of an affected package",
+			vuln: Vulnerability{Affected: []Affected{{Severity: []Severity{
+				{Type: SeverityCVSSV3, Score: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+			}}}},
+			wantType: SeverityCVSSV3, wantScore: 9.8, wantRating: RatingCritical,
+		},
+		{
+			name:     "database specific",
+			vuln:     Vulnerability{DatabaseSpecific: map[string]interface{}{"severity": "MODERATE"}},
+			wantType: SeverityDatabaseSpecific, wantRating: RatingMedium,
+		},
+		{
+			name: "none",
+			vuln: Vulnerability{DatabaseSpecific: map[string]interface{}{"severity": "unknown"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizeSeverity(&tt.vuln)
+			assert.Equal(t, tt.wantType, tt.vuln.SeverityType)
+			assert.InDelta(t, tt.wantScore, tt.vuln.SeverityScore, 0.001)
+			assert.Equal(t, tt.wantRating, tt.vuln.SeverityRating)
+		})
+	}
+}
+
+func TestNormalizeVulnerabilities(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	// Vulnerability nodes used to hold the JSON encoding of the record.
+	record, err := json.Marshal(Vulnerability{ID: "TEST-1", Severity: []Severity{
+		{Type: SeverityCVSSV3, Score: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+	}})
+	require.NoError(t, err)
+	_, err = graph.AddNode(ctx, storage, tools.VulnerabilityType, record, "TEST-1")
+	require.NoError(t, err)
+	_, err = graph.AddNode(ctx, storage, tools.VulnerabilityType, "not a record", "TEST-2")
+	require.NoError(t, err)
+
+	require.NoError(t, NormalizeVulnerabilities(ctx, storage))
+
+	vuln, err := NodeVulnerability(nodeByName(t, storage, "TEST-1").Metadata)
+	require.NoError(t, err)
+	assert.Equal(t, "TEST-1", vuln.ID)
+	assert.Equal(t, RatingCritical, vuln.SeverityRating)
+	assert.Equal(t, "not a record", nodeByName(t, storage, "TEST-2").Metadata)
+}
+
+func TestVulnerabilitySeverity(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:11111111-1111-1111-1111-111111111111", "app", "x")))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-1",
+	  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N"}],
+	  "affected": [{"package": {"ecosystem": "npm", "name": "x"}, "versions": ["1.0.0"]}]}`)))
+
+	vuln, err := NodeVulnerability(nodeByName(t, storage, "TEST-1").Metadata)
+	require.NoError(t, err)
+	assert.Equal(t, SeverityCVSSV3, vuln.SeverityType)
+	assert.InDelta(t, 3.1, vuln.SeverityScore, 0.001)
+	assert.Equal(t, RatingLow, vuln.SeverityRating)
+}
@@ -0,0 +1,716 @@
+package ingest
+
+import (
+	"cmp"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+)
+
+// compareEcosystemVersions compares two versions of a package of the OSV ecosystem, as used by
+// ECOSYSTEM ranges. Ecosystems may name a release, like "Debian:11", which doesn't change how
+// versions compare. Versions that aren't valid for their ecosystem are compared as strings.
+func compareEcosystemVersions(v1, v2, ecosystem string) int {
+	name, _, _ := strings.Cut(ecosystem, ":")
+	switch Ecosystem(name) {
+	case EcosystemNPM, EcosystemGo, EcosystemCratesIO, EcosystemHex:
+		return compareSemver(v1, v2)
+	case EcosystemPyPI:
+		return comparePEP440(v1, v2)
+	case EcosystemMaven:
+		return compareMaven(v1, v2)
+	case EcosystemDebian:
+		return compareDpkg(v1, v2)
+	case EcosystemAlpine:
+		return compareAPK(v1, v2)
+	case EcosystemRubyGems:
+		return compareRubyGems(v1, v2)
+	case EcosystemNuGet:
+		return compareNuGet(v1, v2)
+	case EcosystemPackagist:
+		return comparePackagist(v1, v2)
+	default:
+		// OSS-Fuzz versions and those of ecosystems OSV added since have no known order, they
+		// are compared as strings.
+		return strings.Compare(v1, v2)
+	}
+}
+
+// compareSemver compares semantic versions.
+func compareSemver(v1, v2 string) int {
+	ver1, err1 := semver.NewVersion(v1)
+	ver2, err2 := semver.NewVersion(v2)
+	if err1 != nil || err2 != nil {
+		return strings.Compare(v1, v2)
+	}
+	return ver1.Compare(ver2)
+}
+
+// compareNumeric compares strings of decimal digits by value, so numbers of any size can be
+// compared.
+func compareNumeric(n1, n2 string) int {
+	n1 = strings.TrimLeft(n1, "0")
+	n2 = strings.TrimLeft(n2, "0")
+	if c := cmp.Compare(len(n1), len(n2)); c != 0 {
+		return c
+	}
+	return strings.Compare(n1, n2)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// pep440Pattern is the version pattern of PEP 440, accepting the forms it normalizes.
+var pep440Pattern = regexp.MustCompile(`^(?i)v?` +
+	`(?:([0-9]+)!)?` +
+	`([0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?` +
+	`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440Version is a parsed PEP 440 version.
+type pep440Version struct {
+	epoch   string
+	release []string
+	// preRank is 0 for versions that are only development releases, which sort before any
+	// pre-release, 1 to 3 for a, b and rc pre-releases, and 4 for versions without one.
+	preRank int
+	pre     string
+	hasPost bool
+	post    string
+	hasDev  bool
+	dev     string
+	local   []string
+}
+
+func parsePEP440(version string) (pep440Version, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return pep440Version{}, false
+	}
+	v := pep440Version{epoch: m[1], release: strings.Split(m[2], ".")}
+	// Trailing zeros don't change a release, 1.0 is 1.
+	for len(v.release) > 1 && strings.TrimLeft(v.release[len(v.release)-1], "0") == "" {
+		v.release = v.release[:len(v.release)-1]
+	}
+
+	switch strings.ToLower(m[3]) {
+	case "a", "alpha":
+		v.preRank, v.pre = 1, m[4]
+	case "b", "beta":
+		v.preRank, v.pre = 2, m[4]
+	case "c", "rc", "pre", "preview":
+		v.preRank, v.pre = 3, m[4]
+	default:
+		v.preRank = 4
+	}
+	if m[5] != "" || m[6] != "" {
+		v.hasPost, v.post = true, m[5]+m[7]
+	}
+	if m[8] != "" {
+		v.hasDev, v.dev = true, m[9]
+	}
+	if v.hasDev && v.preRank == 4 && !v.hasPost {
+		v.preRank = 0
+	}
+	if m[10] != "" {
+		v.local = strings.FieldsFunc(strings.ToLower(m[10]), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+	return v, true
+}
+
+// comparePEP440 compares versions of Python packages as PEP 440 orders them. Versions that
+// aren't valid sort before valid ones, as pip does.
+func comparePEP440(v1, v2 string) int {
+	p1, ok1 := parsePEP440(v1)
+	p2, ok2 := parsePEP440(v2)
+	switch {
+	case !ok1 && !ok2:
+		return strings.Compare(v1, v2)
+	case !ok1:
+		return -1
+	case !ok2:
+		return 1
+	}
+
+	if c := compareNumeric(p1.epoch, p2.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < max(len(p1.release), len(p2.release)); i++ {
+		var r1, r2 string
+		if i < len(p1.release) {
+			r1 = p1.release[i]
+		}
+		if i < len(p2.release) {
+			r2 = p2.release[i]
+		}
+		if c := compareNumeric(r1, r2); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(p1.preRank, p2.preRank); c != 0 {
+		return c
+	}
+	if c := compareNumeric(p1.pre, p2.pre); c != 0 {
+		return c
+	}
+	// A post-release sorts after the version without one, a development release before.
+	if p1.hasPost != p2.hasPost {
+		if p1.hasPost {
+			return 1
+		}
+		return -1
+	}
+	if c := compareNumeric(p1.post, p2.post); c != 0 {
+		return c
+	}
+	if p1.hasDev != p2.hasDev {
+		if p1.hasDev {
+			return -1
+		}
+		return 1
+	}
+	if c := compareNumeric(p1.dev, p2.dev); c != 0 {
+		return c
+	}
+
+	// Local versions sort after the public version they are based on. Their numeric segments
+	// sort after alphanumeric ones.
+	for i := 0; i < max(len(p1.local), len(p2.local)); i++ {
+		if i >= len(p1.local) {
+			return -1
+		}
+		if i >= len(p2.local) {
+			return 1
+		}
+		l1, l2 := p1.local[i], p2.local[i]
+		n1, n2 := isDigits(l1), isDigits(l2)
+		var c int
+		switch {
+		case n1 && n2:
+			c = compareNumeric(l1, l2)
+		case n1:
+			c = 1
+		case n2:
+			c = -1
+		default:
+			c = strings.Compare(l1, l2)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// mavenToken is a token of a Maven version, with the separator before it.
+type mavenToken struct {
+	prefix byte
+	value  string
+}
+
+func (t mavenToken) numeric() bool {
+	return isDigits(t.value)
+}
+
+// null reports whether the token is one of the values that are trimmed from the end of a
+// version, so 1.0-final is 1.
+func (t mavenToken) null() bool {
+	return t.value == "" || t.numeric() && strings.TrimLeft(t.value, "0") == ""
+}
+
+// mavenQualifiers are the known qualifiers, in order. Unknown qualifiers sort after them.
+var mavenQualifiers = map[string]int{
+	"alpha":     0,
+	"beta":      1,
+	"milestone": 2,
+	"rc":        3,
+	"snapshot":  4,
+	"":          5,
+	"sp":        6,
+}
+
+// mavenAliases are the qualifiers ComparableVersion treats as another one: ga, final and
+// release are the release itself, cr is rc.
+var mavenAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// parseMaven splits a Maven version into tokens as described by the version order
+// specification of the POM reference.
+func parseMaven(version string) []mavenToken {
+	version = strings.ToLower(strings.TrimSpace(version))
+
+	var tokens []mavenToken
+	prefix := byte('.')
+	start := 0
+	add := func(end int) {
+		value := version[start:end]
+		if value == "" {
+			value = "0"
+		} else if alias, ok := mavenAliases[value]; ok {
+			value = alias
+		}
+		tokens = append(tokens, mavenToken{prefix: prefix, value: value})
+	}
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			add(i)
+			prefix, start = c, i+1
+		case i > start && isDigitAt(version, i) != isDigitAt(version, i-1):
+			// A transition between digits and characters is a hyphen.
+			add(i)
+			prefix, start = '-', i
+			if isDigitAt(version, i) {
+				// a, b and m directly followed by a number are alpha, beta and milestone.
+				last := &tokens[len(tokens)-1]
+				switch last.value {
+				case "a":
+					last.value = "alpha"
+				case "b":
+					last.value = "beta"
+				case "m":
+					last.value = "milestone"
+				}
+			}
+		}
+	}
+	add(len(version))
+
+	// Trailing null values are trimmed from the end, and then before every hyphen.
+	var trimmed []mavenToken
+	for end := len(tokens); end > 0; {
+		start := end - 1
+		for start > 0 && tokens[start].prefix != '-' {
+			start--
+		}
+		group := tokens[start:end]
+		for len(group) > 0 && group[len(group)-1].null() {
+			group = group[:len(group)-1]
+		}
+		trimmed = append(append([]mavenToken(nil), group...), trimmed...)
+		end = start
+	}
+	return trimmed
+}
+
+// mavenTokenOrder orders tokens with different separators: qualifiers sort before numbers
+// following a hyphen, which sort before numbers following a dot.
+func mavenTokenOrder(t mavenToken) int {
+	switch {
+	case !t.numeric():
+		return 0
+	case t.prefix == '-':
+		return 1
+	default:
+		return 2
+	}
+}
+
+func compareMavenTokens(t1, t2 mavenToken) int {
+	n1, n2 := t1.numeric(), t2.numeric()
+	switch {
+	case n1 && n2 && t1.prefix == t2.prefix:
+		return compareNumeric(t1.value, t2.value)
+	case !n1 && !n2:
+		o1, known1 := mavenQualifiers[t1.value]
+		o2, known2 := mavenQualifiers[t2.value]
+		switch {
+		case known1 && known2:
+			return cmp.Compare(o1, o2)
+		case known1:
+			return -1
+		case known2:
+			return 1
+		}
+		return strings.Compare(t1.value, t2.value)
+	}
+	return cmp.Compare(mavenTokenOrder(t1), mavenTokenOrder(t2))
+}
+
+// compareMaven compares versions of Maven artifacts.
+func compareMaven(v1, v2 string) int {
+	t1, t2 := parseMaven(v1), parseMaven(v2)
+	for i := 0; i < max(len(t1), len(t2)); i++ {
+		// The shorter version is padded with null values of the same kind as the other.
+		var a, b mavenToken
+		switch {
+		case i >= len(t1):
+			b = t2[i]
+			a = mavenNull(b)
+		case i >= len(t2):
+			a = t1[i]
+			b = mavenNull(a)
+		default:
+			a, b = t1[i], t2[i]
+		}
+		if c := compareMavenTokens(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func mavenNull(t mavenToken) mavenToken {
+	if t.numeric() {
+		return mavenToken{prefix: t.prefix, value: "0"}
+	}
+	return mavenToken{prefix: t.prefix}
+}
+
+// compareDpkg compares versions of Debian packages as dpkg does.
+func compareDpkg(v1, v2 string) int {
+	e1, u1, r1 := splitDpkg(v1)
+	e2, u2, r2 := splitDpkg(v2)
+	if !isDigits(e1) || !isDigits(e2) {
+		return strings.Compare(v1, v2)
+	}
+	if c := compareNumeric(e1, e2); c != 0 {
+		return c
+	}
+	if c := compareDpkgPart(u1, u2); c != 0 {
+		return c
+	}
+	return compareDpkgPart(r1, r2)
+}
+
+// splitDpkg splits a Debian version into its epoch, upstream version and revision.
+func splitDpkg(version string) (string, string, string) {
+	version = strings.TrimSpace(version)
+	epoch := "0"
+	if before, after, found := strings.Cut(version, ":"); found {
+		epoch, version = before, after
+	}
+	revision := ""
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		version, revision = version[:i], version[i+1:]
+	}
+	return epoch, version, revision
+}
+
+// dpkgOrder orders the characters of the non-digit parts of Debian versions: a tilde sorts
+// before anything, even the end of the part, and letters sort before other characters.
+func dpkgOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case unicode.IsLetter(rune(c)):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func isDigitAt(s string, i int) bool {
+	return i < len(s) && s[i] >= '0' && s[i] <= '9'
+}
+
+// compareDpkgPart compares upstream versions or revisions, alternating between non-digit
+// parts, compared by dpkgOrder, and digit parts, compared by value.
+func compareDpkgPart(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigitAt(a, i)) || (j < len(b) && !isDigitAt(b, j)) {
+			if c := cmp.Compare(dpkgOrder(a, i), dpkgOrder(b, j)); c != 0 {
+				return c
+			}
+			i, j = i+1, j+1
+		}
+		i, j = min(i, len(a)), min(j, len(b))
+		si, sj := i, j
+		for isDigitAt(a, i) {
+			i++
+		}
+		for isDigitAt(b, j) {
+			j++
+		}
+		if c := compareNumeric(a[si:i], b[sj:j]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// apkPattern is the version format of Alpine packages.
+var apkPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)*)([a-z])?((?:_(?:alpha|beta|pre|rc|cvs|svn|git|hg|p)[0-9]*)*)(?:-r([0-9]+))?$`)
+
+// apkSuffixes are the suffixes of Alpine versions, in order. Suffixes before "" mark
+// pre-releases, which sort before the version without a suffix.
+var apkSuffixes = map[string]int{
+	"alpha": 0,
+	"beta":  1,
+	"pre":   2,
+	"rc":    3,
+	"":      4,
+	"cvs":   5,
+	"svn":   6,
+	"git":   7,
+	"hg":    8,
+	"p":     9,
+}
+
+var apkSuffixPattern = regexp.MustCompile(`_([a-z]+)([0-9]*)`)
+
+// compareAPK compares versions of Alpine packages as apk does.
+func compareAPK(v1, v2 string) int {
+	m1 := apkPattern.FindStringSubmatch(strings.TrimSpace(v1))
+	m2 := apkPattern.FindStringSubmatch(strings.TrimSpace(v2))
+	if m1 == nil || m2 == nil {
+		return strings.Compare(v1, v2)
+	}
+
+	n1, n2 := strings.Split(m1[1], "."), strings.Split(m2[1], ".")
+	for i := 0; i < min(len(n1), len(n2)); i++ {
+		var c int
+		// Components after the first with a leading zero are compared like decimal fractions.
+		if i > 0 && (strings.HasPrefix(n1[i], "0") || strings.HasPrefix(n2[i], "0")) {
+			c = strings.Compare(n1[i], n2[i])
+		} else {
+			c = compareNumeric(n1[i], n2[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(len(n1), len(n2)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(m1[2], m2[2]); c != 0 {
+		return c
+	}
+
+	s1 := apkSuffixPattern.FindAllStringSubmatch(m1[3], -1)
+	s2 := apkSuffixPattern.FindAllStringSubmatch(m2[3], -1)
+	for i := 0; i < max(len(s1), len(s2)); i++ {
+		// A missing suffix sorts like no suffix at all.
+		suffix1, suffix2 := []string{"", "", ""}, []string{"", "", ""}
+		if i < len(s1) {
+			suffix1 = s1[i]
+		}
+		if i < len(s2) {
+			suffix2 = s2[i]
+		}
+		if c := cmp.Compare(apkSuffixes[suffix1[1]], apkSuffixes[suffix2[1]]); c != 0 {
+			return c
+		}
+		if c := compareNumeric(suffix1[2], suffix2[2]); c != 0 {
+			return c
+		}
+	}
+	return compareNumeric(m1[4], m2[4])
+}
+
+var rubyGemsSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// rubyGemsSegments returns the canonical segments of a RubyGems version: a hyphen starts a
+// pre-release, and trailing zeros of the release and pre-release are dropped.
+func rubyGemsSegments(version string) []string {
+	version = strings.ReplaceAll(strings.TrimSpace(version), "-", ".pre.")
+	segments := rubyGemsSegmentPattern.FindAllString(version, -1)
+	split := len(segments)
+	for i, segment := range segments {
+		if !isDigits(segment) {
+			split = i
+			break
+		}
+	}
+	trim := func(segments []string) []string {
+		for len(segments) > 0 && isDigits(segments[len(segments)-1]) && strings.TrimLeft(segments[len(segments)-1], "0") == "" {
+			segments = segments[:len(segments)-1]
+		}
+		return segments
+	}
+	return append(trim(segments[:split:split]), trim(segments[split:])...)
+}
+
+// compareRubyGems compares versions of gems as Gem::Version does. Letters mark a
+// pre-release, which sorts before numbers.
+func compareRubyGems(v1, v2 string) int {
+	s1, s2 := rubyGemsSegments(v1), rubyGemsSegments(v2)
+	for i := 0; i < max(len(s1), len(s2)); i++ {
+		a, b := "0", "0"
+		if i < len(s1) {
+			a = s1[i]
+		}
+		if i < len(s2) {
+			b = s2[i]
+		}
+		n1, n2 := isDigits(a), isDigits(b)
+		var c int
+		switch {
+		case n1 && n2:
+			c = compareNumeric(a, b)
+		case n1:
+			c = 1
+		case n2:
+			c = -1
+		default:
+			c = strings.Compare(a, b)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// nugetPattern is the version format of NuGet packages, with up to four numbers.
+var nugetPattern = regexp.MustCompile(`^v?([0-9]+)(?:\.([0-9]+))?(?:\.([0-9]+))?(?:\.([0-9]+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// compareNuGet compares versions of NuGet packages. Pre-release labels are compared without
+// regard to case, and build metadata is ignored.
+func compareNuGet(v1, v2 string) int {
+	m1 := nugetPattern.FindStringSubmatch(strings.TrimSpace(v1))
+	m2 := nugetPattern.FindStringSubmatch(strings.TrimSpace(v2))
+	if m1 == nil || m2 == nil {
+		return strings.Compare(v1, v2)
+	}
+	for i := 1; i <= 4; i++ {
+		if c := compareNumeric(m1[i], m2[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case m1[5] == "" && m2[5] == "":
+		return 0
+	case m1[5] == "":
+		return 1
+	case m2[5] == "":
+		return -1
+	}
+	l1 := strings.Split(strings.ToLower(m1[5]), ".")
+	l2 := strings.Split(strings.ToLower(m2[5]), ".")
+	for i := 0; i < max(len(l1), len(l2)); i++ {
+		if i >= len(l1) {
+			return -1
+		}
+		if i >= len(l2) {
+			return 1
+		}
+		n1, n2 := isDigits(l1[i]), isDigits(l2[i])
+		var c int
+		switch {
+		case n1 && n2:
+			c = compareNumeric(l1[i], l2[i])
+		case n1:
+			c = -1
+		case n2:
+			c = 1
+		default:
+			c = strings.Compare(l1[i], l2[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// packagistForms are the special forms of PHP's version_compare, in order. A part of a version
+// is the first form it starts with, parts that start with none sort before all of them.
+var packagistForms = []struct {
+	prefix string
+	order  int
+}{
+	{"dev", 0}, {"alpha", 1}, {"a", 1}, {"beta", 2}, {"b", 2}, {"RC", 3}, {"rc", 3}, {"#", 4}, {"pl", 5}, {"p", 5},
+}
+
+// packagistOrder returns the order of a part of a version that isn't a number. Numbers sort like
+// the # form.
+func packagistOrder(part string) int {
+	for _, form := range packagistForms {
+		if strings.HasPrefix(part, form.prefix) {
+			return form.order
+		}
+	}
+	return -1
+}
+
+// packagistParts splits a Packagist version as PHP's version_compare canonicalizes it: every
+// character other than a letter or digit separates parts, and so does a change between digits
+// and other characters. A leading v is dropped, as Composer does.
+func packagistParts(version string) []string {
+	version = strings.TrimLeft(strings.TrimSpace(version), "vV")
+	var parts []string
+	start := 0
+	for i := 0; i <= len(version); i++ {
+		switch {
+		case i == len(version) || !unicode.IsLetter(rune(version[i])) && !isDigitAt(version, i):
+			if i > start {
+				parts = append(parts, version[start:i])
+			}
+			start = i + 1
+		case i > start && isDigitAt(version, i) != isDigitAt(version, i-1):
+			parts = append(parts, version[start:i])
+			start = i
+		}
+	}
+	return parts
+}
+
+// comparePackagist compares versions of Composer packages as PHP's version_compare does, which
+// is how OSV orders Packagist versions.
+func comparePackagist(v1, v2 string) int {
+	return comparePackagistParts(packagistParts(v1), packagistParts(v2))
+}
+
+func comparePackagistParts(p1, p2 []string) int {
+	for i := 0; i < min(len(p1), len(p2)); i++ {
+		n1, n2 := isDigits(p1[i]), isDigits(p2[i])
+		var c int
+		switch {
+		case n1 && n2:
+			c = compareNumeric(p1[i], p2[i])
+		case n1:
+			c = cmp.Compare(packagistOrder("#"), packagistOrder(p2[i]))
+		case n2:
+			c = cmp.Compare(packagistOrder(p1[i]), packagistOrder("#"))
+		default:
+			c = cmp.Compare(packagistOrder(p1[i]), packagistOrder(p2[i]))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	// A longer version is newer if it goes on with a number, and otherwise compares its next
+	// parts with the # form.
+	switch {
+	case len(p1) > len(p2):
+		if isDigits(p1[len(p2)]) {
+			return 1
+		}
+		return comparePackagistParts(p1[len(p2):], []string{"#"})
+	case len(p1) < len(p2):
+		if isDigits(p2[len(p1)]) {
+			return -1
+		}
+		return comparePackagistParts([]string{"#"}, p2[len(p1):])
+	}
+	return 0
+}
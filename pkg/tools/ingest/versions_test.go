@@ -0,0 +1,104 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// versionFixtures are the files of version comparisons under testdata/osv-versions, in the
+// format of the fixtures of OSV's version comparators, and the ecosystems they are run against.
+var versionFixtures = map[string][]Ecosystem{
+	"semver-versions.txt":    {EcosystemNPM, EcosystemGo, EcosystemCratesIO, EcosystemHex},
+	"pypi-versions.txt":      {EcosystemPyPI},
+	"maven-versions.txt":     {EcosystemMaven},
+	"debian-versions.txt":    {EcosystemDebian},
+	"alpine-versions.txt":    {EcosystemAlpine},
+	"rubygems-versions.txt":  {EcosystemRubyGems},
+	"nuget-versions.txt":     {EcosystemNuGet},
+	"packagist-versions.txt": {EcosystemPackagist},
+}
+
+func TestCompareEcosystemVersionFixtures(t *testing.T) {
+	want := map[string]int{"<": -1, "=": 0, ">": 1}
+	tested := map[Ecosystem]bool{}
+	for fixture, ecosystems := range versionFixtures {
+		data, err := os.ReadFile(filepath.Join("../../../testdata/osv-versions", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				t.Fatalf("%s: malformed comparison %q", fixture, line)
+			}
+			v1, op, v2 := fields[0], fields[1], fields[2]
+			for _, ecosystem := range ecosystems {
+				tested[ecosystem] = true
+				if got := compareEcosystemVersions(v1, v2, string(ecosystem)); got != want[op] {
+					t.Errorf("%s: compareEcosystemVersions(%q, %q) = %d, want %d", ecosystem, v1, v2, got, want[op])
+				}
+				if got := compareEcosystemVersions(v2, v1, string(ecosystem)); got != -want[op] {
+					t.Errorf("%s: compareEcosystemVersions(%q, %q) = %d, want %d", ecosystem, v2, v1, got, -want[op])
+				}
+			}
+		}
+	}
+
+	// Every ecosystem with an order of its own is tested.
+	for _, namespaces := range purlEcosystems {
+		for _, ecosystem := range namespaces {
+			if ecosystem != EcosystemOSSFuzz && !tested[ecosystem] {
+				t.Errorf("no version fixture for %s", ecosystem)
+			}
+		}
+	}
+}
+
+func TestCompareUnknownEcosystemVersions(t *testing.T) {
+	// Versions of OSS-Fuzz and of ecosystems without a comparator are compared as strings.
+	for _, ecosystem := range []string{string(EcosystemOSSFuzz), "CRAN", ""} {
+		if got := compareEcosystemVersions("1.10.0", "1.9.0", ecosystem); got != -1 {
+			t.Errorf("%q: compareEcosystemVersions(1.10.0, 1.9.0) = %d, want -1", ecosystem, got)
+		}
+		if got := compareEcosystemVersions("1.0", "1.0", ecosystem); got != 0 {
+			t.Errorf("%q: compareEcosystemVersions(1.0, 1.0) = %d, want 0", ecosystem, got)
+		}
+	}
+}
+
+func TestIsVersionInRangesEcosystems(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		version   string
+		events    []Event
+		want      bool
+	}{
+		// Compared as strings, 1.10.0 would sort before 1.9.0.
+		{"npm", "1.10.0", []Event{{Introduced: "0"}, {Fixed: "1.9.0"}}, false},
+		{"PyPI", "2.0rc1", []Event{{Introduced: "1.0"}, {Fixed: "2.0"}}, true},
+		{"PyPI", "2.0.post1", []Event{{Introduced: "1.0"}, {Fixed: "2.0"}}, false},
+		{"Maven", "2.17.0", []Event{{Introduced: "2.0-beta9"}, {Fixed: "2.15.0"}}, false},
+		{"Maven", "2.0", []Event{{Introduced: "2.0-beta9"}, {Fixed: "2.15.0"}}, true},
+		{"Debian:11", "1.0~rc1-1", []Event{{Introduced: "0"}, {Fixed: "1.0-1"}}, true},
+		{"Alpine:v3.16", "1.2.10-r0", []Event{{Introduced: "0"}, {Fixed: "1.2.9-r0"}}, false},
+		{"RubyGems", "6.0.0.rc1", []Event{{Introduced: "5.0"}, {Fixed: "6.0.0"}}, true},
+		{"NuGet", "4.10.0", []Event{{Introduced: "4.0.0"}, {LastAffected: "4.9.0"}}, false},
+		{"Packagist", "5.4.0-RC1", []Event{{Introduced: "5.0.0"}, {Fixed: "5.4.0"}}, true},
+		{"Packagist", "5.4.0-pl1", []Event{{Introduced: "5.0.0"}, {Fixed: "5.4.0"}}, false},
+		// Events aren't necessarily listed in order.
+		{"PyPI", "1.5", []Event{{Fixed: "2.0"}, {Introduced: "1.0"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ecosystem+" "+tt.version, func(t *testing.T) {
+			ranges := []Range{{Type: "ECOSYSTEM", Events: tt.events}}
+			if got := isVersionInRanges(tt.version, ranges, tt.ecosystem); got != tt.want {
+				t.Errorf("isVersionInRanges(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,624 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+const (
+	// VEXTag is the custom data tag under which the statements of every VEX document are
+	// stored, keyed by the name of the document node.
+	VEXTag = "vex"
+	// VEXIndexTag is the custom data tag under which the statements about a product and a
+	// vulnerability are indexed, keyed by the product and the vulnerability separated by a
+	// space. The fields are the names of the documents stating them, a field is empty once a
+	// new version of its document no longer does.
+	VEXIndexTag = "vex-index"
+
+	vexStatementsKey = "statements"
+)
+
+// VEX statuses, as OpenVEX names them. CSAF product statuses are mapped to them.
+const (
+	VEXNotAffected        = "not_affected"
+	VEXAffected           = "affected"
+	VEXFixed              = "fixed"
+	VEXUnderInvestigation = "under_investigation"
+)
+
+// VEXStatement is the status of a vulnerability in a product, as a VEX document states it.
+// Products and subcomponents are named by their purl, or by another identifier if they
+// have none.
+type VEXStatement struct {
+	Vulnerability   string   `json:"vulnerability"`
+	Product         string   `json:"product"`
+	Subcomponents   []string `json:"subcomponents,omitempty"`
+	Status          string   `json:"status"`
+	Justification   string   `json:"justification,omitempty"`
+	ImpactStatement string   `json:"impact_statement,omitempty"`
+	Timestamp       string   `json:"timestamp,omitempty"`
+}
+
+// Suppresses reports whether the statement says the vulnerability doesn't affect the product.
+func (s VEXStatement) Suppresses() bool {
+	return s.Status == VEXNotAffected || s.Status == VEXFixed
+}
+
+// VEX ingests an OpenVEX or CSAF VEX document. Its statements are stored with the document
+// rather than on the graph, and resolved when the graph is queried, so they apply to products
+// and vulnerabilities ingested after them. A new version of a document replaces its
+// statements. Products are stored as the document names them, and indexed by their
+// normalized purl.
+func VEX(ctx context.Context, storage graph.Storage, data []byte, opts ...Option) error {
+	o := newOptions(opts)
+	if len(data) == 0 {
+		return fmt.Errorf("data is empty")
+	}
+
+	var (
+		metadata   DocumentMetadata
+		statements []VEXStatement
+		err        error
+	)
+	if isCSAF(data) {
+		metadata, statements, err = parseCSAF(data)
+	} else {
+		metadata, statements, err = parseOpenVEX(data)
+	}
+	if err != nil {
+		return err
+	}
+	metadata.SHA256 = Digest(data)
+	identity := metadata.SerialNumber
+	if identity == "" {
+		identity = "sha256:" + metadata.SHA256
+	}
+
+	encoded, err := json.Marshal(statements)
+	if err != nil {
+		return fmt.Errorf("failed to encode VEX statements: %w", err)
+	}
+	name := tools.VEXDocumentType + ":" + identity
+	previous, err := documentVEXStatements(ctx, storage, name)
+	if err != nil {
+		return err
+	}
+	if err := storage.AddOrUpdateCustomData(ctx, VEXTag, name, vexStatementsKey, encoded); err != nil {
+		return fmt.Errorf("failed to store VEX statements of %s: %w", name, err)
+	}
+	if err := indexVEXStatements(ctx, storage, o, name, previous, statements); err != nil {
+		return err
+	}
+	return recordDocument(ctx, storage, tools.VEXDocumentType, name, metadata, NewContribution())
+}
+
+// documentVEXStatements returns the statements stored for the VEX document, if any.
+func documentVEXStatements(ctx context.Context, storage graph.Storage, name string) ([]VEXStatement, error) {
+	data, err := storage.GetCustomData(ctx, VEXTag, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VEX statements of %s: %w", name, err)
+	}
+	encoded, ok := data[vexStatementsKey]
+	if !ok {
+		return nil, nil
+	}
+	var statements []VEXStatement
+	if err := json.Unmarshal(encoded, &statements); err != nil {
+		return nil, fmt.Errorf("failed to decode VEX statements of %s: %w", name, err)
+	}
+	return statements, nil
+}
+
+// vexIndexKey returns the key of the statements about the product and the vulnerability under
+// VEXIndexTag. Vulnerability IDs have no spaces, so the key splits at its last one.
+func (o *options) vexIndexKey(statement VEXStatement) string {
+	return o.productNames(statement.Product)[0] + " " + statement.Vulnerability
+}
+
+// indexVEXStatements indexes the statements of the document by product and vulnerability, and
+// empties its entries about the products and vulnerabilities only its previous version stated.
+// Of the statements of a document about the same product and vulnerability, the most recent
+// one is indexed.
+func indexVEXStatements(ctx context.Context, storage graph.Storage, o *options, name string, previous, statements []VEXStatement) error {
+	latest := map[string]VEXStatement{}
+	for _, statement := range statements {
+		key := o.vexIndexKey(statement)
+		if current, ok := latest[key]; !ok || compareModified(statement.Timestamp, current.Timestamp) >= 0 {
+			latest[key] = statement
+		}
+	}
+	for _, statement := range previous {
+		key := o.vexIndexKey(statement)
+		if _, ok := latest[key]; ok {
+			continue
+		}
+		if err := storage.AddOrUpdateCustomData(ctx, VEXIndexTag, key, name, nil); err != nil {
+			return fmt.Errorf("failed to unindex VEX statement of %s: %w", name, err)
+		}
+	}
+	for key, statement := range latest {
+		encoded, err := json.Marshal(statement)
+		if err != nil {
+			return fmt.Errorf("failed to encode VEX statement: %w", err)
+		}
+		if err := storage.AddOrUpdateCustomData(ctx, VEXIndexTag, key, name, encoded); err != nil {
+			return fmt.Errorf("failed to index VEX statement of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// IndexVEXStatements indexes the statements of every VEX document by product and
+// vulnerability, for graphs whose VEX documents were ingested before statements were indexed.
+func IndexVEXStatements(ctx context.Context, storage graph.Storage) error {
+	documents, err := VEXStatements(ctx, storage)
+	if err != nil {
+		return err
+	}
+	o := newOptions(nil)
+	for name, statements := range documents {
+		if err := indexVEXStatements(ctx, storage, o, name, nil, statements); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// productNames returns the names of the node a product may be, its normalized purl, so it
+// names the node of the same SBOM component, and the name the statement gives it, for nodes
+// named by invalid purls.
+func (o *options) productNames(product string) []string {
+	if strings.HasPrefix(product, pkg) {
+		if normalized, err := o.normalizer.Normalize(product); err == nil && normalized != product {
+			return []string{normalized, product}
+		}
+	}
+	return []string{product}
+}
+
+// VEXStatements returns the statements of all ingested VEX documents, by document name.
+func VEXStatements(ctx context.Context, storage graph.Storage) (map[string][]VEXStatement, error) {
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	result := map[string][]VEXStatement{}
+	for _, name := range keys[VEXTag] {
+		data, err := storage.GetCustomData(ctx, VEXTag, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VEX statements of %s: %w", name, err)
+		}
+		var statements []VEXStatement
+		if err := json.Unmarshal(data[vexStatementsKey], &statements); err != nil {
+			return nil, fmt.Errorf("failed to decode VEX statements of %s: %w", name, err)
+		}
+		result[name] = statements
+	}
+	return result, nil
+}
+
+// VEXStatementAbout returns the statement that applies to the product and the vulnerability,
+// the most recent of the statements about them, if any document states one. Only the index
+// entry of the product and the vulnerability is read.
+func VEXStatementAbout(ctx context.Context, storage graph.Storage, product, vulnerability string, opts ...Option) (VEXStatement, bool, error) {
+	o := newOptions(opts)
+	return latestVEXStatement(ctx, storage, o.vexIndexKey(VEXStatement{Product: product, Vulnerability: vulnerability}))
+}
+
+// latestVEXStatement returns the most recent statement indexed under the key. Of statements
+// as recent as each other, the one of the document with the greatest name applies.
+func latestVEXStatement(ctx context.Context, storage graph.Storage, key string) (VEXStatement, bool, error) {
+	data, err := storage.GetCustomData(ctx, VEXIndexTag, key)
+	if err != nil {
+		return VEXStatement{}, false, fmt.Errorf("failed to get VEX statements about %s: %w", key, err)
+	}
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var latest VEXStatement
+	found := false
+	for _, name := range names {
+		if len(data[name]) == 0 {
+			continue
+		}
+		var statement VEXStatement
+		if err := json.Unmarshal(data[name], &statement); err != nil {
+			return VEXStatement{}, false, fmt.Errorf("failed to decode VEX statement of %s about %s: %w", name, key, err)
+		}
+		if !found || compareModified(statement.Timestamp, latest.Timestamp) >= 0 {
+			latest, found = statement, true
+		}
+	}
+	return latest, found, nil
+}
+
+// Suppressions returns the vulnerabilities the ingested VEX statements say don't affect a
+// product, by the node ID of the product. Of the statements about the same product and
+// vulnerability, the most recent one applies. Products and vulnerabilities are resolved by
+// name or alias, so a statement about a CVE applies to the vulnerability node of its GHSA
+// advisory. Statements about nodes that aren't in the graph are ignored.
+func Suppressions(ctx context.Context, storage graph.Storage, opts ...Option) (graph.Suppressions, error) {
+	o := newOptions(opts)
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+
+	suppressions := graph.Suppressions{}
+	for _, key := range keys[VEXIndexTag] {
+		statement, ok, err := latestVEXStatement(ctx, storage, key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !statement.Suppresses() {
+			continue
+		}
+		product, ok, err := resolveAny(ctx, storage, o.productNames(statement.Product))
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		vulnerability, ok, err := resolveAny(ctx, storage, []string{statement.Vulnerability})
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		if suppressions[product] == nil {
+			suppressions[product] = roaring.New()
+		}
+		suppressions[product].Add(vulnerability)
+	}
+	return suppressions, nil
+}
+
+// resolveAny returns the ID of the node the first of the names resolves to, if any does.
+func resolveAny(ctx context.Context, storage graph.Storage, names []string) (uint32, bool, error) {
+	for _, name := range names {
+		id, err := graph.ResolveName(ctx, storage, name)
+		if errors.Is(err, graph.ErrNodeNotFound) {
+			continue
+		} else if err != nil {
+			return 0, false, fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		return id, true, nil
+	}
+	return 0, false, nil
+}
+
+// isCSAF reports whether the document is a CSAF document rather than an OpenVEX one.
+func isCSAF(data []byte) bool {
+	var document struct {
+		Document *json.RawMessage `json:"document"`
+	}
+	return json.Unmarshal(data, &document) == nil && document.Document != nil
+}
+
+type openVEXDocument struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Timestamp  string             `json:"timestamp"`
+	Version    json.Number        `json:"version"`
+	Tooling    string             `json:"tooling"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	// Vulnerability is a name before OpenVEX 0.2.0, and an object since.
+	Vulnerability   json.RawMessage   `json:"vulnerability"`
+	Products        []json.RawMessage `json:"products"`
+	Subcomponents   []json.RawMessage `json:"subcomponents"`
+	Status          string            `json:"status"`
+	Justification   string            `json:"justification"`
+	ImpactStatement string            `json:"impact_statement"`
+	Timestamp       string            `json:"timestamp"`
+}
+
+// openVEXComponent is a product or subcomponent, which is an identifier before OpenVEX 0.2.0,
+// and an object since.
+type openVEXComponent struct {
+	ID            string            `json:"@id"`
+	Identifiers   map[string]string `json:"identifiers"`
+	Subcomponents []json.RawMessage `json:"subcomponents"`
+}
+
+func parseOpenVEX(data []byte) (DocumentMetadata, []VEXStatement, error) {
+	var document openVEXDocument
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return DocumentMetadata{}, nil, fmt.Errorf("failed to unmarshal OpenVEX document: %w", err)
+	}
+	if !strings.Contains(document.Context, "openvex") {
+		return DocumentMetadata{}, nil, fmt.Errorf("document is neither an OpenVEX nor a CSAF VEX document")
+	}
+	metadata := DocumentMetadata{
+		Name:         document.Author,
+		SerialNumber: document.ID,
+		Version:      document.Version.String(),
+		Timestamp:    document.Timestamp,
+	}
+	if document.Tooling != "" {
+		metadata.Tools = []string{document.Tooling}
+	}
+
+	var statements []VEXStatement
+	for _, statement := range document.Statements {
+		vulnerability, err := openVEXVulnerability(statement.Vulnerability)
+		if err != nil {
+			return DocumentMetadata{}, nil, err
+		}
+		if statement.Status == "" {
+			return DocumentMetadata{}, nil, fmt.Errorf("VEX statement about %s has no status", vulnerability)
+		}
+		timestamp := statement.Timestamp
+		if timestamp == "" {
+			timestamp = document.Timestamp
+		}
+		for _, raw := range statement.Products {
+			product, subcomponents, err := openVEXProduct(raw)
+			if err != nil {
+				return DocumentMetadata{}, nil, err
+			}
+			for _, raw := range statement.Subcomponents {
+				subcomponent, _, err := openVEXProduct(raw)
+				if err != nil {
+					return DocumentMetadata{}, nil, err
+				}
+				subcomponents = append(subcomponents, subcomponent)
+			}
+			statements = append(statements, VEXStatement{
+				Vulnerability:   vulnerability,
+				Product:         product,
+				Subcomponents:   subcomponents,
+				Status:          statement.Status,
+				Justification:   statement.Justification,
+				ImpactStatement: statement.ImpactStatement,
+				Timestamp:       timestamp,
+			})
+		}
+	}
+	return metadata, statements, nil
+}
+
+func openVEXVulnerability(raw json.RawMessage) (string, error) {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil && name != "" {
+		return name, nil
+	}
+	var vulnerability struct {
+		ID   string `json:"@id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &vulnerability); err == nil && vulnerability.Name != "" {
+		return vulnerability.Name, nil
+	}
+	return "", fmt.Errorf("VEX statement without a vulnerability name: %s", raw)
+}
+
+// openVEXProduct returns the identifier of a product, its purl if it has one, and the ones of
+// its subcomponents.
+func openVEXProduct(raw json.RawMessage) (string, []string, error) {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil && id != "" {
+		return id, nil, nil
+	}
+	var component openVEXComponent
+	if err := json.Unmarshal(raw, &component); err != nil {
+		return "", nil, fmt.Errorf("invalid VEX product %s: %w", raw, err)
+	}
+	id = component.ID
+	if purl := component.Identifiers["purl"]; purl != "" {
+		id = purl
+	}
+	if id == "" {
+		return "", nil, fmt.Errorf("VEX product without an identifier: %s", raw)
+	}
+	var subcomponents []string
+	for _, raw := range component.Subcomponents {
+		subcomponent, _, err := openVEXProduct(raw)
+		if err != nil {
+			return "", nil, err
+		}
+		subcomponents = append(subcomponents, subcomponent)
+	}
+	return id, subcomponents, nil
+}
+
+type csafDocument struct {
+	Document struct {
+		Category  string `json:"category"`
+		Publisher struct {
+			Name string `json:"name"`
+		} `json:"publisher"`
+		Tracking struct {
+			ID                 string `json:"id"`
+			Version            string `json:"version"`
+			CurrentReleaseDate string `json:"current_release_date"`
+			Generator          struct {
+				Engine struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+				} `json:"engine"`
+			} `json:"generator"`
+		} `json:"tracking"`
+	} `json:"document"`
+	ProductTree struct {
+		Branches         []csafBranch      `json:"branches"`
+		FullProductNames []csafProductName `json:"full_product_names"`
+		Relationships    []struct {
+			ProductReference          string          `json:"product_reference"`
+			RelatesToProductReference string          `json:"relates_to_product_reference"`
+			FullProductName           csafProductName `json:"full_product_name"`
+		} `json:"relationships"`
+	} `json:"product_tree"`
+	Vulnerabilities []struct {
+		CVE string `json:"cve"`
+		IDs []struct {
+			Text string `json:"text"`
+		} `json:"ids"`
+		ProductStatus map[string][]string `json:"product_status"`
+		Flags         []struct {
+			Label      string   `json:"label"`
+			ProductIDs []string `json:"product_ids"`
+		} `json:"flags"`
+		Threats []struct {
+			Category   string   `json:"category"`
+			Details    string   `json:"details"`
+			ProductIDs []string `json:"product_ids"`
+		} `json:"threats"`
+	} `json:"vulnerabilities"`
+}
+
+type csafBranch struct {
+	Branches []csafBranch    `json:"branches"`
+	Product  csafProductName `json:"product"`
+}
+
+type csafProductName struct {
+	ProductID                   string `json:"product_id"`
+	Name                        string `json:"name"`
+	ProductIdentificationHelper struct {
+		PURL string `json:"purl"`
+		CPE  string `json:"cpe"`
+	} `json:"product_identification_helper"`
+}
+
+// identifier returns the purl of the product, or its CPE or name if it has none.
+func (p csafProductName) identifier() string {
+	switch {
+	case p.ProductIdentificationHelper.PURL != "":
+		return p.ProductIdentificationHelper.PURL
+	case p.ProductIdentificationHelper.CPE != "":
+		return p.ProductIdentificationHelper.CPE
+	default:
+		return p.Name
+	}
+}
+
+// csafStatuses maps the CSAF product statuses to VEX statuses.
+var csafStatuses = map[string]string{
+	"known_not_affected":  VEXNotAffected,
+	"fixed":               VEXFixed,
+	"first_fixed":         VEXFixed,
+	"known_affected":      VEXAffected,
+	"first_affected":      VEXAffected,
+	"last_affected":       VEXAffected,
+	"under_investigation": VEXUnderInvestigation,
+}
+
+func parseCSAF(data []byte) (DocumentMetadata, []VEXStatement, error) {
+	var document csafDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return DocumentMetadata{}, nil, fmt.Errorf("failed to unmarshal CSAF document: %w", err)
+	}
+	if document.Document.Category != "csaf_vex" {
+		return DocumentMetadata{}, nil, fmt.Errorf("CSAF document of category %q isn't a VEX document", document.Document.Category)
+	}
+	tracking := document.Document.Tracking
+	metadata := DocumentMetadata{
+		Name:         document.Document.Publisher.Name,
+		SerialNumber: tracking.ID,
+		Version:      tracking.Version,
+		Timestamp:    tracking.CurrentReleaseDate,
+	}
+	if engine := tracking.Generator.Engine; engine.Name != "" {
+		metadata.Tools = appendTool(nil, "", engine.Name, engine.Version)
+	}
+
+	// Products are referenced by ID. A relationship is a component of a product, which is the
+	// subject of the statements about it.
+	type product struct {
+		name          string
+		subcomponents []string
+	}
+	products := map[string]product{}
+	var addBranches func([]csafBranch)
+	addBranches = func(branches []csafBranch) {
+		for _, branch := range branches {
+			if branch.Product.ProductID != "" {
+				products[branch.Product.ProductID] = product{name: branch.Product.identifier()}
+			}
+			addBranches(branch.Branches)
+		}
+	}
+	addBranches(document.ProductTree.Branches)
+	for _, name := range document.ProductTree.FullProductNames {
+		products[name.ProductID] = product{name: name.identifier()}
+	}
+	for _, relationship := range document.ProductTree.Relationships {
+		parent, component := products[relationship.RelatesToProductReference], products[relationship.ProductReference]
+		if parent.name == "" || component.name == "" {
+			continue
+		}
+		products[relationship.FullProductName.ProductID] = product{name: parent.name, subcomponents: []string{component.name}}
+	}
+
+	var statements []VEXStatement
+	for _, vulnerability := range document.Vulnerabilities {
+		name := vulnerability.CVE
+		if name == "" && len(vulnerability.IDs) > 0 {
+			name = vulnerability.IDs[0].Text
+		}
+		if name == "" {
+			return DocumentMetadata{}, nil, fmt.Errorf("CSAF vulnerability without a CVE or ID")
+		}
+		justifications := map[string]string{}
+		for _, flag := range vulnerability.Flags {
+			for _, id := range flag.ProductIDs {
+				justifications[id] = flag.Label
+			}
+		}
+		impacts := map[string]string{}
+		for _, threat := range vulnerability.Threats {
+			if threat.Category != "impact" {
+				continue
+			}
+			for _, id := range threat.ProductIDs {
+				impacts[id] = threat.Details
+			}
+		}
+
+		categories := make([]string, 0, len(vulnerability.ProductStatus))
+		for category := range vulnerability.ProductStatus {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			status, ok := csafStatuses[category]
+			if !ok {
+				continue
+			}
+			for _, id := range vulnerability.ProductStatus[category] {
+				product, ok := products[id]
+				if !ok {
+					return DocumentMetadata{}, nil, fmt.Errorf("CSAF product status of %s references unknown product %s", name, id)
+				}
+				statements = append(statements, VEXStatement{
+					Vulnerability:   name,
+					Product:         product.name,
+					Subcomponents:   product.subcomponents,
+					Status:          status,
+					Justification:   justifications[id],
+					ImpactStatement: impacts[id],
+					Timestamp:       tracking.CurrentReleaseDate,
+				})
+			}
+		}
+	}
+	return metadata, statements, nil
+}
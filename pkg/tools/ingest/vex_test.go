@@ -0,0 +1,191 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openVEX(id, timestamp string, statements ...string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": %q,
+  "author": "Example Security",
+  "timestamp": %q,
+  "version": 1,
+  "statements": [%s]
+}`, id, timestamp, strings.Join(statements, ",")))
+}
+
+const csafVEX = `{
+  "document": {
+    "category": "csaf_vex",
+    "csaf_version": "2.0",
+    "publisher": {"category": "vendor", "name": "Example Security", "namespace": "https://example.com"},
+    "title": "c is not affected",
+    "tracking": {
+      "id": "EXAMPLE-VEX-1",
+      "version": "2",
+      "current_release_date": "2024-06-01T00:00:00Z",
+      "initial_release_date": "2024-05-01T00:00:00Z",
+      "status": "final",
+      "revision_history": [],
+      "generator": {"engine": {"name": "Secvisogram", "version": "1.11.0"}}
+    }
+  },
+  "product_tree": {
+    "branches": [{
+      "category": "vendor", "name": "Example",
+      "branches": [{
+        "category": "product_name", "name": "app",
+        "product": {"product_id": "APP", "name": "app 1.0.0", "product_identification_helper": {"purl": "pkg:npm/app@1.0.0"}}
+      }]
+    }],
+    "full_product_names": [
+      {"product_id": "C", "name": "c 1.0.0", "product_identification_helper": {"purl": "pkg:npm/c@1.0.0"}}
+    ],
+    "relationships": [{
+      "category": "default_component_of",
+      "product_reference": "C",
+      "relates_to_product_reference": "APP",
+      "full_product_name": {"product_id": "APP:C", "name": "c 1.0.0 as a component of app 1.0.0"}
+    }]
+  },
+  "vulnerabilities": [{
+    "cve": "CVE-2024-0001",
+    "product_status": {"known_not_affected": ["C"], "known_affected": ["APP:C"]},
+    "flags": [{"label": "vulnerable_code_not_in_execute_path", "product_ids": ["C"]}],
+    "threats": [{"category": "impact", "details": "c never calls the vulnerable function", "product_ids": ["C"]}]
+  }]
+}`
+
+func TestParseVEX(t *testing.T) {
+	metadata, statements, err := parseOpenVEX(openVEX("https://example.com/vex-1", "2024-05-01T00:00:00Z",
+		`{"vulnerability": {"name": "CVE-2024-0001", "aliases": ["GHSA-xxxx"]},
+		  "products": [{"@id": "pkg:npm/b@1.0.0", "subcomponents": [{"@id": "pkg:npm/d@1.0.0"}]}, "pkg:npm/c@1.0.0"],
+		  "status": "not_affected", "justification": "vulnerable_code_not_present"}`,
+		`{"vulnerability": "CVE-2024-0002", "products": ["pkg:npm/b@1.0.0"], "status": "affected",
+		  "timestamp": "2024-05-02T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, DocumentMetadata{Name: "Example Security", SerialNumber: "https://example.com/vex-1", Version: "1", Timestamp: "2024-05-01T00:00:00Z"}, metadata)
+	assert.Equal(t, []VEXStatement{
+		{Vulnerability: "CVE-2024-0001", Product: "pkg:npm/b@1.0.0", Subcomponents: []string{"pkg:npm/d@1.0.0"}, Status: VEXNotAffected, Justification: "vulnerable_code_not_present", Timestamp: "2024-05-01T00:00:00Z"},
+		{Vulnerability: "CVE-2024-0001", Product: "pkg:npm/c@1.0.0", Status: VEXNotAffected, Justification: "vulnerable_code_not_present", Timestamp: "2024-05-01T00:00:00Z"},
+		{Vulnerability: "CVE-2024-0002", Product: "pkg:npm/b@1.0.0", Status: VEXAffected, Timestamp: "2024-05-02T00:00:00Z"},
+	}, statements)
+
+	metadata, statements, err = parseCSAF([]byte(csafVEX))
+	require.NoError(t, err)
+	assert.Equal(t, DocumentMetadata{Name: "Example Security", SerialNumber: "EXAMPLE-VEX-1", Version: "2", Tools: []string{"Secvisogram 1.11.0"}, Timestamp: "2024-06-01T00:00:00Z"}, metadata)
+	assert.Equal(t, []VEXStatement{
+		{Vulnerability: "CVE-2024-0001", Product: "pkg:npm/app@1.0.0", Subcomponents: []string{"pkg:npm/c@1.0.0"}, Status: VEXAffected, Timestamp: "2024-06-01T00:00:00Z"},
+		{Vulnerability: "CVE-2024-0001", Product: "pkg:npm/c@1.0.0", Status: VEXNotAffected, Justification: "vulnerable_code_not_in_execute_path", ImpactStatement: "c never calls the vulnerable function", Timestamp: "2024-06-01T00:00:00Z"},
+	}, statements)
+
+	_, _, err = parseCSAF([]byte(`{"document": {"category": "csaf_security_advisory"}}`))
+	assert.Error(t, err, "only VEX profiles are ingested")
+	_, _, err = parseOpenVEX([]byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": "CVE-2024-0001", "products": ["pkg:npm/b@1.0.0"]}]}`))
+	assert.Error(t, err, "statements need a status")
+}
+
+func TestVEXSuppressions(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:11111111-1111-1111-1111-111111111111", "app", "b", "c")))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "TEST-1", "aliases": ["CVE-2024-0001"],
+	  "affected": [{"package": {"ecosystem": "npm", "name": "b"}, "versions": ["1.0.0"]},
+	               {"package": {"ecosystem": "npm", "name": "c"}, "versions": ["1.0.0"]}]}`)))
+	app, b, c := nodeByName(t, storage, "pkg:npm/app@1.0.0"), nodeByName(t, storage, "pkg:npm/b@1.0.0"), nodeByName(t, storage, "pkg:npm/c@1.0.0")
+	vuln := nodeByName(t, storage, "TEST-1")
+
+	suppressed := func() (forApp, forB, forC bool) {
+		t.Helper()
+		suppressions, err := Suppressions(ctx, storage)
+		require.NoError(t, err)
+		keys, err := storage.GetAllKeys(ctx)
+		require.NoError(t, err)
+		nodes, err := storage.GetNodes(ctx, keys)
+		require.NoError(t, err)
+		dependencies := roaring.BitmapOf(b.ID, c.ID, vuln.ID)
+		return suppressions.Suppressed(app.ID, vuln.ID, dependencies, nodes),
+			suppressions.Suppressed(b.ID, vuln.ID, roaring.BitmapOf(vuln.ID), nodes),
+			suppressions.Suppressed(c.ID, vuln.ID, roaring.BitmapOf(vuln.ID), nodes)
+	}
+
+	// A statement about an alias of the vulnerability, and a product purl that normalizes to
+	// the one of the node, applies to the node.
+	require.NoError(t, VEX(ctx, storage, openVEX("https://example.com/vex-b", "2024-05-01T00:00:00Z",
+		`{"vulnerability": {"name": "CVE-2024-0001"}, "products": [{"@id": "pkg:npm/B@1.0.0"}], "status": "not_affected",
+		  "justification": "vulnerable_code_not_present"}`,
+		`{"vulnerability": {"name": "CVE-2024-9999"}, "products": [{"@id": "pkg:npm/unknown@1.0.0"}], "status": "not_affected"}`)))
+	forApp, forB, forC := suppressed()
+	assert.False(t, forApp, "the vulnerability still reaches app through c")
+	assert.True(t, forB)
+	assert.False(t, forC)
+
+	require.NoError(t, VEX(ctx, storage, []byte(csafVEX)))
+	forApp, forB, forC = suppressed()
+	assert.True(t, forApp, "no dependency through which app reaches the vulnerability is affected")
+	assert.True(t, forB)
+	assert.True(t, forC)
+
+	// A later statement about the same product and vulnerability supersedes the earlier one.
+	require.NoError(t, VEX(ctx, storage, openVEX("https://example.com/vex-c", "2024-07-01T00:00:00Z",
+		`{"vulnerability": "CVE-2024-0001", "products": ["pkg:npm/c@1.0.0"], "status": "under_investigation"}`)))
+	forApp, _, forC = suppressed()
+	assert.False(t, forApp)
+	assert.False(t, forC)
+
+	// A new version of a document replaces its statements.
+	require.NoError(t, VEX(ctx, storage, openVEX("https://example.com/vex-c", "2024-08-01T00:00:00Z",
+		`{"vulnerability": "CVE-2024-0001", "products": ["pkg:npm/c@1.0.0"], "status": "fixed"}`)))
+	forApp, _, forC = suppressed()
+	assert.True(t, forApp)
+	assert.True(t, forC)
+	statements, err := VEXStatements(ctx, storage)
+	require.NoError(t, err)
+	assert.Len(t, statements, 3)
+	assert.Len(t, statements[tools.VEXDocumentType+":https://example.com/vex-c"], 1)
+	statement, ok, err := VEXStatementAbout(ctx, storage, "pkg:npm/c@1.0.0", "CVE-2024-0001")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, VEXFixed, statement.Status)
+
+	// A statement about the product itself suppresses the vulnerability regardless of its
+	// dependencies.
+	require.NoError(t, VEX(ctx, storage, openVEX("https://example.com/vex-c", "2024-09-01T00:00:00Z",
+		`{"vulnerability": "TEST-1", "products": ["pkg:npm/app@1.0.0"], "status": "not_affected",
+		  "justification": "inline_mitigations_already_exist"}`)))
+	forApp, _, forC = suppressed()
+	assert.True(t, forApp)
+	assert.True(t, forC, "the CSAF statement applies again")
+	statement, ok, err = VEXStatementAbout(ctx, storage, "pkg:npm/c@1.0.0", "CVE-2024-0001")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, VEXNotAffected, statement.Status, "only the CSAF document still states anything about c")
+	_, ok, err = VEXStatementAbout(ctx, storage, "pkg:npm/d@1.0.0", "CVE-2024-0001")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	documents, err := Documents(ctx, storage)
+	require.NoError(t, err)
+	var names []string
+	for _, document := range documents {
+		if document.Type == tools.VEXDocumentType {
+			names = append(names, document.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"vex:https://example.com/vex-b", "vex:EXAMPLE-VEX-1", "vex:https://example.com/vex-c"}, names)
+
+	assert.Error(t, VEX(ctx, storage, nil))
+	assert.Error(t, VEX(ctx, storage, []byte(`{"statements": "not a list"}`)))
+	assert.Error(t, VEX(ctx, storage, []byte(`{}`)), "neither OpenVEX nor CSAF")
+}
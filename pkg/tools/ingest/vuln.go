@@ -1,10 +1,14 @@
 package ingest
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/bitbomdev/minefield/pkg/graph"
@@ -26,6 +30,12 @@ type Vulnerability struct {
 	References       []Reference            `json:"references"`
 	Credits          []Credit               `json:"credits"`
 	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+
+	// The normalized severity of vulnerability nodes, which isn't part of the OSV schema. The
+	// type is the one of the severity it was computed from, see normalizeSeverity.
+	SeverityType   string  `json:"severity_type,omitempty"`
+	SeverityScore  float64 `json:"severity_score,omitempty"`
+	SeverityRating string  `json:"severity_rating,omitempty"`
 }
 
 type Severity struct {
@@ -78,8 +88,11 @@ type PairedVuln struct {
 	Vuln Vulnerability
 }
 
-// Vulnerabilities processes the vulnerabilityType data and adds it to the storage.
-func Vulnerabilities(storage graph.Storage, data []byte) error {
+// Vulnerabilities processes the vulnerabilityType data and adds it to the storage, linking it
+// to the library nodes of the package versions it affects, which are found through the
+// package index.
+func Vulnerabilities(ctx context.Context, storage graph.Storage, data []byte, opts ...Option) error {
+	o := newOptions(opts)
 	if len(data) == 0 {
 		return fmt.Errorf("data is empty")
 	}
@@ -89,82 +102,230 @@ func Vulnerabilities(storage graph.Storage, data []byte) error {
 		return fmt.Errorf("failed to unmarshal vulnerabilityType data: %w", err)
 	}
 
-	errors := []error{}
-
-	vulnMap := map[string][]Vulnerability{}
-
-	for _, affected := range vuln.Affected {
-		vulnMap[affected.Package.Name] = append(vulnMap[affected.Package.Name], vuln)
+	lookup := func(ctx context.Context, ecosystem, name string) (map[*graph.Node]PackageInfo, error) {
+		return packageNodes(ctx, storage, ecosystem, name)
 	}
+	return addVulnerability(ctx, storage, o, vuln, Digest(data), lookup)
+}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("errors occurred during vulnerabilities ingestion: %v", errors)
-	}
+// packageLookup returns the library nodes of the package with the given OSV ecosystem and
+// name, and their package information.
+type packageLookup func(ctx context.Context, ecosystem, name string) (map[*graph.Node]PackageInfo, error)
 
-	keys, err := storage.GetAllKeys()
+// addVulnerability stores the OSV record with the given SHA-256 digest as an advisory, adds the
+// vulnerability node, linked to the nodes of the affected package versions, and records the
+// record as a document. A record modified before the stored version of the advisory is
+// ignored.
+func addVulnerability(ctx context.Context, storage graph.Storage, o *options, vuln Vulnerability, sha256 string, lookup packageLookup) error {
+	stored, _, ok, err := loadAdvisory(ctx, storage, vuln.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get all keys: %w", err)
+		return err
 	}
-
-	nodes, err := storage.GetNodes(keys)
-	if err != nil {
-		return fmt.Errorf("failed to get nodes from storage: %w", err)
+	if ok && compareModified(vuln.Modified, stored.Modified) < 0 {
+		return nil
+	}
+	if err := storeAdvisory(ctx, storage, vuln, sha256); err != nil {
+		return err
 	}
+	contribution := NewContribution()
+	if err := matchVulnerability(ctx, storage, o, vuln, lookup, contribution); err != nil {
+		return err
+	}
+	return recordVulnerability(ctx, storage, vuln, sha256, contribution)
+}
 
-	for _, node := range nodes {
-		if node.Type == tools.LibraryType && strings.HasPrefix(node.Name, pkg) {
-			pkgInfo, err := PURLToPackage(node.Name)
-			if err != nil {
+// matchVulnerability links the nodes of the affected package versions lookup finds to the
+// vulnerability node, which is added on the first match, and adds the edges to the
+// contribution. Withdrawn advisories match nothing, so re-ingesting one unlinks it.
+func matchVulnerability(ctx context.Context, storage graph.Storage, o *options, vuln Vulnerability, lookup packageLookup, contribution *Contribution) error {
+	if vuln.Withdrawn != "" {
+		return nil
+	}
+	var vulnNode *graph.Node
+	for _, affected := range vuln.Affected {
+		nodes, pinned, err := affectedNodes(ctx, lookup, affected)
+		if err != nil {
+			return err
+		}
+		for node, pkgInfo := range nodes {
+			if !pinned && !isVersionAffected(affected, pkgInfo.Version) {
 				continue
 			}
-			vulnsData, ok := vulnMap[pkgInfo.Name]
-			if !ok {
-				continue
+			if vulnNode == nil {
+				if vulnNode, err = addVulnerabilityNode(ctx, storage, o, vuln); err != nil {
+					return err
+				}
 			}
-			if len(vulnsData) == 0 {
-				continue
+			if err := node.SetDependency(ctx, storage, vulnNode); err != nil {
+				return fmt.Errorf("failed to add dependency edge to vulnerabilityType node: %w", err)
 			}
-			for _, vuln := range vulnsData {
-				// We are using the vuln ID from the map instead of the vulnID from the vuln data, since the map key could be an alias of a vulnerabilityType ID
-				vulnData, err := json.Marshal(vuln)
-				if err != nil {
-					errors = append(errors, err)
-					continue
-				}
+			contribution.addEdge(node.ID, vulnNode.ID)
+		}
+	}
+	return nil
+}
 
-				if isPackageAffected(vuln, pkgInfo) {
-					vulnNode, err := graph.AddNode(storage, tools.VulnerabilityType, vulnData, vuln.ID)
-					if err != nil {
-						return fmt.Errorf("failed to add vulnerabilityType node to storage: %w", err)
-					}
+// addVulnerabilityNode returns the node of the vulnerability, adding it if needed. The records
+// of an issue published by several databases, like a GHSA advisory and the CVE it aliases,
+// share one canonical node: the node of the first record of the alias group that was
+// ingested, which the IDs of the others are aliases of. The node's metadata is the most
+// recently modified record with its normalized severity, records modified at the same time are
+// merged by the merge policy.
+func addVulnerabilityNode(ctx context.Context, storage graph.Storage, o *options, vuln Vulnerability) (*graph.Node, error) {
+	normalizeSeverity(&vuln)
+	names := append([]string{vuln.ID}, vuln.Aliases...)
+	var node *graph.Node
+	for _, name := range names {
+		id, err := graph.ResolveName(ctx, storage, name)
+		if errors.Is(err, graph.ErrNodeNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to look up vulnerability %s: %w", name, err)
+		}
+		candidate, err := storage.GetNode(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vulnerability %s: %w", name, err)
+		}
+		if candidate.Type == tools.VulnerabilityType {
+			node = candidate
+			break
+		}
+	}
 
-					if err := node.SetDependency(storage, vulnNode); err != nil {
-						return fmt.Errorf("failed to add dependency edge to vulnerabilityType node: %w", err)
-					}
-				}
-			}
+	var err error
+	if node == nil {
+		node, err = graph.AddNode(ctx, storage, tools.VulnerabilityType, vuln, vuln.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add vulnerabilityType node to storage: %w", err)
+		}
+	} else {
+		node, err = upsertVulnerability(ctx, storage, o, node, vuln)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	aliases := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != node.Name {
+			aliases = append(aliases, name)
+		}
+	}
+	if len(aliases) > 0 {
+		if err := storage.AddAliases(ctx, node.ID, aliases); err != nil {
+			return nil, fmt.Errorf("failed to add aliases to vulnerability %s: %w", node.Name, err)
+		}
+	}
+	return node, nil
 }
 
-// isPackageAffected checks if the package is affected by the vulnerabilityType.
-func isPackageAffected(vuln Vulnerability, pkgInfo PackageInfo) bool {
-	for _, affected := range vuln.Affected {
-		if affected.Package.Name != pkgInfo.Name || affected.Package.Ecosystem != pkgInfo.Ecosystem {
-			continue
+// upsertVulnerability replaces the metadata of the vulnerability node by the record if it was
+// modified after the one the node has.
+func upsertVulnerability(ctx context.Context, storage graph.Storage, o *options, node *graph.Node, vuln Vulnerability) (*graph.Node, error) {
+	current, err := NodeVulnerability(node.Metadata)
+	if err != nil {
+		// Metadata that isn't an OSV record is replaced.
+		current = Vulnerability{}
+	}
+	switch compareModified(vuln.Modified, current.Modified) {
+	case 1:
+		updated, err := storage.UpdateNodeMetadata(ctx, node.ID, func(metadata any) (any, error) {
+			// A newer record may have been stored since the node was read.
+			if latest, err := NodeVulnerability(metadata); err == nil && compareModified(vuln.Modified, latest.Modified) <= 0 {
+				return metadata, nil
+			}
+			return vuln, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update vulnerability %s: %w", node.Name, err)
 		}
+		return updated, nil
+	case 0:
+		return graph.MergeNodeMetadata(ctx, storage, node, vuln, o.mergePolicy.Strategy(tools.VulnerabilityType))
+	default:
+		return node, nil
+	}
+}
 
-		if isVersionIncluded(pkgInfo.Version, affected.Versions) {
-			return true
+// NodeVulnerability returns the OSV record that is the metadata of a vulnerability node.
+func NodeVulnerability(metadata any) (Vulnerability, error) {
+	vuln := Vulnerability{}
+	var data []byte
+	switch metadata := metadata.(type) {
+	case []byte:
+		data = metadata
+	case string:
+		// Nodes added before the record was stored as is hold its JSON encoding, which comes
+		// back from the storage base64 encoded.
+		decoded, err := base64.StdEncoding.DecodeString(metadata)
+		if err != nil {
+			return vuln, fmt.Errorf("failed to decode vulnerability: %w", err)
+		}
+		data = decoded
+	default:
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return vuln, fmt.Errorf("failed to encode vulnerability: %w", err)
 		}
+		data = encoded
+	}
+	if err := json.Unmarshal(data, &vuln); err != nil {
+		return vuln, fmt.Errorf("failed to unmarshal vulnerability: %w", err)
+	}
+	return vuln, nil
+}
 
-		if isVersionInRanges(pkgInfo.Version, affected.Ranges, affected.Package.Ecosystem) {
-			return true
+// compareModified compares the modified timestamps of two OSV records. Timestamps are RFC 3339,
+// ones that don't parse are compared as strings, and a missing one is the oldest.
+func compareModified(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+	aTime, aErr := time.Parse(time.RFC3339, a)
+	bTime, bErr := time.Parse(time.RFC3339, b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+	return aTime.Compare(bTime)
+}
+
+// recordVulnerability records the OSV record with the given SHA-256 digest as a document that
+// asserted the contribution.
+func recordVulnerability(ctx context.Context, storage graph.Storage, vuln Vulnerability, sha256 string, contribution *Contribution) error {
+	metadata := DocumentMetadata{Name: vuln.ID, Version: vuln.SchemaVersion, Timestamp: vuln.Modified, SHA256: sha256}
+	return recordDocument(ctx, storage, tools.OSVDocumentType, tools.OSVDocumentType+":"+vuln.ID, metadata, contribution)
+}
+
+// affectedNodes returns the library nodes of the package an affected entry names. The purl
+// names the package more precisely than the ecosystem and name, so it is preferred when the
+// entry has one. A purl with a version pins the affected version, which is reported as pinned.
+func affectedNodes(ctx context.Context, lookup packageLookup, affected Affected) (map[*graph.Node]PackageInfo, bool, error) {
+	if affected.Package.Purl != "" {
+		if purlInfo, err := PURLToPackage(affected.Package.Purl); err == nil {
+			nodes, err := lookup(ctx, purlInfo.Ecosystem, purlInfo.Name)
+			if err != nil || purlInfo.Version == "" {
+				return nodes, false, err
+			}
+			for node, pkgInfo := range nodes {
+				if pkgInfo.Version != purlInfo.Version {
+					delete(nodes, node)
+				}
+			}
+			return nodes, true, nil
 		}
 	}
-	return false
+	nodes, err := lookup(ctx, affected.Package.Ecosystem, affected.Package.Name)
+	return nodes, false, err
+}
+
+// isVersionAffected checks if the version of the package is affected by the affected entry.
+func isVersionAffected(affected Affected, version string) bool {
+	return isVersionIncluded(version, affected.Versions) || isVersionInRanges(version, affected.Ranges, affected.Package.Ecosystem)
 }
 
 func isVersionIncluded(version string, versions []string) bool {
@@ -182,6 +343,9 @@ func isVersionInRanges(version string, ranges []Range, ecosystem string) bool {
 		sortedEvents := sortRangeEvents(r.Events, r.Type, ecosystem)
 		for _, evt := range sortedEvents {
 			switch {
+			case evt.Introduced == "0":
+				// An introduced version of 0 means every version, however the ecosystem orders them.
+				vulnerable = true
 			case evt.Introduced != "" && compareVersions(version, evt.Introduced, r.Type, ecosystem) >= 0:
 				vulnerable = true
 			case evt.Fixed != "" && compareVersions(version, evt.Fixed, r.Type, ecosystem) >= 0:
@@ -203,12 +367,12 @@ func sortRangeEvents(events []Event, eventType string, ecosystem string) []Event
 	copy(sortedEvents, events)
 
 	lessFunc := func(i, j int) bool {
-		vi := getVersionFromEvent(events[i])
-		vj := getVersionFromEvent(events[j])
+		vi := getVersionFromEvent(sortedEvents[i])
+		vj := getVersionFromEvent(sortedEvents[j])
 		return compareVersions(vi, vj, eventType, ecosystem) < 0
 	}
 
-	sort.Slice(sortedEvents, lessFunc)
+	sort.SliceStable(sortedEvents, lessFunc)
 	return sortedEvents
 }
 
@@ -247,9 +411,3 @@ func compareVersions(v1, v2, eventType, ecosystem string) int {
 		return strings.Compare(v1, v2)
 	}
 }
-
-func compareEcosystemVersions(v1, v2, _ string) int {
-	// Implement ecosystem-specific version comparison logic here.
-	// Placeholder implementation:
-	return strings.Compare(v1, v2)
-}
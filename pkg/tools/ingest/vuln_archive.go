@@ -0,0 +1,128 @@
+package ingest
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+// VulnerabilityArchiveResult counts the advisories of an archive ingested by
+// VulnerabilityArchive.
+type VulnerabilityArchiveResult struct {
+	// Ingested is the number of advisories that were added or updated.
+	Ingested int
+	// Unchanged is the number of advisories that were ingested before, and skipped.
+	Unchanged int
+}
+
+// VulnerabilityArchive ingests the OSV records of a ZIP archive, like the all.zip exports of
+// osv.dev, in one pass. The library nodes are read once into an in-memory package index, so
+// the records are matched without a storage lookup each. Records that were ingested before
+// are skipped.
+func VulnerabilityArchive(ctx context.Context, storage graph.Storage, r io.ReaderAt, size int64, opts ...Option) (VulnerabilityArchiveResult, error) {
+	o := newOptions(opts)
+	result := VulnerabilityArchiveResult{}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return result, fmt.Errorf("failed to open vulnerability archive: %w", err)
+	}
+
+	index, err := loadPackageIndex(ctx, storage)
+	if err != nil {
+		return result, err
+	}
+	lookup := indexLookup(index)
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		data, err := readZipFile(file)
+		if err != nil {
+			return result, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+
+		sha256 := Digest(data)
+		unchanged, err := Unchanged(ctx, storage, sha256)
+		if err != nil {
+			return result, fmt.Errorf("failed to look up %s: %w", file.Name, err)
+		}
+		if unchanged {
+			result.Unchanged++
+			continue
+		}
+
+		vuln := Vulnerability{}
+		if err := json.Unmarshal(data, &vuln); err != nil {
+			return result, fmt.Errorf("failed to unmarshal vulnerabilityType data of %s: %w", file.Name, err)
+		}
+		if err := addVulnerability(ctx, storage, o, vuln, sha256, lookup); err != nil {
+			return result, fmt.Errorf("failed to ingest %s: %w", file.Name, err)
+		}
+		result.Ingested++
+	}
+	return result, nil
+}
+
+// loadPackageIndex reads every library node into a package index by packageKey.
+func loadPackageIndex(ctx context.Context, storage graph.Storage) (map[string]map[*graph.Node]PackageInfo, error) {
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	return packageIndex(nodes), nil
+}
+
+// packageIndex returns the library nodes among the given ones by packageKey.
+func packageIndex(nodes map[uint32]*graph.Node) map[string]map[*graph.Node]PackageInfo {
+	index := map[string]map[*graph.Node]PackageInfo{}
+	for _, node := range nodes {
+		if node.Type != tools.LibraryType {
+			continue
+		}
+		pkgInfo, err := PURLToPackage(node.Name)
+		if err != nil {
+			continue
+		}
+		key := packageKey(pkgInfo.Ecosystem, pkgInfo.Name)
+		if index[key] == nil {
+			index[key] = map[*graph.Node]PackageInfo{}
+		}
+		index[key][node] = pkgInfo
+	}
+	return index
+}
+
+// indexLookup returns a packageLookup finding the nodes in an in-memory package index.
+func indexLookup(index map[string]map[*graph.Node]PackageInfo) packageLookup {
+	return func(_ context.Context, ecosystem, name string) (map[*graph.Node]PackageInfo, error) {
+		// The lookup's result is filtered by the caller, so it gets its own copy.
+		nodes := map[*graph.Node]PackageInfo{}
+		for node, pkgInfo := range index[packageKey(ecosystem, name)] {
+			nodes[node] = pkgInfo
+		}
+		return nodes, nil
+	}
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,62 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// osvTestStorage returns a storage with the SBOMs the OSV test records affect.
+func osvTestStorage(t *testing.T) graph.Storage {
+	t.Helper()
+	storage := graph.NewMockStorage()
+	files, err := filepath.Glob("../../../testdata/osv-sboms/*.json")
+	require.NoError(t, err)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.NoError(t, SBOM(context.Background(), storage, data))
+	}
+	return storage
+}
+
+func TestVulnerabilityArchive(t *testing.T) {
+	ctx := context.Background()
+	files, err := filepath.Glob("../../../testdata/osv-vulns/*.json")
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	expected := osvTestStorage(t)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		f, err := w.Create(filepath.Base(file))
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, Vulnerabilities(ctx, expected, data))
+	}
+	require.NoError(t, w.Close())
+	archive := bytes.NewReader(buf.Bytes())
+
+	// The archive gives the same graph as ingesting its records one by one.
+	actual := osvTestStorage(t)
+	result, err := VulnerabilityArchive(ctx, actual, archive, archive.Size())
+	require.NoError(t, err)
+	assert.Equal(t, VulnerabilityArchiveResult{Ingested: len(files)}, result)
+	assert.Equal(t, graphSnapshot(t, expected), graphSnapshot(t, actual))
+
+	result, err = VulnerabilityArchive(ctx, actual, archive, archive.Size())
+	require.NoError(t, err)
+	assert.Equal(t, VulnerabilityArchiveResult{Unchanged: len(files)}, result)
+
+	_, err = VulnerabilityArchive(ctx, actual, bytes.NewReader([]byte("not a zip")), 9)
+	assert.Error(t, err)
+}
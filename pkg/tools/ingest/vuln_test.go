@@ -1,12 +1,16 @@
 package ingest
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVulnerabilities(t *testing.T) {
@@ -31,12 +35,12 @@ func TestVulnerabilities(t *testing.T) {
 			t.Fatalf("Failed to read SBOM file %s: %v", file.Name(), err)
 		}
 
-		if err := SBOM(storage, data); err != nil {
+		if err := SBOM(context.Background(), storage, data); err != nil {
 			t.Fatalf("Failed to load SBOM from file %s: %v", file.Name(), err)
 		}
 	}
 
-	keys, err := storage.GetAllKeys()
+	keys, err := storage.GetAllKeys(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get all keys, %v", err)
 	}
@@ -59,7 +63,7 @@ func TestVulnerabilities(t *testing.T) {
 			t.Fatalf("Failed to read vulnerability file %s: %v", file.Name(), err)
 		}
 
-		if err := Vulnerabilities(storage, data); err != nil {
+		if err := Vulnerabilities(context.Background(), storage, data); err != nil {
 			t.Fatalf("Failed to load vulnerabilities from file %s: %v", file.Name(), err)
 		}
 		vulnCount++
@@ -69,16 +73,146 @@ func TestVulnerabilities(t *testing.T) {
 		t.Fatal("Expected vulnerabilities to be ingested, got 0")
 	}
 
-	keys, err = storage.GetAllKeys()
+	keys, err = storage.GetAllKeys(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get all keys, %v", err)
 	}
 
-	if len(keys) != numberOfNodes+3 {
-		t.Fatalf("Expected number of nodes to be %d, got %d", numberOfNodes+3, len(keys))
+	// Three vulnerability nodes and one document node for every OSV record.
+	if len(keys) != numberOfNodes+3+vulnCount {
+		t.Fatalf("Expected number of nodes to be %d, got %d", numberOfNodes+3+vulnCount, len(keys))
 	}
 }
 
+func TestVulnerabilitiesMatching(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	for _, name := range []string{
+		"pkg:pypi/foo-bar@1.0",
+		"pkg:pypi/foo-bar@2.0",
+		"pkg:npm/foo-bar@1.0",
+		"pkg:deb/debian/curl@7.0-1",
+		"pkg:npm/x@1.0.0",
+		"pkg:npm/x@1.1.0",
+	} {
+		node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, name)
+		require.NoError(t, err)
+		require.NoError(t, indexPackage(ctx, storage, node))
+	}
+
+	advisories := []string{
+		// Matched by ecosystem and name, which PyPI normalizes.
+		`{"id": "TEST-1", "affected": [{"package": {"ecosystem": "PyPI", "name": "Foo.Bar"},
+		  "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.0"}]}]}]}`,
+		// Matched by purl, though the name is the Debian source package.
+		`{"id": "TEST-2", "affected": [{"package": {"ecosystem": "Debian:11", "name": "curl-source", "purl": "pkg:deb/debian/curl"},
+		  "versions": ["7.0-1"]}]}`,
+		// A purl with a version pins the affected version.
+		`{"id": "TEST-3", "affected": [{"package": {"ecosystem": "npm", "name": "x", "purl": "pkg:npm/x@1.0.0"}}]}`,
+	}
+	for _, advisory := range advisories {
+		require.NoError(t, Vulnerabilities(ctx, storage, []byte(advisory)))
+	}
+
+	affected := func(name string) []string {
+		id, err := storage.NameToID(ctx, name)
+		require.NoError(t, err)
+		node, err := storage.GetNode(ctx, id)
+		require.NoError(t, err)
+		var vulns []string
+		for _, child := range node.Children.ToArray() {
+			childNode, err := storage.GetNode(ctx, child)
+			require.NoError(t, err)
+			vulns = append(vulns, childNode.Name)
+		}
+		return vulns
+	}
+	assert.Equal(t, []string{"TEST-1"}, affected("pkg:pypi/foo-bar@1.0"))
+	assert.Empty(t, affected("pkg:pypi/foo-bar@2.0"))
+	assert.Empty(t, affected("pkg:npm/foo-bar@1.0"), "other ecosystems aren't affected")
+	assert.Equal(t, []string{"TEST-2"}, affected("pkg:deb/debian/curl@7.0-1"))
+	assert.Equal(t, []string{"TEST-3"}, affected("pkg:npm/x@1.0.0"))
+	assert.Empty(t, affected("pkg:npm/x@1.1.0"))
+}
+
+func TestVulnerabilityAliases(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:npm/x@1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, indexPackage(ctx, storage, node))
+
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "GHSA-1", "modified": "2024-01-01T00:00:00Z", "aliases": ["CVE-1"],
+	  "affected": [{"package": {"ecosystem": "npm", "name": "x"}, "versions": ["1.0.0"]}]}`)))
+	require.NoError(t, Vulnerabilities(ctx, storage, []byte(`{"id": "CVE-1", "modified": "2024-02-01T00:00:00Z", "aliases": ["GHSA-1", "OSV-1"],
+	  "affected": [{"package": {"ecosystem": "npm", "name": "x"}, "versions": ["1.0.0"]}]}`)))
+
+	// Both records link the package to the node of the record ingested first.
+	vuln := nodeByName(t, storage, "GHSA-1")
+	assert.Equal(t, []uint32{vuln.ID}, nodeByName(t, storage, "pkg:npm/x@1.0.0").Children.ToArray())
+	aliases, err := storage.GetAliases(ctx, vuln.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CVE-1", "OSV-1"}, aliases)
+	id, err := graph.ResolveName(ctx, storage, "OSV-1")
+	require.NoError(t, err)
+	assert.Equal(t, vuln.ID, id)
+
+	// The node holds the most recently modified record.
+	metadata, err := NodeVulnerability(vuln.Metadata)
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-1", metadata.ID)
+}
+
+func TestVulnerabilityModified(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:11111111-1111-1111-1111-111111111111", "app", "x", "y")))
+	record := func(modified, withdrawn, name string) []byte {
+		return []byte(`{"id": "TEST-1", "modified": "` + modified + `", "withdrawn": "` + withdrawn + `", "summary": "` + modified + `",
+		  "affected": [{"package": {"ecosystem": "npm", "name": "` + name + `"}, "versions": ["1.0.0"]}]}`)
+	}
+	summary := func() string {
+		metadata, err := NodeVulnerability(nodeByName(t, storage, "TEST-1").Metadata)
+		require.NoError(t, err)
+		return metadata.Summary
+	}
+	children := func(name string) []uint32 {
+		return nodeByName(t, storage, name).Children.ToArray()
+	}
+
+	require.NoError(t, Vulnerabilities(ctx, storage, record("2024-01-01T00:00:00Z", "", "x")))
+	vuln := nodeByName(t, storage, "TEST-1")
+	assert.Equal(t, []uint32{vuln.ID}, children("pkg:npm/x@1.0.0"))
+
+	// A newer version of the record replaces the metadata and the links.
+	require.NoError(t, Vulnerabilities(ctx, storage, record("2024-02-01T00:00:00Z", "", "y")))
+	assert.Equal(t, "2024-02-01T00:00:00Z", summary())
+	assert.Empty(t, children("pkg:npm/x@1.0.0"))
+	assert.Equal(t, []uint32{vuln.ID}, children("pkg:npm/y@1.0.0"))
+
+	// An older one is ignored.
+	require.NoError(t, Vulnerabilities(ctx, storage, record("2024-01-15T00:00:00Z", "", "x")))
+	assert.Equal(t, "2024-02-01T00:00:00Z", summary())
+	assert.Empty(t, children("pkg:npm/x@1.0.0"))
+
+	// Withdrawing the advisory unlinks it, also from packages ingested later.
+	require.NoError(t, Vulnerabilities(ctx, storage, record("2024-03-01T00:00:00Z", "2024-03-01T00:00:00Z", "y")))
+	assert.Empty(t, children("pkg:npm/y@1.0.0"))
+	_, err := storage.NameToID(ctx, "TEST-1")
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound, "the unlinked vulnerability node is removed")
+	require.NoError(t, SBOM(ctx, storage, cycloneDX("urn:uuid:22222222-2222-2222-2222-222222222222", "other", "y")))
+	assert.Empty(t, children("pkg:npm/y@1.0.0"))
+}
+
+func TestCompareModified(t *testing.T) {
+	assert.Equal(t, 0, compareModified("2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"))
+	assert.Equal(t, 0, compareModified("2024-01-01T00:00:00Z", "2024-01-01T01:00:00+01:00"))
+	assert.Equal(t, -1, compareModified("2024-01-01T00:00:00Z", "2024-01-01T00:00:00.5Z"))
+	assert.Equal(t, 1, compareModified("2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z"))
+	assert.Equal(t, -1, compareModified("", "2024-01-01T00:00:00Z"))
+	assert.Equal(t, 1, compareModified("2024-01-01T00:00:00Z", ""))
+}
+
 func TestCompareVersions(t *testing.T) {
 	tests := []struct {
 		name      string
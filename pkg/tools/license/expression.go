@@ -0,0 +1,237 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operators of SPDX license expressions.
+const (
+	And = "AND"
+	Or  = "OR"
+)
+
+// Expression is a parsed SPDX license expression. It is either a license, optionally with an
+// exception, or the conjunction or disjunction of two expressions.
+type Expression struct {
+	// Op is And or Or for compound expressions, and empty for licenses.
+	Op    string      `json:"op,omitempty"`
+	Left  *Expression `json:"left,omitempty"`
+	Right *Expression `json:"right,omitempty"`
+	// License is the SPDX ID or LicenseRef of a license, with a trailing "+" for "or later".
+	License   string `json:"license,omitempty"`
+	Exception string `json:"exception,omitempty"`
+}
+
+// Parse parses an SPDX license expression. Operators are case-insensitive, AND binds tighter
+// than OR, and both are left-associative.
+func Parse(expression string) (*Expression, error) {
+	p := &expressionParser{tokens: tokenize(expression)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	parsed, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", expression, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", expression, p.tokens[p.pos])
+	}
+	return parsed, nil
+}
+
+// String returns the expression in its canonical form, with uppercase operators and the
+// parentheses needed to keep its meaning.
+func (e *Expression) String() string {
+	if e.Op == "" {
+		if e.Exception != "" {
+			return e.License + " WITH " + e.Exception
+		}
+		return e.License
+	}
+	left, right := e.Left.String(), e.Right.String()
+	// OR binds looser than AND, so an OR inside an AND needs parentheses. The right operand
+	// needs them for the same operator too, since operators are left-associative.
+	if e.Op == And && e.Left.Op == Or {
+		left = "(" + left + ")"
+	}
+	if e.Right.Op != "" && (e.Op == And || e.Right.Op == Or) {
+		right = "(" + right + ")"
+	}
+	return left + " " + e.Op + " " + right
+}
+
+// Licenses returns the licenses the expression names, with their exceptions, in the order they
+// appear and without duplicates.
+func (e *Expression) Licenses() []*Expression {
+	var licenses []*Expression
+	seen := map[string]bool{}
+	var walk func(*Expression)
+	walk = func(e *Expression) {
+		if e.Op != "" {
+			walk(e.Left)
+			walk(e.Right)
+			return
+		}
+		if name := e.String(); !seen[name] {
+			seen[name] = true
+			licenses = append(licenses, e)
+		}
+	}
+	walk(e)
+	return licenses
+}
+
+// Effective returns the expression that governs a package: the concluded one if the SBOM
+// concludes one, or else the conjunction of the declared ones. NOASSERTION and NONE aren't
+// licenses, so a package with only those has none. A concluded expression that isn't valid is
+// only used if the declared ones aren't either, since protobom concludes an invalid one from
+// the licenses of CycloneDX components with several.
+func Effective(concluded string, declared []string) string {
+	var expressions []string
+	for _, expression := range declared {
+		if !isLicense(expression) {
+			continue
+		}
+		if len(declared) > 1 && strings.ContainsAny(expression, " ") {
+			expression = "(" + expression + ")"
+		}
+		expressions = append(expressions, expression)
+	}
+	conjunction := strings.Join(expressions, " "+And+" ")
+	if !isLicense(concluded) {
+		return conjunction
+	}
+	if _, err := Parse(concluded); err != nil && conjunction != "" {
+		if _, err := Parse(conjunction); err == nil {
+			return conjunction
+		}
+	}
+	return concluded
+}
+
+func isLicense(expression string) bool {
+	switch strings.ToUpper(strings.TrimSpace(expression)) {
+	case "", "NOASSERTION", "NONE":
+		return false
+	default:
+		return true
+	}
+}
+
+// tokenize splits an expression into parentheses and words.
+func tokenize(expression string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(expression) {
+		for word != "" {
+			if i := strings.IndexAny(word, "()"); i == 0 {
+				tokens, word = append(tokens, word[:1]), word[1:]
+			} else if i > 0 {
+				tokens, word = append(tokens, word[:i]), word[i:]
+			} else {
+				tokens, word = append(tokens, word), ""
+			}
+		}
+	}
+	return tokens
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// accept consumes the next token if it is the given operator, regardless of its case.
+func (p *expressionParser) accept(operator string) bool {
+	if strings.EqualFold(p.peek(), operator) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(Or) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expression{Op: Or, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	left, err := p.parseLicense()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(And) {
+		right, err := p.parseLicense()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expression{Op: And, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseLicense() (*Expression, error) {
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	id := p.peek()
+	if !isIdentifier(id) {
+		if id == "" {
+			return nil, fmt.Errorf("unexpected end of expression")
+		}
+		return nil, fmt.Errorf("unexpected %q", id)
+	}
+	p.pos++
+	license := &Expression{License: id}
+	if p.accept("WITH") {
+		exception := p.peek()
+		if !isIdentifier(exception) || strings.HasSuffix(exception, "+") {
+			return nil, fmt.Errorf("invalid exception %q", exception)
+		}
+		p.pos++
+		license.Exception = exception
+	}
+	return license, nil
+}
+
+// isIdentifier reports whether the token is a license or exception ID, a LicenseRef or a
+// DocumentRef, rather than an operator or a parenthesis.
+func isIdentifier(token string) bool {
+	switch strings.ToUpper(token) {
+	case "", And, Or, "WITH":
+		return false
+	}
+	for i, r := range token {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == ':':
+		case r == '+' && i == len(token)-1 && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
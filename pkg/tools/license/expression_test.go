@@ -0,0 +1,68 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		licenses   []string
+		wantErr    bool
+	}{
+		{expression: "MIT", want: "MIT", licenses: []string{"MIT"}},
+		{expression: "MIT or Apache-2.0", want: "MIT OR Apache-2.0", licenses: []string{"MIT", "Apache-2.0"}},
+		{expression: "MIT AND BSD-3-Clause OR Apache-2.0", want: "MIT AND BSD-3-Clause OR Apache-2.0", licenses: []string{"MIT", "BSD-3-Clause", "Apache-2.0"}},
+		{expression: "MIT AND (BSD-3-Clause OR Apache-2.0)", want: "MIT AND (BSD-3-Clause OR Apache-2.0)", licenses: []string{"MIT", "BSD-3-Clause", "Apache-2.0"}},
+		{expression: "((MIT)) OR (MIT AND ISC)", want: "MIT OR MIT AND ISC", licenses: []string{"MIT", "ISC"}},
+		{expression: "GPL-2.0-or-later WITH Classpath-exception-2.0 OR GPL-2.0+", want: "GPL-2.0-or-later WITH Classpath-exception-2.0 OR GPL-2.0+", licenses: []string{"GPL-2.0-or-later WITH Classpath-exception-2.0", "GPL-2.0+"}},
+		{expression: "LicenseRef-Proprietary AND DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", want: "LicenseRef-Proprietary AND DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", licenses: []string{"LicenseRef-Proprietary", "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2"}},
+		{expression: "", wantErr: true},
+		{expression: "MIT OR", wantErr: true},
+		{expression: "(MIT", wantErr: true},
+		{expression: "MIT Apache-2.0", wantErr: true},
+		{expression: "Apache License 2.0", wantErr: true},
+		{expression: "MIT WITH", wantErr: true},
+		{expression: "+", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			parsed, err := Parse(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, parsed.String())
+			var licenses []string
+			for _, license := range parsed.Licenses() {
+				licenses = append(licenses, license.String())
+			}
+			assert.Equal(t, tt.licenses, licenses)
+
+			reparsed, err := Parse(parsed.String())
+			require.NoError(t, err)
+			assert.Equal(t, parsed, reparsed, "the canonical form keeps the meaning")
+		})
+	}
+}
+
+func TestParseRightAssociative(t *testing.T) {
+	parsed, err := Parse("MIT AND (ISC AND Apache-2.0)")
+	require.NoError(t, err)
+	assert.Equal(t, "MIT AND (ISC AND Apache-2.0)", parsed.String())
+}
+
+func TestEffective(t *testing.T) {
+	assert.Equal(t, "MIT", Effective("MIT", []string{"Apache-2.0"}))
+	assert.Equal(t, "Apache-2.0", Effective("NOASSERTION", []string{"Apache-2.0"}))
+	assert.Equal(t, "(MIT OR ISC) AND Apache-2.0", Effective("", []string{"MIT OR ISC", "NONE", "Apache-2.0"}))
+	assert.Equal(t, "MIT OR ISC", Effective("", []string{"MIT OR ISC"}))
+	assert.Empty(t, Effective("NONE", nil))
+	assert.Equal(t, "MIT AND ISC", Effective("MIT(MIT) OR  (ISC)", []string{"MIT", "ISC"}))
+	assert.Equal(t, "Custom License", Effective("Custom License", []string{"Custom License"}))
+}
@@ -0,0 +1,256 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+)
+
+// ExpressionsTag is the custom data tag under which the effective license expressions of the
+// library nodes are stored, keyed by the name of the node. Every document that asserted an
+// expression for the node has a field named by the document, empty once it no longer does.
+const ExpressionsTag = "licenses"
+
+// Verdicts of a license policy, from best to worst.
+const (
+	Allowed    = "allowed"
+	NotAllowed = "not_allowed"
+	Denied     = "denied"
+)
+
+// Policy is a license policy. Denied licenses are never acceptable. If licenses are allowed,
+// only they are acceptable, otherwise every license that isn't denied is. Licenses are named
+// by their SPDX ID, and match case-insensitively. A license with an exception matches the
+// policy of the license with the exception if it has one, or else of the license alone.
+type Policy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Evaluate returns the verdict of the policy on the expression, and the licenses that keep it
+// from being allowed. A disjunction is as acceptable as its best choice, and a conjunction as
+// its worst part.
+func (p Policy) Evaluate(e *Expression) (string, []string) {
+	if e.Op == "" {
+		if verdict := p.evaluateLicense(e); verdict != Allowed {
+			return verdict, []string{e.String()}
+		}
+		return Allowed, nil
+	}
+	left, leftLicenses := p.Evaluate(e.Left)
+	right, rightLicenses := p.Evaluate(e.Right)
+	verdict := left
+	if e.Op == Or && rank(right) > rank(left) || e.Op == And && rank(right) < rank(left) {
+		verdict = right
+	}
+	if verdict == Allowed {
+		return Allowed, nil
+	}
+	var licenses []string
+	for _, name := range append(leftLicenses, rightLicenses...) {
+		if !slices.Contains(licenses, name) {
+			licenses = append(licenses, name)
+		}
+	}
+	return verdict, licenses
+}
+
+func (p Policy) evaluateLicense(e *Expression) string {
+	names := []string{e.License}
+	if e.Exception != "" {
+		names = []string{e.String(), e.License}
+	}
+	for _, name := range names {
+		switch {
+		case containsFold(p.Deny, name):
+			return Denied
+		case containsFold(p.Allow, name):
+			return Allowed
+		}
+	}
+	if len(p.Allow) == 0 {
+		return Allowed
+	}
+	return NotAllowed
+}
+
+// rank orders the verdicts from worst to best.
+func rank(verdict string) int {
+	switch verdict {
+	case Allowed:
+		return 2
+	case NotAllowed:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation is a library of a project whose license expression the policy doesn't allow.
+type Violation struct {
+	Library    string
+	Expression string
+	Verdict    string
+	// Licenses are the licenses of the expression that keep it from being allowed.
+	Licenses []string
+}
+
+// Report is the evaluation of a license policy on a project: a root library and the libraries
+// it depends on.
+type Report struct {
+	Project    string
+	Libraries  int
+	Unlicensed int
+	Violations []Violation
+}
+
+// EvaluatePolicy evaluates the policy on the licenses of the given projects, by name or alias,
+// or else of every root library, a library no other library depends on. Libraries without a
+// license expression are counted as unlicensed, but aren't violations. Expressions that aren't
+// valid SPDX expressions are evaluated as the name of a single license. When documents
+// disagree on the expression of a library, the library has to satisfy all of them.
+func EvaluatePolicy(ctx context.Context, storage graph.Storage, policy Policy, projects []string) ([]Report, error) {
+	keys, err := storage.GetAllKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all keys: %w", err)
+	}
+	nodes, err := storage.GetNodes(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes from storage: %w", err)
+	}
+	expressions, err := Expressions(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*graph.Node
+	if len(projects) > 0 {
+		for _, project := range projects {
+			id, err := graph.ResolveName(ctx, storage, project)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find project %s: %w", project, err)
+			}
+			roots = append(roots, nodes[id])
+		}
+	} else {
+		for _, node := range nodes {
+			if node.Type == tools.LibraryType && !hasLibraryParent(node, nodes) {
+				roots = append(roots, node)
+			}
+		}
+		sort.Slice(roots, func(i, j int) bool { return roots[i].Name < roots[j].Name })
+	}
+
+	reports := make([]Report, 0, len(roots))
+	for _, root := range roots {
+		if root == nil {
+			return nil, fmt.Errorf("failed to find project: %w", graph.ErrNodeNotFound)
+		}
+		report := Report{Project: root.Name}
+		for _, library := range libraries(root, nodes) {
+			report.Libraries++
+			asserted, ok := expressions[library.Name]
+			if !ok {
+				report.Unlicensed++
+				continue
+			}
+			var parsed *Expression
+			for _, expression := range asserted {
+				term, err := Parse(expression)
+				if err != nil {
+					term = &Expression{License: expression}
+				}
+				if parsed == nil {
+					parsed = term
+				} else {
+					parsed = &Expression{Op: And, Left: parsed, Right: term}
+				}
+			}
+			if verdict, licenses := policy.Evaluate(parsed); verdict != Allowed {
+				report.Violations = append(report.Violations, Violation{
+					Library:    library.Name,
+					Expression: parsed.String(),
+					Verdict:    verdict,
+					Licenses:   licenses,
+				})
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Expressions returns the distinct effective license expressions documents assert for the
+// library nodes, sorted, by the name of the node.
+func Expressions(ctx context.Context, storage graph.Storage) (map[string][]string, error) {
+	keys, err := storage.GetCustomDataKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom data keys: %w", err)
+	}
+	expressions := make(map[string][]string, len(keys[ExpressionsTag]))
+	for _, name := range keys[ExpressionsTag] {
+		data, err := storage.GetCustomData(ctx, ExpressionsTag, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the license expressions of %s: %w", name, err)
+		}
+		var asserted []string
+		for _, expression := range data {
+			if len(expression) > 0 && !slices.Contains(asserted, string(expression)) {
+				asserted = append(asserted, string(expression))
+			}
+		}
+		if len(asserted) > 0 {
+			sort.Strings(asserted)
+			expressions[name] = asserted
+		}
+	}
+	return expressions, nil
+}
+
+func hasLibraryParent(node *graph.Node, nodes map[uint32]*graph.Node) bool {
+	if node.Parents == nil {
+		return false
+	}
+	for _, id := range node.Parents.ToArray() {
+		if parent := nodes[id]; parent != nil && parent.Type == tools.LibraryType {
+			return true
+		}
+	}
+	return false
+}
+
+// libraries returns the root and the libraries it depends on, directly or not, sorted by name.
+func libraries(root *graph.Node, nodes map[uint32]*graph.Node) []*graph.Node {
+	seen := map[uint32]bool{root.ID: true}
+	result := []*graph.Node{root}
+	for queue := []*graph.Node{root}; len(queue) > 0; queue = queue[1:] {
+		if queue[0].Children == nil {
+			continue
+		}
+		for _, id := range queue[0].Children.ToArray() {
+			child := nodes[id]
+			if seen[id] || child == nil || child.Type != tools.LibraryType {
+				continue
+			}
+			seen[id] = true
+			result = append(result, child)
+			queue = append(queue, child)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
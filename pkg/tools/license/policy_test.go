@@ -0,0 +1,121 @@
+package license
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitbomdev/minefield/pkg/graph"
+	"github.com/bitbomdev/minefield/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy := Policy{Allow: []string{"mit", "Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"}, Deny: []string{"GPL-2.0-only", "AGPL-3.0-only"}}
+	tests := []struct {
+		expression string
+		verdict    string
+		licenses   []string
+	}{
+		{expression: "MIT", verdict: Allowed},
+		{expression: "ISC", verdict: NotAllowed, licenses: []string{"ISC"}},
+		{expression: "AGPL-3.0-only", verdict: Denied, licenses: []string{"AGPL-3.0-only"}},
+		{expression: "AGPL-3.0-only OR MIT", verdict: Allowed},
+		{expression: "AGPL-3.0-only OR ISC", verdict: NotAllowed, licenses: []string{"AGPL-3.0-only", "ISC"}},
+		{expression: "MIT AND ISC", verdict: NotAllowed, licenses: []string{"ISC"}},
+		{expression: "ISC AND (AGPL-3.0-only OR GPL-2.0-only)", verdict: Denied, licenses: []string{"ISC", "AGPL-3.0-only", "GPL-2.0-only"}},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0", verdict: Allowed},
+		{expression: "GPL-2.0-only WITH GCC-exception-2.0", verdict: Denied, licenses: []string{"GPL-2.0-only WITH GCC-exception-2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			parsed, err := Parse(tt.expression)
+			require.NoError(t, err)
+			verdict, licenses := policy.Evaluate(parsed)
+			assert.Equal(t, tt.verdict, verdict)
+			assert.Equal(t, tt.licenses, licenses)
+		})
+	}
+
+	verdict, _ := Policy{Deny: []string{"AGPL-3.0-only"}}.Evaluate(&Expression{License: "ISC"})
+	assert.Equal(t, Allowed, verdict, "without an allow list, licenses that aren't denied are allowed")
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	library := func(name, expression string) *graph.Node {
+		node, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, name)
+		require.NoError(t, err)
+		if expression != "" {
+			require.NoError(t, storage.AddOrUpdateCustomData(ctx, ExpressionsTag, name, "sbom:a", []byte(expression)))
+		}
+		return node
+	}
+	app := library("pkg:npm/app@1.0.0", "MIT")
+	b := library("pkg:npm/b@1.0.0", "MIT OR GPL-3.0-only")
+	c := library("pkg:npm/c@1.0.0", "GPL-3.0-only")
+	d := library("pkg:npm/d@1.0.0", "")
+	other := library("pkg:npm/other@1.0.0", "Some License")
+	require.NoError(t, app.SetDependency(ctx, storage, b))
+	require.NoError(t, b.SetDependency(ctx, storage, c))
+	require.NoError(t, app.SetDependency(ctx, storage, d))
+	require.NoError(t, other.SetDependency(ctx, storage, c))
+	mit, err := graph.AddNode(ctx, storage, tools.LicenseType, nil, "MIT")
+	require.NoError(t, err)
+	require.NoError(t, app.SetDependency(ctx, storage, mit))
+
+	policy := Policy{Deny: []string{"GPL-3.0-only"}}
+	reports, err := EvaluatePolicy(ctx, storage, policy, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Report{
+		{
+			Project:    "pkg:npm/app@1.0.0",
+			Libraries:  4,
+			Unlicensed: 1,
+			Violations: []Violation{{Library: "pkg:npm/c@1.0.0", Expression: "GPL-3.0-only", Verdict: Denied, Licenses: []string{"GPL-3.0-only"}}},
+		},
+		{
+			Project:    "pkg:npm/other@1.0.0",
+			Libraries:  2,
+			Violations: []Violation{{Library: "pkg:npm/c@1.0.0", Expression: "GPL-3.0-only", Verdict: Denied, Licenses: []string{"GPL-3.0-only"}}},
+		},
+	}, reports)
+
+	reports, err = EvaluatePolicy(ctx, storage, Policy{Allow: []string{"MIT"}}, []string{"pkg:npm/other@1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []Report{{
+		Project:   "pkg:npm/other@1.0.0",
+		Libraries: 2,
+		Violations: []Violation{
+			{Library: "pkg:npm/c@1.0.0", Expression: "GPL-3.0-only", Verdict: NotAllowed, Licenses: []string{"GPL-3.0-only"}},
+			{Library: "pkg:npm/other@1.0.0", Expression: "Some License", Verdict: NotAllowed, Licenses: []string{"Some License"}},
+		},
+	}}, reports)
+
+	_, err = EvaluatePolicy(ctx, storage, policy, []string{"pkg:npm/unknown@1.0.0"})
+	assert.ErrorIs(t, err, graph.ErrNodeNotFound)
+}
+
+func TestEvaluatePolicyConflictingDocuments(t *testing.T) {
+	ctx := context.Background()
+	storage := graph.NewMockStorage()
+	_, err := graph.AddNode(ctx, storage, tools.LibraryType, nil, "pkg:npm/app@1.0.0")
+	require.NoError(t, err)
+	for document, expression := range map[string]string{"sbom:a": "MIT", "sbom:b": "GPL-3.0-only", "sbom:c": "MIT", "sbom:d": ""} {
+		require.NoError(t, storage.AddOrUpdateCustomData(ctx, ExpressionsTag, "pkg:npm/app@1.0.0", document, []byte(expression)))
+	}
+
+	expressions, err := Expressions(ctx, storage)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"pkg:npm/app@1.0.0": {"GPL-3.0-only", "MIT"}}, expressions)
+
+	// The library has to satisfy what every document asserts.
+	reports, err := EvaluatePolicy(ctx, storage, Policy{Deny: []string{"GPL-3.0-only"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Report{{
+		Project:    "pkg:npm/app@1.0.0",
+		Libraries:  1,
+		Violations: []Violation{{Library: "pkg:npm/app@1.0.0", Expression: "GPL-3.0-only AND MIT", Verdict: Denied, Licenses: []string{"GPL-3.0-only"}}},
+	}}, reports)
+}
@@ -4,4 +4,24 @@ const (
 	LibraryType       = "library"
 	VulnerabilityType = "vuln"
 	ScorecardType     = "scorecard"
+	LicenseType       = "license"
+
+	// Document nodes describe an ingested SBOM, OSV record, scorecard result file, VEX document
+	// or lockfile.
+	SBOMDocumentType      = "sbom"
+	OSVDocumentType       = "osv"
+	ScorecardDocumentType = "scorecard-report"
+	VEXDocumentType       = "vex"
+	LockfileDocumentType  = "lockfile"
 )
+
+// Listed reports whether nodes of the type are listed by leaderboards, key listings and glob
+// searches. Document and license nodes describe where the graph came from and what it is
+// licensed under; they aren't packages or findings.
+func Listed(nodeType string) bool {
+	switch nodeType {
+	case LicenseType, SBOMDocumentType, OSVDocumentType, ScorecardDocumentType, VEXDocumentType, LockfileDocumentType:
+		return false
+	}
+	return true
+}
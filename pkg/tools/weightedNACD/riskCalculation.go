@@ -1,6 +1,7 @@
 package weightedNACD
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -35,7 +36,7 @@ type Weights struct {
 	LikelihoodWeight  float64 `json:"likelihoodWeight"`
 }
 
-func WeightedNACD(storage graph.Storage, weights Weights, progress func(int, int)) ([]*PkgAndValue, error) {
+func WeightedNACD(ctx context.Context, storage graph.Storage, weights Weights, progress func(int, int)) ([]*PkgAndValue, error) {
 	weightsForEachType := map[string]weightsForType{}
 
 	if weights.Dependencies != nil {
@@ -56,20 +57,20 @@ func WeightedNACD(storage graph.Storage, weights Weights, progress func(int, int
 
 	var scoresPerPkg []*PkgAndValue
 
-	ids, err := storage.GetAllKeys()
+	ids, err := storage.GetAllKeys(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all leaderboard: %w", err)
 	}
 
 	for index, id := range ids {
-		node, err := storage.GetNode(id)
+		node, err := storage.GetNode(ctx, id)
 		if err != nil {
 			return nil, fmt.Errorf("error getting node with Id %d: %w", id, err)
 		}
 
 		// We can really only calculate the algo on package nodes
 		if node.Type == "library" {
-			deps, err := node.QueryDependencies(storage)
+			deps, err := node.QueryDependencies(ctx, storage)
 			if err != nil {
 				return nil, fmt.Errorf("error querying dependencies for node with Id %d: %w", id, err)
 			}
@@ -1,6 +1,7 @@
 package weightedNACD
 
 import (
+	"context"
 	"math"
 	"testing"
 
@@ -51,23 +52,23 @@ func TestWeightedNACD(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			storage := graph.NewMockStorage()
 			// Add mock nodes to storages
-			node1, err := graph.AddNode(storage, "library", "metadata1", "pkg:generic/dep1@1.0.0")
+			node1, err := graph.AddNode(context.Background(), storage, "library", "metadata1", "pkg:generic/dep1@1.0.0")
 			assert.NoError(t, err)
-			node2, err := graph.AddNode(storage, "library", "metadata2", "pkg:generic/dep2@1.0.0")
+			node2, err := graph.AddNode(context.Background(), storage, "library", "metadata2", "pkg:generic/dep2@1.0.0")
 			assert.NoError(t, err)
 
 			// Set dependencies
-			err = node1.SetDependency(storage, node2)
+			err = node1.SetDependency(context.Background(), storage, node2)
 			assert.NoError(t, err)
 
-			got, err := WeightedNACD(storage, test.weights, nil)
+			got, err := WeightedNACD(context.Background(), storage, test.weights, nil)
 			if (err != nil) != test.wantErr {
-				t.Errorf("WeightedNACD() error = %v, wantErr %v", err, test.wantErr)
+				t.Errorf("WeightedNACD(context.Background()) error = %v, wantErr %v", err, test.wantErr)
 				return
 			}
 
 			if diff := cmp.Diff(test.want, got, cmp.Comparer(compareWithNaNComparison)); diff != "" {
-				t.Errorf("WeightedNACD() mismatch (-want +got):\n%s", diff)
+				t.Errorf("WeightedNACD(context.Background()) mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
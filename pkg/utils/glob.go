@@ -0,0 +1,134 @@
+package utils
+
+import "strings"
+
+// MatchGlob reports whether name matches the glob pattern, using the same rules as Redis
+// KEYS and SCAN MATCH patterns:
+//   - '*' matches any sequence of characters, including '/'
+//   - '?' matches exactly one character
+//   - '[abc]', '[a-z]' and '[^a]' match character classes
+//   - '\' escapes the following character
+func MatchGlob(pattern, name string) bool {
+	p, n := []rune(pattern), []rune(name)
+	// Index in p and n to resume from after the last '*', used for backtracking.
+	starP, starN := -1, 0
+	pi, ni := 0, 0
+	for ni < len(n) {
+		if pi < len(p) {
+			switch p[pi] {
+			case '*':
+				starP, starN = pi, ni
+				pi++
+				continue
+			case '?':
+				pi++
+				ni++
+				continue
+			case '[':
+				if matched, next, ok := matchClass(p, pi, n[ni]); ok {
+					if matched {
+						pi = next
+						ni++
+						continue
+					}
+				} else if n[ni] == '[' {
+					// An unterminated class is matched literally.
+					pi++
+					ni++
+					continue
+				}
+			case '\\':
+				if pi+1 < len(p) && p[pi+1] == n[ni] {
+					pi += 2
+					ni++
+					continue
+				}
+				if pi+1 == len(p) && n[ni] == '\\' {
+					pi++
+					ni++
+					continue
+				}
+			default:
+				if p[pi] == n[ni] {
+					pi++
+					ni++
+					continue
+				}
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		// Let the last '*' absorb one more character and retry.
+		starN++
+		pi, ni = starP+1, starN
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// matchClass matches c against the character class starting at p[start] == '['. It returns
+// whether c is in the class, the index after the closing ']', and false if the class is not
+// terminated.
+func matchClass(p []rune, start int, c rune) (matched bool, next int, ok bool) {
+	i := start + 1
+	negate := false
+	if i < len(p) && p[i] == '^' {
+		negate = true
+		i++
+	}
+	first := true
+	for i < len(p) {
+		if p[i] == ']' && !first {
+			return matched != negate, i + 1, true
+		}
+		first = false
+		lo := p[i]
+		if lo == '\\' && i+1 < len(p) {
+			i++
+			lo = p[i]
+		}
+		if i+2 < len(p) && p[i+1] == '-' && p[i+2] != ']' {
+			hi := p[i+2]
+			if hi == '\\' && i+3 < len(p) {
+				i++
+				hi = p[i+2]
+			}
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			if c >= lo && c <= hi {
+				matched = true
+			}
+			i += 3
+			continue
+		}
+		if c == lo {
+			matched = true
+		}
+		i++
+	}
+	return false, 0, false
+}
+
+// GlobPrefix returns the literal prefix of a glob pattern, that is everything before the
+// first unescaped wildcard, with escapes removed. Every name matching the pattern starts
+// with this prefix.
+func GlobPrefix(pattern string) string {
+	var sb strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*', '?', '[':
+			return sb.String()
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"exact match", "pkg:npm/lodash@4.17.21", "pkg:npm/lodash@4.17.21", true},
+		{"exact mismatch", "pkg:npm/lodash", "pkg:npm/lodash@4.17.21", false},
+		{"star suffix", "pkg:npm/*", "pkg:npm/lodash@4.17.21", true},
+		{"star crosses slashes", "pkg:*@1.0.0", "pkg:golang/github.com/a/b@1.0.0", true},
+		{"star matches empty", "lib*", "lib", true},
+		{"multiple stars", "*minefield*", "pkg:golang/github.com/bitbomdev/minefield@v1", true},
+		{"star backtracking", "*a*b", "aXbXb", true},
+		{"star backtracking mismatch", "*a*b", "aXbXc", false},
+		{"question mark", "node?", "node1", true},
+		{"question mark needs a character", "node?", "node", false},
+		{"class", "node[12]", "node2", true},
+		{"class mismatch", "node[12]", "node3", false},
+		{"range", "node[0-9]", "node7", true},
+		{"negated class", "node[^0-9]", "nodeA", true},
+		{"negated class mismatch", "node[^0-9]", "node7", false},
+		{"escaped star", `lib\*`, "lib*", true},
+		{"escaped star is literal", `lib\*`, "libA", false},
+		{"unterminated class is literal", "lib[", "lib[", true},
+		{"empty pattern", "", "", true},
+		{"empty pattern mismatch", "", "a", false},
+		{"unicode", "pkg:npm/caf?", "pkg:npm/café", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MatchGlob(tt.pattern, tt.input)
+			if result != tt.expected {
+				t.Errorf("MatchGlob(%q, %q) = %v; want %v", tt.pattern, tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGlobPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{"no wildcard", "pkg:npm/lodash", "pkg:npm/lodash"},
+		{"star", "pkg:npm/*", "pkg:npm/"},
+		{"question mark", "node?", "node"},
+		{"class", "node[12]", "node"},
+		{"leading wildcard", "*minefield*", ""},
+		{"escaped wildcard", `lib\*x*`, "lib*x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GlobPrefix(tt.pattern)
+			if result != tt.expected {
+				t.Errorf("GlobPrefix(%q) = %q; want %q", tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}